@@ -2,7 +2,9 @@
 cd ~/www/zione-backend
 echo "Building Zione API..."
 mkdir -p bin
-go build -o bin/zione-api cmd/api/main.go
+COMMIT=$(git rev-parse --short HEAD 2>/dev/null || echo unknown)
+BUILD_TIME=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+go build -ldflags "-X zionechainapi/internal/buildinfo.Version=${VERSION:-dev} -X zionechainapi/internal/buildinfo.Commit=$COMMIT -X zionechainapi/internal/buildinfo.BuildTime=$BUILD_TIME" -o bin/zione-api cmd/api/main.go
 if [ $? -eq 0 ]; then
   echo "Build successful. Binary created at: bin/zione-api"
 else
@@ -9,13 +9,17 @@ import (
 	"os/signal"
 	"strings"
 	"syscall"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"zionechainapi/configs"
+	"zionechainapi/internal/buildinfo"
+	"zionechainapi/internal/cache"
 	"zionechainapi/internal/controllers"
 	"zionechainapi/internal/database"
 	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/storage"
+	"gorm.io/gorm"
 )
 
 // Define available routes for better documentation
@@ -27,53 +31,181 @@ var availableRoutes = []struct {
 }{
 	{"GET", "/", "API Status - Check if API is running", "Public"},
 	{"GET", "/health", "Health Check - Server health status", "Public"},
+	{"GET", "/livez", "Liveness probe - Process is up", "Public"},
+	{"GET", "/readyz", "Readiness probe - Dependencies are ready and migrated", "Public"},
+	{"GET", "/sitemap.xml", "Sitemap of published content", "Public"},
+	{"GET", "/uploads/*filepath", "Uploaded file (local storage)", "Public"},
 	{"GET", "/api", "API Welcome - Welcome message and version info", "Public"},
+	{"GET", "/api/version", "Build information - Version, commit and build time", "Public"},
 	{"POST", "/api/auth/login", "Login via phone/password", "Public"},
 	{"POST", "/api/auth/register", "Register new user", "Public"},
+	{"POST", "/api/auth/refresh", "Refresh access token", "Public"},
+	{"POST", "/api/auth/logout", "Expire the token cookies", "Public"},
+	{"POST", "/api/auth/verify", "Check whether a token is valid", "Public"},
+	{"GET", "/api/auth/verify-email", "Verify email address", "Public"},
 	{"GET", "/api/projects", "Get list of projects", "Public"},
 	{"POST", "/api/projects", "Create project", "Admin"},
+	{"GET", "/api/projects/count", "Count projects", "Public"},
+	{"GET", "/api/projects/last-modified", "Get when published projects last changed", "Public"},
+	{"HEAD", "/api/projects/last-modified", "Check when published projects last changed", "Public"},
+	{"GET", "/api/projects/by-category", "Get published projects grouped by category", "Public"},
+	{"GET", "/api/projects/slug-available", "Check whether a title's slug is free", "Public"},
+	{"GET", "/api/projects/:id/media", "Get project media", "Public"},
+	{"POST", "/api/projects/:id/duplicate", "Duplicate project as a draft", "Admin"},
+	{"POST", "/api/projects/:id/regenerate-slug", "Regenerate project slug from its title", "Admin"},
+	{"GET", "/api/projects/:id/draft", "Get autosaved project draft", "Admin/Preview"},
+	{"PUT", "/api/projects/:id/autosave", "Autosave project draft", "Admin"},
+	{"PATCH", "/api/projects/:id/featured", "Feature or unfeature project", "Admin"},
+	{"PATCH", "/api/projects/:id/publish", "Publish or unpublish project", "Admin"},
+	{"POST", "/api/projects/:id/publish-draft", "Publish autosaved project draft", "Admin"},
+	{"POST", "/api/projects/:id/media/batch", "Add several project media items", "Admin"},
+	{"PUT", "/api/projects/featured/reorder", "Set featured project display order", "Admin"},
+	{"GET", "/api/projects/:id/translations", "Get project translations", "Admin"},
+	{"PUT", "/api/projects/:id/translations/:locale", "Create or replace project translation", "Admin"},
 	{"GET", "/api/blog", "Get blog posts", "Public"},
 	{"POST", "/api/blog", "Create blog post", "Admin"},
+	{"GET", "/api/blog/count", "Count blog posts", "Public"},
+	{"GET", "/api/blog/last-modified", "Get when published blog posts last changed", "Public"},
+	{"HEAD", "/api/blog/last-modified", "Check when published blog posts last changed", "Public"},
+	{"GET", "/api/blog/slug-available", "Check whether a title's slug is free", "Public"},
+	{"GET", "/api/blog/slug/:slug/meta", "Get blog post SEO metadata", "Public"},
+	{"GET", "/api/blog/:id/media", "Get blog post media", "Public"},
+	{"POST", "/api/blog/:id/duplicate", "Duplicate blog post as a draft", "Admin"},
+	{"POST", "/api/blog/:id/regenerate-slug", "Regenerate blog post slug from its title", "Admin"},
+	{"POST", "/api/blog/:id/share", "Create time-limited blog post share link", "Author/Admin"},
+	{"GET", "/api/blog/:id/draft", "Get autosaved blog post draft", "Admin/Preview"},
+	{"PUT", "/api/blog/:id/autosave", "Autosave blog post draft", "Admin"},
+	{"PATCH", "/api/blog/:id/featured", "Feature or unfeature blog post", "Admin"},
+	{"PATCH", "/api/blog/:id/publish", "Publish or unpublish blog post", "Admin"},
+	{"POST", "/api/blog/:id/publish-draft", "Publish autosaved blog post draft", "Admin"},
+	{"GET", "/api/blog/preview", "Preview blog post from a share link", "Public"},
+	{"PUT", "/api/blog/featured/reorder", "Set featured blog post display order", "Admin"},
+	{"GET", "/api/blog/:id/translations", "Get blog post translations", "Admin"},
+	{"PUT", "/api/blog/:id/translations/:locale", "Create or replace blog post translation", "Admin"},
+	{"POST", "/api/blog/:id/like", "Like blog post", "Public"},
+	{"DELETE", "/api/blog/:id/like", "Unlike blog post", "Public"},
+	{"GET", "/api/blog/:id/comments", "Get approved blog post comments", "Public"},
+	{"POST", "/api/blog/:id/comments", "Comment on blog post (held for moderation)", "Public"},
 	{"GET", "/api/categories/projects", "Get project categories", "Public"},
 	{"GET", "/api/categories/blog", "Get blog categories", "Public"},
+	{"GET", "/api/categories/projects/:slug/items", "Get published projects of a category", "Public"},
+	{"GET", "/api/categories/blog/:slug/items", "Get published blog posts of a category", "Public"},
+	{"GET", "/api/audit", "Get audit log of admin actions", "Admin"},
+	{"GET", "/api/stats", "Get portfolio statistics", "Admin"},
+	{"GET", "/api/activity", "Get recent activity feed", "Admin"},
+	{"GET", "/api/drafts", "Get unpublished projects and blog posts", "Admin"},
+	{"POST", "/api/tokens/scoped", "Mint a scoped access token", "Admin"},
+	{"GET", "/api/webhooks", "Get webhook subscriptions", "Admin"},
+	{"POST", "/api/webhooks", "Create webhook subscription", "Admin"},
+	{"GET", "/api/comments", "Get comments for moderation", "Admin"},
+	{"PUT", "/api/comments/:id/approve", "Approve comment", "Admin"},
+	{"DELETE", "/api/comments/:id", "Delete comment", "Admin"},
+	{"POST", "/api/contact", "Send contact message (rate limited)", "Public"},
+	{"GET", "/api/messages", "Get contact messages", "Admin"},
+	{"PUT", "/api/messages/:id/read", "Mark contact message as read", "Admin"},
+	{"PUT", "/api/messages/:id/unread", "Mark contact message as unread", "Admin"},
+	{"GET", "/api/users/:id/projects", "Get projects authored by a user", "Public"},
+	{"GET", "/api/users/:id/blog", "Get blog posts authored by a user", "Public"},
+	{"POST", "/api/categories/projects/:id/reassign", "Move projects to another category", "Admin"},
+	{"POST", "/api/categories/blog/:id/reassign", "Move blog posts to another category", "Admin"},
+	{"GET", "/api/tags", "Get tags", "Public"},
+	{"POST", "/api/tags", "Create tag", "Admin"},
+	{"POST", "/api/tags/:id/merge", "Merge tag into another tag", "Admin"},
+	{"POST", "/api/tags/:id/assign", "Add tag to many projects and blog posts", "Admin"},
+	{"POST", "/api/tags/:id/unassign", "Remove tag from many projects and blog posts", "Admin"},
+	{"GET", "/api/technologies", "Get technologies", "Public"},
+	{"GET", "/api/technologies/:id", "Get technology by ID", "Public"},
+	{"POST", "/api/technologies", "Create technology", "Admin"},
+	{"PUT", "/api/technologies/:id", "Update technology", "Admin"},
+	{"DELETE", "/api/technologies/:id", "Delete technology", "Admin"},
+	{"GET", "/api/export", "Export all content as a JSON backup", "Admin"},
+	{"POST", "/api/import", "Import content from a JSON backup", "Admin"},
+	{"POST", "/api/uploads", "Upload a file for use as media", "Admin"},
+	{"POST", "/api/media/gc", "Purge orphaned media files from storage", "Admin"},
 	
 	// Resume endpoints
 	{"GET", "/api/resume/personal", "Get personal information", "Public"},
+	{"GET", "/api/resume/personal/:id", "Get personal information by ID", "Public"},
 	{"POST", "/api/resume/personal", "Create personal information", "Admin"},
 	{"PUT", "/api/resume/personal/:id", "Update personal information", "Admin"},
 	{"DELETE", "/api/resume/personal/:id", "Delete personal information", "Admin"},
+	{"POST", "/api/resume/personal/:id/avatar", "Upload profile image for personal information", "Admin"},
 	
 	{"GET", "/api/resume/skills", "Get skills", "Public"},
+	{"GET", "/api/resume/skills/summary", "Get skill statistics by category", "Public"},
+	{"GET", "/api/resume/skills/categories", "Get distinct skill categories", "Public"},
+	{"GET", "/api/resume/skills/:id", "Get skill by ID", "Public"},
 	{"POST", "/api/resume/skills", "Create skill", "Admin"},
 	{"PUT", "/api/resume/skills/:id", "Update skill", "Admin"},
 	{"DELETE", "/api/resume/skills/:id", "Delete skill", "Admin"},
 	
 	{"GET", "/api/resume/experience", "Get work experience", "Public"},
+	{"GET", "/api/resume/experience/:id", "Get work experience by ID", "Public"},
 	{"POST", "/api/resume/experience", "Create work experience", "Admin"},
 	{"PUT", "/api/resume/experience/:id", "Update work experience", "Admin"},
 	{"DELETE", "/api/resume/experience/:id", "Delete work experience", "Admin"},
 	
 	{"GET", "/api/resume/education", "Get education details", "Public"},
+	{"GET", "/api/resume/education/:id", "Get education detail by ID", "Public"},
 	{"POST", "/api/resume/education", "Create education detail", "Admin"},
 	{"PUT", "/api/resume/education/:id", "Update education detail", "Admin"},
 	{"DELETE", "/api/resume/education/:id", "Delete education detail", "Admin"},
 	
 	{"GET", "/api/resume/certificates", "Get certificates", "Public"},
+	{"GET", "/api/resume/certificates/:id", "Get certificate by ID", "Public"},
 	{"POST", "/api/resume/certificates", "Create certificate", "Admin"},
 	{"PUT", "/api/resume/certificates/:id", "Update certificate", "Admin"},
 	{"DELETE", "/api/resume/certificates/:id", "Delete certificate", "Admin"},
 	
 	{"GET", "/api/resume/languages", "Get languages", "Public"},
+	{"GET", "/api/resume/languages/:id", "Get language by ID", "Public"},
 	{"POST", "/api/resume/languages", "Create language", "Admin"},
 	{"PUT", "/api/resume/languages/:id", "Update language", "Admin"},
 	{"DELETE", "/api/resume/languages/:id", "Delete language", "Admin"},
 	
 	{"GET", "/api/resume/publications", "Get publications", "Public"},
+	{"GET", "/api/resume/publications/:id", "Get publication by ID", "Public"},
 	{"POST", "/api/resume/publications", "Create publication", "Admin"},
 	{"PUT", "/api/resume/publications/:id", "Update publication", "Admin"},
 	{"DELETE", "/api/resume/publications/:id", "Delete publication", "Admin"},
 	
+	{"GET", "/api/resume/personal/trash", "Get soft-deleted personal information", "Admin"},
+	{"POST", "/api/resume/personal/:id/restore", "Restore soft-deleted personal information", "Admin"},
+	{"DELETE", "/api/resume/personal/:id/purge", "Permanently delete soft-deleted personal information", "Admin"},
+	{"GET", "/api/resume/skills/trash", "Get soft-deleted skills", "Admin"},
+	{"POST", "/api/resume/skills/:id/restore", "Restore a soft-deleted skill", "Admin"},
+	{"DELETE", "/api/resume/skills/:id/purge", "Permanently delete a soft-deleted skill", "Admin"},
+	{"GET", "/api/resume/experience/trash", "Get soft-deleted work experience", "Admin"},
+	{"POST", "/api/resume/experience/:id/restore", "Restore soft-deleted work experience", "Admin"},
+	{"DELETE", "/api/resume/experience/:id/purge", "Permanently delete soft-deleted work experience", "Admin"},
+	{"GET", "/api/resume/education/trash", "Get soft-deleted education details", "Admin"},
+	{"POST", "/api/resume/education/:id/restore", "Restore a soft-deleted education detail", "Admin"},
+	{"DELETE", "/api/resume/education/:id/purge", "Permanently delete a soft-deleted education detail", "Admin"},
+	{"GET", "/api/resume/projects/trash", "Get soft-deleted resume projects", "Admin"},
+	{"POST", "/api/resume/projects/:id/restore", "Restore a soft-deleted resume project", "Admin"},
+	{"DELETE", "/api/resume/projects/:id/purge", "Permanently delete a soft-deleted resume project", "Admin"},
+	{"GET", "/api/resume/certificates/trash", "Get soft-deleted certificates", "Admin"},
+	{"POST", "/api/resume/certificates/:id/restore", "Restore a soft-deleted certificate", "Admin"},
+	{"DELETE", "/api/resume/certificates/:id/purge", "Permanently delete a soft-deleted certificate", "Admin"},
+	{"GET", "/api/resume/languages/trash", "Get soft-deleted languages", "Admin"},
+	{"POST", "/api/resume/languages/:id/restore", "Restore a soft-deleted language", "Admin"},
+	{"DELETE", "/api/resume/languages/:id/purge", "Permanently delete a soft-deleted language", "Admin"},
+	{"GET", "/api/resume/publications/trash", "Get soft-deleted publications", "Admin"},
+	{"POST", "/api/resume/publications/:id/restore", "Restore a soft-deleted publication", "Admin"},
+	{"DELETE", "/api/resume/publications/:id/purge", "Permanently delete a soft-deleted publication", "Admin"},
+	
 	{"GET", "/api/resume/complete", "Get complete resume", "Public"},
+	{"GET", "/api/resume/overview", "Get personal information with section counts", "Public"},
+}
+
+// timeoutExemptRoutes stream their response or read large uploads, so the
+// request timeout would buffer them in memory and cut them off
+var timeoutExemptRoutes = []string{
+	"/api/export",
+	"/api/import",
+	"/api/uploads",
+	"/api/resume/personal/:id/avatar",
+	storage.LocalRoute + "/*filepath",
 }
 
 func main() {
@@ -94,63 +226,58 @@ func main() {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	// Link resume projects to the technology catalog
+	if linked, err := services.NewTechnologyService().MigrateResumeTechnologies(); err != nil {
+		log.Printf("Failed to migrate project technologies: %v", err)
+	} else if linked > 0 {
+		log.Printf("Linked technologies of %d projects", linked)
+	}
+
+	// Deliver the webhook events recorded with content changes
+	outboxWorker := services.NewOutboxWorker(services.NewWebhookDispatcher(config.Webhook), config.Webhook.OutboxBatchSize).
+		Start(config.Webhook.OutboxInterval)
+
 	// Set Gin mode based on environment
-	if config.App.Env == "production" {
+	if config.IsProduction() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	// Initialize Gin router
-	router := gin.Default()
-
-	// Add basic middleware
+	// Initialize Gin router. Recovery covers every route, while the request
+	// logger is added below so that the probes stay out of the logs.
+	router := gin.New()
 	router.Use(gin.Recovery())
-	router.Use(middleware.RequestLogger())
 
-	// Root endpoint
-	router.GET("/", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "Zione API is running!"})
-	})
+	// Redirect /path/ to /path and answer wrong methods on known paths with 405
+	router.RedirectTrailingSlash = true
+	router.HandleMethodNotAllowed = true
 
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "OK"})
-	})
+	// Only believe X-Forwarded-For from the configured proxies, so clients
+	// cannot pick the address that per-IP limits count them under
+	if err := router.SetTrustedProxies(config.App.TrustedProxies); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
 
-	// API base group
-	api := router.Group("/api")
-	
-	// API welcome endpoint
-	api.GET("", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Welcome to Zione API",
-			"version": "1.0.0",
-		})
-	})
+	// Register orchestration probes before the request logger to keep them out of the logs
+	healthController := controllers.NewHealthController(database.Ping, database.PendingMigrations)
+	healthController.Routes(router)
 
-	// Initialize controllers
-	authController := controllers.NewAuthController(config)
-	projectController := controllers.NewProjectController(config)
-	blogController := controllers.NewBlogController(config)
-	categoryController := controllers.NewCategoryController(config)
-	tagController := controllers.NewTagController(config)
-	
-	// Initialize resume controller with the database connection
-	resumeController := controllers.NewResumeController(db)
+	// Track in-flight requests so shutdown can report how many were drained
+	inFlight := middleware.NewInFlightCounter()
 
-	// Register auth routes (no middleware needed for these)
-	authController.Routes(api)
-	
-	// Create auth middleware for protected routes
-	authMiddleware := middleware.Auth(config)
-	
-	// Register controller routes that need auth for some endpoints
-	projectController.Routes(api, authMiddleware)
-	blogController.Routes(api, authMiddleware)
-	categoryController.Routes(api, authMiddleware)
-	tagController.Routes(api, authMiddleware)
-	
-	// Register resume routes
-	resumeController.Routes(api)
+	// Add basic middleware
+	router.Use(inFlight.Middleware())
+	router.Use(middleware.RequestLogger())
+
+	// Public routes accept the configured origins, admin routes only the admin panel
+	publicCORS, overrides := corsPolicies(config)
+	router.Use(middleware.CORS(publicCORS, overrides...))
+
+	// Answer requests that outlive the configured timeout with a 503. Exports
+	// stream and uploads carry large bodies, so they are left to run.
+	router.Use(middleware.Timeout(config.App.RequestTimeout, timeoutExemptRoutes...))
+
+	// Register the application routes
+	counted := registerRoutes(router, config, db)
 
 	// Get port from environment or use default
 	port := os.Getenv("APP_PORT")
@@ -190,17 +317,35 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	
-	fmt.Println("Shutting down server...")
+	pending := inFlight.Count()
+	fmt.Printf("Shutting down server, waiting up to %s for %d in-flight requests...\n", config.App.ShutdownTimeout, pending)
 	
 	// Create context with timeout for shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), config.App.ShutdownTimeout)
 	defer cancel()
 	
-	// Attempt graceful shutdown
+	// Attempt graceful shutdown, waiting for in-flight requests to complete
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		log.Printf("Server forced to shutdown with %d requests still in flight: %v", inFlight.Count(), err)
+	} else {
+		fmt.Printf("Drained %d in-flight requests\n", pending)
 	}
 	
+	// Write the views counted since the last flush
+	for _, controller := range counted {
+		if err := controller.Close(); err != nil {
+			log.Printf("Failed to write view counts: %v", err)
+		}
+	}
+
+	// Undelivered events stay in the outbox for the next start
+	outboxWorker.Stop()
+
+	// Close the shared cache connections
+	if err := cache.Close(); err != nil {
+		log.Printf("Failed to close cache connections: %v", err)
+	}
+
 	// Close database connection
 	if err := database.Close(); err != nil {
 		log.Fatalf("Failed to close database connection: %v", err)
@@ -208,3 +353,142 @@ func main() {
 	
 	fmt.Println("Server exited properly")
 } 
+
+// registerRoutes registers every route of the API on router, after the
+// middleware they share, and returns the controllers that count views, which
+// must be closed on shutdown to write the views not yet written
+func registerRoutes(router *gin.Engine, config *configs.Config, db *gorm.DB) []interface{ Close() error } {
+	// Root endpoint
+	router.GET("/", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "Zione API is running!"})
+	})
+
+	// Health check endpoint
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "OK"})
+	})
+
+	// Sitemap for search engines
+	sitemapController := controllers.NewSitemapController(config)
+	sitemapController.Routes(router)
+
+	// Uploaded files, when stored on local disk
+	uploadController := controllers.NewUploadController(config)
+	uploadController.FileRoutes(router)
+
+	// API base group, where request bodies must be JSON
+	api := router.Group("/api")
+	api.Use(middleware.RequireJSON())
+	
+	// API welcome endpoint
+	api.GET("", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Welcome to Zione API",
+			"version": buildinfo.Version,
+		})
+	})
+
+	// Build information for deployment checks
+	versionController := controllers.NewVersionController(config)
+	versionController.Routes(api)
+
+	// Initialize controllers
+	authController := controllers.NewAuthController(config)
+	projectController := controllers.NewProjectController(config)
+	blogController := controllers.NewBlogController(config)
+	categoryController := controllers.NewCategoryController(config)
+	tagController := controllers.NewTagController(config)
+	technologyController := controllers.NewTechnologyController(config)
+	auditController := controllers.NewAuditController(config)
+	statsController := controllers.NewStatsController(config)
+	activityController := controllers.NewActivityController(config)
+	draftController := controllers.NewDraftController(config)
+	tokenController := controllers.NewTokenController(config)
+	webhookController := controllers.NewWebhookController(config)
+	commentController := controllers.NewCommentController(config)
+	contactController := controllers.NewContactController(config)
+	backupController := controllers.NewBackupController(config)
+	userController := controllers.NewUserController(config)
+	
+	// Initialize resume controller with the database connection
+	resumeController := controllers.NewResumeController(db, config)
+
+	// Register auth routes (no middleware needed for these)
+	authController.Routes(api)
+	
+	// Create auth middleware for protected routes
+	authMiddleware := middleware.Auth(config)
+	
+	// Register controller routes that need auth for some endpoints
+	projectController.Routes(api, authMiddleware)
+	blogController.Routes(api, authMiddleware)
+	categoryController.Routes(api, authMiddleware)
+	tagController.Routes(api, authMiddleware)
+	technologyController.Routes(api, authMiddleware)
+	auditController.Routes(api, authMiddleware)
+	statsController.Routes(api, authMiddleware)
+	activityController.Routes(api, authMiddleware)
+	draftController.Routes(api, authMiddleware)
+	tokenController.Routes(api, authMiddleware)
+	webhookController.Routes(api, authMiddleware)
+	commentController.Routes(api, authMiddleware)
+	contactController.Routes(api, authMiddleware)
+	backupController.Routes(api, authMiddleware)
+	// Uploads send multipart bodies, so they get a group without the JSON requirement
+	uploadController.Routes(router.Group("/api"), authMiddleware)
+	userController.Routes(api, middleware.OptionalAuth(config))
+	
+	// Register resume routes
+	resumeController.Routes(api, authMiddleware)
+	resumeController.UploadRoutes(router.Group("/api"), authMiddleware)
+
+	// Documented routes that are not registered yet answer 501 instead of 404 or 405
+	declared := declaredRoutes()
+	router.NoRoute(middleware.NotImplemented(router, declared, nil))
+	router.NoMethod(middleware.NotImplemented(router, declared, middleware.MethodNotAllowed(router)))
+
+	return []interface{ Close() error }{projectController, blogController}
+}
+
+// declaredRoutes returns the method and path of the available routes
+func declaredRoutes() []middleware.DeclaredRoute {
+	declared := make([]middleware.DeclaredRoute, 0, len(availableRoutes))
+	for _, route := range availableRoutes {
+		declared = append(declared, middleware.DeclaredRoute{Method: route.Method, Path: route.Path})
+	}
+	return declared
+}
+
+// corsPolicies returns the CORS policy of public routes and its overrides.
+// Writes are admin routes unless documented as public, such as login or
+// commenting, so that undocumented write routes fail closed. Reads are public
+// unless documented as admin routes, which include the drafts only editors and
+// preview links can read.
+func corsPolicies(config *configs.Config) (middleware.CORSPolicy, []middleware.CORSPolicy) {
+	public := middleware.CORSPolicy{
+		AllowedOrigins:   config.CORS.AllowedOrigins,
+		AllowedMethods:   config.CORS.AllowedMethods,
+		AllowedHeaders:   config.CORS.AllowedHeaders,
+		AllowCredentials: config.CORS.AllowCredentials,
+	}
+
+	publicWrites := public
+	admin := public
+	admin.AllowedOrigins = config.CORS.AdminAllowedOrigins
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		admin.Routes = append(admin.Routes, middleware.CORSRoute{Method: method, Path: "/*path"})
+	}
+
+	for _, route := range availableRoutes {
+		cors := middleware.CORSRoute{Method: route.Method, Path: route.Path}
+		switch {
+		case route.Access == "Public" && route.Method != http.MethodGet:
+			publicWrites.Routes = append(publicWrites.Routes, cors)
+		case route.Access == "Admin" || route.Access == "Author/Admin" || route.Access == "Admin/Preview":
+			admin.Routes = append(admin.Routes, cors)
+		}
+	}
+
+	// The first covering policy applies, so the public writes go first
+	return public, []middleware.CORSPolicy{publicWrites, admin}
+}
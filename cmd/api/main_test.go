@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/configs"
+	"zionechainapi/internal/controllers"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/middleware"
+)
+
+func TestEveryAvailableRouteIsRegistered(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config, err := configs.LoadConfig()
+	assert.NoError(t, err)
+	config.Storage.Driver = "local"
+
+	// Routes are only registered here, so no database is needed
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+	controllers.NewHealthController(database.Ping, database.PendingMigrations).Routes(router)
+	for _, controller := range registerRoutes(router, config, nil) {
+		defer controller.Close()
+	}
+
+	assert.Empty(t, middleware.UnregisteredRoutes(router, declaredRoutes()))
+
+	// Sections without a trash are not found rather than not implemented
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/resume/bogus/trash", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"zionechainapi/configs"
+	"zionechainapi/internal/database"
+)
+
+// repair cleans up orphaned rows left behind by earlier delete flows
+func main() {
+	// Load configuration
+	config, err := configs.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Setup database connection
+	if _, err := database.Connect(config); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	// Remove orphaned tag and technology associations
+	removed, err := database.RepairOrphanedJoinRows()
+	if err != nil {
+		log.Fatalf("Failed to repair orphaned join rows: %v", err)
+	}
+
+	fmt.Printf("Removed %d orphaned tag and technology association rows\n", removed)
+}
@@ -1,6 +1,7 @@
 package configs
 
 import (
+	"errors"
 	"log"
 	"os"
 	"strings"
@@ -10,33 +11,64 @@ import (
 	"github.com/spf13/viper"
 )
 
+// DefaultJWTSecret is the JWT secret used when JWT_SECRET is not set. It is
+// public, so it is refused in production.
+const DefaultJWTSecret = "default-jwt-secret-change-in-production"
+
+var (
+	// ErrDefaultJWTSecret is returned when production runs with the default JWT secret
+	ErrDefaultJWTSecret = errors.New("JWT_SECRET must be set to a non-default value in production")
+	// ErrEmptyDatabasePassword is returned when production runs without a database password
+	ErrEmptyDatabasePassword = errors.New("DB_PASSWORD must be set in production")
+)
+
 // Config holds all configuration for the application
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	CORS     CORSConfig
-	Log      LogConfig
-	TLS      TLSConfig
+	App        AppConfig
+	Database   DatabaseConfig
+	JWT        JWTConfig
+	Auth       AuthConfig
+	Pagination PaginationConfig
+	Cache      CacheConfig
+	Redis      RedisConfig
+	Content    ContentConfig
+	Webhook    WebhookConfig
+	Comment    CommentConfig
+	Contact    ContactConfig
+	Storage    StorageConfig
+	Mail       MailConfig
+	CORS       CORSConfig
+	Log        LogConfig
+	TLS        TLSConfig
 }
 
 // AppConfig holds all application-specific configuration
 type AppConfig struct {
-	Env  string
-	Port string
-	Host string
-	Name string
-	URL  string
+	Env             string
+	Port            string
+	Host            string
+	Name            string
+	URL             string
+	ShutdownTimeout time.Duration
+	RequestTimeout  time.Duration
+	// TrustedProxies are the addresses or CIDRs of the proxies whose
+	// X-Forwarded-For is believed. With none, the client address is the remote
+	// address of the connection.
+	TrustedProxies []string
 }
 
 // DatabaseConfig holds all database-specific configuration
 type DatabaseConfig struct {
+	// Driver selects the database: "mysql", "postgres" or "sqlite". SQLite
+	// reads Name as the path of the database file, or ":memory:".
+	Driver          string
 	Host            string
 	Port            string
 	Name            string
 	User            string
 	Password        string
 	Charset         string
+	SSLMode         string // sslmode of PostgreSQL connections
 	MaxIdleConns    int
 	MaxOpenConns    int
 	ConnMaxLifetime time.Duration
@@ -47,13 +79,187 @@ type JWTConfig struct {
 	Secret               string
 	AccessTokenExpiry    time.Duration
 	RefreshTokenExpiry   time.Duration
+
+	// Issuer and Audience are set on issued tokens and required on validated ones
+	Issuer   string
+	Audience string
+	// AcceptLegacyUntil keeps accepting tokens without issuer and audience
+	// claims, issued before they were introduced, until this time. Tokens with
+	// other claims are still rejected. The zero time accepts none.
+	AcceptLegacyUntil time.Time
+
+	// Leeway tolerates clock skew when checking token time claims
+	Leeway time.Duration
+
+	// KeyID is the kid header of tokens signed with Secret
+	KeyID string
+	// SecondaryKeys maps retired key IDs to secrets still accepted for verification
+	SecondaryKeys map[string]string
+}
+
+// AuthConfig holds all login protection and token delivery configuration
+type AuthConfig struct {
+	MaxFailedLogins int
+	LockoutDuration time.Duration
+
+	// TokenDelivery selects how login, register and refresh hand out tokens:
+	// "body" returns them in the JSON body, "cookie" sets them as HttpOnly
+	// cookies instead and "both" does both
+	TokenDelivery string
+	// CookieDomain, CookieSecure and CookieSameSite ("lax", "strict" or "none")
+	// are the attributes of the token cookies
+	CookieDomain   string
+	CookieSecure   bool
+	CookieSameSite string
+}
+
+// PageLimits holds the default and maximum page size of a list
+type PageLimits struct {
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// PaginationConfig holds list page sizes, with optional per-resource overrides
+type PaginationConfig struct {
+	PageLimits
+	Resources map[string]PageLimits
+}
+
+// paginatedResources are the resources whose page sizes can be overridden. Every
+// name passed to Limits must be listed here, or its override is never read.
+var paginatedResources = []string{"projects", "blog", "audit", "comments", "media", "publications", "messages", "drafts", "activity"}
+
+// Limits returns the page limits of a resource, falling back to the global
+// limits for anything the resource does not override
+func (p PaginationConfig) Limits(resource string) PageLimits {
+	limits := p.PageLimits
+	if override, ok := p.Resources[resource]; ok {
+		if override.DefaultLimit > 0 {
+			limits.DefaultLimit = override.DefaultLimit
+		}
+		if override.MaxLimit > 0 {
+			limits.MaxLimit = override.MaxLimit
+		}
+	}
+	return limits
+}
+
+// CacheConfig holds all response cache configuration
+type CacheConfig struct {
+	// Driver selects the cache store: "memory" or "redis"
+	Driver string
+	// ListTTL is how long anonymous public list responses are cached; zero disables caching
+	ListTTL time.Duration
+}
+
+// RedisConfig holds all Redis-specific configuration
+type RedisConfig struct {
+	Host     string
+	Port     string
+	Password string
+	DB       int
+}
+
+// ContentConfig holds all project and blog content configuration
+type ContentConfig struct {
+	// SanitizeHTML strips unsafe HTML from content before it is stored
+	SanitizeHTML bool
+	// DefaultLocale is the locale of the base content, served without translations
+	DefaultLocale string
+	// PreviewExpiry is how long a draft share link stays valid
+	PreviewExpiry time.Duration
+	// LikeWindow is how long a client's like or unlike of a post is remembered to ignore repeats
+	LikeWindow time.Duration
+	// ViewFlushInterval is how often views counted in memory are written to the database
+	ViewFlushInterval time.Duration
+}
+
+// WebhookConfig holds all webhook delivery configuration
+type WebhookConfig struct {
+	// MaxAttempts is how many times a delivery is tried before it is dropped
+	MaxAttempts int
+	// RetryBackoff is the wait before the first retry, doubled on each further retry
+	RetryBackoff time.Duration
+	// Timeout bounds a single delivery request
+	Timeout time.Duration
+	// OutboxInterval is how often undelivered events are looked up in the outbox
+	OutboxInterval time.Duration
+	// OutboxBatchSize is the most events delivered per lookup
+	OutboxBatchSize int
+}
+
+// CommentConfig holds all blog comment spam protection configuration
+type CommentConfig struct {
+	// MaxLength is the maximum number of characters in a comment body
+	MaxLength int
+	// RateLimit is how many comments one client may post per RateWindow; zero disables the limit
+	RateLimit  int
+	RateWindow time.Duration
+}
+
+// ContactConfig holds all contact form configuration
+type ContactConfig struct {
+	// MaxLength is the maximum number of characters in a message body
+	MaxLength int
+	// RateLimit is how many messages one client may send per RateWindow; zero disables the limit
+	RateLimit  int
+	RateWindow time.Duration
+	// NotifyEmail is emailed about every new message; empty disables the notification
+	NotifyEmail string
+}
+
+// StorageConfig holds all uploaded file storage configuration
+type StorageConfig struct {
+	// Driver selects where uploads are stored: "local" or "s3"
+	Driver string
+	// LocalPath is the directory the local driver writes uploads to
+	LocalPath string
+	// BaseURL is the public URL uploads are served from. The local driver
+	// defaults to APP_URL/uploads and the s3 driver to the bucket URL.
+	BaseURL string
+	// MaxSize is the largest accepted upload in bytes
+	MaxSize int64
+	// AllowedExtensions and AllowedContentTypes whitelist uploads; a file must match both
+	AllowedExtensions   []string
+	AllowedContentTypes []string
+	// WebPDerivatives saves a WebP copy next to uploaded PNG, JPEG and GIF images
+	WebPDerivatives bool
+	S3              S3Config
+}
+
+// S3Config holds the settings of an S3-compatible object store
+type S3Config struct {
+	// Endpoint is the base URL of the store, such as https://s3.eu-west-1.amazonaws.com or a MinIO URL.
+	// Objects are addressed path-style as Endpoint/Bucket/key.
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// MailConfig holds all outgoing email configuration
+type MailConfig struct {
+	// Driver selects how emails are sent: "log" writes them to the application log, "smtp" sends them
+	Driver   string
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// From is the sender address of all outgoing emails
+	From string
 }
 
 // CORSConfig holds all CORS-specific configuration
 type CORSConfig struct {
+	// AllowedOrigins are allowed on public routes and may contain "*"
 	AllowedOrigins []string
-	AllowedMethods []string
-	AllowedHeaders []string
+	// AdminAllowedOrigins are the origins of the admin panel, the only ones allowed on admin routes
+	AdminAllowedOrigins []string
+	AllowedMethods      []string
+	AllowedHeaders      []string
+	// AllowCredentials lets browsers send cookies, such as the refresh token cookie, cross-origin
+	AllowCredentials bool
 }
 
 // LogConfig holds all logging-specific configuration
@@ -84,27 +290,117 @@ func LoadConfig() (*Config, error) {
 			Host: getEnv("APP_HOST", "0.0.0.0"),
 			Name: getEnv("APP_NAME", "zione-backend"),
 			URL:  getEnv("APP_URL", "http://localhost:8080"),
+			// How long to wait for in-flight requests on shutdown
+			ShutdownTimeout: getDurationEnv("APP_SHUTDOWN_TIMEOUT", 5*time.Second),
+			// How long a request may take before it is answered with a 503; 0 disables the limit
+			RequestTimeout: getDurationEnv("APP_REQUEST_TIMEOUT", 30*time.Second),
+			// Comma separated proxy addresses or CIDRs; none are trusted by default
+			TrustedProxies: getStringSliceEnv("TRUSTED_PROXIES", nil),
 		},
 		Database: DatabaseConfig{
+			Driver:          getEnv("DB_DRIVER", "mysql"),
 			Host:            getEnv("DB_HOST", "localhost"),
 			Port:            getEnv("DB_PORT", "3306"),
 			Name:            getEnv("DB_NAME", "zione_db"),
 			User:            getEnv("DB_USER", "root"),
 			Password:        getEnv("DB_PASSWORD", ""),
 			Charset:         getEnv("DB_CHARSET", "utf8mb4"),
+			SSLMode:         getEnv("DB_SSLMODE", "disable"),
 			MaxIdleConns:    getIntEnv("DB_MAX_IDLE_CONNS", 10),
 			MaxOpenConns:    getIntEnv("DB_MAX_OPEN_CONNS", 100),
 			ConnMaxLifetime: getDurationEnv("DB_CONN_MAX_LIFETIME", time.Hour),
 		},
 		JWT: JWTConfig{
-			Secret:             getEnv("JWT_SECRET", "default-jwt-secret-change-in-production"),
+			Secret:             getEnv("JWT_SECRET", DefaultJWTSecret),
 			AccessTokenExpiry:  getDurationEnv("JWT_ACCESS_TOKEN_EXPIRY", 15*time.Minute),
 			RefreshTokenExpiry: getDurationEnv("JWT_REFRESH_TOKEN_EXPIRY", 7*24*time.Hour), // 7 days
+			Issuer:             getEnv("JWT_ISSUER", "zionechainapi"),
+			Audience:           getEnv("JWT_AUDIENCE", "zionechainapi"),
+			AcceptLegacyUntil:  getTimeEnv("JWT_ACCEPT_LEGACY_UNTIL", time.Time{}),
+			Leeway:             getDurationEnv("JWT_LEEWAY", 30*time.Second),
+			KeyID:              getEnv("JWT_KEY_ID", ""),
+			SecondaryKeys:      getStringMapEnv("JWT_SECONDARY_KEYS", map[string]string{}),
+		},
+		Auth: AuthConfig{
+			MaxFailedLogins: getIntEnv("AUTH_MAX_FAILED_LOGINS", 5),
+			LockoutDuration: getDurationEnv("AUTH_LOCKOUT_DURATION", 15*time.Minute),
+			TokenDelivery:   getEnv("AUTH_TOKEN_DELIVERY", "body"),
+			CookieDomain:    getEnv("AUTH_COOKIE_DOMAIN", ""),
+			CookieSecure:    getBoolEnv("AUTH_COOKIE_SECURE", true),
+			CookieSameSite:  getEnv("AUTH_COOKIE_SAMESITE", "lax"),
+		},
+		Pagination: PaginationConfig{
+			PageLimits: PageLimits{
+				DefaultLimit: getIntEnv("PAGINATION_DEFAULT_LIMIT", 10),
+				MaxLimit:     getIntEnv("PAGINATION_MAX_LIMIT", 100),
+			},
+			Resources: getPaginationOverrides(),
+		},
+		Cache: CacheConfig{
+			Driver:  getEnv("CACHE_DRIVER", "memory"),
+			ListTTL: getDurationEnv("CACHE_LIST_TTL", 30*time.Second),
+		},
+		Redis: RedisConfig{
+			Host:     getEnv("REDIS_HOST", "localhost"),
+			Port:     getEnv("REDIS_PORT", "6379"),
+			Password: getEnv("REDIS_PASSWORD", ""),
+			DB:       getIntEnv("REDIS_DB", 0),
+		},
+		Content: ContentConfig{
+			SanitizeHTML:      getBoolEnv("CONTENT_SANITIZE_HTML", true),
+			DefaultLocale:     getEnv("CONTENT_DEFAULT_LOCALE", "en"),
+			PreviewExpiry:     getDurationEnv("CONTENT_PREVIEW_EXPIRY", 72*time.Hour),
+			LikeWindow:        getDurationEnv("CONTENT_LIKE_WINDOW", 24*time.Hour),
+			ViewFlushInterval: getDurationEnv("CONTENT_VIEW_FLUSH_INTERVAL", 30*time.Second),
+		},
+		Webhook: WebhookConfig{
+			MaxAttempts:     getIntEnv("WEBHOOK_MAX_ATTEMPTS", 3),
+			RetryBackoff:    getDurationEnv("WEBHOOK_RETRY_BACKOFF", 2*time.Second),
+			Timeout:         getDurationEnv("WEBHOOK_TIMEOUT", 5*time.Second),
+			OutboxInterval:  getDurationEnv("WEBHOOK_OUTBOX_INTERVAL", 5*time.Second),
+			OutboxBatchSize: getIntEnv("WEBHOOK_OUTBOX_BATCH_SIZE", 100),
+		},
+		Comment: CommentConfig{
+			MaxLength:  getIntEnv("COMMENT_MAX_LENGTH", 2000),
+			RateLimit:  getIntEnv("COMMENT_RATE_LIMIT", 5),
+			RateWindow: getDurationEnv("COMMENT_RATE_WINDOW", 10*time.Minute),
+		},
+		Contact: ContactConfig{
+			MaxLength:   getIntEnv("CONTACT_MAX_LENGTH", 5000),
+			RateLimit:   getIntEnv("CONTACT_RATE_LIMIT", 3),
+			RateWindow:  getDurationEnv("CONTACT_RATE_WINDOW", time.Hour),
+			NotifyEmail: getEnv("CONTACT_NOTIFY_EMAIL", ""),
+		},
+		Storage: StorageConfig{
+			Driver:              getEnv("STORAGE_DRIVER", "local"),
+			LocalPath:           getEnv("STORAGE_LOCAL_PATH", "./uploads"),
+			BaseURL:             getEnv("STORAGE_BASE_URL", ""),
+			MaxSize:             int64(getIntEnv("STORAGE_MAX_SIZE", 10<<20)), // 10 MiB
+			AllowedExtensions:   getStringSliceEnv("STORAGE_ALLOWED_EXTENSIONS", []string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".avif", ".pdf", ".mp4"}),
+			AllowedContentTypes: getStringSliceEnv("STORAGE_ALLOWED_CONTENT_TYPES", []string{"image/jpeg", "image/png", "image/gif", "image/webp", "image/avif", "application/pdf", "video/mp4"}),
+			WebPDerivatives:     getBoolEnv("STORAGE_WEBP_DERIVATIVES", false),
+			S3: S3Config{
+				Endpoint:  getEnv("S3_ENDPOINT", ""),
+				Region:    getEnv("S3_REGION", "us-east-1"),
+				Bucket:    getEnv("S3_BUCKET", ""),
+				AccessKey: getEnv("S3_ACCESS_KEY", ""),
+				SecretKey: getEnv("S3_SECRET_KEY", ""),
+			},
+		},
+		Mail: MailConfig{
+			Driver:   getEnv("MAIL_DRIVER", "log"),
+			Host:     getEnv("MAIL_HOST", "localhost"),
+			Port:     getIntEnv("MAIL_PORT", 587),
+			Username: getEnv("MAIL_USERNAME", ""),
+			Password: getEnv("MAIL_PASSWORD", ""),
+			From:     getEnv("MAIL_FROM", "no-reply@localhost"),
 		},
 		CORS: CORSConfig{
-			AllowedOrigins: getStringSliceEnv("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
-			AllowedMethods: getStringSliceEnv("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
-			AllowedHeaders: getStringSliceEnv("CORS_ALLOWED_HEADERS", []string{"Origin", "Content-Type", "Accept", "Authorization"}),
+			AllowedOrigins:      getStringSliceEnv("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
+			AdminAllowedOrigins: getStringSliceEnv("CORS_ADMIN_ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
+			AllowedMethods:      getStringSliceEnv("CORS_ALLOWED_METHODS", []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
+			AllowedHeaders:      getStringSliceEnv("CORS_ALLOWED_HEADERS", []string{"Origin", "Content-Type", "Accept", "Authorization"}),
+			AllowCredentials:    getBoolEnv("CORS_ALLOW_CREDENTIALS", false),
 		},
 		Log: LogConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
@@ -117,9 +413,36 @@ func LoadConfig() (*Config, error) {
 		},
 	}
 
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
+// IsProduction reports whether the application runs in the production environment
+func (c *Config) IsProduction() bool {
+	return strings.EqualFold(c.App.Env, "production")
+}
+
+// Validate refuses settings that are only acceptable outside production
+func (c *Config) Validate() error {
+	if !c.IsProduction() {
+		return nil
+	}
+
+	if c.JWT.Secret == DefaultJWTSecret {
+		return ErrDefaultJWTSecret
+	}
+
+	// SQLite databases are files without a password
+	if c.Database.Password == "" && !strings.EqualFold(c.Database.Driver, "sqlite") {
+		return ErrEmptyDatabasePassword
+	}
+
+	return nil
+}
+
 // Helper functions to get environment variables with defaults
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -162,6 +485,21 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	return v.GetDuration(key)
 }
 
+// getTimeEnv parses an RFC 3339 time, keeping the default when it is malformed
+func getTimeEnv(key string, defaultValue time.Time) time.Time {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Printf("Ignoring %s: %v", key, err)
+		return defaultValue
+	}
+	return parsed
+}
+
 func getStringSliceEnv(key string, defaultValue []string) []string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -169,4 +507,41 @@ func getStringSliceEnv(key string, defaultValue []string) []string {
 	}
 
 	return strings.Split(value, ",")
-} 
\ No newline at end of file
+}
+
+// getStringMapEnv parses a comma separated list of key:value pairs
+func getStringMapEnv(key string, defaultValue map[string]string) map[string]string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	result := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			log.Printf("Warning: ignoring malformed entry in %s", key)
+			continue
+		}
+		result[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return result
+}
+
+// getPaginationOverrides reads PAGINATION_<RESOURCE>_DEFAULT_LIMIT and
+// PAGINATION_<RESOURCE>_MAX_LIMIT for each paginated resource
+func getPaginationOverrides() map[string]PageLimits {
+	overrides := make(map[string]PageLimits)
+	for _, resource := range paginatedResources {
+		prefix := "PAGINATION_" + strings.ToUpper(resource)
+		limits := PageLimits{
+			DefaultLimit: getIntEnv(prefix+"_DEFAULT_LIMIT", 0),
+			MaxLimit:     getIntEnv(prefix+"_MAX_LIMIT", 0),
+		}
+		if limits.DefaultLimit > 0 || limits.MaxLimit > 0 {
+			overrides[resource] = limits
+		}
+	}
+	return overrides
+}
\ No newline at end of file
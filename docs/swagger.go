@@ -1 +1,3 @@
- 
\ No newline at end of file
+// Package docs holds the Swagger documentation generated from the handler
+// annotations. Regenerate it with swag init.
+package docs
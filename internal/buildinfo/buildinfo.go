@@ -0,0 +1,16 @@
+// Package buildinfo holds details about the running build. They are injected
+// at build time with ldflags, for example:
+//
+//	go build -ldflags "-X zionechainapi/internal/buildinfo.Version=1.2.0 \
+//		-X zionechainapi/internal/buildinfo.Commit=$(git rev-parse --short HEAD) \
+//		-X zionechainapi/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package buildinfo
+
+var (
+	// Version is the release version of the build
+	Version = "dev"
+	// Commit is the git commit the build was made from
+	Commit = "unknown"
+	// BuildTime is when the build was made
+	BuildTime = "unknown"
+)
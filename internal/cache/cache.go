@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"container/list"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"zionechainapi/configs"
+	"zionechainapi/internal/clock"
+)
+
+// Cache stores byte values with an expiry. Implementations must not fail
+// requests: store errors are treated as cache misses.
+type Cache interface {
+	// Get returns the value of a key that has not expired
+	Get(key string) ([]byte, bool)
+	// Set stores a value for the given time to live
+	Set(key string, value []byte, ttl time.Duration)
+	// Clear removes every key
+	Clear()
+}
+
+// New creates the cache store selected by the configured driver. Keys are
+// namespaced by prefix so stores sharing a Redis database clear independently.
+// Memory stores with the same prefix are shared within the process, as Redis
+// stores are, so one controller can clear a store another one fills. Redis
+// stores share one client per server, which Close releases.
+func New(config *configs.Config, prefix string) Cache {
+	switch strings.ToLower(config.Cache.Driver) {
+	case "redis":
+		return NewRedisCache(sharedRedisClient(config.Redis), prefix)
+	case "", "memory":
+		return sharedMemoryCache(prefix)
+	default:
+		log.Printf("Warning: unknown cache driver %q, using memory cache", config.Cache.Driver)
+		return sharedMemoryCache(prefix)
+	}
+}
+
+// memoryStores holds the memory stores created by New, by prefix
+var (
+	memoryStoresMu sync.Mutex
+	memoryStores   = make(map[string]*MemoryCache)
+)
+
+// sharedMemoryCache returns the memory store of prefix, creating it on first use
+func sharedMemoryCache(prefix string) *MemoryCache {
+	memoryStoresMu.Lock()
+	defer memoryStoresMu.Unlock()
+
+	store, ok := memoryStores[prefix]
+	if !ok {
+		store = NewMemoryCache()
+		memoryStores[prefix] = store
+	}
+	return store
+}
+
+// maxMemoryEntries is the number of entries the memory cache holds before it
+// evicts the least recently used ones
+const maxMemoryEntries = 1024
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryCache is an in-process Cache holding at most maxMemoryEntries entries
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // of *memoryEntry, most recently used first
+	clock   clock.Clock
+}
+
+// NewMemoryCache creates a new in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		clock:   clock.Real{},
+	}
+}
+
+// WithClock replaces the clock used to expire entries
+func (c *MemoryCache) WithClock(clk clock.Clock) *MemoryCache {
+	c.clock = clk
+	return c
+}
+
+// Get returns the value of a key that has not expired
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*memoryEntry)
+	if c.clock.Now().After(entry.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// Set stores a value for the given time to live, evicting the least recently
+// used entry when the cache is full
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.clock.Now().Add(ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*memoryEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, value: value, expiresAt: expiresAt})
+	for len(c.entries) > maxMemoryEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// Clear removes every key
+func (c *MemoryCache) Clear() {
+	c.mu.Lock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.mu.Unlock()
+}
+
+// remove deletes an entry. The caller must hold the lock.
+func (c *MemoryCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"context"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"zionechainapi/configs"
+)
+
+// redisTimeout bounds every Redis call so an unreachable server cannot stall a request
+const redisTimeout = 500 * time.Millisecond
+
+// redisScanCount is the number of keys requested per SCAN call when clearing
+const redisScanCount = 100
+
+// RedisCache is a Cache stored in Redis under a key prefix. When Redis is
+// unreachable reads miss and writes are dropped, so requests fall through to
+// the database.
+type RedisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// newRedisClient creates a Redis client from configuration
+func newRedisClient(config configs.RedisConfig) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:         net.JoinHostPort(config.Host, config.Port),
+		Password:     config.Password,
+		DB:           config.DB,
+		DialTimeout:  redisTimeout,
+		ReadTimeout:  redisTimeout,
+		WriteTimeout: redisTimeout,
+	})
+}
+
+// redisClients holds the Redis clients created by New, by server, so that the
+// stores of the process share one connection pool
+var (
+	redisClientsMu sync.Mutex
+	redisClients   = make(map[configs.RedisConfig]*redis.Client)
+)
+
+// sharedRedisClient returns the client of a Redis server, creating it on first use
+func sharedRedisClient(config configs.RedisConfig) *redis.Client {
+	redisClientsMu.Lock()
+	defer redisClientsMu.Unlock()
+
+	client, ok := redisClients[config]
+	if !ok {
+		client = newRedisClient(config)
+		redisClients[config] = client
+	}
+	return client
+}
+
+// Close closes the Redis clients shared by the stores New created. Stores
+// created afterwards connect again.
+func Close() error {
+	redisClientsMu.Lock()
+	defer redisClientsMu.Unlock()
+
+	var firstErr error
+	for config, client := range redisClients {
+		if err := client.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(redisClients, config)
+	}
+	return firstErr
+}
+
+// NewRedisCache creates a new Redis cache whose keys start with prefix
+func NewRedisCache(client *redis.Client, prefix string) *RedisCache {
+	return &RedisCache{
+		client: client,
+		prefix: prefix + ":",
+	}
+}
+
+// Get returns the value of a key that has not expired
+func (c *RedisCache) Get(key string) ([]byte, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("Warning: redis cache get failed: %v", err)
+		}
+		return nil, false
+	}
+	return value, true
+}
+
+// Set stores a value for the given time to live
+func (c *RedisCache) Set(key string, value []byte, ttl time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := c.client.Set(ctx, c.prefix+key, value, ttl).Err(); err != nil {
+		log.Printf("Warning: redis cache set failed: %v", err)
+	}
+}
+
+// Clear removes every key under the cache prefix
+func (c *RedisCache) Clear() {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Scan(ctx, cursor, c.prefix+"*", redisScanCount).Result()
+		if err != nil {
+			log.Printf("Warning: redis cache clear failed: %v", err)
+			return
+		}
+		if len(keys) > 0 {
+			if err := c.client.Del(ctx, keys...).Err(); err != nil {
+				log.Printf("Warning: redis cache clear failed: %v", err)
+				return
+			}
+		}
+		if next == 0 {
+			return
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,52 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time. Services take a Clock instead of calling
+// time.Now directly so tests can control time.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is a Clock backed by the system time
+type Real struct{}
+
+// Now returns the current system time
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Fake is a Clock that only moves when told to
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake creates a fake clock frozen at the given time
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the frozen time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Set moves the clock to the given time
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	f.now = now
+	f.mu.Unlock()
+}
+
+// Advance moves the clock forward by d
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	f.mu.Unlock()
+}
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// ActivityController handles the recent activity feed routes
+type ActivityController struct {
+	config          *configs.Config
+	activityService *services.ActivityService
+}
+
+// NewActivityController creates a new activity controller
+func NewActivityController(config *configs.Config) *ActivityController {
+	return &ActivityController{
+		config:          config,
+		activityService: services.NewActivityService(),
+	}
+}
+
+// List godoc
+// @Summary Get recent activity
+// @Description Get the most recently created or updated projects, blog posts and comments as one feed, newest first
+// @Tags activity
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of items"
+// @Success 200 {object} utils.Response{data=[]services.ActivityItem} "Activity retrieved successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/activity [get]
+func (c *ActivityController) List(ctx *gin.Context) {
+	_, limit := parsePage(ctx, c.config, "activity")
+
+	items, err := c.activityService.RecentActivity(limit)
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Activity retrieved successfully", items)
+}
+
+// Routes registers activity routes
+func (c *ActivityController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	activity := router.Group("/activity")
+	activity.Use(authMiddleware, middleware.RequireRole("admin"))
+	{
+		activity.GET("", c.List)
+	}
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// AuditController handles audit log routes
+type AuditController struct {
+	config       *configs.Config
+	auditService *services.AuditService
+}
+
+// NewAuditController creates a new audit controller
+func NewAuditController(config *configs.Config) *AuditController {
+	return &AuditController{
+		config:       config,
+		auditService: services.NewAuditService(),
+	}
+}
+
+// List godoc
+// @Summary List audit log entries
+// @Description List recorded admin actions with pagination
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size"
+// @Param actor_id query int false "Actor user ID"
+// @Param resource_type query string false "Resource type"
+// @Success 200 {object} utils.Response{data=services.AuditLogListResponse} "Audit logs retrieved successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/audit [get]
+func (c *AuditController) List(ctx *gin.Context) {
+	page, limit := parsePage(ctx, c.config, "audit")
+	var actorID uint
+
+	// Parse query parameters
+	if actorIDStr := ctx.Query("actor_id"); actorIDStr != "" {
+		if actorIDNum, err := strconv.ParseUint(actorIDStr, 10, 64); err == nil {
+			actorID = uint(actorIDNum)
+		}
+	}
+
+	resourceType := ctx.Query("resource_type")
+
+	logs, total, err := c.auditService.WithContext(ctx.Request.Context()).ListAuditLogs(page, limit, actorID, resourceType)
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	response := services.AuditLogListResponse{
+		Logs:     logs,
+		Metadata: services.NewPageMetadata(total, page, limit),
+	}
+
+	utils.OKResponse(ctx, "Audit logs retrieved successfully", response)
+}
+
+// Routes registers audit routes
+func (c *AuditController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	audit := router.Group("/audit")
+	audit.Use(authMiddleware, middleware.RequireRole("admin"))
+	{
+		audit.GET("", c.List)
+	}
+}
@@ -1,8 +1,13 @@
 package controllers
 
 import (
+	"errors"
+	"net/http"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
 	"zionechainapi/internal/services"
 	"zionechainapi/internal/utils"
 )
@@ -28,25 +33,36 @@ func NewAuthController(config *configs.Config) *AuthController {
 // @Accept json
 // @Produce json
 // @Param body body services.RegisterRequest true "Register request"
+// @Param token_delivery query string false "Deliver tokens in the body, as cookies or both, overriding AUTH_TOKEN_DELIVERY" Enums(body, cookie, both)
 // @Success 201 {object} utils.Response{data=services.TokenResponse} "User registered successfully"
 // @Failure 400 {object} utils.Response "Bad request"
-// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 422 {object} utils.Response{error=map[string]string} "Validation error, with messages keyed by field"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/auth/register [post]
 func (c *AuthController) Register(ctx *gin.Context) {
+	delivery, err := c.tokenDelivery(ctx)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid token delivery", err.Error())
+		return
+	}
+
 	var req services.RegisterRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		utils.ValidationErrorResponse(ctx, err.Error())
+		validationErrorResponse(ctx, err, &req)
 		return
 	}
 
 	token, err := c.authService.Register(req)
 	if err != nil {
+		if errors.Is(err, services.ErrUserExists) {
+			utils.CodedErrorResponse(ctx, http.StatusBadRequest, utils.CodeAuthUserExists, "Failed to register user", err.Error())
+			return
+		}
 		utils.BadRequestResponse(ctx, "Failed to register user", err.Error())
 		return
 	}
 
-	utils.CreatedResponse(ctx, "User registered successfully", token)
+	utils.CreatedResponse(ctx, "User registered successfully", c.deliverTokens(ctx, delivery, token))
 }
 
 // Login godoc
@@ -56,35 +72,51 @@ func (c *AuthController) Register(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param body body services.LoginRequest true "Login request"
+// @Param token_delivery query string false "Deliver tokens in the body, as cookies or both, overriding AUTH_TOKEN_DELIVERY" Enums(body, cookie, both)
 // @Success 200 {object} utils.Response{data=services.TokenResponse} "User logged in successfully"
 // @Failure 400 {object} utils.Response "Bad request"
 // @Failure 401 {object} utils.Response "Unauthorized"
 // @Failure 422 {object} utils.Response "Validation error"
+// @Failure 423 {object} utils.Response "Account locked"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/auth/login [post]
 func (c *AuthController) Login(ctx *gin.Context) {
+	delivery, err := c.tokenDelivery(ctx)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid token delivery", err.Error())
+		return
+	}
+
 	var req services.LoginRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		utils.ValidationErrorResponse(ctx, err.Error())
+		validationErrorResponse(ctx, err, &req)
 		return
 	}
 
 	token, err := c.authService.Login(req)
 	if err != nil {
-		utils.UnauthorizedResponse(ctx, err.Error())
+		switch {
+		case errors.Is(err, services.ErrAccountLocked):
+			utils.CodedErrorResponse(ctx, http.StatusLocked, utils.CodeAuthAccountLocked, err.Error(), nil)
+		case errors.Is(err, services.ErrInvalidCredentials):
+			utils.CodedErrorResponse(ctx, http.StatusUnauthorized, utils.CodeAuthInvalidCredentials, err.Error(), nil)
+		default:
+			utils.InternalServerErrorResponse(ctx, err.Error())
+		}
 		return
 	}
 
-	utils.OKResponse(ctx, "User logged in successfully", token)
+	utils.OKResponse(ctx, "User logged in successfully", c.deliverTokens(ctx, delivery, token))
 }
 
 // RefreshToken godoc
 // @Summary Refresh access token
-// @Description Refresh access token using refresh token
+// @Description Refresh access token using the refresh token of the body or, without one, of the refresh_token cookie
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param body body map[string]string true "Refresh token request"
+// @Param body body map[string]string false "Refresh token request, optional when the refresh token cookie is sent"
+// @Param token_delivery query string false "Deliver tokens in the body, as cookies or both, overriding AUTH_TOKEN_DELIVERY" Enums(body, cookie, both)
 // @Success 200 {object} utils.Response{data=services.TokenResponse} "Token refreshed successfully"
 // @Failure 400 {object} utils.Response "Bad request"
 // @Failure 401 {object} utils.Response "Unauthorized"
@@ -92,25 +124,123 @@ func (c *AuthController) Login(ctx *gin.Context) {
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/auth/refresh [post]
 func (c *AuthController) RefreshToken(ctx *gin.Context) {
-	var req map[string]string
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		utils.ValidationErrorResponse(ctx, err.Error())
+	delivery, err := c.tokenDelivery(ctx)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid token delivery", err.Error())
 		return
 	}
 
-	refreshToken, ok := req["refresh_token"]
-	if !ok || refreshToken == "" {
+	var req map[string]string
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(ctx, err.Error())
+			return
+		}
+	}
+
+	// Fall back to the refresh token cookie
+	refreshToken := req["refresh_token"]
+	if refreshToken == "" {
+		refreshToken, _ = ctx.Cookie(middleware.RefreshTokenCookie)
+	}
+
+	if refreshToken == "" {
 		utils.BadRequestResponse(ctx, "Refresh token is required", nil)
 		return
 	}
 
 	token, err := c.authService.RefreshToken(refreshToken)
 	if err != nil {
-		utils.UnauthorizedResponse(ctx, err.Error())
+		code := utils.CodeAuthTokenInvalid
+		if errors.Is(err, services.ErrTokenExpired) {
+			code = utils.CodeAuthTokenExpired
+		}
+		utils.CodedErrorResponse(ctx, http.StatusUnauthorized, code, err.Error(), nil)
 		return
 	}
 
-	utils.OKResponse(ctx, "Token refreshed successfully", token)
+	utils.OKResponse(ctx, "Token refreshed successfully", c.deliverTokens(ctx, delivery, token))
+}
+
+// Verify godoc
+// @Summary Verify an access token
+// @Description Check whether an access token is valid without issuing new tokens. The token is read from the body or the Authorization header. Invalid and expired tokens are reported with valid set to false rather than a 401.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body services.VerifyTokenRequest false "Verify token request"
+// @Success 200 {object} utils.Response{data=services.VerifyTokenResponse} "Token verified"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Router /api/auth/verify [post]
+func (c *AuthController) Verify(ctx *gin.Context) {
+	var req services.VerifyTokenRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(ctx, err.Error())
+			return
+		}
+	}
+
+	// Fall back to the Authorization header
+	token := req.Token
+	if token == "" {
+		parts := strings.Split(ctx.GetHeader("Authorization"), " ")
+		if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
+			token = parts[1]
+		}
+	}
+
+	if token == "" {
+		utils.BadRequestResponse(ctx, "Token is required", nil)
+		return
+	}
+
+	utils.OKResponse(ctx, "Token verified", c.authService.VerifyToken(token))
+}
+
+// VerifyEmail godoc
+// @Summary Verify an email address
+// @Description Mark the email of a registered user as verified with the token sent to it on registration. Tokens expire after 24 hours and can only be used once.
+// @Tags auth
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} utils.Response "Email verified successfully"
+// @Failure 400 {object} utils.Response "Invalid or expired token"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/auth/verify-email [get]
+func (c *AuthController) VerifyEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		utils.BadRequestResponse(ctx, "Token is required", nil)
+		return
+	}
+
+	if err := c.authService.VerifyEmail(token); err != nil {
+		switch {
+		case errors.Is(err, services.ErrVerificationTokenInvalid):
+			utils.CodedErrorResponse(ctx, http.StatusBadRequest, utils.CodeAuthVerificationTokenInvalid, "Failed to verify email", err.Error())
+			return
+		case errors.Is(err, services.ErrVerificationTokenExpired):
+			utils.CodedErrorResponse(ctx, http.StatusBadRequest, utils.CodeAuthVerificationTokenExpired, "Failed to verify email", err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Email verified successfully", nil)
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Expire the access and refresh token cookies set by cookie token delivery. Tokens delivered in the body are simply discarded by the client.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} utils.Response "Logged out successfully"
+// @Router /api/auth/logout [post]
+func (c *AuthController) Logout(ctx *gin.Context) {
+	c.clearTokenCookies(ctx)
+	utils.OKResponse(ctx, "Logged out successfully", nil)
 }
 
 // Me godoc
@@ -138,11 +268,12 @@ func (c *AuthController) Me(ctx *gin.Context) {
 	}
 
 	utils.OKResponse(ctx, "User retrieved successfully", services.UserResponse{
-		ID:    user.ID,
-		Name:  user.Name,
-		Email: user.Email,
-		Phone: user.Phone,
-		Role:  user.Role.Name,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Phone:         user.Phone,
+		Role:          user.Role.Name,
 	})
 }
 
@@ -153,6 +284,9 @@ func (c *AuthController) Routes(router *gin.RouterGroup) {
 		auth.POST("/register", c.Register)
 		auth.POST("/login", c.Login)
 		auth.POST("/refresh", c.RefreshToken)
+		auth.POST("/logout", c.Logout)
+		auth.POST("/verify", c.Verify)
+		auth.GET("/verify-email", c.VerifyEmail)
 		auth.GET("/me", c.Me)
 	}
 } 
\ No newline at end of file
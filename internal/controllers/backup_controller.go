@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// BackupController handles content export and import routes
+type BackupController struct {
+	config        *configs.Config
+	backupService *services.BackupService
+}
+
+// NewBackupController creates a new backup controller
+func NewBackupController(config *configs.Config) *BackupController {
+	return &BackupController{
+		config:        config,
+		backupService: services.NewBackupService(),
+	}
+}
+
+// Export godoc
+// @Summary Export all content
+// @Description Stream a JSON backup of users (without password hashes), categories, tags, technologies, projects and blog posts with their media and tags, and resume sections
+// @Tags backup
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Backup document"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Router /api/export [get]
+func (c *BackupController) Export(ctx *gin.Context) {
+	filename := fmt.Sprintf("zione-export-%s.json", time.Now().UTC().Format("20060102-150405"))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Header("Content-Type", "application/json; charset=utf-8")
+	ctx.Status(http.StatusOK)
+
+	userID := middleware.GetUserID(ctx)
+	if err := c.backupService.Export(ctx.Writer, userID); err != nil {
+		// The status is already sent, so the client only sees a truncated document
+		log.Printf("Failed to export content: %v", err)
+	}
+}
+
+// Import godoc
+// @Summary Import content
+// @Description Restore a backup written by the export endpoint in one transaction. Rows are matched by ID, so existing rows are overwritten and missing rows are recreated with their original IDs. Existing users keep their passwords; recreated users must reset theirs.
+// @Tags backup
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body object true "Backup document"
+// @Success 200 {object} utils.Response{data=services.ImportResponse} "Content imported successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/import [post]
+func (c *BackupController) Import(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	result, err := c.backupService.Import(ctx.Request.Body, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBackup) || errors.Is(err, services.ErrUnsupportedBackupVersion) {
+			utils.ValidationErrorResponse(ctx, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Content imported successfully", result)
+}
+
+// Routes registers backup routes
+func (c *BackupController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	backup := router.Group("")
+	backup.Use(authMiddleware, middleware.RequireRole("admin"), invalidateLists(c.config))
+	{
+		backup.GET("/export", c.Export)
+		backup.POST("/import", c.Import)
+	}
+}
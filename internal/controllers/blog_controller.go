@@ -1,29 +1,63 @@
 package controllers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"zionechainapi/configs"
+	"zionechainapi/internal/cache"
 	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/models"
 	"zionechainapi/internal/services"
+	"zionechainapi/internal/storage"
 	"zionechainapi/internal/utils"
 )
 
 // BlogController handles blog-related routes
 type BlogController struct {
-	config      *configs.Config
-	blogService *services.BlogService
+	config             *configs.Config
+	blogService        *services.BlogService
+	translationService *services.TranslationService
+	previewService     *services.PreviewService
+	likeService        *services.LikeService
+	views              *services.ViewCounter
+	listCache          cache.Cache
 }
 
 // NewBlogController creates a new blog controller
 func NewBlogController(config *configs.Config) *BlogController {
 	return &BlogController{
-		config:      config,
-		blogService: services.NewBlogService(),
+		config:             config,
+		blogService:        services.NewBlogService().WithContentSanitization(config.Content.SanitizeHTML).WithWebhooks(services.NewWebhookDispatcher(config.Webhook)).WithStorage(storage.New(config)),
+		translationService: services.NewTranslationService().WithDefaultLocale(config.Content.DefaultLocale).WithContentSanitization(config.Content.SanitizeHTML),
+		previewService:     services.NewPreviewService(config),
+		likeService:        services.NewLikeService(cache.New(config, "blog-likes"), config.Content.LikeWindow),
+		views:              services.NewViewCounter("blog_posts").Start(config.Content.ViewFlushInterval),
+		listCache:          cache.New(config, blogListCache),
 	}
 }
 
+// Close writes the views counted since the last flush and stops flushing them
+func (c *BlogController) Close() error {
+	return c.views.Stop()
+}
+
+// service returns the blog service with its queries bound to the request
+// context, so they are cancelled when the request times out or the client leaves
+func (c *BlogController) service(ctx *gin.Context) *services.BlogService {
+	return c.blogService.WithContext(ctx.Request.Context())
+}
+
+// countView counts a view of a published blog post and adds the views not yet
+// written to the database to its count
+func (c *BlogController) countView(blog *services.BlogResponse) {
+	if blog.Published {
+		c.views.Record(blog.ID)
+	}
+	blog.Views += c.views.Pending(blog.ID)
+}
+
 // Create godoc
 // @Summary Create a new blog post
 // @Description Create a new blog post
@@ -47,8 +81,12 @@ func (c *BlogController) Create(ctx *gin.Context) {
 	}
 
 	userID := middleware.GetUserID(ctx)
-	blog, err := c.blogService.CreateBlog(req, userID)
+	blog, err := c.service(ctx).CreateBlog(req, userID)
 	if err != nil {
+		if errors.Is(err, services.ErrCategoryNotFound) {
+			utils.ValidationErrorResponse(ctx, err.Error())
+			return
+		}
 		utils.BadRequestResponse(ctx, "Failed to create blog post", err.Error())
 		return
 	}
@@ -58,11 +96,15 @@ func (c *BlogController) Create(ctx *gin.Context) {
 
 // Get godoc
 // @Summary Get a blog post by ID
-// @Description Get a blog post by ID
+// @Description Get a blog post by ID. Unpublished blog posts are only found for admins, editors and preview tokens.
 // @Tags blog
 // @Accept json
 // @Produce json
 // @Param id path int true "Blog Post ID"
+// @Param locale query string false "Locale of translations to overlay, falling back to the base content"
+// @Param fields query string false "Comma-separated fields to return, such as id,title,slug; all fields by default"
+// @Param media_limit query int false "Media per page; all media by default"
+// @Param media_page query int false "Media page number, 1 by default"
 // @Success 200 {object} utils.Response{data=services.BlogResponse} "Blog post retrieved successfully"
 // @Failure 400 {object} utils.Response "Bad request"
 // @Failure 404 {object} utils.Response "Not found"
@@ -75,23 +117,43 @@ func (c *BlogController) Get(ctx *gin.Context) {
 		return
 	}
 
-	blog, err := c.blogService.GetBlogByID(uint(id))
+	mediaPage, err := parseMediaPage(ctx, c.config)
+	if err != nil {
+		utils.BadRequestResponse(ctx, err.Error(), nil)
+		return
+	}
+
+	blog, err := c.service(ctx).GetBlogByID(uint(id), mediaPage, canReadDrafts(ctx))
 	if err != nil {
 		utils.NotFoundResponse(ctx, err.Error())
 		return
 	}
 
-	utils.OKResponse(ctx, "Blog post retrieved successfully", blog)
+	c.countView(blog)
+
+	if locale := ctx.Query("locale"); locale != "" {
+		if err := c.translationService.LocalizeBlog(blog, locale); err != nil {
+			localizeErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	okWithFields(ctx, "Blog post retrieved successfully", blog, "")
 }
 
 // GetBySlug godoc
 // @Summary Get a blog post by slug
-// @Description Get a blog post by slug
+// @Description Get a blog post by slug. A slug the blog post used to have is answered with a permanent redirect to its current slug. Unpublished blog posts are only found for admins, editors and preview tokens.
 // @Tags blog
 // @Accept json
 // @Produce json
 // @Param slug path string true "Blog Post Slug"
+// @Param locale query string false "Locale of translations to overlay, falling back to the base content"
+// @Param fields query string false "Comma-separated fields to return, such as id,title,slug; all fields by default"
+// @Param media_limit query int false "Media per page; all media by default"
+// @Param media_page query int false "Media page number, 1 by default"
 // @Success 200 {object} utils.Response{data=services.BlogResponse} "Blog post retrieved successfully"
+// @Success 301 {object} utils.Response{data=services.SlugRedirectResponse} "Moved to the current slug"
 // @Failure 400 {object} utils.Response "Bad request"
 // @Failure 404 {object} utils.Response "Not found"
 // @Failure 500 {object} utils.Response "Internal server error"
@@ -99,90 +161,226 @@ func (c *BlogController) Get(ctx *gin.Context) {
 func (c *BlogController) GetBySlug(ctx *gin.Context) {
 	slug := ctx.Param("slug")
 
-	blog, err := c.blogService.GetBlogBySlug(slug)
+	mediaPage, err := parseMediaPage(ctx, c.config)
+	if err != nil {
+		utils.BadRequestResponse(ctx, err.Error(), nil)
+		return
+	}
+
+	blog, err := c.service(ctx).GetBlogBySlug(slug, mediaPage, canReadDrafts(ctx))
 	if err != nil {
 		utils.NotFoundResponse(ctx, err.Error())
 		return
 	}
+	if redirectToCurrentSlug(ctx, blog.Slug) {
+		return
+	}
 
-	utils.OKResponse(ctx, "Blog post retrieved successfully", blog)
+	c.countView(blog)
+
+	if locale := ctx.Query("locale"); locale != "" {
+		if err := c.translationService.LocalizeBlog(blog, locale); err != nil {
+			localizeErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	okWithFields(ctx, "Blog post retrieved successfully", blog, "")
+}
+
+// GetMetaBySlug godoc
+// @Summary Get the SEO metadata of a blog post
+// @Description Get the meta title, meta description and Open Graph image of a blog post for prerendering. Empty fields fall back to the title, excerpt and first image. Unpublished posts are only found for admins, editors and preview tokens.
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Param slug path string true "Blog Post Slug"
+// @Success 200 {object} utils.Response{data=services.SEOMetaResponse} "Blog post metadata retrieved successfully"
+// @Success 301 {object} utils.Response{data=services.SlugRedirectResponse} "Moved to the current slug"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/slug/{slug}/meta [get]
+func (c *BlogController) GetMetaBySlug(ctx *gin.Context) {
+	meta, err := c.service(ctx).GetBlogMetaBySlug(ctx.Param("slug"), canReadDrafts(ctx))
+	if err != nil {
+		if errors.Is(err, services.ErrBlogNotFound) {
+			utils.NotFoundResponse(ctx, err.Error())
+		} else {
+			utils.InternalServerErrorResponse(ctx, err.Error())
+		}
+		return
+	}
+	if redirectToCurrentSlug(ctx, meta.Slug) {
+		return
+	}
+
+	utils.OKResponse(ctx, "Blog post metadata retrieved successfully", meta)
+}
+
+// SlugAvailable godoc
+// @Summary Check slug availability
+// @Description Preview the slug generated from a blog post title and whether it is free
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Param title query string true "Title"
+// @Success 200 {object} utils.Response{data=services.SlugAvailabilityResponse} "Slug availability checked successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/slug-available [get]
+func (c *BlogController) SlugAvailable(ctx *gin.Context) {
+	title := ctx.Query("title")
+	if title == "" {
+		utils.BadRequestResponse(ctx, "title is required", nil)
+		return
+	}
+
+	availability, err := c.service(ctx).CheckSlugAvailability(title)
+	if err != nil {
+		if errors.Is(err, services.ErrSlugTitleInvalid) {
+			utils.BadRequestResponse(ctx, err.Error(), nil)
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Slug availability checked successfully", availability)
 }
 
 // List godoc
 // @Summary List blog posts
-// @Description List blog posts with pagination
+// @Description List blog posts with pagination. With a cursor the metadata holds limit and next_cursor (services.CursorMetadata) instead of page numbers.
 // @Tags blog
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number"
 // @Param limit query int false "Page size"
-// @Param category_id query int false "Category ID"
+// @Param cursor query string false "Cursor from next_cursor; selects cursor pagination instead of page. Not supported with featured"
+// @Param category_id query []int false "Category ID, repeat for items in any of several categories" collectionFormat(multi)
+// @Param category_ids query string false "Comma-separated category IDs, combined with category_id"
 // @Param featured query bool false "Featured flag"
-// @Success 200 {object} utils.Response{data=[]services.BlogResponse} "Blog posts retrieved successfully"
+// @Param tag query string false "Tag slug"
+// @Param q query string false "Search text"
+// @Param published query bool false "Defaults to every status for admins and editors and to published only for everyone else. Set to false for drafts: all drafts for admins, editors and preview tokens, their own for other signed-in users"
+// @Param created_after query string false "Only items created at or after this RFC3339 time"
+// @Param created_before query string false "Only items created at or before this RFC3339 time"
+// @Param locale query string false "Locale of translations to overlay, falling back to the base content"
+// @Param fields query string false "Comma-separated fields to return, such as id,title,slug; all fields by default"
+// @Success 200 {object} utils.Response{data=services.BlogListResponse} "Blog posts retrieved successfully"
 // @Failure 400 {object} utils.Response "Bad request"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/blog [get]
 func (c *BlogController) List(ctx *gin.Context) {
-	page := 1
-	limit := 10
-	var categoryID uint
-	featured := false
-	published := true // Default to published only
-
-	// Parse query parameters
-	if pageStr := ctx.Query("page"); pageStr != "" {
-		if pageNum, err := strconv.Atoi(pageStr); err == nil {
-			page = pageNum
-		}
+	page, limit := parsePage(ctx, c.config, "blog")
+
+	filter, err := parseListFilter(ctx)
+	if err != nil {
+		utils.BadRequestResponse(ctx, err.Error(), nil)
+		return
 	}
 
-	if limitStr := ctx.Query("limit"); limitStr != "" {
-		if limitNum, err := strconv.Atoi(limitStr); err == nil {
-			limit = limitNum
+	// A cursor parameter, even an empty one for the first page, selects cursor pagination
+	if cursor, ok := ctx.GetQuery("cursor"); ok {
+		items, nextCursor, err := c.service(ctx).ListBlogsAfter(cursor, limit, filter)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidCursor) || errors.Is(err, services.ErrFeaturedCursor) {
+				utils.BadRequestResponse(ctx, err.Error(), nil)
+				return
+			}
+			utils.InternalServerErrorResponse(ctx, err.Error())
+			return
 		}
-	}
 
-	if categoryIDStr := ctx.Query("category_id"); categoryIDStr != "" {
-		if categoryIDNum, err := strconv.ParseUint(categoryIDStr, 10, 64); err == nil {
-			categoryID = uint(categoryIDNum)
+		if locale := ctx.Query("locale"); locale != "" {
+			if err := c.translationService.LocalizeBlogs(items, locale); err != nil {
+				localizeErrorResponse(ctx, err)
+				return
+			}
 		}
+
+		okWithFields(ctx, "Blog posts retrieved successfully", services.BlogCursorListResponse{
+			Blogs:    items,
+			Metadata: services.CursorMetadata{Limit: limit, NextCursor: nextCursor},
+		}, "blogs")
+		return
 	}
 
-	if featuredStr := ctx.Query("featured"); featuredStr != "" {
-		if featuredBool, err := strconv.ParseBool(featuredStr); err == nil {
-			featured = featuredBool
-		}
+	blogs, total, err := c.service(ctx).ListBlogs(page, limit, filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
 	}
 
-	// Check if user is admin or editor
-	userRole := middleware.GetUserRole(ctx)
-	if userRole == "admin" || userRole == "editor" {
-		// If user is admin or editor, check if they want to see unpublished blog posts
-		if publishedStr := ctx.Query("published"); publishedStr != "" {
-			if publishedBool, err := strconv.ParseBool(publishedStr); err == nil {
-				published = publishedBool
-			}
+	if locale := ctx.Query("locale"); locale != "" {
+		if err := c.translationService.LocalizeBlogs(blogs, locale); err != nil {
+			localizeErrorResponse(ctx, err)
+			return
 		}
 	}
 
-	blogs, total, err := c.blogService.ListBlogs(page, limit, categoryID, featured, published)
+	// Create response with pagination metadata
+	response := services.BlogListResponse{
+		Blogs:    blogs,
+		Metadata: services.NewPageMetadata(total, page, limit),
+	}
+
+	okWithFields(ctx, "Blog posts retrieved successfully", response, "blogs")
+}
+
+// Count godoc
+// @Summary Count blog posts
+// @Description Count blog posts matching the same filters as the list endpoint
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Param category_id query []int false "Category ID, repeat for items in any of several categories" collectionFormat(multi)
+// @Param category_ids query string false "Comma-separated category IDs, combined with category_id"
+// @Param featured query bool false "Featured flag"
+// @Param tag query string false "Tag slug"
+// @Param q query string false "Search text"
+// @Param created_after query string false "Only items created at or after this RFC3339 time"
+// @Param created_before query string false "Only items created at or before this RFC3339 time"
+// @Param published query bool false "Defaults to every status for admins and editors and to published only for everyone else. Set to false for drafts: all drafts for admins, editors and preview tokens, their own for other signed-in users"
+// @Success 200 {object} utils.Response{data=map[string]int64} "Blog posts counted successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/count [get]
+func (c *BlogController) Count(ctx *gin.Context) {
+	filter, err := parseListFilter(ctx)
+	if err != nil {
+		utils.BadRequestResponse(ctx, err.Error(), nil)
+		return
+	}
+
+	count, err := c.service(ctx).CountBlogs(filter)
 	if err != nil {
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
 	}
 
-	// Create response with pagination metadata
-	response := map[string]interface{}{
-		"blogs": blogs,
-		"metadata": map[string]interface{}{
-			"total":       total,
-			"page":        page,
-			"limit":       limit,
-			"total_pages": (total + int64(limit) - 1) / int64(limit),
-		},
+	utils.OKResponse(ctx, "Blog posts counted successfully", gin.H{"count": count})
+}
+
+// LastModified godoc
+// @Summary Get when the blog posts last changed
+// @Description Get the latest update time of any published blog post in the Last-Modified header, so clients can cheaply decide whether to refetch the list. Answers 304 when it is not after If-Modified-Since.
+// @Tags blog
+// @Produce json
+// @Param If-Modified-Since header string false "HTTP date of the client's copy"
+// @Success 200 {object} utils.Response{data=services.LastModifiedResponse} "Blog posts last modified time retrieved successfully"
+// @Success 304 "Not modified"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/last-modified [get]
+// @Router /api/blog/last-modified [head]
+func (c *BlogController) LastModified(ctx *gin.Context) {
+	result, err := c.service(ctx).LastModifiedBlogs()
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
 	}
 
-	utils.OKResponse(ctx, "Blog posts retrieved successfully", response)
+	lastModifiedResponse(ctx, "Blog posts last modified time retrieved successfully", result)
 }
 
 // Update godoc
@@ -202,6 +400,7 @@ func (c *BlogController) List(ctx *gin.Context) {
 // @Failure 422 {object} utils.Response "Validation error"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/blog/{id} [put]
+// @Router /api/blog/{id} [patch]
 func (c *BlogController) Update(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -216,7 +415,71 @@ func (c *BlogController) Update(ctx *gin.Context) {
 	}
 
 	userID := middleware.GetUserID(ctx)
-	blog, err := c.blogService.UpdateBlog(uint(id), req, userID)
+	blog, err := c.service(ctx).UpdateBlog(uint(id), req, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrCategoryNotFound) {
+			utils.ValidationErrorResponse(ctx, err.Error())
+			return
+		}
+		utils.BadRequestResponse(ctx, "Failed to update blog post", err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Blog post updated successfully", blog)
+}
+
+// SetFeatured godoc
+// @Summary Feature or unfeature a blog post
+// @Description Set only the featured flag of a blog post, leaving its other fields unchanged
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Blog post ID"
+// @Param body body services.ToggleRequest true "New value of the flag"
+// @Success 200 {object} utils.Response{data=services.BlogResponse} "Blog post updated successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response{error=map[string]string} "Validation error"
+// @Router /api/blog/{id}/featured [patch]
+func (c *BlogController) SetFeatured(ctx *gin.Context) {
+	id, value, ok := bindToggle(ctx, "Invalid blog post ID")
+	if !ok {
+		return
+	}
+
+	blog, err := c.service(ctx).SetBlogFeatured(id, value, middleware.GetUserID(ctx))
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Failed to update blog post", err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Blog post updated successfully", blog)
+}
+
+// SetPublished godoc
+// @Summary Publish or unpublish a blog post
+// @Description Set only the published flag of a blog post, leaving its other fields unchanged
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Blog post ID"
+// @Param body body services.ToggleRequest true "New value of the flag"
+// @Success 200 {object} utils.Response{data=services.BlogResponse} "Blog post updated successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response{error=map[string]string} "Validation error"
+// @Router /api/blog/{id}/publish [patch]
+func (c *BlogController) SetPublished(ctx *gin.Context) {
+	id, value, ok := bindToggle(ctx, "Invalid blog post ID")
+	if !ok {
+		return
+	}
+
+	blog, err := c.service(ctx).SetBlogPublished(id, value, middleware.GetUserID(ctx))
 	if err != nil {
 		utils.BadRequestResponse(ctx, "Failed to update blog post", err.Error())
 		return
@@ -247,7 +510,8 @@ func (c *BlogController) Delete(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.blogService.DeleteBlog(uint(id)); err != nil {
+	userID := middleware.GetUserID(ctx)
+	if err := c.service(ctx).DeleteBlog(uint(id), userID); err != nil {
 		utils.BadRequestResponse(ctx, "Failed to delete blog post", err.Error())
 		return
 	}
@@ -255,6 +519,78 @@ func (c *BlogController) Delete(ctx *gin.Context) {
 	utils.NoContentResponse(ctx)
 }
 
+// Duplicate godoc
+// @Summary Duplicate a blog post
+// @Description Copy a blog post with its tags and media into a new unpublished draft owned by the current user
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Blog Post ID"
+// @Success 201 {object} utils.Response{data=services.BlogResponse} "Blog post duplicated successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/{id}/duplicate [post]
+func (c *BlogController) Duplicate(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid blog post ID", nil)
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	duplicate, err := c.service(ctx).DuplicateBlog(uint(id), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrBlogNotFound) {
+			utils.NotFoundResponse(ctx, err.Error())
+		} else {
+			utils.InternalServerErrorResponse(ctx, err.Error())
+		}
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Blog post duplicated successfully", duplicate)
+}
+
+// ReorderFeatured godoc
+// @Summary Reorder featured blog posts
+// @Description Set the display order of featured blog posts to the order of the given ids. Featured blog posts left out are listed after the ordered ones.
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body services.ReorderFeaturedRequest true "Featured blog post ids in display order"
+// @Success 200 {object} utils.Response{data=[]services.BlogResponse} "Featured blog posts reordered successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/featured/reorder [put]
+func (c *BlogController) ReorderFeatured(ctx *gin.Context) {
+	var req services.ReorderFeaturedRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	items, err := c.service(ctx).ReorderFeatured(req, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidFeaturedOrder) {
+			utils.BadRequestResponse(ctx, "Failed to reorder featured blog posts", err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Featured blog posts reordered successfully", items)
+}
+
 // AddMedia godoc
 // @Summary Add media to a blog post
 // @Description Add media to a blog post
@@ -285,8 +621,12 @@ func (c *BlogController) AddMedia(ctx *gin.Context) {
 		return
 	}
 
-	media, err := c.blogService.AddBlogMedia(uint(id), req)
+	media, err := c.service(ctx).AddBlogMedia(uint(id), middleware.GetUserID(ctx), req)
 	if err != nil {
+		if services.IsMediaValidationError(err) {
+			utils.ValidationErrorResponse(ctx, err.Error())
+			return
+		}
 		utils.BadRequestResponse(ctx, "Failed to add media", err.Error())
 		return
 	}
@@ -294,15 +634,47 @@ func (c *BlogController) AddMedia(ctx *gin.Context) {
 	utils.CreatedResponse(ctx, "Media added successfully", media)
 }
 
+// ListMedia godoc
+// @Summary List blog post media
+// @Description List the media of a blog post ordered by sort order. The media of unpublished posts are only listed for admins, editors and preview tokens.
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Param id path int true "Blog post ID"
+// @Success 200 {object} utils.Response{data=[]services.BlogMediaResponse} "Media retrieved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/{id}/media [get]
+func (c *BlogController) ListMedia(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid blog post ID", nil)
+		return
+	}
+
+	media, err := c.service(ctx).ListBlogMedia(uint(id), canReadDrafts(ctx))
+	if err != nil {
+		if errors.Is(err, services.ErrBlogNotFound) {
+			utils.NotFoundResponse(ctx, err.Error())
+		} else {
+			utils.InternalServerErrorResponse(ctx, err.Error())
+		}
+		return
+	}
+
+	utils.OKResponse(ctx, "Media retrieved successfully", media)
+}
+
 // UpdateMedia godoc
 // @Summary Update blog media
-// @Description Update blog media
+// @Description Update blog media. Only the fields present in the request are changed.
 // @Tags blog
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Media ID"
-// @Param body body services.BlogMediaRequest true "Update media request"
+// @Param body body services.UpdateBlogMediaRequest true "Update media request"
 // @Success 200 {object} utils.Response{data=services.BlogMediaResponse} "Media updated successfully"
 // @Failure 400 {object} utils.Response "Bad request"
 // @Failure 401 {object} utils.Response "Unauthorized"
@@ -311,6 +683,7 @@ func (c *BlogController) AddMedia(ctx *gin.Context) {
 // @Failure 422 {object} utils.Response "Validation error"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/blog/media/{id} [put]
+// @Router /api/blog/media/{id} [patch]
 func (c *BlogController) UpdateMedia(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -318,14 +691,18 @@ func (c *BlogController) UpdateMedia(ctx *gin.Context) {
 		return
 	}
 
-	var req services.BlogMediaRequest
+	var req services.UpdateBlogMediaRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		utils.ValidationErrorResponse(ctx, err.Error())
 		return
 	}
 
-	media, err := c.blogService.UpdateBlogMedia(uint(id), req)
+	media, err := c.service(ctx).UpdateBlogMedia(uint(id), middleware.GetUserID(ctx), req)
 	if err != nil {
+		if services.IsMediaValidationError(err) {
+			utils.ValidationErrorResponse(ctx, err.Error())
+			return
+		}
 		utils.BadRequestResponse(ctx, "Failed to update media", err.Error())
 		return
 	}
@@ -355,7 +732,7 @@ func (c *BlogController) DeleteMedia(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.blogService.DeleteBlogMedia(uint(id)); err != nil {
+	if err := c.service(ctx).DeleteBlogMedia(uint(id), middleware.GetUserID(ctx)); err != nil {
 		utils.BadRequestResponse(ctx, "Failed to delete media", err.Error())
 		return
 	}
@@ -363,30 +740,428 @@ func (c *BlogController) DeleteMedia(ctx *gin.Context) {
 	utils.NoContentResponse(ctx)
 }
 
+// GetDraft godoc
+// @Summary Get the autosaved draft of a blog post
+// @Description Get the unpublished draft content of a blog post. The draft content is null when there is none. Preview tokens may read drafts too.
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Blog Post ID"
+// @Success 200 {object} utils.Response{data=services.DraftResponse} "Draft retrieved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/{id}/draft [get]
+func (c *BlogController) GetDraft(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid blog post ID", nil)
+		return
+	}
+
+	draft, err := c.service(ctx).GetBlogDraft(uint(id))
+	if err != nil {
+		draftErrorResponse(ctx, "Failed to get draft", err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Draft retrieved successfully", draft)
+}
+
+// Autosave godoc
+// @Summary Autosave a blog post draft
+// @Description Save draft content for a blog post without changing its published content or update time
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Blog Post ID"
+// @Param body body services.DraftRequest true "Draft content"
+// @Success 200 {object} utils.Response{data=services.DraftResponse} "Draft saved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/{id}/autosave [put]
+func (c *BlogController) Autosave(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid blog post ID", nil)
+		return
+	}
+
+	var req services.DraftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	draft, err := c.service(ctx).AutosaveBlogDraft(uint(id), req)
+	if err != nil {
+		draftErrorResponse(ctx, "Failed to save draft", err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Draft saved successfully", draft)
+}
+
+// RegenerateSlug godoc
+// @Summary Regenerate the slug of a blog post
+// @Description Recompute the slug of a blog post from its current title, for titles changed without their slug following. The slug is unique among blog posts. The previous slug keeps resolving to the blog post.
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Blog ID"
+// @Success 200 {object} utils.Response{data=services.SlugRegenerationResponse} "Slug regenerated successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/{id}/regenerate-slug [post]
+func (c *BlogController) RegenerateSlug(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid blog ID", nil)
+		return
+	}
+
+	regenerated, err := c.service(ctx).RegenerateBlogSlug(uint(id), middleware.GetUserID(ctx))
+	if err != nil {
+		slugErrorResponse(ctx, "Failed to regenerate slug", err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Slug regenerated successfully", regenerated)
+}
+
+// PublishDraft godoc
+// @Summary Publish the autosaved draft of a blog post
+// @Description Replace the content of a blog post with its autosaved draft and discard the draft
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Blog Post ID"
+// @Success 200 {object} utils.Response{data=services.BlogResponse} "Draft published successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 409 {object} utils.Response "No draft to publish"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/{id}/publish-draft [post]
+func (c *BlogController) PublishDraft(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid blog post ID", nil)
+		return
+	}
+
+	blog, err := c.service(ctx).PublishBlogDraft(uint(id), middleware.GetUserID(ctx))
+	if err != nil {
+		draftErrorResponse(ctx, "Failed to publish draft", err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Draft published successfully", blog)
+}
+
+// ListTranslations godoc
+// @Summary List blog post translations
+// @Description List the translations of a blog post ordered by locale
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Blog Post ID"
+// @Success 200 {object} utils.Response{data=[]services.TranslationResponse} "Translations retrieved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/{id}/translations [get]
+func (c *BlogController) ListTranslations(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid blog post ID", nil)
+		return
+	}
+
+	translations, err := c.translationService.ListTranslations(models.TranslationResourceBlogPost, uint(id))
+	if err != nil {
+		translationErrorResponse(ctx, "Failed to list translations", err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Translations retrieved successfully", translations)
+}
+
+// SaveTranslation godoc
+// @Summary Create or replace a blog post translation
+// @Description Create or replace the translation of a blog post in a locale. Empty fields fall back to the base content.
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Blog Post ID"
+// @Param locale path string true "Locale, such as fa"
+// @Param body body services.TranslationRequest true "Translation request"
+// @Success 200 {object} utils.Response{data=services.TranslationResponse} "Translation saved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/{id}/translations/{locale} [put]
+func (c *BlogController) SaveTranslation(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid blog post ID", nil)
+		return
+	}
+
+	var req services.TranslationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	translation, err := c.translationService.SaveTranslation(models.TranslationResourceBlogPost, uint(id), ctx.Param("locale"), req, userID)
+	if err != nil {
+		translationErrorResponse(ctx, "Failed to save translation", err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Translation saved successfully", translation)
+}
+
+// DeleteTranslation godoc
+// @Summary Delete a blog post translation
+// @Description Delete the translation of a blog post in a locale
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Blog Post ID"
+// @Param locale path string true "Locale, such as fa"
+// @Success 204 {object} utils.Response "Translation deleted successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/{id}/translations/{locale} [delete]
+func (c *BlogController) DeleteTranslation(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid blog post ID", nil)
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	if err := c.translationService.DeleteTranslation(models.TranslationResourceBlogPost, uint(id), ctx.Param("locale"), userID); err != nil {
+		translationErrorResponse(ctx, "Failed to delete translation", err)
+		return
+	}
+
+	utils.NoContentResponse(ctx)
+}
+
+// Share godoc
+// @Summary Create a share link for a blog post
+// @Description Create a time-limited signed link that previews the blog post, including drafts. Only the author or an admin can share a post.
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Blog Post ID"
+// @Success 201 {object} utils.Response{data=services.ShareLinkResponse} "Share link created successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/{id}/share [post]
+func (c *BlogController) Share(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid blog post ID", nil)
+		return
+	}
+
+	link, err := c.previewService.ShareBlog(uint(id), middleware.GetUserID(ctx), middleware.GetUserRole(ctx))
+	if err != nil {
+		if errors.Is(err, services.ErrShareForbidden) {
+			utils.ForbiddenResponse(ctx, err.Error())
+			return
+		}
+		utils.NotFoundResponse(ctx, err.Error())
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Share link created successfully", link)
+}
+
+// Preview godoc
+// @Summary Preview a blog post from a share link
+// @Description Get a blog post, published or not, using the token of a share link
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Param token query string true "Share link token"
+// @Success 200 {object} utils.Response{data=services.BlogResponse} "Blog post retrieved successfully"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/preview [get]
+func (c *BlogController) Preview(ctx *gin.Context) {
+	// Drafts must not be cached or indexed through a shared link
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("X-Robots-Tag", "noindex")
+
+	blog, err := c.previewService.PreviewBlog(ctx.Query("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrPreviewTokenInvalid) {
+			utils.ForbiddenResponse(ctx, err.Error())
+			return
+		}
+		utils.NotFoundResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Blog post retrieved successfully", blog)
+}
+
+// Like godoc
+// @Summary Like a blog post
+// @Description Add a like to a published blog post. Repeated likes from the same client within the like window are ignored.
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Param id path int true "Blog Post ID"
+// @Success 200 {object} utils.Response{data=services.LikeResponse} "Blog post liked successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/{id}/like [post]
+func (c *BlogController) Like(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid blog post ID", nil)
+		return
+	}
+
+	likes, err := c.likeService.LikeBlog(uint(id), ctx.ClientIP())
+	if err != nil {
+		likeErrorResponse(ctx, err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Blog post liked successfully", likes)
+}
+
+// Unlike godoc
+// @Summary Unlike a blog post
+// @Description Remove the like the same client gave a published blog post within the like window. Unlikes from clients without such a like are ignored.
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Param id path int true "Blog Post ID"
+// @Success 200 {object} utils.Response{data=services.LikeResponse} "Blog post unliked successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/{id}/like [delete]
+func (c *BlogController) Unlike(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid blog post ID", nil)
+		return
+	}
+
+	likes, err := c.likeService.UnlikeBlog(uint(id), ctx.ClientIP())
+	if err != nil {
+		likeErrorResponse(ctx, err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Blog post unliked successfully", likes)
+}
+
+// likeErrorResponse maps a like service error to a response
+func likeErrorResponse(ctx *gin.Context, err error) {
+	if errors.Is(err, services.ErrLikedBlogNotFound) {
+		utils.NotFoundResponse(ctx, err.Error())
+		return
+	}
+	utils.InternalServerErrorResponse(ctx, err.Error())
+}
+
 // Routes registers blog routes
 func (c *BlogController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
 	blog := router.Group("/blog")
 	{
 		// Public routes
-		blog.GET("", c.List)
-		blog.GET("/:id", c.Get)
-		blog.GET("/slug/:slug", c.GetBySlug)
+		blog.GET("", middleware.OptionalAuth(c.config), middleware.CacheResponse(c.listCache, c.config.Cache.ListTTL), c.List)
+		blog.GET("/count", middleware.OptionalAuth(c.config), c.Count)
+		blog.GET("/last-modified", c.LastModified)
+		blog.HEAD("/last-modified", c.LastModified)
+		blog.GET("/slug-available", c.SlugAvailable)
+		blog.GET("/preview", c.Preview)
+		blog.GET("/:id", middleware.OptionalAuth(c.config), c.Get)
+		blog.GET("/slug/:slug", middleware.OptionalAuth(c.config), c.GetBySlug)
+		blog.GET("/slug/:slug/meta", middleware.OptionalAuth(c.config), c.GetMetaBySlug)
+		blog.GET("/:id/media", middleware.OptionalAuth(c.config), c.ListMedia)
+		blog.POST("/:id/like", c.Like)
+		blog.DELETE("/:id/like", c.Unlike)
 
 		// Protected routes
 		authenticated := blog.Group("")
 		authenticated.Use(authMiddleware)
 		{
+			// Drafts leave the published content alone, so saving them keeps the list cache
+			drafts := authenticated.Group("")
+			drafts.Use(middleware.RequireRole("admin", "editor"))
+			{
+				drafts.PUT("/:id/autosave", c.Autosave)
+			}
+
+			// Preview tokens may read drafts too
+			authenticated.GET("/:id/draft", middleware.RequireRoleOrScope(services.ScopePreviewRead, "admin", "editor"), c.GetDraft)
+
 			// Admin and editor routes
 			adminEditor := authenticated.Group("")
-			adminEditor.Use(middleware.RequireRole("admin", "editor"))
+			adminEditor.Use(middleware.RequireRole("admin", "editor"), middleware.InvalidateCache(c.listCache))
 			{
 				adminEditor.POST("", c.Create)
 				adminEditor.PUT("/:id", c.Update)
+				adminEditor.PATCH("/:id", c.Update)
+				adminEditor.PATCH("/:id/featured", c.SetFeatured)
+				adminEditor.PATCH("/:id/publish", c.SetPublished)
 				adminEditor.DELETE("/:id", c.Delete)
+				adminEditor.POST("/:id/duplicate", c.Duplicate)
+				adminEditor.POST("/:id/regenerate-slug", c.RegenerateSlug)
+				adminEditor.POST("/:id/share", c.Share)
+				adminEditor.POST("/:id/publish-draft", c.PublishDraft)
+				adminEditor.PUT("/featured/reorder", c.ReorderFeatured)
+				adminEditor.GET("/:id/translations", c.ListTranslations)
+				adminEditor.PUT("/:id/translations/:locale", c.SaveTranslation)
+				adminEditor.DELETE("/:id/translations/:locale", c.DeleteTranslation)
 				adminEditor.POST("/:id/media", c.AddMedia)
 				adminEditor.PUT("/media/:id", c.UpdateMedia)
+				adminEditor.PATCH("/media/:id", c.UpdateMedia)
 				adminEditor.DELETE("/media/:id", c.DeleteMedia)
 			}
 		}
 	}
-} 
\ No newline at end of file
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -14,6 +15,8 @@ import (
 type CategoryController struct {
 	config          *configs.Config
 	categoryService *services.CategoryService
+	projectService  *services.ProjectService
+	blogService     *services.BlogService
 }
 
 // NewCategoryController creates a new category controller
@@ -21,9 +24,17 @@ func NewCategoryController(config *configs.Config) *CategoryController {
 	return &CategoryController{
 		config:          config,
 		categoryService: services.NewCategoryService(),
+		projectService:  services.NewProjectService(),
+		blogService:     services.NewBlogService(),
 	}
 }
 
+// service returns the category service with its queries bound to the request
+// context, so they are cancelled when the request times out or the client leaves
+func (c *CategoryController) service(ctx *gin.Context) *services.CategoryService {
+	return c.categoryService.WithContext(ctx.Request.Context())
+}
+
 // CreateProjectCategory godoc
 // @Summary Create a new project category
 // @Description Create a new project category
@@ -36,6 +47,7 @@ func NewCategoryController(config *configs.Config) *CategoryController {
 // @Failure 400 {object} utils.Response "Bad request"
 // @Failure 401 {object} utils.Response "Unauthorized"
 // @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 409 {object} utils.Response "Conflict"
 // @Failure 422 {object} utils.Response "Validation error"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/categories/projects [post]
@@ -46,8 +58,13 @@ func (c *CategoryController) CreateProjectCategory(ctx *gin.Context) {
 		return
 	}
 
-	category, err := c.categoryService.CreateProjectCategory(req)
+	userID := middleware.GetUserID(ctx)
+	category, err := c.service(ctx).CreateProjectCategory(req, userID)
 	if err != nil {
+		if errors.Is(err, services.ErrCategoryNameExists) {
+			utils.ConflictResponse(ctx, "Failed to create project category", err.Error())
+			return
+		}
 		utils.BadRequestResponse(ctx, "Failed to create project category", err.Error())
 		return
 	}
@@ -65,7 +82,7 @@ func (c *CategoryController) CreateProjectCategory(ctx *gin.Context) {
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/categories/projects [get]
 func (c *CategoryController) ListProjectCategories(ctx *gin.Context) {
-	categories, err := c.categoryService.ListProjectCategories()
+	categories, err := c.service(ctx).ListProjectCategories()
 	if err != nil {
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
@@ -93,7 +110,7 @@ func (c *CategoryController) GetProjectCategory(ctx *gin.Context) {
 		return
 	}
 
-	category, err := c.categoryService.GetProjectCategoryByID(uint(id))
+	category, err := c.service(ctx).GetProjectCategoryByID(uint(id))
 	if err != nil {
 		utils.NotFoundResponse(ctx, err.Error())
 		return
@@ -102,6 +119,41 @@ func (c *CategoryController) GetProjectCategory(ctx *gin.Context) {
 	utils.OKResponse(ctx, "Project category retrieved successfully", category)
 }
 
+// ListProjectCategoryItems godoc
+// @Summary List the projects of a project category
+// @Description List the published projects of a project category found by its slug, with pagination
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size"
+// @Success 200 {object} utils.Response{data=services.ProjectCategoryItemsResponse} "Projects retrieved successfully"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/categories/projects/{slug}/items [get]
+func (c *CategoryController) ListProjectCategoryItems(ctx *gin.Context) {
+	// Registered under :id, as gin needs one wildcard name per path segment
+	category, err := c.service(ctx).GetProjectCategoryBySlug(ctx.Param("id"))
+	if err != nil {
+		utils.NotFoundResponse(ctx, err.Error())
+		return
+	}
+
+	page, limit := parsePage(ctx, c.config, "projects")
+	items, total, err := c.projectService.WithContext(ctx.Request.Context()).ListProjects(page, limit, services.ListFilter{Published: true, CategoryID: category.ID})
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Projects retrieved successfully", services.ProjectCategoryItemsResponse{
+		Category: *category,
+		Projects: items,
+		Metadata: services.NewPageMetadata(total, page, limit),
+	})
+}
+
 // UpdateProjectCategory godoc
 // @Summary Update a project category
 // @Description Update a project category
@@ -116,6 +168,7 @@ func (c *CategoryController) GetProjectCategory(ctx *gin.Context) {
 // @Failure 401 {object} utils.Response "Unauthorized"
 // @Failure 403 {object} utils.Response "Forbidden"
 // @Failure 404 {object} utils.Response "Not found"
+// @Failure 409 {object} utils.Response "Conflict"
 // @Failure 422 {object} utils.Response "Validation error"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/categories/projects/{id} [put]
@@ -132,8 +185,13 @@ func (c *CategoryController) UpdateProjectCategory(ctx *gin.Context) {
 		return
 	}
 
-	category, err := c.categoryService.UpdateProjectCategory(uint(id), req)
+	userID := middleware.GetUserID(ctx)
+	category, err := c.service(ctx).UpdateProjectCategory(uint(id), req, userID)
 	if err != nil {
+		if errors.Is(err, services.ErrCategoryNameExists) {
+			utils.ConflictResponse(ctx, "Failed to update project category", err.Error())
+			return
+		}
 		utils.BadRequestResponse(ctx, "Failed to update project category", err.Error())
 		return
 	}
@@ -149,6 +207,7 @@ func (c *CategoryController) UpdateProjectCategory(ctx *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Category ID"
+// @Param reassign_to query int false "Category ID to move projects to before deleting"
 // @Success 204 {object} utils.Response "Category deleted successfully"
 // @Failure 400 {object} utils.Response "Bad request"
 // @Failure 401 {object} utils.Response "Unauthorized"
@@ -163,7 +222,30 @@ func (c *CategoryController) DeleteProjectCategory(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.categoryService.DeleteProjectCategory(uint(id)); err != nil {
+	userID := middleware.GetUserID(ctx)
+
+	// Move items to another category first if requested
+	if reassignStr := ctx.Query("reassign_to"); reassignStr != "" {
+		targetID, err := strconv.ParseUint(reassignStr, 10, 64)
+		if err != nil {
+			utils.BadRequestResponse(ctx, "Invalid reassign_to category ID", nil)
+			return
+		}
+
+		req := services.ReassignCategoryRequest{
+			TargetCategoryID: uint(targetID),
+			DeleteSource:     true,
+		}
+		if _, err := c.service(ctx).ReassignProjectCategory(uint(id), req, userID); err != nil {
+			utils.BadRequestResponse(ctx, "Failed to delete project category", err.Error())
+			return
+		}
+
+		utils.NoContentResponse(ctx)
+		return
+	}
+
+	if err := c.service(ctx).DeleteProjectCategory(uint(id), userID); err != nil {
 		utils.BadRequestResponse(ctx, "Failed to delete project category", err.Error())
 		return
 	}
@@ -171,6 +253,45 @@ func (c *CategoryController) DeleteProjectCategory(ctx *gin.Context) {
 	utils.NoContentResponse(ctx)
 }
 
+// ReassignProjectCategory godoc
+// @Summary Reassign projects to another category
+// @Description Move all projects from a project category to another one, optionally deleting the source category
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Source category ID"
+// @Param body body services.ReassignCategoryRequest true "Reassign category request"
+// @Success 200 {object} utils.Response{data=services.ReassignCategoryResponse} "Category reassigned successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/categories/projects/{id}/reassign [post]
+func (c *CategoryController) ReassignProjectCategory(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid category ID", nil)
+		return
+	}
+
+	var req services.ReassignCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	result, err := c.service(ctx).ReassignProjectCategory(uint(id), req, userID)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Failed to reassign project category", err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Project category reassigned successfully", result)
+}
+
 // CreateBlogCategory godoc
 // @Summary Create a new blog category
 // @Description Create a new blog category
@@ -183,6 +304,7 @@ func (c *CategoryController) DeleteProjectCategory(ctx *gin.Context) {
 // @Failure 400 {object} utils.Response "Bad request"
 // @Failure 401 {object} utils.Response "Unauthorized"
 // @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 409 {object} utils.Response "Conflict"
 // @Failure 422 {object} utils.Response "Validation error"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/categories/blog [post]
@@ -193,8 +315,13 @@ func (c *CategoryController) CreateBlogCategory(ctx *gin.Context) {
 		return
 	}
 
-	category, err := c.categoryService.CreateBlogCategory(req)
+	userID := middleware.GetUserID(ctx)
+	category, err := c.service(ctx).CreateBlogCategory(req, userID)
 	if err != nil {
+		if errors.Is(err, services.ErrCategoryNameExists) {
+			utils.ConflictResponse(ctx, "Failed to create blog category", err.Error())
+			return
+		}
 		utils.BadRequestResponse(ctx, "Failed to create blog category", err.Error())
 		return
 	}
@@ -212,7 +339,7 @@ func (c *CategoryController) CreateBlogCategory(ctx *gin.Context) {
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/categories/blog [get]
 func (c *CategoryController) ListBlogCategories(ctx *gin.Context) {
-	categories, err := c.categoryService.ListBlogCategories()
+	categories, err := c.service(ctx).ListBlogCategories()
 	if err != nil {
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
@@ -240,7 +367,7 @@ func (c *CategoryController) GetBlogCategory(ctx *gin.Context) {
 		return
 	}
 
-	category, err := c.categoryService.GetBlogCategoryByID(uint(id))
+	category, err := c.service(ctx).GetBlogCategoryByID(uint(id))
 	if err != nil {
 		utils.NotFoundResponse(ctx, err.Error())
 		return
@@ -249,6 +376,41 @@ func (c *CategoryController) GetBlogCategory(ctx *gin.Context) {
 	utils.OKResponse(ctx, "Blog category retrieved successfully", category)
 }
 
+// ListBlogCategoryItems godoc
+// @Summary List the blog posts of a blog category
+// @Description List the published blog posts of a blog category found by its slug, with pagination
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size"
+// @Success 200 {object} utils.Response{data=services.BlogCategoryItemsResponse} "Blog posts retrieved successfully"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/categories/blog/{slug}/items [get]
+func (c *CategoryController) ListBlogCategoryItems(ctx *gin.Context) {
+	// Registered under :id, as gin needs one wildcard name per path segment
+	category, err := c.service(ctx).GetBlogCategoryBySlug(ctx.Param("id"))
+	if err != nil {
+		utils.NotFoundResponse(ctx, err.Error())
+		return
+	}
+
+	page, limit := parsePage(ctx, c.config, "blog")
+	items, total, err := c.blogService.WithContext(ctx.Request.Context()).ListBlogs(page, limit, services.ListFilter{Published: true, CategoryID: category.ID})
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Blog posts retrieved successfully", services.BlogCategoryItemsResponse{
+		Category: *category,
+		Blogs:    items,
+		Metadata: services.NewPageMetadata(total, page, limit),
+	})
+}
+
 // UpdateBlogCategory godoc
 // @Summary Update a blog category
 // @Description Update a blog category
@@ -263,6 +425,7 @@ func (c *CategoryController) GetBlogCategory(ctx *gin.Context) {
 // @Failure 401 {object} utils.Response "Unauthorized"
 // @Failure 403 {object} utils.Response "Forbidden"
 // @Failure 404 {object} utils.Response "Not found"
+// @Failure 409 {object} utils.Response "Conflict"
 // @Failure 422 {object} utils.Response "Validation error"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/categories/blog/{id} [put]
@@ -279,8 +442,13 @@ func (c *CategoryController) UpdateBlogCategory(ctx *gin.Context) {
 		return
 	}
 
-	category, err := c.categoryService.UpdateBlogCategory(uint(id), req)
+	userID := middleware.GetUserID(ctx)
+	category, err := c.service(ctx).UpdateBlogCategory(uint(id), req, userID)
 	if err != nil {
+		if errors.Is(err, services.ErrCategoryNameExists) {
+			utils.ConflictResponse(ctx, "Failed to update blog category", err.Error())
+			return
+		}
 		utils.BadRequestResponse(ctx, "Failed to update blog category", err.Error())
 		return
 	}
@@ -296,6 +464,7 @@ func (c *CategoryController) UpdateBlogCategory(ctx *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Category ID"
+// @Param reassign_to query int false "Category ID to move blog posts to before deleting"
 // @Success 204 {object} utils.Response "Category deleted successfully"
 // @Failure 400 {object} utils.Response "Bad request"
 // @Failure 401 {object} utils.Response "Unauthorized"
@@ -310,7 +479,30 @@ func (c *CategoryController) DeleteBlogCategory(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.categoryService.DeleteBlogCategory(uint(id)); err != nil {
+	userID := middleware.GetUserID(ctx)
+
+	// Move items to another category first if requested
+	if reassignStr := ctx.Query("reassign_to"); reassignStr != "" {
+		targetID, err := strconv.ParseUint(reassignStr, 10, 64)
+		if err != nil {
+			utils.BadRequestResponse(ctx, "Invalid reassign_to category ID", nil)
+			return
+		}
+
+		req := services.ReassignCategoryRequest{
+			TargetCategoryID: uint(targetID),
+			DeleteSource:     true,
+		}
+		if _, err := c.service(ctx).ReassignBlogCategory(uint(id), req, userID); err != nil {
+			utils.BadRequestResponse(ctx, "Failed to delete blog category", err.Error())
+			return
+		}
+
+		utils.NoContentResponse(ctx)
+		return
+	}
+
+	if err := c.service(ctx).DeleteBlogCategory(uint(id), userID); err != nil {
 		utils.BadRequestResponse(ctx, "Failed to delete blog category", err.Error())
 		return
 	}
@@ -318,6 +510,58 @@ func (c *CategoryController) DeleteBlogCategory(ctx *gin.Context) {
 	utils.NoContentResponse(ctx)
 }
 
+// ReassignBlogCategory godoc
+// @Summary Reassign blog posts to another category
+// @Description Move all blog posts from a blog category to another one, optionally deleting the source category
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Source category ID"
+// @Param body body services.ReassignCategoryRequest true "Reassign category request"
+// @Success 200 {object} utils.Response{data=services.ReassignCategoryResponse} "Category reassigned successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/categories/blog/{id}/reassign [post]
+func (c *CategoryController) ReassignBlogCategory(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid category ID", nil)
+		return
+	}
+
+	var req services.ReassignCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	result, err := c.service(ctx).ReassignBlogCategory(uint(id), req, userID)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Failed to reassign blog category", err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Blog category reassigned successfully", result)
+}
+
+// requireAdminToReassign limits deleting a category with reassign_to to
+// admins, as moving its items is an admin action like the reassign routes
+func requireAdminToReassign() gin.HandlerFunc {
+	requireAdmin := middleware.RequireRole("admin")
+	return func(ctx *gin.Context) {
+		if ctx.Query("reassign_to") == "" {
+			ctx.Next()
+			return
+		}
+		requireAdmin(ctx)
+	}
+}
+
 // Routes registers category routes
 func (c *CategoryController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
 	categories := router.Group("/categories")
@@ -328,10 +572,11 @@ func (c *CategoryController) Routes(router *gin.RouterGroup, authMiddleware gin.
 			// Public routes
 			projectCategories.GET("", c.ListProjectCategories)
 			projectCategories.GET("/:id", c.GetProjectCategory)
+			projectCategories.GET("/:id/items", c.ListProjectCategoryItems)
 
 			// Protected routes
 			authenticated := projectCategories.Group("")
-			authenticated.Use(authMiddleware)
+			authenticated.Use(authMiddleware, invalidateLists(c.config))
 			{
 				// Admin and editor routes
 				adminEditor := authenticated.Group("")
@@ -339,7 +584,14 @@ func (c *CategoryController) Routes(router *gin.RouterGroup, authMiddleware gin.
 				{
 					adminEditor.POST("", c.CreateProjectCategory)
 					adminEditor.PUT("/:id", c.UpdateProjectCategory)
-					adminEditor.DELETE("/:id", c.DeleteProjectCategory)
+					adminEditor.DELETE("/:id", requireAdminToReassign(), c.DeleteProjectCategory)
+				}
+
+				// Admin only routes
+				admin := authenticated.Group("")
+				admin.Use(middleware.RequireRole("admin"))
+				{
+					admin.POST("/:id/reassign", c.ReassignProjectCategory)
 				}
 			}
 		}
@@ -350,10 +602,11 @@ func (c *CategoryController) Routes(router *gin.RouterGroup, authMiddleware gin.
 			// Public routes
 			blogCategories.GET("", c.ListBlogCategories)
 			blogCategories.GET("/:id", c.GetBlogCategory)
+			blogCategories.GET("/:id/items", c.ListBlogCategoryItems)
 
 			// Protected routes
 			authenticated := blogCategories.Group("")
-			authenticated.Use(authMiddleware)
+			authenticated.Use(authMiddleware, invalidateLists(c.config))
 			{
 				// Admin and editor routes
 				adminEditor := authenticated.Group("")
@@ -361,7 +614,14 @@ func (c *CategoryController) Routes(router *gin.RouterGroup, authMiddleware gin.
 				{
 					adminEditor.POST("", c.CreateBlogCategory)
 					adminEditor.PUT("/:id", c.UpdateBlogCategory)
-					adminEditor.DELETE("/:id", c.DeleteBlogCategory)
+					adminEditor.DELETE("/:id", requireAdminToReassign(), c.DeleteBlogCategory)
+				}
+
+				// Admin only routes
+				admin := authenticated.Group("")
+				admin.Use(middleware.RequireRole("admin"))
+				{
+					admin.POST("/:id/reassign", c.ReassignBlogCategory)
 				}
 			}
 		}
@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// CommentController handles blog comment and moderation routes
+type CommentController struct {
+	config         *configs.Config
+	commentService *services.CommentService
+}
+
+// NewCommentController creates a new comment controller
+func NewCommentController(config *configs.Config) *CommentController {
+	return &CommentController{
+		config:         config,
+		commentService: services.NewCommentService(config.Comment),
+	}
+}
+
+// Create godoc
+// @Summary Comment on a blog post
+// @Description Add a comment to a published blog post. Comments stay hidden until a moderator approves them. Anonymous readers must give an author name.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Blog Post ID"
+// @Param body body services.CreateCommentRequest true "Create comment request"
+// @Success 201 {object} utils.Response{data=services.CommentResponse} "Comment submitted for moderation"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 429 {object} utils.Response "Too many comments"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/{id}/comments [post]
+func (c *CommentController) Create(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid blog post ID", nil)
+		return
+	}
+
+	var req services.CreateCommentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	comment, err := c.commentService.CreateComment(uint(id), req, userID, ctx.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCommentBlogNotFound):
+			utils.NotFoundResponse(ctx, err.Error())
+		case errors.Is(err, services.ErrCommentRateLimited):
+			utils.ErrorResponse(ctx, http.StatusTooManyRequests, err.Error(), nil)
+		case services.IsCommentValidationError(err):
+			utils.ValidationErrorResponse(ctx, err.Error())
+		default:
+			utils.InternalServerErrorResponse(ctx, err.Error())
+		}
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Comment submitted for moderation", comment)
+}
+
+// ListApproved godoc
+// @Summary List comments of a blog post
+// @Description List the approved comments of a blog post, oldest first
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Blog Post ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response{data=services.CommentListResponse} "Comments retrieved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/blog/{id}/comments [get]
+func (c *CommentController) ListApproved(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid blog post ID", nil)
+		return
+	}
+
+	page, limit := parsePage(ctx, c.config, "comments")
+	comments, total, err := c.commentService.ListApprovedComments(uint(id), page, limit)
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Comments retrieved successfully", commentPage(comments, total, page, limit))
+}
+
+// List godoc
+// @Summary List comments for moderation
+// @Description List comments of all blog posts, newest first, including author emails
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param approved query bool false "Filter by approval status"
+// @Param blog_id query int false "Filter by blog post ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response{data=services.CommentListResponse} "Comments retrieved successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/comments [get]
+func (c *CommentController) List(ctx *gin.Context) {
+	page, limit := parsePage(ctx, c.config, "comments")
+
+	var approved *bool
+	if approvedStr := ctx.Query("approved"); approvedStr != "" {
+		if value, err := strconv.ParseBool(approvedStr); err == nil {
+			approved = &value
+		}
+	}
+
+	var blogID uint
+	if blogIDStr := ctx.Query("blog_id"); blogIDStr != "" {
+		if blogIDNum, err := strconv.ParseUint(blogIDStr, 10, 64); err == nil {
+			blogID = uint(blogIDNum)
+		}
+	}
+
+	comments, total, err := c.commentService.ListComments(page, limit, approved, blogID)
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Comments retrieved successfully", commentPage(comments, total, page, limit))
+}
+
+// Approve godoc
+// @Summary Approve a comment
+// @Description Approve a pending comment so it appears on its blog post
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Comment ID"
+// @Success 200 {object} utils.Response{data=services.CommentResponse} "Comment approved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/comments/{id}/approve [put]
+func (c *CommentController) Approve(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid comment ID", nil)
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	comment, err := c.commentService.ApproveComment(uint(id), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrCommentNotFound) {
+			utils.NotFoundResponse(ctx, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Comment approved successfully", comment)
+}
+
+// Delete godoc
+// @Summary Delete a comment
+// @Description Delete a pending or approved comment
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Comment ID"
+// @Success 204 {object} utils.Response "Comment deleted successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/comments/{id} [delete]
+func (c *CommentController) Delete(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid comment ID", nil)
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	if err := c.commentService.DeleteComment(uint(id), userID); err != nil {
+		if errors.Is(err, services.ErrCommentNotFound) {
+			utils.NotFoundResponse(ctx, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.NoContentResponse(ctx)
+}
+
+// commentPage wraps a page of comments with pagination metadata
+func commentPage(comments []services.CommentResponse, total int64, page, limit int) services.CommentListResponse {
+	return services.CommentListResponse{
+		Comments: comments,
+		Metadata: services.NewPageMetadata(total, page, limit),
+	}
+}
+
+// Routes registers comment routes. Readers may comment anonymously, so the
+// public routes only identify signed-in users when a token is sent.
+func (c *CommentController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	blogComments := router.Group("/blog/:id/comments")
+	{
+		blogComments.GET("", c.ListApproved)
+		blogComments.POST("", middleware.OptionalAuth(c.config), c.Create)
+	}
+
+	comments := router.Group("/comments")
+	comments.Use(authMiddleware, middleware.RequireRole("admin"), invalidateLists(c.config))
+	{
+		comments.GET("", c.List)
+		comments.PUT("/:id/approve", c.Approve)
+		comments.DELETE("/:id", c.Delete)
+	}
+}
@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// ContactController handles the contact form and its messages
+type ContactController struct {
+	config         *configs.Config
+	messageService *services.MessageService
+}
+
+// NewContactController creates a new contact controller
+func NewContactController(config *configs.Config) *ContactController {
+	return &ContactController{
+		config:         config,
+		messageService: services.NewMessageService(config),
+	}
+}
+
+// Create godoc
+// @Summary Send a contact message
+// @Description Send a message through the contact form. Clients are limited to a number of messages per window, and messages that look like spam are rejected.
+// @Tags contact
+// @Accept json
+// @Produce json
+// @Param body body services.CreateMessageRequest true "Contact message"
+// @Success 201 {object} utils.Response{data=services.MessageResponse} "Message sent successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 422 {object} utils.Response{error=map[string]string} "Validation error"
+// @Failure 429 {object} utils.Response "Too many messages"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/contact [post]
+func (c *ContactController) Create(ctx *gin.Context) {
+	var req services.CreateMessageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationErrorResponse(ctx, err, &req)
+		return
+	}
+
+	message, err := c.messageService.CreateMessage(req, ctx.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMessageHoneypot):
+			// Bots are told their message went through so they do not adapt
+			utils.CreatedResponse(ctx, "Message sent successfully", nil)
+		case errors.Is(err, services.ErrMessageRateLimited):
+			utils.ErrorResponse(ctx, http.StatusTooManyRequests, err.Error(), nil)
+		case services.IsMessageValidationError(err):
+			utils.ValidationErrorResponse(ctx, err.Error())
+		default:
+			utils.InternalServerErrorResponse(ctx, err.Error())
+		}
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Message sent successfully", message)
+}
+
+// List godoc
+// @Summary List contact messages
+// @Description List messages sent through the contact form, newest first
+// @Tags contact
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param read query bool false "Filter by read status"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response{data=services.MessageListResponse} "Messages retrieved successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/messages [get]
+func (c *ContactController) List(ctx *gin.Context) {
+	page, limit := parsePage(ctx, c.config, "messages")
+
+	var read *bool
+	if readStr := ctx.Query("read"); readStr != "" {
+		if value, err := strconv.ParseBool(readStr); err == nil {
+			read = &value
+		}
+	}
+
+	messages, total, err := c.messageService.ListMessages(page, limit, read)
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Messages retrieved successfully", services.MessageListResponse{
+		Messages: messages,
+		Metadata: services.NewPageMetadata(total, page, limit),
+	})
+}
+
+// MarkRead godoc
+// @Summary Mark a contact message as read
+// @Description Mark a contact message as read
+// @Tags contact
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Message ID"
+// @Success 200 {object} utils.Response{data=services.MessageResponse} "Message marked as read"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/messages/{id}/read [put]
+func (c *ContactController) MarkRead(ctx *gin.Context) {
+	c.markRead(ctx, true, "Message marked as read")
+}
+
+// MarkUnread godoc
+// @Summary Mark a contact message as unread
+// @Description Mark a contact message as unread again
+// @Tags contact
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Message ID"
+// @Success 200 {object} utils.Response{data=services.MessageResponse} "Message marked as unread"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/messages/{id}/unread [put]
+func (c *ContactController) MarkUnread(ctx *gin.Context) {
+	c.markRead(ctx, false, "Message marked as unread")
+}
+
+// markRead sets the read flag of the message in the path
+func (c *ContactController) markRead(ctx *gin.Context, read bool, successMessage string) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid message ID", nil)
+		return
+	}
+
+	message, err := c.messageService.MarkMessageRead(uint(id), read)
+	if err != nil {
+		if errors.Is(err, services.ErrMessageNotFound) {
+			utils.NotFoundResponse(ctx, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, successMessage, message)
+}
+
+// Routes registers contact routes
+func (c *ContactController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	router.POST("/contact", c.Create)
+
+	messages := router.Group("/messages")
+	messages.Use(authMiddleware, middleware.RequireRole("admin"))
+	{
+		messages.GET("", c.List)
+		messages.PUT("/:id/read", c.MarkRead)
+		messages.PUT("/:id/unread", c.MarkUnread)
+	}
+}
@@ -0,0 +1,21 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// draftErrorResponse writes the error response for a failed draft change
+func draftErrorResponse(ctx *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrDraftResourceNotFound):
+		utils.NotFoundResponse(ctx, err.Error())
+	case errors.Is(err, services.ErrNoDraft):
+		utils.ConflictResponse(ctx, message, err.Error())
+	default:
+		utils.InternalServerErrorResponse(ctx, err.Error())
+	}
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// DraftController handles the listing of unpublished content
+type DraftController struct {
+	config       *configs.Config
+	draftService *services.DraftService
+}
+
+// NewDraftController creates a new draft controller
+func NewDraftController(config *configs.Config) *DraftController {
+	return &DraftController{
+		config:       config,
+		draftService: services.NewDraftService(),
+	}
+}
+
+// List godoc
+// @Summary Get drafts
+// @Description Get unpublished projects and blog posts as one listing, most recently updated first
+// @Tags drafts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param type query string false "Only drafts of this type" Enums(project, blog_post)
+// @Param author_id query int false "Only drafts created by this user"
+// @Success 200 {object} utils.Response{data=services.DraftListResponse} "Drafts retrieved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/drafts [get]
+func (c *DraftController) List(ctx *gin.Context) {
+	page, limit := parsePage(ctx, c.config, "drafts")
+
+	filter := services.DraftFilter{Type: ctx.Query("type")}
+	if authorIDStr := ctx.Query("author_id"); authorIDStr != "" {
+		authorID, err := strconv.ParseUint(authorIDStr, 10, 64)
+		if err != nil {
+			utils.BadRequestResponse(ctx, "Invalid author ID", err.Error())
+			return
+		}
+		filter.AuthorID = uint(authorID)
+	}
+
+	drafts, total, err := c.draftService.ListDrafts(page, limit, filter)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownDraftType) {
+			utils.BadRequestResponse(ctx, "Invalid draft type", err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Drafts retrieved successfully", services.DraftListResponse{
+		Drafts:   drafts,
+		Metadata: services.NewPageMetadata(total, page, limit),
+	})
+}
+
+// Routes registers draft routes
+func (c *DraftController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	drafts := router.Group("/drafts")
+	drafts.Use(authMiddleware, middleware.RequireRole("admin", "editor"))
+	{
+		drafts.GET("", c.List)
+	}
+}
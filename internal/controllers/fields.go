@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/internal/utils"
+)
+
+// parseFields parses the comma-separated fields query parameter. It returns
+// nil when the full objects are requested.
+func parseFields(ctx *gin.Context) []string {
+	var fields []string
+	for _, field := range strings.Split(ctx.Query("fields"), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// okWithFields responds like utils.OKResponse, trimming objects to the fields
+// requested by the fields query parameter. With an empty key data itself is
+// trimmed, otherwise each item of the list under key is. Unknown fields are
+// ignored.
+func okWithFields(ctx *gin.Context, message string, data interface{}, key string) {
+	fields := parseFields(ctx)
+	if len(fields) == 0 {
+		utils.OKResponse(ctx, message, data)
+		return
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	// Keep numbers as they are rather than turning them into floats
+	var shaped map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&shaped); err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	if key == "" {
+		utils.OKResponse(ctx, message, selectFields(shaped, fields))
+		return
+	}
+
+	if items, ok := shaped[key].([]interface{}); ok {
+		for i, item := range items {
+			if object, ok := item.(map[string]interface{}); ok {
+				items[i] = selectFields(object, fields)
+			}
+		}
+	}
+	utils.OKResponse(ctx, message, shaped)
+}
+
+// selectFields returns the requested fields of an object
+func selectFields(object map[string]interface{}, fields []string) map[string]interface{} {
+	selected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if value, ok := object[field]; ok {
+			selected[field] = value
+		}
+	}
+	return selected
+}
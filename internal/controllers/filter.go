@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+)
+
+// parseListFilter parses the list filters shared by the project and blog list and count endpoints.
+// It returns an error when a date bound is not a valid RFC3339 timestamp or a
+// category ID is not a number.
+func parseListFilter(ctx *gin.Context) (services.ListFilter, error) {
+	filter := services.ListFilter{
+		Published:  true, // Published only unless the caller may see drafts, see below
+		Tag:        ctx.Query("tag"),
+		Technology: ctx.Query("technology"),
+		Query:      ctx.Query("q"),
+	}
+
+	categoryIDs, err := parseCategoryIDs(ctx)
+	if err != nil {
+		return filter, err
+	}
+	filter.CategoryIDs = categoryIDs
+
+	if featuredStr := ctx.Query("featured"); featuredStr != "" {
+		if featuredBool, err := strconv.ParseBool(featuredStr); err == nil {
+			filter.Featured = featuredBool
+		}
+	}
+
+	if createdAfterStr := ctx.Query("created_after"); createdAfterStr != "" {
+		createdAfter, err := time.Parse(time.RFC3339, createdAfterStr)
+		if err != nil {
+			return filter, errors.New("created_after must be an RFC3339 timestamp")
+		}
+		filter.CreatedAfter = createdAfter
+	}
+
+	if createdBeforeStr := ctx.Query("created_before"); createdBeforeStr != "" {
+		createdBefore, err := time.Parse(time.RFC3339, createdBeforeStr)
+		if err != nil {
+			return filter, errors.New("created_before must be an RFC3339 timestamp")
+		}
+		filter.CreatedBefore = createdBefore
+	}
+
+	// Admins, editors and preview tokens can see all unpublished content, other
+	// signed-in users only their own. Without a published parameter admins and
+	// editors list every status, so their drafts show up next to the published
+	// items, while preview tokens and everyone else list published items only.
+	userID := middleware.GetUserID(ctx)
+	userRole := middleware.GetUserRole(ctx)
+	editor := userID > 0 && (userRole == "admin" || userRole == "editor")
+	preview := middleware.GetScope(ctx) == services.ScopePreviewRead
+	publishedStr := ctx.Query("published")
+	if publishedStr == "" {
+		filter.AnyStatus = editor && !preview
+	} else if userID > 0 || preview {
+		if publishedBool, err := strconv.ParseBool(publishedStr); err == nil && !publishedBool {
+			filter.Published = false
+			if !editor && !preview {
+				filter.CreatedBy = userID
+			}
+		}
+	}
+
+	return filter, nil
+}
+
+// canReadDrafts reports whether the caller may read unpublished content by ID
+// or slug: admins, editors and preview tokens
+func canReadDrafts(ctx *gin.Context) bool {
+	userRole := middleware.GetUserRole(ctx)
+	return userRole == "admin" || userRole == "editor" || middleware.GetScope(ctx) == services.ScopePreviewRead
+}
+
+// parseCategoryIDs collects the categories to list from repeated category_id
+// parameters and a comma-separated category_ids parameter. Items in any of them match.
+func parseCategoryIDs(ctx *gin.Context) ([]uint, error) {
+	values := ctx.QueryArray("category_id")
+	if categoryIDsStr := ctx.Query("category_ids"); categoryIDsStr != "" {
+		values = append(values, strings.Split(categoryIDsStr, ",")...)
+	}
+
+	var categoryIDs []uint
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		categoryID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || categoryID == 0 {
+			return nil, fmt.Errorf("category ID %q must be a positive integer", value)
+		}
+		categoryIDs = append(categoryIDs, uint(categoryID))
+	}
+	return categoryIDs, nil
+}
+
+// parsePage parses the page and limit query parameters, applying the configured
+// default page size of the resource and capping the limit at its maximum
+func parsePage(ctx *gin.Context, config *configs.Config, resource string) (int, int) {
+	limits := config.Pagination.Limits(resource)
+	page := 1
+	limit := limits.DefaultLimit
+
+	if pageStr := ctx.Query("page"); pageStr != "" {
+		if pageNum, err := strconv.Atoi(pageStr); err == nil && pageNum > 0 {
+			page = pageNum
+		}
+	}
+
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		if limitNum, err := strconv.Atoi(limitStr); err == nil && limitNum > 0 {
+			limit = limitNum
+		}
+	}
+
+	if limits.MaxLimit > 0 && limit > limits.MaxLimit {
+		limit = limits.MaxLimit
+	}
+
+	// Guard against a misconfigured default
+	if limit <= 0 {
+		limit = 10
+	}
+
+	return page, limit
+}
+
+// parseMediaPage parses the media_page and media_limit query parameters of a
+// detail request, capping the limit at the maximum page size of media. Without
+// media_limit all media are returned.
+func parseMediaPage(ctx *gin.Context, config *configs.Config) (services.MediaPage, error) {
+	mediaPage := services.MediaPage{Page: 1}
+
+	if limitStr := ctx.Query("media_limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return mediaPage, fmt.Errorf("media_limit %q must be a positive integer", limitStr)
+		}
+		if maxLimit := config.Pagination.Limits("media").MaxLimit; maxLimit > 0 && limit > maxLimit {
+			limit = maxLimit
+		}
+		mediaPage.Limit = limit
+	}
+
+	if pageStr := ctx.Query("media_page"); pageStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil || page <= 0 {
+			return mediaPage, fmt.Errorf("media_page %q must be a positive integer", pageStr)
+		}
+		mediaPage.Page = page
+	}
+
+	return mediaPage, nil
+}
+
+// includeCategorySiblings is the include value that embeds other published
+// projects of the category in a project response
+const includeCategorySiblings = "category_siblings"
+
+// defaultSiblingsLimit is the number of category siblings embedded without siblings_limit
+const defaultSiblingsLimit = 4
+
+// parseCategorySiblings parses the include and siblings_limit query parameters
+// of a project detail request and returns how many category siblings to embed,
+// 0 when include does not ask for them. The limit is capped at the maximum page
+// size of projects.
+func parseCategorySiblings(ctx *gin.Context, config *configs.Config) (int, error) {
+	requested := false
+	if includeStr := ctx.Query("include"); includeStr != "" {
+		for _, include := range strings.Split(includeStr, ",") {
+			if include = strings.TrimSpace(include); include != includeCategorySiblings {
+				return 0, fmt.Errorf("include %q is not supported", include)
+			}
+			requested = true
+		}
+	}
+	if !requested {
+		return 0, nil
+	}
+
+	limit := defaultSiblingsLimit
+	if limitStr := ctx.Query("siblings_limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			return 0, fmt.Errorf("siblings_limit %q must be a positive integer", limitStr)
+		}
+		limit = parsed
+	}
+	if maxLimit := config.Pagination.Limits("projects").MaxLimit; maxLimit > 0 && limit > maxLimit {
+		limit = maxLimit
+	}
+	return limit, nil
+}
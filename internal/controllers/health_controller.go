@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthController handles liveness and readiness probes
+type HealthController struct {
+	ping              func() error
+	pendingMigrations func() ([]string, error)
+}
+
+// NewHealthController creates a new health controller that uses ping to check
+// database connectivity and pendingMigrations to list the tables not migrated yet
+func NewHealthController(ping func() error, pendingMigrations func() ([]string, error)) *HealthController {
+	return &HealthController{
+		ping:              ping,
+		pendingMigrations: pendingMigrations,
+	}
+}
+
+// Livez godoc
+// @Summary Liveness probe
+// @Description Report that the process is up
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string "Process is alive"
+// @Router /livez [get]
+func (c *HealthController) Livez(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": "alive"})
+}
+
+// Readyz godoc
+// @Summary Readiness probe
+// @Description Report whether dependencies such as the database are ready and the database schema is migrated
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Service is ready"
+// @Failure 503 {object} map[string]interface{} "Service is not ready"
+// @Router /readyz [get]
+func (c *HealthController) Readyz(ctx *gin.Context) {
+	// The probe is public, so the database error is only logged to keep
+	// hosts and credentials out of the response
+	if err := c.ping(); err != nil {
+		log.Printf("Readiness check failed to reach the database: %v", err)
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":   "not ready",
+			"database": "database unavailable",
+		})
+		return
+	}
+
+	// Traffic is held back until every table exists, so a half-migrated
+	// instance is not served requests
+	pending, err := c.pendingMigrations()
+	if err != nil {
+		log.Printf("Readiness check failed to list pending migrations: %v", err)
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":   "not ready",
+			"database": "database unavailable",
+		})
+		return
+	}
+	if len(pending) > 0 {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":         "not ready",
+			"migrations":     "pending",
+			"pending_tables": pending,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "ready", "migrations": "up-to-date"})
+}
+
+// Routes registers probe routes
+func (c *HealthController) Routes(router gin.IRoutes) {
+	router.GET("/livez", c.Livez)
+	router.GET("/readyz", c.Readyz)
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// lastModifiedResponse answers with the collection's last modification time in
+// the Last-Modified header and body, or with 304 when the time is not after
+// the request's If-Modified-Since, so clients can cheaply tell whether to
+// refetch a list
+func lastModifiedResponse(ctx *gin.Context, message string, result *services.LastModifiedResponse) {
+	if result.LastModified != nil {
+		// HTTP dates have a resolution of one second
+		lastModified := result.LastModified.UTC().Truncate(time.Second)
+		ctx.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+
+		if since, err := http.ParseTime(ctx.GetHeader("If-Modified-Since")); err == nil && !lastModified.After(since) {
+			ctx.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	utils.OKResponse(ctx, message, result)
+}
@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/cache"
+	"zionechainapi/internal/middleware"
+)
+
+// Prefixes of the stores caching the public project and blog lists
+const (
+	projectListCache = "projects"
+	blogListCache    = "blog"
+)
+
+// invalidateLists returns a middleware that clears the cached project and blog
+// lists after a successful write. Categories, tags, technologies, comments and
+// imported content all show up in those lists, so their writes must clear them
+// as much as project and blog writes do.
+func invalidateLists(config *configs.Config) gin.HandlerFunc {
+	return middleware.InvalidateCache(cache.New(config, projectListCache), cache.New(config, blogListCache))
+}
@@ -1,29 +1,75 @@
 package controllers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"zionechainapi/configs"
+	"zionechainapi/internal/cache"
 	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/models"
 	"zionechainapi/internal/services"
+	"zionechainapi/internal/storage"
 	"zionechainapi/internal/utils"
 )
 
 // ProjectController handles project-related routes
 type ProjectController struct {
-	config         *configs.Config
-	projectService *services.ProjectService
+	config             *configs.Config
+	projectService     *services.ProjectService
+	translationService *services.TranslationService
+	views              *services.ViewCounter
+	listCache          cache.Cache
 }
 
 // NewProjectController creates a new project controller
 func NewProjectController(config *configs.Config) *ProjectController {
 	return &ProjectController{
-		config:         config,
-		projectService: services.NewProjectService(),
+		config:             config,
+		projectService:     services.NewProjectService().WithContentSanitization(config.Content.SanitizeHTML).WithWebhooks(services.NewWebhookDispatcher(config.Webhook)).WithStorage(storage.New(config)),
+		translationService: services.NewTranslationService().WithDefaultLocale(config.Content.DefaultLocale).WithContentSanitization(config.Content.SanitizeHTML),
+		views:              services.NewViewCounter("projects").Start(config.Content.ViewFlushInterval),
+		listCache:          cache.New(config, projectListCache),
 	}
 }
 
+// Close writes the views counted since the last flush and stops flushing them
+func (c *ProjectController) Close() error {
+	return c.views.Stop()
+}
+
+// service returns the project service with its queries bound to the request
+// context, so they are cancelled when the request times out or the client leaves
+func (c *ProjectController) service(ctx *gin.Context) *services.ProjectService {
+	return c.projectService.WithContext(ctx.Request.Context())
+}
+
+// countView counts a view of a published project and adds the views not yet
+// written to the database to its count
+func (c *ProjectController) countView(project *services.ProjectResponse) {
+	if project.Published {
+		c.views.Record(project.ID)
+	}
+	project.Views += c.views.Pending(project.ID)
+}
+
+// withCategorySiblings returns project with at most limit other published
+// projects of its category, or project unchanged when limit is 0. ok is false
+// when an error response was written.
+func (c *ProjectController) withCategorySiblings(ctx *gin.Context, project *services.ProjectResponse, limit int) (data interface{}, ok bool) {
+	if limit == 0 {
+		return project, true
+	}
+
+	siblings, err := c.service(ctx).ListCategorySiblings(project, limit)
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return nil, false
+	}
+	return services.ProjectWithSiblingsResponse{ProjectResponse: project, CategorySiblings: siblings}, true
+}
+
 // Create godoc
 // @Summary Create a new project
 // @Description Create a new project
@@ -47,8 +93,12 @@ func (c *ProjectController) Create(ctx *gin.Context) {
 	}
 
 	userID := middleware.GetUserID(ctx)
-	project, err := c.projectService.CreateProject(req, userID)
+	project, err := c.service(ctx).CreateProject(req, userID)
 	if err != nil {
+		if errors.Is(err, services.ErrCategoryNotFound) {
+			utils.ValidationErrorResponse(ctx, err.Error())
+			return
+		}
 		utils.BadRequestResponse(ctx, "Failed to create project", err.Error())
 		return
 	}
@@ -58,11 +108,17 @@ func (c *ProjectController) Create(ctx *gin.Context) {
 
 // Get godoc
 // @Summary Get a project by ID
-// @Description Get a project by ID
+// @Description Get a project by ID. Unpublished projects are only found for admins, editors and preview tokens.
 // @Tags projects
 // @Accept json
 // @Produce json
 // @Param id path int true "Project ID"
+// @Param locale query string false "Locale of translations to overlay, falling back to the base content"
+// @Param fields query string false "Comma-separated fields to return, such as id,title,slug; all fields by default"
+// @Param media_limit query int false "Media per page; all media by default"
+// @Param media_page query int false "Media page number, 1 by default"
+// @Param include query string false "Set to category_siblings to embed other published projects of the category"
+// @Param siblings_limit query int false "Category siblings to embed, 4 by default"
 // @Success 200 {object} utils.Response{data=services.ProjectResponse} "Project retrieved successfully"
 // @Failure 400 {object} utils.Response "Bad request"
 // @Failure 404 {object} utils.Response "Not found"
@@ -75,23 +131,56 @@ func (c *ProjectController) Get(ctx *gin.Context) {
 		return
 	}
 
-	project, err := c.projectService.GetProjectByID(uint(id))
+	mediaPage, err := parseMediaPage(ctx, c.config)
+	if err != nil {
+		utils.BadRequestResponse(ctx, err.Error(), nil)
+		return
+	}
+
+	siblingsLimit, err := parseCategorySiblings(ctx, c.config)
+	if err != nil {
+		utils.BadRequestResponse(ctx, err.Error(), nil)
+		return
+	}
+
+	project, err := c.service(ctx).GetProjectByID(uint(id), mediaPage, canReadDrafts(ctx))
 	if err != nil {
 		utils.NotFoundResponse(ctx, err.Error())
 		return
 	}
 
-	utils.OKResponse(ctx, "Project retrieved successfully", project)
+	c.countView(project)
+
+	if locale := ctx.Query("locale"); locale != "" {
+		if err := c.translationService.LocalizeProject(project, locale); err != nil {
+			localizeErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	data, ok := c.withCategorySiblings(ctx, project, siblingsLimit)
+	if !ok {
+		return
+	}
+
+	okWithFields(ctx, "Project retrieved successfully", data, "")
 }
 
 // GetBySlug godoc
 // @Summary Get a project by slug
-// @Description Get a project by slug
+// @Description Get a project by slug. A slug the project used to have is answered with a permanent redirect to its current slug. Unpublished projects are only found for admins, editors and preview tokens.
 // @Tags projects
 // @Accept json
 // @Produce json
 // @Param slug path string true "Project Slug"
+// @Param locale query string false "Locale of translations to overlay, falling back to the base content"
+// @Param fields query string false "Comma-separated fields to return, such as id,title,slug; all fields by default"
+// @Param media_limit query int false "Media per page; all media by default"
+// @Param media_page query int false "Media page number, 1 by default"
+// @Param include query string false "Set to category_siblings to embed other published projects of the category"
+// @Param siblings_limit query int false "Category siblings to embed, 4 by default"
 // @Success 200 {object} utils.Response{data=services.ProjectResponse} "Project retrieved successfully"
+// @Success 301 {object} utils.Response{data=services.SlugRedirectResponse} "Moved to the current slug"
 // @Failure 400 {object} utils.Response "Bad request"
 // @Failure 404 {object} utils.Response "Not found"
 // @Failure 500 {object} utils.Response "Internal server error"
@@ -99,90 +188,234 @@ func (c *ProjectController) Get(ctx *gin.Context) {
 func (c *ProjectController) GetBySlug(ctx *gin.Context) {
 	slug := ctx.Param("slug")
 
-	project, err := c.projectService.GetProjectBySlug(slug)
+	mediaPage, err := parseMediaPage(ctx, c.config)
+	if err != nil {
+		utils.BadRequestResponse(ctx, err.Error(), nil)
+		return
+	}
+
+	siblingsLimit, err := parseCategorySiblings(ctx, c.config)
+	if err != nil {
+		utils.BadRequestResponse(ctx, err.Error(), nil)
+		return
+	}
+
+	project, err := c.service(ctx).GetProjectBySlug(slug, mediaPage, canReadDrafts(ctx))
 	if err != nil {
 		utils.NotFoundResponse(ctx, err.Error())
 		return
 	}
+	if redirectToCurrentSlug(ctx, project.Slug) {
+		return
+	}
+
+	c.countView(project)
+
+	if locale := ctx.Query("locale"); locale != "" {
+		if err := c.translationService.LocalizeProject(project, locale); err != nil {
+			localizeErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	data, ok := c.withCategorySiblings(ctx, project, siblingsLimit)
+	if !ok {
+		return
+	}
+
+	okWithFields(ctx, "Project retrieved successfully", data, "")
+}
+
+// SlugAvailable godoc
+// @Summary Check slug availability
+// @Description Preview the slug generated from a project title and whether it is free
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param title query string true "Title"
+// @Success 200 {object} utils.Response{data=services.SlugAvailabilityResponse} "Slug availability checked successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/slug-available [get]
+func (c *ProjectController) SlugAvailable(ctx *gin.Context) {
+	title := ctx.Query("title")
+	if title == "" {
+		utils.BadRequestResponse(ctx, "title is required", nil)
+		return
+	}
+
+	availability, err := c.service(ctx).CheckSlugAvailability(title)
+	if err != nil {
+		if errors.Is(err, services.ErrSlugTitleInvalid) {
+			utils.BadRequestResponse(ctx, err.Error(), nil)
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
 
-	utils.OKResponse(ctx, "Project retrieved successfully", project)
+	utils.OKResponse(ctx, "Slug availability checked successfully", availability)
 }
 
 // List godoc
 // @Summary List projects
-// @Description List projects with pagination
+// @Description List projects with pagination. With a cursor the metadata holds limit and next_cursor (services.CursorMetadata) instead of page numbers.
 // @Tags projects
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number"
 // @Param limit query int false "Page size"
-// @Param category_id query int false "Category ID"
+// @Param cursor query string false "Cursor from next_cursor; selects cursor pagination instead of page. Not supported with featured"
+// @Param category_id query []int false "Category ID, repeat for items in any of several categories" collectionFormat(multi)
+// @Param category_ids query string false "Comma-separated category IDs, combined with category_id"
 // @Param featured query bool false "Featured flag"
-// @Success 200 {object} utils.Response{data=[]services.ProjectResponse} "Projects retrieved successfully"
+// @Param tag query string false "Tag slug"
+// @Param technology query string false "Technology slug"
+// @Param q query string false "Search text"
+// @Param published query bool false "Defaults to every status for admins and editors and to published only for everyone else. Set to false for drafts: all drafts for admins, editors and preview tokens, their own for other signed-in users"
+// @Param created_after query string false "Only items created at or after this RFC3339 time"
+// @Param created_before query string false "Only items created at or before this RFC3339 time"
+// @Param locale query string false "Locale of translations to overlay, falling back to the base content"
+// @Param fields query string false "Comma-separated fields to return, such as id,title,slug; all fields by default"
+// @Success 200 {object} utils.Response{data=services.ProjectListResponse} "Projects retrieved successfully"
 // @Failure 400 {object} utils.Response "Bad request"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/projects [get]
 func (c *ProjectController) List(ctx *gin.Context) {
-	page := 1
-	limit := 10
-	var categoryID uint
-	featured := false
-	published := true // Default to published only
-
-	// Parse query parameters
-	if pageStr := ctx.Query("page"); pageStr != "" {
-		if pageNum, err := strconv.Atoi(pageStr); err == nil {
-			page = pageNum
-		}
+	page, limit := parsePage(ctx, c.config, "projects")
+
+	filter, err := parseListFilter(ctx)
+	if err != nil {
+		utils.BadRequestResponse(ctx, err.Error(), nil)
+		return
 	}
 
-	if limitStr := ctx.Query("limit"); limitStr != "" {
-		if limitNum, err := strconv.Atoi(limitStr); err == nil {
-			limit = limitNum
+	// A cursor parameter, even an empty one for the first page, selects cursor pagination
+	if cursor, ok := ctx.GetQuery("cursor"); ok {
+		items, nextCursor, err := c.service(ctx).ListProjectsAfter(cursor, limit, filter)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidCursor) || errors.Is(err, services.ErrFeaturedCursor) {
+				utils.BadRequestResponse(ctx, err.Error(), nil)
+				return
+			}
+			utils.InternalServerErrorResponse(ctx, err.Error())
+			return
 		}
-	}
 
-	if categoryIDStr := ctx.Query("category_id"); categoryIDStr != "" {
-		if categoryIDNum, err := strconv.ParseUint(categoryIDStr, 10, 64); err == nil {
-			categoryID = uint(categoryIDNum)
+		if locale := ctx.Query("locale"); locale != "" {
+			if err := c.translationService.LocalizeProjects(items, locale); err != nil {
+				localizeErrorResponse(ctx, err)
+				return
+			}
 		}
+
+		okWithFields(ctx, "Projects retrieved successfully", services.ProjectCursorListResponse{
+			Projects: items,
+			Metadata: services.CursorMetadata{Limit: limit, NextCursor: nextCursor},
+		}, "projects")
+		return
 	}
 
-	if featuredStr := ctx.Query("featured"); featuredStr != "" {
-		if featuredBool, err := strconv.ParseBool(featuredStr); err == nil {
-			featured = featuredBool
-		}
+	projects, total, err := c.service(ctx).ListProjects(page, limit, filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
 	}
 
-	// Check if user is admin or editor
-	userRole := middleware.GetUserRole(ctx)
-	if userRole == "admin" || userRole == "editor" {
-		// If user is admin or editor, check if they want to see unpublished projects
-		if publishedStr := ctx.Query("published"); publishedStr != "" {
-			if publishedBool, err := strconv.ParseBool(publishedStr); err == nil {
-				published = publishedBool
-			}
+	if locale := ctx.Query("locale"); locale != "" {
+		if err := c.translationService.LocalizeProjects(projects, locale); err != nil {
+			localizeErrorResponse(ctx, err)
+			return
 		}
 	}
 
-	projects, total, err := c.projectService.ListProjects(page, limit, categoryID, featured, published)
+	// Create response with pagination metadata
+	response := services.ProjectListResponse{
+		Projects: projects,
+		Metadata: services.NewPageMetadata(total, page, limit),
+	}
+
+	okWithFields(ctx, "Projects retrieved successfully", response, "projects")
+}
+
+// Count godoc
+// @Summary Count projects
+// @Description Count projects matching the same filters as the list endpoint
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param category_id query []int false "Category ID, repeat for items in any of several categories" collectionFormat(multi)
+// @Param category_ids query string false "Comma-separated category IDs, combined with category_id"
+// @Param featured query bool false "Featured flag"
+// @Param tag query string false "Tag slug"
+// @Param technology query string false "Technology slug"
+// @Param q query string false "Search text"
+// @Param created_after query string false "Only items created at or after this RFC3339 time"
+// @Param created_before query string false "Only items created at or before this RFC3339 time"
+// @Param published query bool false "Defaults to every status for admins and editors and to published only for everyone else. Set to false for drafts: all drafts for admins, editors and preview tokens, their own for other signed-in users"
+// @Success 200 {object} utils.Response{data=map[string]int64} "Projects counted successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/count [get]
+func (c *ProjectController) Count(ctx *gin.Context) {
+	filter, err := parseListFilter(ctx)
+	if err != nil {
+		utils.BadRequestResponse(ctx, err.Error(), nil)
+		return
+	}
+
+	count, err := c.service(ctx).CountProjects(filter)
 	if err != nil {
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
 	}
 
-	// Create response with pagination metadata
-	response := map[string]interface{}{
-		"projects": projects,
-		"metadata": map[string]interface{}{
-			"total":       total,
-			"page":        page,
-			"limit":       limit,
-			"total_pages": (total + int64(limit) - 1) / int64(limit),
-		},
+	utils.OKResponse(ctx, "Projects counted successfully", gin.H{"count": count})
+}
+
+// LastModified godoc
+// @Summary Get when the projects last changed
+// @Description Get the latest update time of any published project in the Last-Modified header, so clients can cheaply decide whether to refetch the list. Answers 304 when it is not after If-Modified-Since.
+// @Tags projects
+// @Produce json
+// @Param If-Modified-Since header string false "HTTP date of the client's copy"
+// @Success 200 {object} utils.Response{data=services.LastModifiedResponse} "Projects last modified time retrieved successfully"
+// @Success 304 "Not modified"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/last-modified [get]
+// @Router /api/projects/last-modified [head]
+func (c *ProjectController) LastModified(ctx *gin.Context) {
+	result, err := c.service(ctx).LastModifiedProjects()
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
 	}
 
-	utils.OKResponse(ctx, "Projects retrieved successfully", response)
+	lastModifiedResponse(ctx, "Projects last modified time retrieved successfully", result)
+}
+
+// ListByCategory godoc
+// @Summary List projects grouped by category
+// @Description List every project category with its most recent published projects, for a portfolio overview
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param limit query int false "Projects per category, capped at the maximum projects page size"
+// @Param skip_empty query bool false "Leave out categories without published projects"
+// @Success 200 {object} utils.Response{data=[]services.ProjectCategoryGroup} "Projects retrieved successfully"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/by-category [get]
+func (c *ProjectController) ListByCategory(ctx *gin.Context) {
+	_, limit := parsePage(ctx, c.config, "projects")
+	skipEmpty, _ := strconv.ParseBool(ctx.Query("skip_empty"))
+
+	groups, err := c.service(ctx).ListProjectsByCategory(limit, skipEmpty)
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Projects retrieved successfully", groups)
 }
 
 // Update godoc
@@ -202,6 +435,7 @@ func (c *ProjectController) List(ctx *gin.Context) {
 // @Failure 422 {object} utils.Response "Validation error"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/projects/{id} [put]
+// @Router /api/projects/{id} [patch]
 func (c *ProjectController) Update(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -216,7 +450,71 @@ func (c *ProjectController) Update(ctx *gin.Context) {
 	}
 
 	userID := middleware.GetUserID(ctx)
-	project, err := c.projectService.UpdateProject(uint(id), req, userID)
+	project, err := c.service(ctx).UpdateProject(uint(id), req, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrCategoryNotFound) {
+			utils.ValidationErrorResponse(ctx, err.Error())
+			return
+		}
+		utils.BadRequestResponse(ctx, "Failed to update project", err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Project updated successfully", project)
+}
+
+// SetFeatured godoc
+// @Summary Feature or unfeature a project
+// @Description Set only the featured flag of a project, leaving its other fields unchanged
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param body body services.ToggleRequest true "New value of the flag"
+// @Success 200 {object} utils.Response{data=services.ProjectResponse} "Project updated successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response{error=map[string]string} "Validation error"
+// @Router /api/projects/{id}/featured [patch]
+func (c *ProjectController) SetFeatured(ctx *gin.Context) {
+	id, value, ok := bindToggle(ctx, "Invalid project ID")
+	if !ok {
+		return
+	}
+
+	project, err := c.service(ctx).SetProjectFeatured(id, value, middleware.GetUserID(ctx))
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Failed to update project", err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Project updated successfully", project)
+}
+
+// SetPublished godoc
+// @Summary Publish or unpublish a project
+// @Description Set only the published flag of a project, leaving its other fields unchanged
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param body body services.ToggleRequest true "New value of the flag"
+// @Success 200 {object} utils.Response{data=services.ProjectResponse} "Project updated successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response{error=map[string]string} "Validation error"
+// @Router /api/projects/{id}/publish [patch]
+func (c *ProjectController) SetPublished(ctx *gin.Context) {
+	id, value, ok := bindToggle(ctx, "Invalid project ID")
+	if !ok {
+		return
+	}
+
+	project, err := c.service(ctx).SetProjectPublished(id, value, middleware.GetUserID(ctx))
 	if err != nil {
 		utils.BadRequestResponse(ctx, "Failed to update project", err.Error())
 		return
@@ -247,7 +545,8 @@ func (c *ProjectController) Delete(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.projectService.DeleteProject(uint(id)); err != nil {
+	userID := middleware.GetUserID(ctx)
+	if err := c.service(ctx).DeleteProject(uint(id), userID); err != nil {
 		utils.BadRequestResponse(ctx, "Failed to delete project", err.Error())
 		return
 	}
@@ -255,6 +554,78 @@ func (c *ProjectController) Delete(ctx *gin.Context) {
 	utils.NoContentResponse(ctx)
 }
 
+// Duplicate godoc
+// @Summary Duplicate a project
+// @Description Copy a project with its tags and media into a new unpublished draft owned by the current user
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 201 {object} utils.Response{data=services.ProjectResponse} "Project duplicated successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/{id}/duplicate [post]
+func (c *ProjectController) Duplicate(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid project ID", nil)
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	duplicate, err := c.service(ctx).DuplicateProject(uint(id), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrProjectNotFound) {
+			utils.NotFoundResponse(ctx, err.Error())
+		} else {
+			utils.InternalServerErrorResponse(ctx, err.Error())
+		}
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Project duplicated successfully", duplicate)
+}
+
+// ReorderFeatured godoc
+// @Summary Reorder featured projects
+// @Description Set the display order of featured projects to the order of the given ids. Featured projects left out are listed after the ordered ones.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body services.ReorderFeaturedRequest true "Featured project ids in display order"
+// @Success 200 {object} utils.Response{data=[]services.ProjectResponse} "Featured projects reordered successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/featured/reorder [put]
+func (c *ProjectController) ReorderFeatured(ctx *gin.Context) {
+	var req services.ReorderFeaturedRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	items, err := c.service(ctx).ReorderFeatured(req, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidFeaturedOrder) {
+			utils.BadRequestResponse(ctx, "Failed to reorder featured projects", err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Featured projects reordered successfully", items)
+}
+
 // AddMedia godoc
 // @Summary Add media to a project
 // @Description Add media to a project
@@ -285,8 +656,12 @@ func (c *ProjectController) AddMedia(ctx *gin.Context) {
 		return
 	}
 
-	media, err := c.projectService.AddProjectMedia(uint(id), req)
+	media, err := c.service(ctx).AddProjectMedia(uint(id), middleware.GetUserID(ctx), req)
 	if err != nil {
+		if services.IsMediaValidationError(err) {
+			utils.ValidationErrorResponse(ctx, err.Error())
+			return
+		}
 		utils.BadRequestResponse(ctx, "Failed to add media", err.Error())
 		return
 	}
@@ -294,15 +669,90 @@ func (c *ProjectController) AddMedia(ctx *gin.Context) {
 	utils.CreatedResponse(ctx, "Media added successfully", media)
 }
 
+// AddMediaBatch godoc
+// @Summary Add several media items to a project
+// @Description Add several media items to a project in one transaction. Sort orders are assigned after the existing media, and an invalid entry rejects the whole batch.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param body body services.BatchProjectMediaRequest true "Batch media request"
+// @Success 201 {object} utils.Response{data=[]services.ProjectMediaResponse} "Media added successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/{id}/media/batch [post]
+func (c *ProjectController) AddMediaBatch(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid project ID", nil)
+		return
+	}
+
+	var req services.BatchProjectMediaRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	media, err := c.service(ctx).AddProjectMediaBatch(uint(id), middleware.GetUserID(ctx), req)
+	if err != nil {
+		if services.IsMediaValidationError(err) {
+			utils.ValidationErrorResponse(ctx, err.Error())
+			return
+		}
+		utils.BadRequestResponse(ctx, "Failed to add media", err.Error())
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Media added successfully", media)
+}
+
+// ListMedia godoc
+// @Summary List project media
+// @Description List the media of a project ordered by sort order. The media of unpublished projects are only listed for admins, editors and preview tokens.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} utils.Response{data=[]services.ProjectMediaResponse} "Media retrieved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/{id}/media [get]
+func (c *ProjectController) ListMedia(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid project ID", nil)
+		return
+	}
+
+	media, err := c.service(ctx).ListProjectMedia(uint(id), canReadDrafts(ctx))
+	if err != nil {
+		if errors.Is(err, services.ErrProjectNotFound) {
+			utils.NotFoundResponse(ctx, err.Error())
+		} else {
+			utils.InternalServerErrorResponse(ctx, err.Error())
+		}
+		return
+	}
+
+	utils.OKResponse(ctx, "Media retrieved successfully", media)
+}
+
 // UpdateMedia godoc
 // @Summary Update project media
-// @Description Update project media
+// @Description Update project media. Only the fields present in the request are changed.
 // @Tags projects
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Media ID"
-// @Param body body services.ProjectMediaRequest true "Update media request"
+// @Param body body services.UpdateProjectMediaRequest true "Update media request"
 // @Success 200 {object} utils.Response{data=services.ProjectMediaResponse} "Media updated successfully"
 // @Failure 400 {object} utils.Response "Bad request"
 // @Failure 401 {object} utils.Response "Unauthorized"
@@ -311,6 +761,7 @@ func (c *ProjectController) AddMedia(ctx *gin.Context) {
 // @Failure 422 {object} utils.Response "Validation error"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /api/projects/media/{id} [put]
+// @Router /api/projects/media/{id} [patch]
 func (c *ProjectController) UpdateMedia(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -318,14 +769,18 @@ func (c *ProjectController) UpdateMedia(ctx *gin.Context) {
 		return
 	}
 
-	var req services.ProjectMediaRequest
+	var req services.UpdateProjectMediaRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		utils.ValidationErrorResponse(ctx, err.Error())
 		return
 	}
 
-	media, err := c.projectService.UpdateProjectMedia(uint(id), req)
+	media, err := c.service(ctx).UpdateProjectMedia(uint(id), middleware.GetUserID(ctx), req)
 	if err != nil {
+		if services.IsMediaValidationError(err) {
+			utils.ValidationErrorResponse(ctx, err.Error())
+			return
+		}
 		utils.BadRequestResponse(ctx, "Failed to update media", err.Error())
 		return
 	}
@@ -355,7 +810,7 @@ func (c *ProjectController) DeleteMedia(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.projectService.DeleteProjectMedia(uint(id)); err != nil {
+	if err := c.service(ctx).DeleteProjectMedia(uint(id), middleware.GetUserID(ctx)); err != nil {
 		utils.BadRequestResponse(ctx, "Failed to delete media", err.Error())
 		return
 	}
@@ -363,30 +818,295 @@ func (c *ProjectController) DeleteMedia(ctx *gin.Context) {
 	utils.NoContentResponse(ctx)
 }
 
+// GetDraft godoc
+// @Summary Get the autosaved draft of a project
+// @Description Get the unpublished draft content of a project. The draft content is null when there is none. Preview tokens may read drafts too.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 200 {object} utils.Response{data=services.DraftResponse} "Draft retrieved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/{id}/draft [get]
+func (c *ProjectController) GetDraft(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid project ID", nil)
+		return
+	}
+
+	draft, err := c.service(ctx).GetProjectDraft(uint(id))
+	if err != nil {
+		draftErrorResponse(ctx, "Failed to get draft", err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Draft retrieved successfully", draft)
+}
+
+// Autosave godoc
+// @Summary Autosave a project draft
+// @Description Save draft content for a project without changing its published content or update time
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param body body services.DraftRequest true "Draft content"
+// @Success 200 {object} utils.Response{data=services.DraftResponse} "Draft saved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/{id}/autosave [put]
+func (c *ProjectController) Autosave(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid project ID", nil)
+		return
+	}
+
+	var req services.DraftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	draft, err := c.service(ctx).AutosaveProjectDraft(uint(id), req)
+	if err != nil {
+		draftErrorResponse(ctx, "Failed to save draft", err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Draft saved successfully", draft)
+}
+
+// RegenerateSlug godoc
+// @Summary Regenerate the slug of a project
+// @Description Recompute the slug of a project from its current title, for titles changed without their slug following. The slug is unique among projects. The previous slug keeps resolving to the project.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 200 {object} utils.Response{data=services.SlugRegenerationResponse} "Slug regenerated successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/{id}/regenerate-slug [post]
+func (c *ProjectController) RegenerateSlug(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid project ID", nil)
+		return
+	}
+
+	regenerated, err := c.service(ctx).RegenerateProjectSlug(uint(id), middleware.GetUserID(ctx))
+	if err != nil {
+		slugErrorResponse(ctx, "Failed to regenerate slug", err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Slug regenerated successfully", regenerated)
+}
+
+// PublishDraft godoc
+// @Summary Publish the autosaved draft of a project
+// @Description Replace the content of a project with its autosaved draft and discard the draft
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 200 {object} utils.Response{data=services.ProjectResponse} "Draft published successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 409 {object} utils.Response "No draft to publish"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/{id}/publish-draft [post]
+func (c *ProjectController) PublishDraft(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid project ID", nil)
+		return
+	}
+
+	project, err := c.service(ctx).PublishProjectDraft(uint(id), middleware.GetUserID(ctx))
+	if err != nil {
+		draftErrorResponse(ctx, "Failed to publish draft", err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Draft published successfully", project)
+}
+
+// ListTranslations godoc
+// @Summary List project translations
+// @Description List the translations of a project ordered by locale
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 200 {object} utils.Response{data=[]services.TranslationResponse} "Translations retrieved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/{id}/translations [get]
+func (c *ProjectController) ListTranslations(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid project ID", nil)
+		return
+	}
+
+	translations, err := c.translationService.ListTranslations(models.TranslationResourceProject, uint(id))
+	if err != nil {
+		translationErrorResponse(ctx, "Failed to list translations", err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Translations retrieved successfully", translations)
+}
+
+// SaveTranslation godoc
+// @Summary Create or replace a project translation
+// @Description Create or replace the translation of a project in a locale. Empty fields fall back to the base content.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param locale path string true "Locale, such as fa"
+// @Param body body services.TranslationRequest true "Translation request"
+// @Success 200 {object} utils.Response{data=services.TranslationResponse} "Translation saved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/{id}/translations/{locale} [put]
+func (c *ProjectController) SaveTranslation(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid project ID", nil)
+		return
+	}
+
+	var req services.TranslationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	translation, err := c.translationService.SaveTranslation(models.TranslationResourceProject, uint(id), ctx.Param("locale"), req, userID)
+	if err != nil {
+		translationErrorResponse(ctx, "Failed to save translation", err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Translation saved successfully", translation)
+}
+
+// DeleteTranslation godoc
+// @Summary Delete a project translation
+// @Description Delete the translation of a project in a locale
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param locale path string true "Locale, such as fa"
+// @Success 204 {object} utils.Response "Translation deleted successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/projects/{id}/translations/{locale} [delete]
+func (c *ProjectController) DeleteTranslation(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid project ID", nil)
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	if err := c.translationService.DeleteTranslation(models.TranslationResourceProject, uint(id), ctx.Param("locale"), userID); err != nil {
+		translationErrorResponse(ctx, "Failed to delete translation", err)
+		return
+	}
+
+	utils.NoContentResponse(ctx)
+}
+
 // Routes registers project routes
 func (c *ProjectController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
 	projects := router.Group("/projects")
 	{
 		// Public routes
-		projects.GET("", c.List)
-		projects.GET("/:id", c.Get)
-		projects.GET("/slug/:slug", c.GetBySlug)
+		projects.GET("", middleware.OptionalAuth(c.config), middleware.CacheResponse(c.listCache, c.config.Cache.ListTTL), c.List)
+		projects.GET("/count", middleware.OptionalAuth(c.config), c.Count)
+		projects.GET("/last-modified", c.LastModified)
+		projects.HEAD("/last-modified", c.LastModified)
+		projects.GET("/by-category", c.ListByCategory)
+		projects.GET("/slug-available", c.SlugAvailable)
+		projects.GET("/:id", middleware.OptionalAuth(c.config), c.Get)
+		projects.GET("/slug/:slug", middleware.OptionalAuth(c.config), c.GetBySlug)
+		projects.GET("/:id/media", middleware.OptionalAuth(c.config), c.ListMedia)
 
 		// Protected routes
 		authenticated := projects.Group("")
 		authenticated.Use(authMiddleware)
 		{
+			// Drafts leave the published content alone, so saving them keeps the list cache
+			drafts := authenticated.Group("")
+			drafts.Use(middleware.RequireRole("admin", "editor"))
+			{
+				drafts.PUT("/:id/autosave", c.Autosave)
+			}
+
+			// Preview tokens may read drafts too
+			authenticated.GET("/:id/draft", middleware.RequireRoleOrScope(services.ScopePreviewRead, "admin", "editor"), c.GetDraft)
+
 			// Admin and editor routes
 			adminEditor := authenticated.Group("")
-			adminEditor.Use(middleware.RequireRole("admin", "editor"))
+			adminEditor.Use(middleware.RequireRole("admin", "editor"), middleware.InvalidateCache(c.listCache))
 			{
 				adminEditor.POST("", c.Create)
 				adminEditor.PUT("/:id", c.Update)
+				adminEditor.PATCH("/:id", c.Update)
+				adminEditor.PATCH("/:id/featured", c.SetFeatured)
+				adminEditor.PATCH("/:id/publish", c.SetPublished)
 				adminEditor.DELETE("/:id", c.Delete)
+				adminEditor.POST("/:id/duplicate", c.Duplicate)
+				adminEditor.POST("/:id/regenerate-slug", c.RegenerateSlug)
+				adminEditor.POST("/:id/publish-draft", c.PublishDraft)
+				adminEditor.PUT("/featured/reorder", c.ReorderFeatured)
+				adminEditor.GET("/:id/translations", c.ListTranslations)
+				adminEditor.PUT("/:id/translations/:locale", c.SaveTranslation)
+				adminEditor.DELETE("/:id/translations/:locale", c.DeleteTranslation)
 				adminEditor.POST("/:id/media", c.AddMedia)
+				adminEditor.POST("/:id/media/batch", c.AddMediaBatch)
 				adminEditor.PUT("/media/:id", c.UpdateMedia)
+				adminEditor.PATCH("/media/:id", c.UpdateMedia)
 				adminEditor.DELETE("/media/:id", c.DeleteMedia)
 			}
 		}
 	}
-} 
\ No newline at end of file
+}
@@ -1,109 +1,182 @@
 package controllers
 
 import (
+	"errors"
+	"mime"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/clock"
+	"zionechainapi/internal/imaging"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/storage"
+	"zionechainapi/internal/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
-	"github.com/arashdm2020/banckend-zione/internal/models"
+// Chronological orders of the resume sections, newest first. Ongoing
+// experience and education entries come before finished ones.
+const (
+	experienceOrder  = "current_job DESC, start_date DESC, id DESC"
+	certificateOrder = "issue_date DESC, id DESC"
+	publicationOrder = "publish_date DESC, id DESC"
 )
 
+// educationOrder is built from columns rather than raw SQL because current is
+// a reserved word that every dialect quotes differently.
+var educationOrder = clause.OrderBy{Columns: []clause.OrderByColumn{
+	{Column: clause.Column{Name: "current"}, Desc: true},
+	{Column: clause.Column{Name: "start_date"}, Desc: true},
+	{Column: clause.Column{Name: "id"}, Desc: true},
+}}
+
 // ResumeController handles resume-related API requests
 type ResumeController struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	Config  *configs.Config
+	Clock   clock.Clock
+	Uploads *services.UploadService
 }
 
 // NewResumeController creates a new resume controller
-func NewResumeController(db *gorm.DB) *ResumeController {
+func NewResumeController(db *gorm.DB, config *configs.Config) *ResumeController {
 	return &ResumeController{
-		DB: db,
+		DB:      db,
+		Config:  config,
+		Clock:   clock.Real{},
+		Uploads: services.NewUploadService(storage.New(config), config.Storage),
 	}
 }
 
-// Routes sets up the resume routes
-func (c *ResumeController) Routes(router *gin.RouterGroup) {
+// db returns DB with its queries bound to the request context, so they are
+// cancelled when the request times out or the client leaves
+func (c *ResumeController) db(ctx *gin.Context) *gorm.DB {
+	return c.DB.WithContext(ctx.Request.Context())
+}
+
+// Routes sets up the resume routes. Reads are public, changes are limited to
+// admins and editors so that they can be attributed to a user.
+func (c *ResumeController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
 	resumeRoutes := router.Group("/resume")
+	adminEditor := resumeRoutes.Group("")
+	adminEditor.Use(authMiddleware, middleware.RequireRole("admin", "editor"))
 	{
 		// Personal Info
 		resumeRoutes.GET("/personal", c.GetPersonalInfo)
-		resumeRoutes.POST("/personal", c.CreatePersonalInfo)
-		resumeRoutes.PUT("/personal/:id", c.UpdatePersonalInfo)
-		resumeRoutes.DELETE("/personal/:id", c.DeletePersonalInfo)
+		resumeRoutes.GET("/personal/:id", c.GetPersonalInfoByID)
+		adminEditor.POST("/personal", c.CreatePersonalInfo)
+		adminEditor.PUT("/personal/:id", c.UpdatePersonalInfo)
+		adminEditor.DELETE("/personal/:id", c.DeletePersonalInfo)
 
 		// Skills
 		resumeRoutes.GET("/skills", c.GetSkills)
-		resumeRoutes.POST("/skills", c.CreateSkill)
-		resumeRoutes.PUT("/skills/:id", c.UpdateSkill)
-		resumeRoutes.DELETE("/skills/:id", c.DeleteSkill)
+		resumeRoutes.GET("/skills/summary", c.GetSkillsSummary)
+		resumeRoutes.GET("/skills/categories", c.GetSkillCategories)
+		resumeRoutes.GET("/skills/:id", c.GetSkill)
+		adminEditor.POST("/skills", c.CreateSkill)
+		adminEditor.PUT("/skills/:id", c.UpdateSkill)
+		adminEditor.DELETE("/skills/:id", c.DeleteSkill)
 
 		// Experience
 		resumeRoutes.GET("/experience", c.GetExperiences)
-		resumeRoutes.POST("/experience", c.CreateExperience)
-		resumeRoutes.PUT("/experience/:id", c.UpdateExperience)
-		resumeRoutes.DELETE("/experience/:id", c.DeleteExperience)
+		resumeRoutes.GET("/experience/:id", c.GetExperience)
+		adminEditor.POST("/experience", c.CreateExperience)
+		adminEditor.PUT("/experience/:id", c.UpdateExperience)
+		adminEditor.DELETE("/experience/:id", c.DeleteExperience)
 
 		// Education
 		resumeRoutes.GET("/education", c.GetEducations)
-		resumeRoutes.POST("/education", c.CreateEducation)
-		resumeRoutes.PUT("/education/:id", c.UpdateEducation)
-		resumeRoutes.DELETE("/education/:id", c.DeleteEducation)
+		resumeRoutes.GET("/education/:id", c.GetEducation)
+		adminEditor.POST("/education", c.CreateEducation)
+		adminEditor.PUT("/education/:id", c.UpdateEducation)
+		adminEditor.DELETE("/education/:id", c.DeleteEducation)
 
 		// Projects
 		resumeRoutes.GET("/projects", c.GetProjects)
-		resumeRoutes.POST("/projects", c.CreateProject)
-		resumeRoutes.PUT("/projects/:id", c.UpdateProject)
-		resumeRoutes.DELETE("/projects/:id", c.DeleteProject)
+		resumeRoutes.GET("/projects/:id", c.GetProject)
+		adminEditor.POST("/projects", c.CreateProject)
+		adminEditor.PUT("/projects/:id", c.UpdateProject)
+		adminEditor.DELETE("/projects/:id", c.DeleteProject)
 
 		// Certificates
 		resumeRoutes.GET("/certificates", c.GetCertificates)
-		resumeRoutes.POST("/certificates", c.CreateCertificate)
-		resumeRoutes.PUT("/certificates/:id", c.UpdateCertificate)
-		resumeRoutes.DELETE("/certificates/:id", c.DeleteCertificate)
+		resumeRoutes.GET("/certificates/:id", c.GetCertificate)
+		adminEditor.POST("/certificates", c.CreateCertificate)
+		adminEditor.PUT("/certificates/:id", c.UpdateCertificate)
+		adminEditor.DELETE("/certificates/:id", c.DeleteCertificate)
 
 		// Languages
 		resumeRoutes.GET("/languages", c.GetLanguages)
-		resumeRoutes.POST("/languages", c.CreateLanguage)
-		resumeRoutes.PUT("/languages/:id", c.UpdateLanguage)
-		resumeRoutes.DELETE("/languages/:id", c.DeleteLanguage)
+		resumeRoutes.GET("/languages/:id", c.GetLanguage)
+		adminEditor.POST("/languages", c.CreateLanguage)
+		adminEditor.PUT("/languages/:id", c.UpdateLanguage)
+		adminEditor.DELETE("/languages/:id", c.DeleteLanguage)
 
 		// Publications
 		resumeRoutes.GET("/publications", c.GetPublications)
-		resumeRoutes.POST("/publications", c.CreatePublication)
-		resumeRoutes.PUT("/publications/:id", c.UpdatePublication)
-		resumeRoutes.DELETE("/publications/:id", c.DeletePublication)
+		resumeRoutes.GET("/publications/:id", c.GetPublication)
+		adminEditor.POST("/publications", c.CreatePublication)
+		adminEditor.PUT("/publications/:id", c.UpdatePublication)
+		adminEditor.DELETE("/publications/:id", c.DeletePublication)
+
+		// Trash of soft-deleted entries
+		for _, section := range resumeSections {
+			adminEditor.GET("/"+section.name+"/trash", c.listTrash(section))
+			adminEditor.POST("/"+section.name+"/:id/restore", c.restoreFromTrash(section))
+			adminEditor.DELETE("/"+section.name+"/:id/purge", c.purgeFromTrash(section))
+		}
 
 		// Complete Resume
 		resumeRoutes.GET("/complete", c.GetCompleteResume)
+
+		// Header with the personal info and section sizes
+		resumeRoutes.GET("/overview", c.GetOverview)
+	}
+}
+
+// UploadRoutes registers the resume routes that take multipart bodies, outside the JSON-only API group
+func (c *ResumeController) UploadRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	uploads := router.Group("/resume")
+	uploads.Use(authMiddleware, middleware.RequireRole("admin", "editor"), middleware.RequireContentType("multipart/form-data"))
+	{
+		uploads.POST("/personal/:id/avatar", c.UploadAvatar)
 	}
 }
 
 // GetCompleteResume returns all resume sections
 func (c *ResumeController) GetCompleteResume(ctx *gin.Context) {
-	var personalInfo []models.PersonalInfo
-	var skills []models.Skill
-	var experiences []models.Experience
-	var educations []models.Education
-	var projects []models.Project
-	var certificates []models.Certificate
-	var languages []models.Language
-	var publications []models.Publication
-
-	c.DB.Find(&personalInfo)
-	c.DB.Find(&skills)
-	c.DB.Find(&experiences)
-	c.DB.Find(&educations)
-	c.DB.Find(&projects)
-	c.DB.Find(&certificates)
-	c.DB.Find(&languages)
-	c.DB.Find(&publications)
-
+	personalInfo := make([]models.PersonalInfo, 0)
+	skills := make([]models.Skill, 0)
+	experiences := make([]models.Experience, 0)
+	educations := make([]models.Education, 0)
+	projects := make([]models.ResumeProject, 0)
+	certificates := make([]models.Certificate, 0)
+	languages := make([]models.Language, 0)
+	publications := make([]models.Publication, 0)
+
+	c.db(ctx).Find(&personalInfo)
+	c.db(ctx).Find(&skills)
+	c.db(ctx).Order(experienceOrder).Find(&experiences)
+	c.db(ctx).Clauses(educationOrder).Find(&educations)
+	c.db(ctx).Find(&projects)
+	c.db(ctx).Order(certificateOrder).Find(&certificates)
+	c.db(ctx).Find(&languages)
+	c.db(ctx).Order(publicationOrder).Find(&publications)
+
+	now := c.Clock.Now()
 	response := gin.H{
 		"personal_info": personalInfo,
 		"skills":        skills,
-		"experience":    experiences,
-		"education":     educations,
+		"experience":    newExperienceResponses(experiences, now),
+		"education":     newEducationResponses(educations, now),
 		"projects":      projects,
 		"certificates":  certificates,
 		"languages":     languages,
@@ -113,13 +186,63 @@ func (c *ResumeController) GetCompleteResume(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, response)
 }
 
+// ResumeOverview is the personal info of the resume with the number of entries
+// in each of the other sections, keyed by section
+type ResumeOverview struct {
+	PersonalInfo models.PersonalInfo `json:"personal_info"`
+	Counts       map[string]int64    `json:"counts"`
+}
+
+// GetOverview returns the most recently created personal info with the entry
+// counts of the other sections
+func (c *ResumeController) GetOverview(ctx *gin.Context) {
+	var overview ResumeOverview
+	if err := c.db(ctx).Order("created_at DESC, id DESC").First(&overview.PersonalInfo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	overview.Counts = make(map[string]int64, len(resumeSections)-1)
+	for _, section := range resumeSections {
+		if section.name == "personal" {
+			continue
+		}
+
+		var count int64
+		if err := c.db(ctx).Model(section.newItem()).Count(&count).Error; err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		overview.Counts[section.name] = count
+	}
+
+	ctx.JSON(http.StatusOK, overview)
+}
+
 // Personal Info controller methods
 func (c *ResumeController) GetPersonalInfo(ctx *gin.Context) {
-	var personalInfo []models.PersonalInfo
-	c.DB.Find(&personalInfo)
+	personalInfo := make([]models.PersonalInfo, 0)
+	c.db(ctx).Find(&personalInfo)
 	ctx.JSON(http.StatusOK, personalInfo)
 }
 
+func (c *ResumeController) GetPersonalInfoByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var personalInfo models.PersonalInfo
+	if err := c.db(ctx).First(&personalInfo, id).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, personalInfo)
+}
+
+// CreatePersonalInfo creates the personal info of the resume. A resume has one
+// person, so once it exists it is updated instead and creating another is a conflict.
 func (c *ResumeController) CreatePersonalInfo(ctx *gin.Context) {
 	var input models.PersonalInfo
 	if err := ctx.ShouldBindJSON(&input); err != nil {
@@ -127,14 +250,39 @@ func (c *ResumeController) CreatePersonalInfo(ctx *gin.Context) {
 		return
 	}
 
-	c.DB.Create(&input)
+	if c.rejectSecondPersonalInfo(ctx) {
+		return
+	}
+
+	input.CreatedBy = middleware.GetUserID(ctx)
+	input.UpdatedBy = input.CreatedBy
+	c.db(ctx).Create(&input)
 	ctx.JSON(http.StatusCreated, input)
 }
 
+// rejectSecondPersonalInfo responds with a conflict naming the existing personal
+// info and reports true when the resume already has one
+func (c *ResumeController) rejectSecondPersonalInfo(ctx *gin.Context) bool {
+	var existing models.PersonalInfo
+	err := c.db(ctx).Order("created_at DESC, id DESC").First(&existing).Error
+	if err == nil {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error": "Personal info already exists, update it with PUT /api/resume/personal/:id",
+			"id":    existing.ID,
+		})
+		return true
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return true
+	}
+	return false
+}
+
 func (c *ResumeController) UpdatePersonalInfo(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var personalInfo models.PersonalInfo
-	if err := c.DB.First(&personalInfo, id).Error; err != nil {
+	if err := c.db(ctx).First(&personalInfo, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
@@ -145,29 +293,150 @@ func (c *ResumeController) UpdatePersonalInfo(ctx *gin.Context) {
 		return
 	}
 
-	c.DB.Model(&personalInfo).Updates(input)
+	// The creator never changes
+	input.CreatedBy = 0
+	input.UpdatedBy = middleware.GetUserID(ctx)
+	c.db(ctx).Model(&personalInfo).Updates(input)
 	ctx.JSON(http.StatusOK, personalInfo)
 }
 
 func (c *ResumeController) DeletePersonalInfo(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var personalInfo models.PersonalInfo
-	if err := c.DB.First(&personalInfo, id).Error; err != nil {
+	if err := c.db(ctx).First(&personalInfo, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
 
-	c.DB.Delete(&personalInfo)
+	c.db(ctx).Delete(&personalInfo)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
 }
 
+// UploadAvatar stores an uploaded image as the profile image of personal info
+// and removes the previously uploaded one. Images are checked like any other
+// upload, and files that are not images are rejected.
+func (c *ResumeController) UploadAvatar(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var personalInfo models.PersonalInfo
+	if err := c.db(ctx).First(&personalInfo, id).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		return
+	}
+
+	if maxSize := c.Uploads.MaxSize(); maxSize > 0 {
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize+multipartOverhead)
+	}
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrUploadTooLarge.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "A file is required"})
+		return
+	}
+
+	// The upload checks that files named as images really are images
+	if !imaging.IsImage(mime.TypeByExtension(strings.ToLower(filepath.Ext(header.Filename)))) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Avatar must be an image"})
+		return
+	}
+
+	upload, err := c.Uploads.Upload(header)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUploadTooLarge):
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		case services.IsUploadValidationError(err):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	previous := personalInfo.ProfileImage
+	if err := c.db(ctx).Model(&personalInfo).Updates(map[string]interface{}{
+		"profile_image": upload.URL,
+		"updated_by":    middleware.GetUserID(ctx),
+	}).Error; err != nil {
+		c.Uploads.Delete(upload.URL)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if previous != "" && previous != upload.URL {
+		c.Uploads.Delete(previous)
+	}
+	ctx.JSON(http.StatusOK, personalInfo)
+}
+
 // Skills controller methods
 func (c *ResumeController) GetSkills(ctx *gin.Context) {
-	var skills []models.Skill
-	c.DB.Find(&skills)
+	skills := make([]models.Skill, 0)
+	c.db(ctx).Find(&skills)
 	ctx.JSON(http.StatusOK, skills)
 }
 
+// SkillCategorySummary holds the skill count and average proficiency of a category
+type SkillCategorySummary struct {
+	Category           string  `json:"category" gorm:"column:category_name"`
+	Count              int64   `json:"count"`
+	AverageProficiency float64 `json:"average_proficiency"`
+}
+
+// GetSkillsSummary returns per-category skill statistics and the top skills by proficiency
+func (c *ResumeController) GetSkillsSummary(ctx *gin.Context) {
+	categories := []SkillCategorySummary{}
+	if err := c.db(ctx).Model(&models.Skill{}).
+		Select("COALESCE(NULLIF(TRIM(category), ''), 'Other') AS category_name, COUNT(*) AS count, AVG(proficiency) AS average_proficiency").
+		Group("category_name").
+		Order("category_name ASC").
+		Scan(&categories).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	topSkills := []models.Skill{}
+	if err := c.db(ctx).Order("proficiency DESC, name ASC").Limit(5).Find(&topSkills).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"categories": categories,
+		"top_skills": topSkills,
+	})
+}
+
+// GetSkillCategories returns the distinct non-empty skill categories in alphabetical order
+func (c *ResumeController) GetSkillCategories(ctx *gin.Context) {
+	categories := []string{}
+	if err := c.db(ctx).Model(&models.Skill{}).
+		Distinct().
+		Where("TRIM(category) <> ''").
+		Order("category ASC").
+		Pluck("category", &categories).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, categories)
+}
+
+func (c *ResumeController) GetSkill(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var skill models.Skill
+	if err := c.db(ctx).First(&skill, id).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, skill)
+}
+
 func (c *ResumeController) CreateSkill(ctx *gin.Context) {
 	var input models.Skill
 	if err := ctx.ShouldBindJSON(&input); err != nil {
@@ -175,14 +444,16 @@ func (c *ResumeController) CreateSkill(ctx *gin.Context) {
 		return
 	}
 
-	c.DB.Create(&input)
+	input.CreatedBy = middleware.GetUserID(ctx)
+	input.UpdatedBy = input.CreatedBy
+	c.db(ctx).Create(&input)
 	ctx.JSON(http.StatusCreated, input)
 }
 
 func (c *ResumeController) UpdateSkill(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var skill models.Skill
-	if err := c.DB.First(&skill, id).Error; err != nil {
+	if err := c.db(ctx).First(&skill, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
@@ -193,27 +464,70 @@ func (c *ResumeController) UpdateSkill(ctx *gin.Context) {
 		return
 	}
 
-	c.DB.Model(&skill).Updates(input)
+	// The creator never changes
+	input.CreatedBy = 0
+	input.UpdatedBy = middleware.GetUserID(ctx)
+	c.db(ctx).Model(&skill).Updates(input)
 	ctx.JSON(http.StatusOK, skill)
 }
 
 func (c *ResumeController) DeleteSkill(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var skill models.Skill
-	if err := c.DB.First(&skill, id).Error; err != nil {
+	if err := c.db(ctx).First(&skill, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
 
-	c.DB.Delete(&skill)
+	c.db(ctx).Delete(&skill)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
 }
 
+// ExperienceResponse is an experience entry with a computed duration. The end
+// date of a current job is always null.
+type ExperienceResponse struct {
+	models.Experience
+	EndDate  *time.Time `json:"end_date"`
+	Duration string     `json:"duration"`
+}
+
+// NewExperienceResponse maps an experience entry, measuring ongoing entries up to now
+func NewExperienceResponse(experience models.Experience, now time.Time) ExperienceResponse {
+	response := ExperienceResponse{Experience: experience}
+	end := now
+	if !experience.CurrentJob && experience.EndDate != nil {
+		response.EndDate = experience.EndDate
+		end = *experience.EndDate
+	}
+	response.Duration = utils.FormatSpan(experience.StartDate, end)
+	return response
+}
+
+// newExperienceResponses maps experience entries, measuring ongoing entries up to now
+func newExperienceResponses(experiences []models.Experience, now time.Time) []ExperienceResponse {
+	response := make([]ExperienceResponse, 0, len(experiences))
+	for _, experience := range experiences {
+		response = append(response, NewExperienceResponse(experience, now))
+	}
+	return response
+}
+
 // Experience controller methods
 func (c *ResumeController) GetExperiences(ctx *gin.Context) {
-	var experiences []models.Experience
-	c.DB.Find(&experiences)
-	ctx.JSON(http.StatusOK, experiences)
+	experiences := make([]models.Experience, 0)
+	c.db(ctx).Order(experienceOrder).Find(&experiences)
+	ctx.JSON(http.StatusOK, newExperienceResponses(experiences, c.Clock.Now()))
+}
+
+func (c *ResumeController) GetExperience(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var experience models.Experience
+	if err := c.db(ctx).First(&experience, id).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, NewExperienceResponse(experience, c.Clock.Now()))
 }
 
 func (c *ResumeController) CreateExperience(ctx *gin.Context) {
@@ -223,14 +537,16 @@ func (c *ResumeController) CreateExperience(ctx *gin.Context) {
 		return
 	}
 
-	c.DB.Create(&input)
-	ctx.JSON(http.StatusCreated, input)
+	input.CreatedBy = middleware.GetUserID(ctx)
+	input.UpdatedBy = input.CreatedBy
+	c.db(ctx).Create(&input)
+	ctx.JSON(http.StatusCreated, NewExperienceResponse(input, c.Clock.Now()))
 }
 
 func (c *ResumeController) UpdateExperience(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var experience models.Experience
-	if err := c.DB.First(&experience, id).Error; err != nil {
+	if err := c.db(ctx).First(&experience, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
@@ -241,27 +557,70 @@ func (c *ResumeController) UpdateExperience(ctx *gin.Context) {
 		return
 	}
 
-	c.DB.Model(&experience).Updates(input)
-	ctx.JSON(http.StatusOK, experience)
+	// The creator never changes
+	input.CreatedBy = 0
+	input.UpdatedBy = middleware.GetUserID(ctx)
+	c.db(ctx).Model(&experience).Updates(input)
+	ctx.JSON(http.StatusOK, NewExperienceResponse(experience, c.Clock.Now()))
 }
 
 func (c *ResumeController) DeleteExperience(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var experience models.Experience
-	if err := c.DB.First(&experience, id).Error; err != nil {
+	if err := c.db(ctx).First(&experience, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
 
-	c.DB.Delete(&experience)
+	c.db(ctx).Delete(&experience)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
 }
 
+// EducationResponse is an education entry with a computed duration. The end
+// date of current studies is always null.
+type EducationResponse struct {
+	models.Education
+	EndDate  *time.Time `json:"end_date"`
+	Duration string     `json:"duration"`
+}
+
+// NewEducationResponse maps an education entry, measuring ongoing entries up to now
+func NewEducationResponse(education models.Education, now time.Time) EducationResponse {
+	response := EducationResponse{Education: education}
+	end := now
+	if !education.Current && education.EndDate != nil {
+		response.EndDate = education.EndDate
+		end = *education.EndDate
+	}
+	response.Duration = utils.FormatSpan(education.StartDate, end)
+	return response
+}
+
+// newEducationResponses maps education entries, measuring ongoing entries up to now
+func newEducationResponses(educations []models.Education, now time.Time) []EducationResponse {
+	response := make([]EducationResponse, 0, len(educations))
+	for _, education := range educations {
+		response = append(response, NewEducationResponse(education, now))
+	}
+	return response
+}
+
 // Education controller methods
 func (c *ResumeController) GetEducations(ctx *gin.Context) {
-	var educations []models.Education
-	c.DB.Find(&educations)
-	ctx.JSON(http.StatusOK, educations)
+	educations := make([]models.Education, 0)
+	c.db(ctx).Clauses(educationOrder).Find(&educations)
+	ctx.JSON(http.StatusOK, newEducationResponses(educations, c.Clock.Now()))
+}
+
+func (c *ResumeController) GetEducation(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var education models.Education
+	if err := c.db(ctx).First(&education, id).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, NewEducationResponse(education, c.Clock.Now()))
 }
 
 func (c *ResumeController) CreateEducation(ctx *gin.Context) {
@@ -271,14 +630,16 @@ func (c *ResumeController) CreateEducation(ctx *gin.Context) {
 		return
 	}
 
-	c.DB.Create(&input)
-	ctx.JSON(http.StatusCreated, input)
+	input.CreatedBy = middleware.GetUserID(ctx)
+	input.UpdatedBy = input.CreatedBy
+	c.db(ctx).Create(&input)
+	ctx.JSON(http.StatusCreated, NewEducationResponse(input, c.Clock.Now()))
 }
 
 func (c *ResumeController) UpdateEducation(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var education models.Education
-	if err := c.DB.First(&education, id).Error; err != nil {
+	if err := c.db(ctx).First(&education, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
@@ -289,77 +650,107 @@ func (c *ResumeController) UpdateEducation(ctx *gin.Context) {
 		return
 	}
 
-	c.DB.Model(&education).Updates(input)
-	ctx.JSON(http.StatusOK, education)
+	// The creator never changes
+	input.CreatedBy = 0
+	input.UpdatedBy = middleware.GetUserID(ctx)
+	c.db(ctx).Model(&education).Updates(input)
+	ctx.JSON(http.StatusOK, NewEducationResponse(education, c.Clock.Now()))
 }
 
 func (c *ResumeController) DeleteEducation(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var education models.Education
-	if err := c.DB.First(&education, id).Error; err != nil {
+	if err := c.db(ctx).First(&education, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
 
-	c.DB.Delete(&education)
+	c.db(ctx).Delete(&education)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
 }
 
 // Project controller methods
 func (c *ResumeController) GetProjects(ctx *gin.Context) {
-	var projects []models.Project
-	c.DB.Find(&projects)
+	projects := make([]models.ResumeProject, 0)
+	c.db(ctx).Find(&projects)
 	ctx.JSON(http.StatusOK, projects)
 }
 
+func (c *ResumeController) GetProject(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var project models.ResumeProject
+	if err := c.db(ctx).First(&project, id).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, project)
+}
+
 func (c *ResumeController) CreateProject(ctx *gin.Context) {
-	var input models.Project
+	var input models.ResumeProject
 	if err := ctx.ShouldBindJSON(&input); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.DB.Create(&input)
+	input.CreatedBy = middleware.GetUserID(ctx)
+	input.UpdatedBy = input.CreatedBy
+	c.db(ctx).Create(&input)
 	ctx.JSON(http.StatusCreated, input)
 }
 
 func (c *ResumeController) UpdateProject(ctx *gin.Context) {
 	id := ctx.Param("id")
-	var project models.Project
-	if err := c.DB.First(&project, id).Error; err != nil {
+	var project models.ResumeProject
+	if err := c.db(ctx).First(&project, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
 
-	var input models.Project
+	var input models.ResumeProject
 	if err := ctx.ShouldBindJSON(&input); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.DB.Model(&project).Updates(input)
+	// The creator never changes
+	input.CreatedBy = 0
+	input.UpdatedBy = middleware.GetUserID(ctx)
+	c.db(ctx).Model(&project).Updates(input)
 	ctx.JSON(http.StatusOK, project)
 }
 
 func (c *ResumeController) DeleteProject(ctx *gin.Context) {
 	id := ctx.Param("id")
-	var project models.Project
-	if err := c.DB.First(&project, id).Error; err != nil {
+	var project models.ResumeProject
+	if err := c.db(ctx).First(&project, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
 
-	c.DB.Delete(&project)
+	c.db(ctx).Delete(&project)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
 }
 
 // Certificate controller methods
 func (c *ResumeController) GetCertificates(ctx *gin.Context) {
-	var certificates []models.Certificate
-	c.DB.Find(&certificates)
+	certificates := make([]models.Certificate, 0)
+	c.db(ctx).Order(certificateOrder).Find(&certificates)
 	ctx.JSON(http.StatusOK, certificates)
 }
 
+func (c *ResumeController) GetCertificate(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var certificate models.Certificate
+	if err := c.db(ctx).First(&certificate, id).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, certificate)
+}
+
 func (c *ResumeController) CreateCertificate(ctx *gin.Context) {
 	var input models.Certificate
 	if err := ctx.ShouldBindJSON(&input); err != nil {
@@ -367,14 +758,16 @@ func (c *ResumeController) CreateCertificate(ctx *gin.Context) {
 		return
 	}
 
-	c.DB.Create(&input)
+	input.CreatedBy = middleware.GetUserID(ctx)
+	input.UpdatedBy = input.CreatedBy
+	c.db(ctx).Create(&input)
 	ctx.JSON(http.StatusCreated, input)
 }
 
 func (c *ResumeController) UpdateCertificate(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var certificate models.Certificate
-	if err := c.DB.First(&certificate, id).Error; err != nil {
+	if err := c.db(ctx).First(&certificate, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
@@ -385,29 +778,43 @@ func (c *ResumeController) UpdateCertificate(ctx *gin.Context) {
 		return
 	}
 
-	c.DB.Model(&certificate).Updates(input)
+	// The creator never changes
+	input.CreatedBy = 0
+	input.UpdatedBy = middleware.GetUserID(ctx)
+	c.db(ctx).Model(&certificate).Updates(input)
 	ctx.JSON(http.StatusOK, certificate)
 }
 
 func (c *ResumeController) DeleteCertificate(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var certificate models.Certificate
-	if err := c.DB.First(&certificate, id).Error; err != nil {
+	if err := c.db(ctx).First(&certificate, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
 
-	c.DB.Delete(&certificate)
+	c.db(ctx).Delete(&certificate)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
 }
 
 // Language controller methods
 func (c *ResumeController) GetLanguages(ctx *gin.Context) {
-	var languages []models.Language
-	c.DB.Find(&languages)
+	languages := make([]models.Language, 0)
+	c.db(ctx).Find(&languages)
 	ctx.JSON(http.StatusOK, languages)
 }
 
+func (c *ResumeController) GetLanguage(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var language models.Language
+	if err := c.db(ctx).First(&language, id).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, language)
+}
+
 func (c *ResumeController) CreateLanguage(ctx *gin.Context) {
 	var input models.Language
 	if err := ctx.ShouldBindJSON(&input); err != nil {
@@ -415,14 +822,16 @@ func (c *ResumeController) CreateLanguage(ctx *gin.Context) {
 		return
 	}
 
-	c.DB.Create(&input)
+	input.CreatedBy = middleware.GetUserID(ctx)
+	input.UpdatedBy = input.CreatedBy
+	c.db(ctx).Create(&input)
 	ctx.JSON(http.StatusCreated, input)
 }
 
 func (c *ResumeController) UpdateLanguage(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var language models.Language
-	if err := c.DB.First(&language, id).Error; err != nil {
+	if err := c.db(ctx).First(&language, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
@@ -433,27 +842,80 @@ func (c *ResumeController) UpdateLanguage(ctx *gin.Context) {
 		return
 	}
 
-	c.DB.Model(&language).Updates(input)
+	// The creator never changes
+	input.CreatedBy = 0
+	input.UpdatedBy = middleware.GetUserID(ctx)
+	c.db(ctx).Model(&language).Updates(input)
 	ctx.JSON(http.StatusOK, language)
 }
 
 func (c *ResumeController) DeleteLanguage(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var language models.Language
-	if err := c.DB.First(&language, id).Error; err != nil {
+	if err := c.db(ctx).First(&language, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
 
-	c.DB.Delete(&language)
+	c.db(ctx).Delete(&language)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
 }
 
 // Publication controller methods
+// GetPublications returns a page of publications newest first, optionally
+// narrowed to the year they were published and to authors containing the
+// author query, with pagination metadata like the other paginated lists.
 func (c *ResumeController) GetPublications(ctx *gin.Context) {
-	var publications []models.Publication
-	c.DB.Find(&publications)
-	ctx.JSON(http.StatusOK, publications)
+	query := c.db(ctx).Model(&models.Publication{})
+	if yearStr := ctx.Query("year"); yearStr != "" {
+		year, err := strconv.Atoi(yearStr)
+		if err != nil || year <= 0 || year > 9999 {
+			utils.BadRequestResponse(ctx, "year must be a year between 1 and 9999", nil)
+			return
+		}
+
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		query = query.Where("publish_date >= ? AND publish_date < ?", start, start.AddDate(1, 0, 0))
+	}
+	if author := strings.TrimSpace(ctx.Query("author")); author != "" {
+		query = query.Where(services.LikeCondition("authors"), services.ContainsPattern(author))
+	}
+
+	page, limit := parsePage(ctx, c.Config, "publications")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	publications := make([]models.Publication, 0)
+	if err := query.Order(publicationOrder).Offset((page - 1) * limit).Limit(limit).Find(&publications).Error; err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Publications retrieved successfully", publicationPage{
+		Publications: publications,
+		Metadata:     services.NewPageMetadata(total, page, limit),
+	})
+}
+
+// publicationPage represents a page of publications
+type publicationPage struct {
+	Publications []models.Publication  `json:"publications"`
+	Metadata     services.PageMetadata `json:"metadata"`
+}
+
+func (c *ResumeController) GetPublication(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var publication models.Publication
+	if err := c.db(ctx).First(&publication, id).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, publication)
 }
 
 func (c *ResumeController) CreatePublication(ctx *gin.Context) {
@@ -463,14 +925,16 @@ func (c *ResumeController) CreatePublication(ctx *gin.Context) {
 		return
 	}
 
-	c.DB.Create(&input)
+	input.CreatedBy = middleware.GetUserID(ctx)
+	input.UpdatedBy = input.CreatedBy
+	c.db(ctx).Create(&input)
 	ctx.JSON(http.StatusCreated, input)
 }
 
 func (c *ResumeController) UpdatePublication(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var publication models.Publication
-	if err := c.DB.First(&publication, id).Error; err != nil {
+	if err := c.db(ctx).First(&publication, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
@@ -481,18 +945,101 @@ func (c *ResumeController) UpdatePublication(ctx *gin.Context) {
 		return
 	}
 
-	c.DB.Model(&publication).Updates(input)
+	// The creator never changes
+	input.CreatedBy = 0
+	input.UpdatedBy = middleware.GetUserID(ctx)
+	c.db(ctx).Model(&publication).Updates(input)
 	ctx.JSON(http.StatusOK, publication)
 }
 
 func (c *ResumeController) DeletePublication(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var publication models.Publication
-	if err := c.DB.First(&publication, id).Error; err != nil {
+	if err := c.db(ctx).First(&publication, id).Error; err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
 		return
 	}
 
-	c.DB.Delete(&publication)
+	c.db(ctx).Delete(&publication)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
-} 
\ No newline at end of file
+} 
+
+// resumeSection describes a soft-deleted resume section for the trash endpoints
+type resumeSection struct {
+	name    string
+	newItem func() interface{}
+	newList func() interface{}
+}
+
+// resumeSections are the resume sections by route segment
+var resumeSections = []resumeSection{
+	{"personal", func() interface{} { return &models.PersonalInfo{} }, func() interface{} { return &[]models.PersonalInfo{} }},
+	{"skills", func() interface{} { return &models.Skill{} }, func() interface{} { return &[]models.Skill{} }},
+	{"experience", func() interface{} { return &models.Experience{} }, func() interface{} { return &[]models.Experience{} }},
+	{"education", func() interface{} { return &models.Education{} }, func() interface{} { return &[]models.Education{} }},
+	{"projects", func() interface{} { return &models.ResumeProject{} }, func() interface{} { return &[]models.ResumeProject{} }},
+	{"certificates", func() interface{} { return &models.Certificate{} }, func() interface{} { return &[]models.Certificate{} }},
+	{"languages", func() interface{} { return &models.Language{} }, func() interface{} { return &[]models.Language{} }},
+	{"publications", func() interface{} { return &models.Publication{} }, func() interface{} { return &[]models.Publication{} }},
+}
+
+// listTrash returns the soft-deleted entries of a section, most recently deleted first
+func (c *ResumeController) listTrash(section resumeSection) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		items := section.newList()
+		if err := c.db(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC, id DESC").Find(items).Error; err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, items)
+	}
+}
+
+// restoreFromTrash undoes the soft delete of a section entry
+func (c *ResumeController) restoreFromTrash(section resumeSection) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		item := section.newItem()
+		if err := c.db(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(item, id).Error; err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found in trash"})
+			return
+		}
+
+		// Restoring personal info must not give the resume a second person
+		if _, ok := item.(*models.PersonalInfo); ok && c.rejectSecondPersonalInfo(ctx) {
+			return
+		}
+
+		if err := c.db(ctx).Unscoped().Model(item).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"updated_by": middleware.GetUserID(ctx),
+		}).Error; err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.db(ctx).First(item, id)
+		ctx.JSON(http.StatusOK, item)
+	}
+}
+
+// purgeFromTrash permanently deletes a soft-deleted section entry. Entries that
+// are not in the trash have to be deleted first.
+func (c *ResumeController) purgeFromTrash(section resumeSection) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.Param("id")
+		item := section.newItem()
+		if err := c.db(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(item, id).Error; err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Record not found in trash"})
+			return
+		}
+
+		if err := c.db(ctx).Unscoped().Delete(item).Error; err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Record permanently deleted"})
+	}
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/services"
+)
+
+// SitemapController serves the sitemap of published content for search engines
+type SitemapController struct {
+	config         *configs.Config
+	sitemapService *services.SitemapService
+}
+
+// NewSitemapController creates a new sitemap controller
+func NewSitemapController(config *configs.Config) *SitemapController {
+	return &SitemapController{
+		config:         config,
+		sitemapService: services.NewSitemapService(config.App.URL),
+	}
+}
+
+// Sitemap godoc
+// @Summary Get the sitemap
+// @Description Get a sitemap of published projects and blog posts and of the categories they belong to
+// @Tags sitemap
+// @Produce xml
+// @Success 200 {object} services.SitemapURLSet "Sitemap"
+// @Failure 500 {string} string "Internal server error"
+// @Router /sitemap.xml [get]
+func (c *SitemapController) Sitemap(ctx *gin.Context) {
+	urlSet, err := c.sitemapService.BuildSitemap()
+	if err != nil {
+		ctx.String(http.StatusInternalServerError, "Failed to build sitemap")
+		return
+	}
+
+	body, err := xml.Marshal(urlSet)
+	if err != nil {
+		ctx.String(http.StatusInternalServerError, "Failed to build sitemap")
+		return
+	}
+
+	ctx.Data(http.StatusOK, "application/xml; charset=utf-8", append([]byte(xml.Header), body...))
+}
+
+// Routes registers the sitemap route
+func (c *SitemapController) Routes(router gin.IRoutes) {
+	router.GET("/sitemap.xml", c.Sitemap)
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// redirectToCurrentSlug answers a request made with a previous slug with a
+// permanent redirect to the same route under the current slug, keeping the
+// query string. It reports whether it responded.
+func redirectToCurrentSlug(ctx *gin.Context, current string) bool {
+	if ctx.Param("slug") == current {
+		return false
+	}
+
+	location := strings.Replace(ctx.FullPath(), ":slug", url.PathEscape(current), 1)
+	if ctx.Request.URL.RawQuery != "" {
+		location += "?" + ctx.Request.URL.RawQuery
+	}
+
+	ctx.Header("Location", location)
+	utils.SuccessResponse(ctx, http.StatusMovedPermanently, "Moved to the current slug", services.SlugRedirectResponse{
+		Slug:      current,
+		Canonical: location,
+	})
+	return true
+}
+
+// slugErrorResponse maps the errors of regenerating a slug to responses
+func slugErrorResponse(ctx *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrSlugResourceNotFound):
+		utils.NotFoundResponse(ctx, err.Error())
+	case errors.Is(err, services.ErrSlugTitleInvalid):
+		utils.BadRequestResponse(ctx, message, err.Error())
+	default:
+		utils.InternalServerErrorResponse(ctx, err.Error())
+	}
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// StatsController handles portfolio statistics routes
+type StatsController struct {
+	config       *configs.Config
+	statsService *services.StatsService
+}
+
+// NewStatsController creates a new stats controller
+func NewStatsController(config *configs.Config) *StatsController {
+	return &StatsController{
+		config:       config,
+		statsService: services.NewStatsService(),
+	}
+}
+
+// Get godoc
+// @Summary Get portfolio statistics
+// @Description Get published and draft counts of projects and blog posts, and totals of categories, tags and media
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=services.StatsResponse} "Stats retrieved successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/stats [get]
+func (c *StatsController) Get(ctx *gin.Context) {
+	stats, err := c.statsService.WithContext(ctx.Request.Context()).GetStats()
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Stats retrieved successfully", stats)
+}
+
+// Routes registers stats routes
+func (c *StatsController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	stats := router.Group("/stats")
+	stats.Use(authMiddleware, middleware.RequireRole("admin"))
+	{
+		stats.GET("", c.Get)
+	}
+}
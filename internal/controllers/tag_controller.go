@@ -1 +1,329 @@
- 
\ No newline at end of file
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// TagController handles tag-related routes
+type TagController struct {
+	config     *configs.Config
+	tagService *services.TagService
+}
+
+// NewTagController creates a new tag controller
+func NewTagController(config *configs.Config) *TagController {
+	return &TagController{
+		config:     config,
+		tagService: services.NewTagService(),
+	}
+}
+
+// service returns the tag service with its queries bound to the request
+// context, so they are cancelled when the request times out or the client leaves
+func (c *TagController) service(ctx *gin.Context) *services.TagService {
+	return c.tagService.WithContext(ctx.Request.Context())
+}
+
+// Create godoc
+// @Summary Create a new tag
+// @Description Create a new tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body services.TagRequest true "Create tag request"
+// @Success 201 {object} utils.Response{data=services.TagResponse} "Tag created successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/tags [post]
+func (c *TagController) Create(ctx *gin.Context) {
+	var req services.TagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	tag, err := c.service(ctx).CreateTag(req, userID)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Failed to create tag", err.Error())
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Tag created successfully", tag)
+}
+
+// List godoc
+// @Summary List all tags
+// @Description List all tags
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]services.TagResponse} "Tags retrieved successfully"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/tags [get]
+func (c *TagController) List(ctx *gin.Context) {
+	tags, err := c.service(ctx).ListTags()
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Tags retrieved successfully", tags)
+}
+
+// Get godoc
+// @Summary Get a tag by ID
+// @Description Get a tag by ID
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} utils.Response{data=services.TagResponse} "Tag retrieved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/tags/{id} [get]
+func (c *TagController) Get(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid tag ID", nil)
+		return
+	}
+
+	tag, err := c.service(ctx).GetTagByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Tag retrieved successfully", tag)
+}
+
+// Update godoc
+// @Summary Update a tag
+// @Description Update a tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tag ID"
+// @Param body body services.TagRequest true "Update tag request"
+// @Success 200 {object} utils.Response{data=services.TagResponse} "Tag updated successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/tags/{id} [put]
+func (c *TagController) Update(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid tag ID", nil)
+		return
+	}
+
+	var req services.TagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	tag, err := c.service(ctx).UpdateTag(uint(id), req, userID)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Failed to update tag", err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Tag updated successfully", tag)
+}
+
+// Delete godoc
+// @Summary Delete a tag
+// @Description Delete a tag and remove it from all projects and blog posts
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tag ID"
+// @Success 204 {object} utils.Response "Tag deleted successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/tags/{id} [delete]
+func (c *TagController) Delete(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid tag ID", nil)
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	if err := c.service(ctx).DeleteTag(uint(id), userID); err != nil {
+		utils.BadRequestResponse(ctx, "Failed to delete tag", err.Error())
+		return
+	}
+
+	utils.NoContentResponse(ctx)
+}
+
+// Merge godoc
+// @Summary Merge a tag into another tag
+// @Description Move all project and blog post associations of a tag to the target tag, then delete the source tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Source tag ID"
+// @Param body body services.MergeTagRequest true "Merge tag request"
+// @Success 200 {object} utils.Response{data=services.MergeTagResponse} "Tag merged successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/tags/{id}/merge [post]
+func (c *TagController) Merge(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid tag ID", nil)
+		return
+	}
+
+	var req services.MergeTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	result, err := c.service(ctx).MergeTag(uint(id), req, userID)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Failed to merge tag", err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Tag merged successfully", result)
+}
+
+// Assign godoc
+// @Summary Add a tag to many items
+// @Description Add a tag to the given projects and blog posts in one transaction. Items already carrying the tag are skipped and missing ids are reported.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tag ID"
+// @Param body body services.AssignTagRequest true "Projects and blog posts to tag"
+// @Success 200 {object} utils.Response{data=services.AssignTagResponse} "Tag assigned successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/tags/{id}/assign [post]
+func (c *TagController) Assign(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid tag ID", nil)
+		return
+	}
+
+	var req services.AssignTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	result, err := c.service(ctx).AssignTag(uint(id), req, userID)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Failed to assign tag", err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Tag assigned successfully", result)
+}
+
+// Unassign godoc
+// @Summary Remove a tag from many items
+// @Description Remove a tag from the given projects and blog posts in one transaction. Items that do not carry the tag are ignored.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tag ID"
+// @Param body body services.UnassignTagRequest true "Projects and blog posts to untag"
+// @Success 200 {object} utils.Response{data=services.UnassignTagResponse} "Tag unassigned successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/tags/{id}/unassign [post]
+func (c *TagController) Unassign(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid tag ID", nil)
+		return
+	}
+
+	var req services.UnassignTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	result, err := c.service(ctx).UnassignTag(uint(id), req, userID)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Failed to unassign tag", err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Tag unassigned successfully", result)
+}
+
+// Routes registers tag routes
+func (c *TagController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	tags := router.Group("/tags")
+	{
+		// Public routes
+		tags.GET("", c.List)
+		tags.GET("/:id", c.Get)
+
+		// Protected routes
+		authenticated := tags.Group("")
+		authenticated.Use(authMiddleware, invalidateLists(c.config))
+		{
+			// Admin and editor routes
+			adminEditor := authenticated.Group("")
+			adminEditor.Use(middleware.RequireRole("admin", "editor"))
+			{
+				adminEditor.POST("", c.Create)
+				adminEditor.PUT("/:id", c.Update)
+				adminEditor.DELETE("/:id", c.Delete)
+				adminEditor.POST("/:id/assign", c.Assign)
+				adminEditor.POST("/:id/unassign", c.Unassign)
+			}
+
+			// Admin only routes
+			admin := authenticated.Group("")
+			admin.Use(middleware.RequireRole("admin"))
+			{
+				admin.POST("/:id/merge", c.Merge)
+			}
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// TechnologyController handles technology-related routes
+type TechnologyController struct {
+	config            *configs.Config
+	technologyService *services.TechnologyService
+}
+
+// NewTechnologyController creates a new technology controller
+func NewTechnologyController(config *configs.Config) *TechnologyController {
+	return &TechnologyController{
+		config:            config,
+		technologyService: services.NewTechnologyService(),
+	}
+}
+
+// Create godoc
+// @Summary Create a new technology
+// @Description Create a new technology that projects can be linked to
+// @Tags technologies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body services.TechnologyRequest true "Create technology request"
+// @Success 201 {object} utils.Response{data=services.TechnologyResponse} "Technology created successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 409 {object} utils.Response "Technology already exists"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/technologies [post]
+func (c *TechnologyController) Create(ctx *gin.Context) {
+	var req services.TechnologyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	technology, err := c.technologyService.CreateTechnology(req, userID)
+	if err != nil {
+		technologyErrorResponse(ctx, err)
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Technology created successfully", technology)
+}
+
+// List godoc
+// @Summary List technologies
+// @Description List technologies ordered by name
+// @Tags technologies
+// @Accept json
+// @Produce json
+// @Param category query string false "Only technologies in this category"
+// @Success 200 {object} utils.Response{data=[]services.TechnologyResponse} "Technologies retrieved successfully"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/technologies [get]
+func (c *TechnologyController) List(ctx *gin.Context) {
+	technologies, err := c.technologyService.ListTechnologies(ctx.Query("category"))
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Technologies retrieved successfully", technologies)
+}
+
+// Get godoc
+// @Summary Get a technology by ID
+// @Description Get a technology by ID
+// @Tags technologies
+// @Accept json
+// @Produce json
+// @Param id path int true "Technology ID"
+// @Success 200 {object} utils.Response{data=services.TechnologyResponse} "Technology retrieved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/technologies/{id} [get]
+func (c *TechnologyController) Get(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid technology ID", nil)
+		return
+	}
+
+	technology, err := c.technologyService.GetTechnologyByID(uint(id))
+	if err != nil {
+		technologyErrorResponse(ctx, err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Technology retrieved successfully", technology)
+}
+
+// Update godoc
+// @Summary Update a technology
+// @Description Update a technology
+// @Tags technologies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Technology ID"
+// @Param body body services.TechnologyRequest true "Update technology request"
+// @Success 200 {object} utils.Response{data=services.TechnologyResponse} "Technology updated successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 409 {object} utils.Response "Technology already exists"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/technologies/{id} [put]
+func (c *TechnologyController) Update(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid technology ID", nil)
+		return
+	}
+
+	var req services.TechnologyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	technology, err := c.technologyService.UpdateTechnology(uint(id), req, userID)
+	if err != nil {
+		technologyErrorResponse(ctx, err)
+		return
+	}
+
+	utils.OKResponse(ctx, "Technology updated successfully", technology)
+}
+
+// Delete godoc
+// @Summary Delete a technology
+// @Description Delete a technology and remove it from all projects
+// @Tags technologies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Technology ID"
+// @Success 204 {object} utils.Response "Technology deleted successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/technologies/{id} [delete]
+func (c *TechnologyController) Delete(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid technology ID", nil)
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	if err := c.technologyService.DeleteTechnology(uint(id), userID); err != nil {
+		technologyErrorResponse(ctx, err)
+		return
+	}
+
+	utils.NoContentResponse(ctx)
+}
+
+// technologyErrorResponse maps technology service errors to responses
+func technologyErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTechnologyNotFound):
+		utils.NotFoundResponse(ctx, err.Error())
+	case errors.Is(err, services.ErrTechnologyExists):
+		utils.ConflictResponse(ctx, err.Error(), nil)
+	case errors.Is(err, services.ErrInvalidTechnologyName):
+		utils.ValidationErrorResponse(ctx, err.Error())
+	default:
+		utils.InternalServerErrorResponse(ctx, err.Error())
+	}
+}
+
+// Routes registers technology routes
+func (c *TechnologyController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	technologies := router.Group("/technologies")
+	{
+		// Public routes
+		technologies.GET("", c.List)
+		technologies.GET("/:id", c.Get)
+
+		// Admin and editor routes
+		adminEditor := technologies.Group("")
+		adminEditor.Use(authMiddleware, middleware.RequireRole("admin", "editor"), invalidateLists(c.config))
+		{
+			adminEditor.POST("", c.Create)
+			adminEditor.PUT("/:id", c.Update)
+			adminEditor.DELETE("/:id", c.Delete)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// bindToggle parses the ID in the path and the new flag value in the body of a
+// toggle request. It responds with an error and returns false when either is invalid.
+func bindToggle(ctx *gin.Context, invalidIDMessage string) (uint, bool, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, invalidIDMessage, nil)
+		return 0, false, false
+	}
+
+	var req services.ToggleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationErrorResponse(ctx, err, &req)
+		return 0, false, false
+	}
+
+	return uint(id), *req.Value, true
+}
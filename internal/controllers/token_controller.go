@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// TokenController handles minting scoped access tokens
+type TokenController struct {
+	config      *configs.Config
+	authService *services.AuthService
+}
+
+// NewTokenController creates a new token controller
+func NewTokenController(config *configs.Config) *TokenController {
+	return &TokenController{
+		config:      config,
+		authService: services.NewAuthService(config),
+	}
+}
+
+// CreateScoped godoc
+// @Summary Mint a scoped access token
+// @Description Mint an access token limited to a scope, for frontends that must not get full rights. The preview:read scope can list and read unpublished content but nothing else. Scoped tokens cannot be refreshed.
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body services.ScopedTokenRequest true "Scoped token request"
+// @Success 201 {object} utils.Response{data=services.ScopedTokenResponse} "Scoped token created successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/tokens/scoped [post]
+func (c *TokenController) CreateScoped(ctx *gin.Context) {
+	var req services.ScopedTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	token, err := c.authService.IssueScopedToken(middleware.GetUserID(ctx), req)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownScope) || errors.Is(err, services.ErrScopedTokenExpiry) {
+			utils.BadRequestResponse(ctx, err.Error(), nil)
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Scoped token created successfully", token)
+}
+
+// Routes registers token routes
+func (c *TokenController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	tokens := router.Group("/tokens")
+	tokens.Use(authMiddleware, middleware.RequireRole("admin"))
+	{
+		tokens.POST("/scoped", c.CreateScoped)
+	}
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+)
+
+// Token delivery modes of login, register and refresh
+const (
+	tokenDeliveryBody   = "body"
+	tokenDeliveryCookie = "cookie"
+	tokenDeliveryBoth   = "both"
+)
+
+// refreshCookiePath limits the refresh token cookie to the auth routes
+const refreshCookiePath = "/api/auth"
+
+// errInvalidTokenDelivery is returned for a token_delivery query parameter that is not a known mode
+var errInvalidTokenDelivery = errors.New("token_delivery must be body, cookie or both")
+
+// tokenDelivery returns the token delivery mode of a request: the token_delivery
+// query parameter when set, otherwise the configured mode
+func (c *AuthController) tokenDelivery(ctx *gin.Context) (string, error) {
+	if requested := strings.ToLower(ctx.Query("token_delivery")); requested != "" {
+		switch requested {
+		case tokenDeliveryBody, tokenDeliveryCookie, tokenDeliveryBoth:
+			return requested, nil
+		}
+		return "", errInvalidTokenDelivery
+	}
+
+	switch configured := strings.ToLower(c.config.Auth.TokenDelivery); configured {
+	case tokenDeliveryCookie, tokenDeliveryBoth:
+		return configured, nil
+	}
+	return tokenDeliveryBody, nil
+}
+
+// deliverTokens sets the issued tokens as cookies when the delivery mode asks
+// for it and returns the response body, which only keeps the tokens when they
+// are also delivered in the body
+func (c *AuthController) deliverTokens(ctx *gin.Context, delivery string, token *services.TokenResponse) *services.TokenResponse {
+	if delivery == tokenDeliveryBody {
+		return token
+	}
+
+	c.setTokenCookie(ctx, middleware.AccessTokenCookie, token.AccessToken, "/", token.ExpiresAt)
+	c.setTokenCookie(ctx, middleware.RefreshTokenCookie, token.RefreshToken, refreshCookiePath, time.Now().Add(c.config.JWT.RefreshTokenExpiry))
+
+	if delivery == tokenDeliveryCookie {
+		body := *token
+		body.AccessToken = ""
+		body.RefreshToken = ""
+		return &body
+	}
+	return token
+}
+
+// setTokenCookie sets an HttpOnly cookie holding a token until it expires
+func (c *AuthController) setTokenCookie(ctx *gin.Context, name, value, path string, expiresAt time.Time) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   c.config.Auth.CookieDomain,
+		Expires:  expiresAt,
+		MaxAge:   int(time.Until(expiresAt).Seconds()),
+		Secure:   c.config.Auth.CookieSecure,
+		HttpOnly: true,
+		SameSite: cookieSameSite(c.config.Auth.CookieSameSite),
+	})
+}
+
+// clearTokenCookies expires the token cookies set by deliverTokens. The
+// cookies are overwritten with the path and domain they were set with, as a
+// browser only replaces a cookie matching both.
+func (c *AuthController) clearTokenCookies(ctx *gin.Context) {
+	for _, cookie := range []struct{ name, path string }{
+		{middleware.AccessTokenCookie, "/"},
+		{middleware.RefreshTokenCookie, refreshCookiePath},
+	} {
+		http.SetCookie(ctx.Writer, &http.Cookie{
+			Name:     cookie.name,
+			Path:     cookie.path,
+			Domain:   c.config.Auth.CookieDomain,
+			Expires:  time.Unix(0, 0),
+			MaxAge:   -1,
+			Secure:   c.config.Auth.CookieSecure,
+			HttpOnly: true,
+			SameSite: cookieSameSite(c.config.Auth.CookieSameSite),
+		})
+	}
+}
+
+// cookieSameSite maps the configured SameSite attribute, defaulting to Lax
+func cookieSameSite(sameSite string) http.SameSite {
+	switch strings.ToLower(sameSite) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// localizeErrorResponse writes the error response for a failed ?locale= overlay
+func localizeErrorResponse(ctx *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidLocale) {
+		utils.BadRequestResponse(ctx, err.Error(), nil)
+		return
+	}
+	utils.InternalServerErrorResponse(ctx, err.Error())
+}
+
+// translationErrorResponse writes the error response for a failed translation change
+func translationErrorResponse(ctx *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidLocale):
+		utils.BadRequestResponse(ctx, message, err.Error())
+	case errors.Is(err, services.ErrTranslatedResourceNotFound), errors.Is(err, services.ErrTranslationNotFound):
+		utils.NotFoundResponse(ctx, err.Error())
+	default:
+		utils.InternalServerErrorResponse(ctx, err.Error())
+	}
+}
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/storage"
+	"zionechainapi/internal/utils"
+)
+
+// multipartOverhead is the room left for multipart headers on top of the maximum file size
+const multipartOverhead = 1 << 20
+
+// UploadController handles file upload routes
+type UploadController struct {
+	config        *configs.Config
+	uploadService *services.UploadService
+}
+
+// NewUploadController creates a new upload controller
+func NewUploadController(config *configs.Config) *UploadController {
+	return &UploadController{
+		config:        config,
+		uploadService: services.NewUploadService(storage.New(config), config.Storage),
+	}
+}
+
+// Upload godoc
+// @Summary Upload a file
+// @Description Upload a file to the storage backend and get its public URL for use as project or blog media. The extension and detected content type must both be allowed, and images must decode as their type. When STORAGE_WEBP_DERIVATIVES is enabled, PNG, JPEG and GIF images also get a WebP copy whose URL is returned as webp_url.
+// @Tags uploads
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "File to upload"
+// @Success 201 {object} utils.Response{data=services.UploadResponse} "File uploaded successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 413 {object} utils.Response "File too large"
+// @Failure 415 {object} utils.Response "Unsupported media type"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/uploads [post]
+func (c *UploadController) Upload(ctx *gin.Context) {
+	if c.config.Storage.MaxSize > 0 {
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.config.Storage.MaxSize+multipartOverhead)
+	}
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.ErrorResponse(ctx, http.StatusRequestEntityTooLarge, services.ErrUploadTooLarge.Error(), nil)
+			return
+		}
+		utils.BadRequestResponse(ctx, "A file is required", err.Error())
+		return
+	}
+
+	upload, err := c.uploadService.Upload(header)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUploadTooLarge):
+			utils.ErrorResponse(ctx, http.StatusRequestEntityTooLarge, err.Error(), nil)
+		case services.IsUploadValidationError(err):
+			utils.ValidationErrorResponse(ctx, err.Error())
+		default:
+			utils.InternalServerErrorResponse(ctx, err.Error())
+		}
+		return
+	}
+
+	utils.CreatedResponse(ctx, "File uploaded successfully", upload)
+}
+
+// CollectGarbage godoc
+// @Summary Purge orphaned media files
+// @Description Delete the stored files that no media, SEO image, resume entry or content references any more, such as files of media deleted before cascade cleanup. Files stored within the last day are kept, as media are saved after their files are uploaded. With dry_run=true the files are only reported.
+// @Tags uploads
+// @Produce json
+// @Security BearerAuth
+// @Param dry_run query bool false "Report orphaned files without deleting them"
+// @Success 200 {object} utils.Response{data=services.MediaGCResponse} "Orphaned files collected successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/media/gc [post]
+func (c *UploadController) CollectGarbage(ctx *gin.Context) {
+	dryRun := false
+	if value := ctx.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			utils.BadRequestResponse(ctx, "dry_run must be a boolean", nil)
+			return
+		}
+		dryRun = parsed
+	}
+
+	userID := middleware.GetUserID(ctx)
+	result, err := c.uploadService.CollectGarbage(dryRun, userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	message := "Orphaned files collected successfully"
+	if dryRun {
+		message = "Orphaned files found"
+	}
+	utils.OKResponse(ctx, message, result)
+}
+
+// Routes registers upload routes
+func (c *UploadController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	uploads := router.Group("/uploads")
+	uploads.Use(authMiddleware, middleware.RequireRole("admin", "editor"), middleware.RequireContentType("multipart/form-data"))
+	{
+		uploads.POST("", c.Upload)
+	}
+
+	// Purging files is left to admins
+	media := router.Group("/media")
+	media.Use(authMiddleware, middleware.RequireRole("admin"), invalidateLists(c.config))
+	{
+		media.POST("/gc", c.CollectGarbage)
+	}
+}
+
+// FileRoutes serves files kept by the local storage driver. Other drivers serve their own files.
+func (c *UploadController) FileRoutes(router gin.IRoutes) {
+	if storage.IsLocal(c.config) {
+		router.Static(storage.LocalRoute, c.config.Storage.LocalPath)
+	}
+}
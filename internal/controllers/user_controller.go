@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// UserController handles user profile routes
+type UserController struct {
+	config         *configs.Config
+	authService    *services.AuthService
+	projectService *services.ProjectService
+	blogService    *services.BlogService
+}
+
+// NewUserController creates a new user controller
+func NewUserController(config *configs.Config) *UserController {
+	return &UserController{
+		config:         config,
+		authService:    services.NewAuthService(config),
+		projectService: services.NewProjectService(),
+		blogService:    services.NewBlogService(),
+	}
+}
+
+// ListProjects godoc
+// @Summary List a user's projects
+// @Description List the published projects authored by a user. The user themselves and admins also see drafts.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size"
+// @Success 200 {object} utils.Response{data=services.ProjectListResponse} "Projects retrieved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/users/{id}/projects [get]
+func (c *UserController) ListProjects(ctx *gin.Context) {
+	filter, ok := c.authorFilter(ctx)
+	if !ok {
+		return
+	}
+
+	page, limit := parsePage(ctx, c.config, "projects")
+
+	projects, total, err := c.projectService.WithContext(ctx.Request.Context()).ListProjects(page, limit, filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	response := services.ProjectListResponse{
+		Projects: projects,
+		Metadata: services.NewPageMetadata(total, page, limit),
+	}
+
+	utils.OKResponse(ctx, "Projects retrieved successfully", response)
+}
+
+// ListBlogs godoc
+// @Summary List a user's blog posts
+// @Description List the published blog posts authored by a user. The user themselves and admins also see drafts.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size"
+// @Success 200 {object} utils.Response{data=services.BlogListResponse} "Blog posts retrieved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/users/{id}/blog [get]
+func (c *UserController) ListBlogs(ctx *gin.Context) {
+	filter, ok := c.authorFilter(ctx)
+	if !ok {
+		return
+	}
+
+	page, limit := parsePage(ctx, c.config, "blog")
+
+	blogs, total, err := c.blogService.WithContext(ctx.Request.Context()).ListBlogs(page, limit, filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	response := services.BlogListResponse{
+		Blogs:    blogs,
+		Metadata: services.NewPageMetadata(total, page, limit),
+	}
+
+	utils.OKResponse(ctx, "Blog posts retrieved successfully", response)
+}
+
+// Routes registers the user routes
+func (c *UserController) Routes(router *gin.RouterGroup, optionalAuthMiddleware gin.HandlerFunc) {
+	users := router.Group("/users")
+	users.Use(optionalAuthMiddleware)
+	{
+		users.GET("/:id/projects", c.ListProjects)
+		users.GET("/:id/blog", c.ListBlogs)
+	}
+}
+
+// authorFilter builds the listing filter for the user in the path, writing the
+// error response and returning false when the user cannot be resolved
+func (c *UserController) authorFilter(ctx *gin.Context) (services.ListFilter, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid user ID", nil)
+		return services.ListFilter{}, false
+	}
+
+	if _, err := c.authService.GetUserByID(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.NotFoundResponse(ctx, "user not found")
+			return services.ListFilter{}, false
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return services.ListFilter{}, false
+	}
+
+	filter := services.ListFilter{CreatedBy: uint(id), Published: true}
+
+	// Owners and admins also see drafts
+	if middleware.GetUserID(ctx) == uint(id) || middleware.GetUserRole(ctx) == "admin" {
+		filter.AnyStatus = true
+	}
+
+	return filter, true
+}
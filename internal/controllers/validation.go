@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"zionechainapi/internal/utils"
+)
+
+// validationErrorResponse responds with a validation error for a failed bind of
+// req. Failed validators are reported as messages keyed by the JSON name of
+// their field, so forms can show them next to the input; other errors, such as
+// malformed JSON, are reported as they are.
+func validationErrorResponse(ctx *gin.Context, err error, req interface{}) {
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	fields := make(map[string]string, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		name := jsonFieldName(req, fieldError.StructField())
+		if _, ok := fields[name]; !ok {
+			fields[name] = fieldErrorMessage(fieldError)
+		}
+	}
+	utils.ValidationErrorResponse(ctx, fields)
+}
+
+// fieldErrorMessage describes a failed validator in words
+func fieldErrorMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		if fieldError.Kind() == reflect.String {
+			return fmt.Sprintf("is too short, must be at least %s characters", fieldError.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fieldError.Param())
+	case "max":
+		if fieldError.Kind() == reflect.String {
+			return fmt.Sprintf("is too long, must be at most %s characters", fieldError.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fieldError.Param())
+	default:
+		return "is invalid"
+	}
+}
+
+// jsonFieldName returns the JSON name of the field of req, falling back to the
+// field name when it has no json tag
+func jsonFieldName(req interface{}, field string) string {
+	t := reflect.TypeOf(req)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return field
+	}
+
+	structField, ok := t.FieldByName(field)
+	if !ok {
+		return field
+	}
+	name := strings.Split(structField.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field
+	}
+	return name
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/buildinfo"
+	"zionechainapi/internal/utils"
+)
+
+// VersionResponse describes the deployed build. It only carries details that
+// are safe to show anyone.
+type VersionResponse struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Commit      string `json:"commit"`
+	BuildTime   string `json:"build_time"`
+	Environment string `json:"environment"`
+}
+
+// VersionController reports which build is deployed
+type VersionController struct {
+	config *configs.Config
+}
+
+// NewVersionController creates a new version controller
+func NewVersionController(config *configs.Config) *VersionController {
+	return &VersionController{
+		config: config,
+	}
+}
+
+// Get godoc
+// @Summary Get build information
+// @Description Report the application name, version, git commit and build time of the deployed build, and the environment it runs in
+// @Tags version
+// @Produce json
+// @Success 200 {object} utils.Response{data=controllers.VersionResponse} "Version retrieved successfully"
+// @Router /api/version [get]
+func (c *VersionController) Get(ctx *gin.Context) {
+	utils.OKResponse(ctx, "Version retrieved successfully", VersionResponse{
+		Name:        c.config.App.Name,
+		Version:     buildinfo.Version,
+		Commit:      buildinfo.Commit,
+		BuildTime:   buildinfo.BuildTime,
+		Environment: c.config.App.Env,
+	})
+}
+
+// Routes registers version routes
+func (c *VersionController) Routes(router gin.IRoutes) {
+	router.GET("/version", c.Get)
+}
@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+// WebhookController handles webhook subscription routes
+type WebhookController struct {
+	config         *configs.Config
+	webhookService *services.WebhookService
+}
+
+// NewWebhookController creates a new webhook controller
+func NewWebhookController(config *configs.Config) *WebhookController {
+	return &WebhookController{
+		config:         config,
+		webhookService: services.NewWebhookService(),
+	}
+}
+
+// List godoc
+// @Summary List webhooks
+// @Description List all webhook subscriptions
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]services.WebhookResponse} "Webhooks retrieved successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/webhooks [get]
+func (c *WebhookController) List(ctx *gin.Context) {
+	webhooks, err := c.webhookService.ListWebhooks()
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Webhooks retrieved successfully", webhooks)
+}
+
+// Get godoc
+// @Summary Get a webhook by ID
+// @Description Get a webhook subscription by ID
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} utils.Response{data=services.WebhookResponse} "Webhook retrieved successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/webhooks/{id} [get]
+func (c *WebhookController) Get(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid webhook ID", nil)
+		return
+	}
+
+	webhook, err := c.webhookService.GetWebhookByID(uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrWebhookNotFound) {
+			utils.NotFoundResponse(ctx, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Webhook retrieved successfully", webhook)
+}
+
+// Create godoc
+// @Summary Create a webhook
+// @Description Subscribe a URL to content events such as project.created or blog.published. Deliveries are signed with the secret.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body services.CreateWebhookRequest true "Create webhook request"
+// @Success 201 {object} utils.Response{data=services.WebhookResponse} "Webhook created successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/webhooks [post]
+func (c *WebhookController) Create(ctx *gin.Context) {
+	var req services.CreateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	webhook, err := c.webhookService.CreateWebhook(req, userID)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Failed to create webhook", err.Error())
+		return
+	}
+
+	utils.CreatedResponse(ctx, "Webhook created successfully", webhook)
+}
+
+// Update godoc
+// @Summary Update a webhook
+// @Description Update a webhook subscription; omitted fields are left unchanged
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param body body services.UpdateWebhookRequest true "Update webhook request"
+// @Success 200 {object} utils.Response{data=services.WebhookResponse} "Webhook updated successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 422 {object} utils.Response "Validation error"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/webhooks/{id} [put]
+func (c *WebhookController) Update(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid webhook ID", nil)
+		return
+	}
+
+	var req services.UpdateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(ctx, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	webhook, err := c.webhookService.UpdateWebhook(uint(id), req, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrWebhookNotFound) {
+			utils.NotFoundResponse(ctx, err.Error())
+			return
+		}
+		utils.BadRequestResponse(ctx, "Failed to update webhook", err.Error())
+		return
+	}
+
+	utils.OKResponse(ctx, "Webhook updated successfully", webhook)
+}
+
+// Delete godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook subscription
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 204 {object} utils.Response "Webhook deleted successfully"
+// @Failure 400 {object} utils.Response "Bad request"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Forbidden"
+// @Failure 404 {object} utils.Response "Not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /api/webhooks/{id} [delete]
+func (c *WebhookController) Delete(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid webhook ID", nil)
+		return
+	}
+
+	userID := middleware.GetUserID(ctx)
+	if err := c.webhookService.DeleteWebhook(uint(id), userID); err != nil {
+		if errors.Is(err, services.ErrWebhookNotFound) {
+			utils.NotFoundResponse(ctx, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.NoContentResponse(ctx)
+}
+
+// Routes registers webhook routes
+func (c *WebhookController) Routes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	webhooks := router.Group("/webhooks")
+	webhooks.Use(authMiddleware, middleware.RequireRole("admin"))
+	{
+		webhooks.GET("", c.List)
+		webhooks.GET("/:id", c.Get)
+		webhooks.POST("", c.Create)
+		webhooks.PUT("/:id", c.Update)
+		webhooks.DELETE("/:id", c.Delete)
+	}
+}
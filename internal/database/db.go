@@ -2,12 +2,18 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"strings"
 	"time"
 
 	"zionechainapi/configs"
 	"zionechainapi/internal/models"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -15,18 +21,48 @@ import (
 // DB is the database connection
 var DB *gorm.DB
 
+// ErrUnknownDriver is returned when DB_DRIVER names a database that is not supported
+var ErrUnknownDriver = errors.New("unknown database driver")
+
+// memoryDSN is the SQLite database name that keeps the database in memory
+const memoryDSN = ":memory:"
+
+// Dialector returns the GORM dialector of the configured driver, mysql unless
+// another is set
+func Dialector(config configs.DatabaseConfig) (gorm.Dialector, error) {
+	switch strings.ToLower(config.Driver) {
+	case "", "mysql":
+		return mysql.Open(fmt.Sprintf(
+			"%s:%s@tcp(%s:%s)/%s?charset=%s&parseTime=True&loc=Local",
+			config.User,
+			config.Password,
+			config.Host,
+			config.Port,
+			config.Name,
+			config.Charset,
+		)), nil
+	case "postgres":
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(config.User, config.Password),
+			Host:     net.JoinHostPort(config.Host, config.Port),
+			Path:     config.Name,
+			RawQuery: url.Values{"sslmode": {config.SSLMode}}.Encode(),
+		}
+		return postgres.Open(dsn.String()), nil
+	case "sqlite":
+		return sqlite.Open(config.Name), nil
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownDriver, config.Driver)
+	}
+}
+
 // Connect connects to the database
 func Connect(config *configs.Config) (*gorm.DB, error) {
-	// Create DSN string
-	dsn := fmt.Sprintf(
-		"%s:%s@tcp(%s:%s)/%s?charset=%s&parseTime=True&loc=Local",
-		config.Database.User,
-		config.Database.Password,
-		config.Database.Host,
-		config.Database.Port,
-		config.Database.Name,
-		config.Database.Charset,
-	)
+	dialector, err := Dialector(config.Database)
+	if err != nil {
+		return nil, err
+	}
 
 	// Set up GORM logger
 	var logLevel logger.LogLevel
@@ -46,15 +82,21 @@ func Connect(config *configs.Config) (*gorm.DB, error) {
 	// Configure GORM
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(logLevel),
+		// Surface driver errors such as duplicate keys as gorm.ErrDuplicatedKey
+		TranslateError: true,
 	}
 
 	// Connect to database
-	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), gormConfig)
+	DB, err = gorm.Open(dialector, gormConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Render timestamps in UTC whatever the server time zone
+	if err := registerUTCCallbacks(DB); err != nil {
+		return nil, fmt.Errorf("failed to register database callbacks: %w", err)
+	}
+
 	// Configure connection pool
 	sqlDB, err := DB.DB()
 	if err != nil {
@@ -64,13 +106,21 @@ func Connect(config *configs.Config) (*gorm.DB, error) {
 	sqlDB.SetMaxOpenConns(config.Database.MaxOpenConns)
 	sqlDB.SetConnMaxLifetime(config.Database.ConnMaxLifetime)
 
+	// Every connection to an in-memory SQLite database opens a new, empty
+	// database, so keep the first one for good
+	if strings.EqualFold(config.Database.Driver, "sqlite") && config.Database.Name == memoryDSN {
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	}
+
 	return DB, nil
 }
 
-// AutoMigrate automatically migrates the database schema
-func AutoMigrate() error {
+// migratedModels returns the models AutoMigrate creates tables for
+func migratedModels() []interface{} {
 	// Register models here
-	return DB.AutoMigrate(
+	return []interface{}{
 		&models.User{},
 		&models.Role{},
 		&models.Project{},
@@ -80,19 +130,106 @@ func AutoMigrate() error {
 		&models.BlogCategory{},
 		&models.BlogMedia{},
 		&models.Tag{},
+		&models.Technology{},
+		&models.Translation{},
+		&models.Webhook{},
+		&models.OutboxEvent{},
+		&models.Comment{},
+		&models.Message{},
+		&models.AuditLog{},
+		&models.SlugHistory{},
 		// Resume models
 		&models.PersonalInfo{},
 		&models.Skill{},
 		&models.Experience{},
 		&models.Education{},
+		&models.ResumeProject{},
 		&models.Certificate{},
 		&models.Language{},
 		&models.Publication{},
-	)
+	}
+}
+
+// AutoMigrate automatically migrates the database schema
+func AutoMigrate() error {
+	return DB.AutoMigrate(migratedModels()...)
+}
+
+// PendingMigrations returns the tables of the migrated models, including their
+// join tables, that do not exist yet. It only checks that tables exist, so it
+// is cheap enough for a readiness probe.
+func PendingMigrations() ([]string, error) {
+	if DB == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	tables, err := DB.Migrator().GetTables()
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		existing[table] = true
+	}
+
+	pending := []string{}
+	seen := make(map[string]bool)
+	for _, model := range migratedModels() {
+		stmt := &gorm.Statement{DB: DB}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+
+		expected := []string{stmt.Schema.Table}
+		for _, relation := range stmt.Schema.Relationships.Relations {
+			if relation.JoinTable != nil {
+				expected = append(expected, relation.JoinTable.Table)
+			}
+		}
+		for _, table := range expected {
+			if !existing[table] && !seen[table] {
+				seen[table] = true
+				pending = append(pending, table)
+			}
+		}
+	}
+	return pending, nil
+}
+
+// RepairOrphanedJoinRows removes tag and technology join-table rows that
+// reference projects, blog posts, tags or technologies that no longer exist,
+// returning the number of removed rows
+func RepairOrphanedJoinRows() (int64, error) {
+	var removed int64
+
+	queries := []string{
+		"DELETE FROM project_tags WHERE project_id NOT IN (SELECT id FROM projects) OR tag_id NOT IN (SELECT id FROM tags)",
+		"DELETE FROM blog_tags WHERE blog_post_id NOT IN (SELECT id FROM blog_posts) OR tag_id NOT IN (SELECT id FROM tags)",
+		"DELETE FROM project_technologies WHERE project_id NOT IN (SELECT id FROM projects) OR technology_id NOT IN (SELECT id FROM technologies)",
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		for _, query := range queries {
+			result := tx.Exec(query)
+			if result.Error != nil {
+				return result.Error
+			}
+			removed += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return removed, nil
 }
 
 // Ping checks if database connection is alive
 func Ping() error {
+	if DB == nil {
+		return errors.New("database connection is not initialized")
+	}
 	sqlDB, err := DB.DB()
 	if err != nil {
 		return err
@@ -109,8 +246,18 @@ func Close() error {
 	return sqlDB.Close()
 }
 
-// DBWithTimeout returns a new DB instance with timeout context
-func DBWithTimeout(timeout time.Duration) *gorm.DB {
+// FromContext returns DB with its queries bound to ctx, so they are cancelled
+// along with it. A nil ctx leaves the queries unbound.
+func FromContext(ctx context.Context) *gorm.DB {
+	if ctx == nil {
+		return DB
+	}
+	return DB.WithContext(ctx)
+}
+
+// DBWithTimeout returns a new DB instance with timeout context. Call cancel
+// once the queries are done to release the context.
+func DBWithTimeout(timeout time.Duration) (db *gorm.DB, cancel context.CancelFunc) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	return DB.WithContext(ctx).Session(&gorm.Session{NewDB: true})
+	return DB.WithContext(ctx).Session(&gorm.Session{NewDB: true}), cancel
 } 
\ No newline at end of file
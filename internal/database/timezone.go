@@ -0,0 +1,62 @@
+package database
+
+import (
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// registerUTCCallbacks normalizes the timestamps of loaded and saved models to
+// UTC, so models serialized directly (such as the resume) render in a
+// predictable zone regardless of the server's local time zone
+func registerUTCCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Query().After("gorm:query").Register("app:utc_timestamps", normalizeTimestamps); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().After("gorm:create").Register("app:utc_timestamps", normalizeTimestamps); err != nil {
+		return err
+	}
+	return db.Callback().Update().After("gorm:update").Register("app:utc_timestamps", normalizeTimestamps)
+}
+
+// normalizeTimestamps converts the time fields of the statement's models to UTC
+func normalizeTimestamps(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	value := reflect.Indirect(db.Statement.ReflectValue)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			normalizeModelTimestamps(db, reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		normalizeModelTimestamps(db, value)
+	}
+}
+
+func normalizeModelTimestamps(db *gorm.DB, model reflect.Value) {
+	// Plucked columns, such as a time.Time, are not models of the schema
+	if model.Kind() != reflect.Struct || !model.CanAddr() || model.Type() != db.Statement.Schema.ModelType {
+		return
+	}
+
+	for _, field := range db.Statement.Schema.Fields {
+		fieldValue := field.ReflectValueOf(db.Statement.Context, model)
+		if !fieldValue.CanSet() {
+			continue
+		}
+
+		switch t := fieldValue.Interface().(type) {
+		case time.Time:
+			fieldValue.Set(reflect.ValueOf(t.UTC()))
+		case *time.Time:
+			if t != nil {
+				utc := t.UTC()
+				fieldValue.Set(reflect.ValueOf(&utc))
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"  // register the GIF decoder
+	_ "image/jpeg" // register the JPEG decoder
+	_ "image/png"  // register the PNG decoder
+
+	_ "golang.org/x/image/webp" // register the WebP decoder
+)
+
+// MaxPixels is the largest number of pixels an image may have to be decoded,
+// which guards against small files that expand into huge images
+const MaxPixels = 40 << 20
+
+var (
+	// ErrInvalidImage is returned when a file's content is not a well-formed image of its type
+	ErrInvalidImage = errors.New("file is not a valid image")
+	// ErrImageTooLarge is returned when an image has more than MaxPixels pixels
+	ErrImageTooLarge = errors.New("image dimensions are too large")
+)
+
+// decodedFormats maps the content types of images Go can decode to their format names
+var decodedFormats = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpeg",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// IsImage reports whether contentType is an image type whose content can be validated
+func IsImage(contentType string) bool {
+	_, ok := decodedFormats[contentType]
+	return ok || contentType == "image/avif"
+}
+
+// CanConvert reports whether images of contentType can be converted to WebP
+func CanConvert(contentType string) bool {
+	return contentType != "image/webp" && decodedFormats[contentType] != ""
+}
+
+// Validate checks that data is a well-formed image of contentType and returns it
+// decoded. AVIF images, which Go cannot decode, are only checked structurally and
+// returned as nil.
+func Validate(data []byte, contentType string) (image.Image, error) {
+	if contentType == "image/avif" {
+		if !IsAVIF(data) {
+			return nil, ErrInvalidImage
+		}
+		return nil, nil
+	}
+
+	format, ok := decodedFormats[contentType]
+	if !ok {
+		return nil, ErrInvalidImage
+	}
+
+	// Check the dimensions before decoding the pixels
+	config, configFormat, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || configFormat != format {
+		return nil, ErrInvalidImage
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrInvalidImage
+	}
+	if int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	return img, nil
+}
+
+// IsAVIF reports whether data starts with the file type box of an AVIF image
+func IsAVIF(data []byte) bool {
+	// The box is its 4-byte size, "ftyp", the major brand, a minor version and compatible brands
+	if len(data) < 16 || string(data[4:8]) != "ftyp" {
+		return false
+	}
+	size := int(data[0])<<24 | int(data[1])<<16 | int(data[2])<<8 | int(data[3])
+	if size < 16 || size > len(data) || size%4 != 0 {
+		return false
+	}
+	for i := 8; i+4 <= size; i += 4 {
+		if i == 12 {
+			continue // minor version
+		}
+		if brand := string(data[i : i+4]); brand == "avif" || brand == "avis" {
+			return true
+		}
+	}
+	return false
+}
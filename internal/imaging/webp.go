@@ -0,0 +1,335 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"io"
+	"sort"
+)
+
+// MaxWebPDimension is the largest width or height a WebP image can have
+const MaxWebPDimension = 1 << 14
+
+// ErrWebPTooLarge is returned when an image is too wide or tall to be stored as WebP
+var ErrWebPTooLarge = errors.New("image is too large for WebP")
+
+// VP8L constants, see https://developers.google.com/speed/webp/docs/webp_lossless_bitstream_specification
+const (
+	vp8lSignature         = 0x2f
+	vp8lSubtractGreen     = 2
+	vp8lNumLiteralCodes   = 256
+	vp8lNumLengthCodes    = 24
+	vp8lNumDistanceCodes  = 40
+	vp8lMaxCodeLength     = 15
+	vp8lMaxCodeLengthCode = 7
+)
+
+// vp8lCodeLengthCodeOrder is the order code length code lengths are stored in
+var vp8lCodeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// EncodeWebP writes img to w as a lossless WebP image. The encoder only applies
+// the subtract green transform and codes every pixel as a literal, so it favours
+// simplicity over compression; it is meant for derivatives, not archival.
+func EncodeWebP(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 || width > MaxWebPDimension || height > MaxWebPDimension {
+		return ErrWebPTooLarge
+	}
+
+	// VP8L stores non-premultiplied ARGB
+	pixels := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(pixels, pixels.Bounds(), img, bounds.Min, draw.Src)
+
+	opaque := true
+	green := make([]int, vp8lNumLiteralCodes+vp8lNumLengthCodes)
+	red := make([]int, vp8lNumLiteralCodes)
+	blue := make([]int, vp8lNumLiteralCodes)
+	alpha := make([]int, vp8lNumLiteralCodes)
+	for i := 0; i < len(pixels.Pix); i += 4 {
+		r, g, b, a := pixels.Pix[i], pixels.Pix[i+1], pixels.Pix[i+2], pixels.Pix[i+3]
+		// Subtract green
+		r, b = r-g, b-g
+		pixels.Pix[i], pixels.Pix[i+2] = r, b
+
+		green[g]++
+		red[r]++
+		blue[b]++
+		alpha[a]++
+		if a != 0xff {
+			opaque = false
+		}
+	}
+
+	bw := &bitWriter{}
+	bw.writeBits(vp8lSignature, 8)
+	bw.writeBits(uint32(width-1), 14)
+	bw.writeBits(uint32(height-1), 14)
+	if opaque {
+		bw.writeBits(0, 1)
+	} else {
+		bw.writeBits(1, 1)
+	}
+	bw.writeBits(0, 3) // version
+
+	// A single subtract green transform
+	bw.writeBits(1, 1)
+	bw.writeBits(vp8lSubtractGreen, 2)
+	bw.writeBits(0, 1)
+
+	bw.writeBits(0, 1) // no color cache
+	bw.writeBits(0, 1) // no meta prefix codes
+
+	codes := []prefixCode{
+		newPrefixCode(green, vp8lMaxCodeLength),
+		newPrefixCode(red, vp8lMaxCodeLength),
+		newPrefixCode(blue, vp8lMaxCodeLength),
+		newPrefixCode(alpha, vp8lMaxCodeLength),
+		newPrefixCode(make([]int, vp8lNumDistanceCodes), vp8lMaxCodeLength),
+	}
+	for _, code := range codes {
+		code.writeHeader(bw)
+	}
+
+	for i := 0; i < len(pixels.Pix); i += 4 {
+		codes[0].write(bw, int(pixels.Pix[i+1]))
+		codes[1].write(bw, int(pixels.Pix[i]))
+		codes[2].write(bw, int(pixels.Pix[i+2]))
+		codes[3].write(bw, int(pixels.Pix[i+3]))
+	}
+
+	data := bw.bytes()
+	padding := len(data) & 1
+
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+8+len(data)+padding))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(data)))
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if padding == 1 {
+		if _, err := w.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bitWriter packs bits least significant first, as VP8L reads them
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits uint
+}
+
+// writeBits appends the n low bits of v
+func (w *bitWriter) writeBits(v uint32, n int) {
+	w.acc |= uint64(v) << w.nbits
+	w.nbits += uint(n)
+	for w.nbits >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.nbits -= 8
+	}
+}
+
+// bytes returns the written bits, padding the last byte with zeros
+func (w *bitWriter) bytes() []byte {
+	if w.nbits > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.nbits = 0, 0
+	}
+	return w.buf
+}
+
+// prefixCode is a canonical Huffman code over an alphabet
+type prefixCode struct {
+	lengths []int
+	codes   []uint32 // bit reversed, ready to be written least significant bit first
+	used    []int    // the symbols with a non-zero length
+}
+
+// newPrefixCode builds the prefix code of the symbol frequencies freqs with no
+// code longer than maxLength
+func newPrefixCode(freqs []int, maxLength int) prefixCode {
+	lengths := huffmanLengths(freqs, maxLength)
+	code := prefixCode{lengths: lengths, codes: make([]uint32, len(lengths))}
+
+	var lengthCounts [vp8lMaxCodeLength + 1]int
+	for symbol, length := range lengths {
+		if length > 0 {
+			lengthCounts[length]++
+			code.used = append(code.used, symbol)
+		}
+	}
+
+	var nextCode [vp8lMaxCodeLength + 1]uint32
+	next := uint32(0)
+	for length := 1; length <= vp8lMaxCodeLength; length++ {
+		next = (next + uint32(lengthCounts[length-1])) << 1
+		nextCode[length] = next
+	}
+	for symbol, length := range lengths {
+		if length > 0 {
+			code.codes[symbol] = reverseBits(nextCode[length], length)
+			nextCode[length]++
+		}
+	}
+
+	return code
+}
+
+// write appends the code of symbol. A code with a single symbol takes no bits.
+func (p prefixCode) write(w *bitWriter, symbol int) {
+	if len(p.used) > 1 {
+		w.writeBits(p.codes[symbol], p.lengths[symbol])
+	}
+}
+
+// writeHeader appends the code lengths of the code, as a simple code when at
+// most one symbol is used and as a normal code otherwise
+func (p prefixCode) writeHeader(w *bitWriter) {
+	if len(p.used) <= 1 {
+		symbol := 0
+		if len(p.used) == 1 {
+			symbol = p.used[0]
+		}
+		w.writeBits(1, 1) // simple code
+		w.writeBits(0, 1) // one symbol
+		if symbol < 2 {
+			w.writeBits(0, 1)
+			w.writeBits(uint32(symbol), 1)
+		} else {
+			w.writeBits(1, 1)
+			w.writeBits(uint32(symbol), 8)
+		}
+		return
+	}
+
+	// The code lengths are themselves prefix coded, as plain lengths 0 to 15
+	lengthFreqs := make([]int, len(vp8lCodeLengthCodeOrder))
+	for _, length := range p.lengths {
+		lengthFreqs[length]++
+	}
+	lengthCode := newPrefixCode(lengthFreqs, vp8lMaxCodeLengthCode)
+
+	numCodes := 4
+	for i, symbol := range vp8lCodeLengthCodeOrder {
+		if lengthCode.lengths[symbol] > 0 && i+1 > numCodes {
+			numCodes = i + 1
+		}
+	}
+
+	w.writeBits(0, 1) // normal code
+	w.writeBits(uint32(numCodes-4), 4)
+	for _, symbol := range vp8lCodeLengthCodeOrder[:numCodes] {
+		w.writeBits(uint32(lengthCode.lengths[symbol]), 3)
+	}
+	w.writeBits(0, 1) // lengths are given for the whole alphabet
+	for _, length := range p.lengths {
+		lengthCode.write(w, length)
+	}
+}
+
+// huffmanLengths returns the Huffman code lengths of the symbol frequencies
+// freqs. When the code would be longer than maxLength, rare symbols are made
+// more frequent until it fits, which keeps the code complete.
+func huffmanLengths(freqs []int, maxLength int) []int {
+	lengths := make([]int, len(freqs))
+	var symbols []int
+	for symbol, freq := range freqs {
+		if freq > 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		return lengths
+	}
+	if len(symbols) == 1 {
+		lengths[symbols[0]] = 1
+		return lengths
+	}
+
+	weights := make([]int, len(symbols))
+	for minFreq := 1; ; minFreq *= 2 {
+		for i, symbol := range symbols {
+			weights[i] = freqs[symbol]
+			if weights[i] < minFreq {
+				weights[i] = minFreq
+			}
+		}
+
+		depths := huffmanDepths(weights)
+		fits := true
+		for _, depth := range depths {
+			if depth > maxLength {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			for i, symbol := range symbols {
+				lengths[symbol] = depths[i]
+			}
+			return lengths
+		}
+	}
+}
+
+// huffmanDepths returns the depth of each leaf in a Huffman tree over weights,
+// built with the two queue method
+func huffmanDepths(weights []int) []int {
+	n := len(weights)
+	leaves := make([]int, n)
+	for i := range leaves {
+		leaves[i] = i
+	}
+	sort.SliceStable(leaves, func(i, j int) bool { return weights[leaves[i]] < weights[leaves[j]] })
+
+	// Nodes 0..n-1 are leaves, the rest are merged in order of creation
+	nodeWeights := make([]int, n, 2*n-1)
+	copy(nodeWeights, weights)
+	parents := make([]int, 2*n-1)
+
+	leaf, merged := 0, n
+	pick := func() int {
+		if leaf < n && (merged >= len(nodeWeights) || nodeWeights[leaves[leaf]] <= nodeWeights[merged]) {
+			leaf++
+			return leaves[leaf-1]
+		}
+		merged++
+		return merged - 1
+	}
+	for len(nodeWeights) < 2*n-1 {
+		a, b := pick(), pick()
+		parents[a] = len(nodeWeights)
+		parents[b] = len(nodeWeights)
+		nodeWeights = append(nodeWeights, nodeWeights[a]+nodeWeights[b])
+	}
+
+	// Parents are created after their children, so walk down from the root
+	root := 2*n - 2
+	depths := make([]int, 2*n-1)
+	for node := root - 1; node >= 0; node-- {
+		depths[node] = depths[parents[node]] + 1
+	}
+	return depths[:n]
+}
+
+// reverseBits reverses the n low bits of v
+func reverseBits(v uint32, n int) uint32 {
+	var r uint32
+	for i := 0; i < n; i++ {
+		r = r<<1 | v&1
+		v >>= 1
+	}
+	return r
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"log"
+	"strings"
+
+	"zionechainapi/configs"
+)
+
+// Mailer sends plain text emails. Services take a Mailer instead of talking to
+// a mail server directly so tests can capture what would have been sent.
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// New creates the mailer selected by the configured driver
+func New(config *configs.Config) Mailer {
+	switch strings.ToLower(config.Mail.Driver) {
+	case "smtp":
+		return NewSMTPMailer(config.Mail)
+	case "", "log":
+		return Log{}
+	default:
+		log.Printf("Warning: unknown mail driver %q, logging emails instead", config.Mail.Driver)
+		return Log{}
+	}
+}
+
+// Log is a Mailer that writes messages to the application log instead of
+// sending them, for development setups without a mail server
+type Log struct{}
+
+// Send logs the message
+func (Log) Send(to, subject, body string) error {
+	log.Printf("Mail to %s: %s\n%s", to, subject, body)
+	return nil
+}
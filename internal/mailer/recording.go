@@ -0,0 +1,37 @@
+package mailer
+
+import "sync"
+
+// Message is an email captured by a Recording mailer
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Recording is a Mailer that keeps messages in memory instead of sending them,
+// for tests
+type Recording struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewRecording creates an empty recording mailer
+func NewRecording() *Recording {
+	return &Recording{}
+}
+
+// Send records the message
+func (r *Recording) Send(to, subject, body string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, Message{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+// Messages returns the recorded messages in the order they were sent
+func (r *Recording) Messages() []Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Message(nil), r.messages...)
+}
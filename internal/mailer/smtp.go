@@ -0,0 +1,60 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"zionechainapi/configs"
+)
+
+// ErrInvalidHeader is returned when a recipient or subject would break the
+// message headers
+var ErrInvalidHeader = errors.New("mail header contains a line break")
+
+// SMTPMailer sends emails through an SMTP server, authenticating with PLAIN
+// auth when a username is configured
+type SMTPMailer struct {
+	config configs.MailConfig
+}
+
+// NewSMTPMailer creates a mailer for the configured SMTP server
+func NewSMTPMailer(config configs.MailConfig) *SMTPMailer {
+	return &SMTPMailer{config: config}
+}
+
+// Send sends a plain text message to a single recipient
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return ErrInvalidHeader
+	}
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	if err := smtp.SendMail(addr, auth, m.config.From, []string{to}, m.message(to, subject, body)); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return nil
+}
+
+// message builds the RFC 5322 message with CRLF line endings
+func (m *SMTPMailer) message(to, subject, body string) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + m.config.From + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: " + subject + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
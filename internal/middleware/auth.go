@@ -1,48 +1,97 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"zionechainapi/configs"
 	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
 )
 
-// Auth is the authentication middleware
+// Names of the cookies tokens are delivered in when cookie delivery is enabled
+const (
+	AccessTokenCookie  = "access_token"
+	RefreshTokenCookie = "refresh_token"
+)
+
+// requestToken returns the bearer token of the Authorization header, falling
+// back to the access token cookie when no header is sent. ok is false when the
+// header is not in the Bearer {token} format.
+func requestToken(c *gin.Context) (token string, ok bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		token, _ := c.Cookie(AccessTokenCookie)
+		return token, true
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// Auth is the authentication middleware. The token is read from the
+// Authorization header or, without one, from the access token cookie.
 func Auth(config *configs.Config) gin.HandlerFunc {
+	authService := services.NewAuthService(config)
 	return func(c *gin.Context) {
-		// Get auth header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		// Get token
+		token, ok := requestToken(c)
+		if !ok {
+			utils.CodedErrorResponse(c, http.StatusUnauthorized, utils.CodeAuthTokenInvalid, "authorization header format must be Bearer {token}", nil)
 			c.Abort()
 			return
 		}
-
-		// Check if header is in correct format
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header format must be Bearer {token}"})
+		if token == "" {
+			utils.UnauthorizedResponse(c, "authorization header is required")
 			c.Abort()
 			return
 		}
 
-		// Get token
-		token := parts[1]
-
 		// Validate token
-		authService := services.NewAuthService(config)
 		claims, err := authService.ValidateToken(token)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+			// Tell clients with an expired token to refresh instead of logging in again
+			if errors.Is(err, services.ErrTokenExpired) {
+				utils.CodedErrorResponse(c, http.StatusUnauthorized, utils.CodeAuthTokenExpired, "token has expired", nil)
+			} else {
+				utils.CodedErrorResponse(c, http.StatusUnauthorized, utils.CodeAuthTokenInvalid, "invalid token", nil)
+			}
 			c.Abort()
 			return
 		}
 
-		// Set user ID and role in context
+		// Set user ID, role and scope in context. Scoped tokens carry no role,
+		// so only routes that accept their scope let them through.
 		c.Set("userID", claims.UserID)
 		c.Set("userRole", claims.Role)
+		c.Set("scope", claims.Scope)
+
+		c.Next()
+	}
+}
+
+// OptionalAuth identifies the user when a valid bearer token or access token
+// cookie is sent, but lets anonymous requests through so public routes can
+// tailor their response
+func OptionalAuth(config *configs.Config) gin.HandlerFunc {
+	authService := services.NewAuthService(config)
+	return func(c *gin.Context) {
+		if token, ok := requestToken(c); ok && token != "" {
+			if claims, err := authService.ValidateToken(token); err == nil {
+				// A scoped token does not sign its bearer in as the user who minted it
+				if claims.Scope != "" {
+					c.Set("scope", claims.Scope)
+				} else {
+					c.Set("userID", claims.UserID)
+					c.Set("userRole", claims.Role)
+				}
+			}
+		}
 
 		c.Next()
 	}
@@ -54,7 +103,7 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 		// Get user role from context
 		userRole, exists := c.Get("userRole")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			utils.UnauthorizedResponse(c, "user not authenticated")
 			c.Abort()
 			return
 		}
@@ -69,11 +118,24 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 			}
 		}
 
-		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		utils.CodedErrorResponse(c, http.StatusForbidden, utils.CodeAuthInsufficientRole, "insufficient permissions", nil)
 		c.Abort()
 	}
 }
 
+// RequireRoleOrScope lets requests through with one of the roles, like
+// RequireRole, or with a scoped token granting scope
+func RequireRoleOrScope(scope string, roles ...string) gin.HandlerFunc {
+	requireRole := RequireRole(roles...)
+	return func(c *gin.Context) {
+		if GetScope(c) == scope {
+			c.Next()
+			return
+		}
+		requireRole(c)
+	}
+}
+
 // GetUserID gets the user ID from the context
 func GetUserID(c *gin.Context) uint {
 	userID, exists := c.Get("userID")
@@ -90,4 +152,13 @@ func GetUserRole(c *gin.Context) string {
 		return ""
 	}
 	return userRole.(string)
-} 
\ No newline at end of file
+}
+
+// GetScope gets the scope of a scoped token from the context
+func GetScope(c *gin.Context) string {
+	scope, exists := c.Get("scope")
+	if !exists {
+		return ""
+	}
+	return scope.(string)
+}
\ No newline at end of file
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/internal/cache"
+)
+
+// cachingWriter keeps a copy of the response body
+type cachingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *cachingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cachingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// CacheResponse serves successful anonymous GET responses from the store for
+// the given time to live, keyed by the full request URI. Requests carrying a
+// token in the Authorization header or the access token cookie are never
+// cached, since their response may include drafts. A zero ttl disables caching.
+func CacheResponse(store cache.Cache, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, _ := requestToken(c)
+		if ttl <= 0 || c.Request.Method != http.MethodGet || c.GetHeader("Authorization") != "" || token != "" {
+			c.Next()
+			return
+		}
+
+		key := c.Request.URL.RequestURI()
+		if body, ok := store.Get(key); ok {
+			c.Header("X-Cache", "HIT")
+			c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+			c.Abort()
+			return
+		}
+
+		c.Header("X-Cache", "MISS")
+		writer := &cachingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		if writer.Status() == http.StatusOK {
+			store.Set(key, writer.body.Bytes(), ttl)
+		}
+	}
+}
+
+// InvalidateCache clears the stores after any successful non-GET request
+func InvalidateCache(stores ...cache.Cache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Request.Method != http.MethodGet && c.Writer.Status() < http.StatusBadRequest {
+			for _, store := range stores {
+				store.Clear()
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireContentType rejects write requests whose body is not one of the given
+// media types with 415 Unsupported Media Type. Requests without a body, such as
+// duplicating a project or liking a post, are let through.
+func RequireContentType(mediaTypes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength == 0 {
+			c.Next()
+			return
+		}
+
+		contentType := c.ContentType()
+		for _, mediaType := range mediaTypes {
+			if strings.EqualFold(contentType, mediaType) {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be " + strings.Join(mediaTypes, " or ")})
+		c.Abort()
+	}
+}
+
+// RequireJSON rejects write requests whose body is not JSON
+func RequireJSON() gin.HandlerFunc {
+	return RequireContentType("application/json")
+}
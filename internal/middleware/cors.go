@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSRoute identifies a route by its method and gin path pattern
+type CORSRoute struct {
+	Method string
+	Path   string
+}
+
+// CORSPolicy is the cross-origin access allowed on a group of routes
+type CORSPolicy struct {
+	// AllowedOrigins may contain "*" to allow any origin
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	// AllowCredentials lets browsers send cookies from the listed origins, with
+	// the request origin echoed. It does not apply to a policy allowing any
+	// origin, which would let every site make credentialed requests.
+	AllowCredentials bool
+	// Routes are the routes the policy applies to when it overrides the default policy
+	Routes []CORSRoute
+}
+
+// allows reports whether the policy allows requests from origin
+func (p CORSPolicy) allows(origin string) bool {
+	for _, allowed := range p.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcard reports whether the policy allows any origin
+func (p CORSPolicy) wildcard() bool {
+	for _, allowed := range p.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// covers reports whether a request with method to path is made to one of the routes of the policy
+func (p CORSPolicy) covers(method, path string) bool {
+	for _, route := range p.Routes {
+		if route.Method == method && routeMatches(route.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// CORS returns a middleware that answers cross-origin requests under
+// defaultPolicy, or under the first override covering the requested route.
+// Preflight requests are resolved by the method they ask for, so register it
+// on the engine, where it also runs for the OPTIONS requests no route handles.
+// Preflights from a disallowed origin are refused with 403; other requests from
+// one are served without CORS headers, so browsers do not expose the response.
+func CORS(defaultPolicy CORSPolicy, overrides ...CORSPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		method := c.Request.Method
+		requestedMethod := c.GetHeader("Access-Control-Request-Method")
+		preflight := method == http.MethodOptions && requestedMethod != ""
+		if preflight {
+			method = requestedMethod
+		}
+
+		policy := defaultPolicy
+		for _, override := range overrides {
+			if override.covers(method, c.Request.URL.Path) {
+				policy = override
+				break
+			}
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+		if !policy.allows(origin) {
+			if preflight {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Origin not allowed"})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if policy.wildcard() {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+			if policy.AllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if preflight {
+			c.Header("Access-Control-Allow-Methods", strings.Join(policy.AllowedMethods, ", "))
+			c.Header("Access-Control-Allow-Headers", strings.Join(policy.AllowedHeaders, ", "))
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
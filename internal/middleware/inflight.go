@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InFlightCounter tracks the number of requests currently being handled
+type InFlightCounter struct {
+	count int64
+}
+
+// NewInFlightCounter creates a new in-flight request counter
+func NewInFlightCounter() *InFlightCounter {
+	return &InFlightCounter{}
+}
+
+// Middleware returns a middleware that counts requests while they are handled
+func (c *InFlightCounter) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		atomic.AddInt64(&c.count, 1)
+		defer atomic.AddInt64(&c.count, -1)
+
+		ctx.Next()
+	}
+}
+
+// Count returns the number of requests currently being handled
+func (c *InFlightCounter) Count() int64 {
+	return atomic.LoadInt64(&c.count)
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/internal/utils"
+)
+
+// MethodNotAllowed returns the handler for requests to a known path with an
+// unsupported method. It answers 405 with an Allow header listing the methods
+// registered on engine for that path. Register it with engine.NoMethod once
+// engine.HandleMethodNotAllowed is enabled.
+func MethodNotAllowed(engine *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Allow", strings.Join(allowedMethods(engine, c.Request.URL.Path), ", "))
+		utils.ErrorResponse(c, http.StatusMethodNotAllowed, "Method not allowed", nil)
+		c.Abort()
+	}
+}
+
+// allowedMethods returns the sorted methods of the routes matching path
+func allowedMethods(engine *gin.Engine, path string) []string {
+	seen := make(map[string]bool)
+	methods := []string{}
+	for _, route := range engine.Routes() {
+		if seen[route.Method] || !routeMatches(route.Path, path) {
+			continue
+		}
+		seen[route.Method] = true
+		methods = append(methods, route.Method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// routeMatches checks if a request path matches a route pattern with :param
+// and *catchall segments
+func routeMatches(pattern, path string) bool {
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(path, "/")
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "*") {
+			return true
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		if strings.HasPrefix(segment, ":") {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return len(patternSegments) == len(pathSegments)
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/internal/utils"
+)
+
+// DeclaredRoute is a route the API documents, with :param and *catchall
+// segments in its path
+type DeclaredRoute struct {
+	Method string
+	Path   string
+}
+
+// NotImplemented returns the handler for requests that no registered route
+// serves. Requests to a declared route that engine does not register are
+// answered with 501, so clients are not told a call succeeded or that the
+// route does not exist. Other requests are passed to next, or left to gin's
+// default response when next is nil. Register it with engine.NoRoute and,
+// wrapping MethodNotAllowed, with engine.NoMethod once every route is
+// registered, since the registered routes are only looked up here.
+func NotImplemented(engine *gin.Engine, declared []DeclaredRoute, next gin.HandlerFunc) gin.HandlerFunc {
+	pending := UnregisteredRoutes(engine, declared)
+	return func(c *gin.Context) {
+		if unimplemented(pending, c.Request.Method, c.Request.URL.Path) {
+			utils.ErrorResponse(c, http.StatusNotImplemented, "Not implemented", nil)
+			c.Abort()
+			return
+		}
+		if next != nil {
+			next(c)
+		}
+	}
+}
+
+// UnregisteredRoutes returns the declared routes that engine does not register.
+// Wildcards may be named differently where a route is declared and where it
+// is registered, so only their positions are compared.
+func UnregisteredRoutes(engine *gin.Engine, declared []DeclaredRoute) []DeclaredRoute {
+	registered := make(map[DeclaredRoute]bool)
+	for _, route := range engine.Routes() {
+		registered[DeclaredRoute{Method: route.Method, Path: unnamedWildcards(route.Path)}] = true
+	}
+
+	pending := make([]DeclaredRoute, 0)
+	for _, route := range declared {
+		if !registered[DeclaredRoute{Method: route.Method, Path: unnamedWildcards(route.Path)}] {
+			pending = append(pending, route)
+		}
+	}
+	return pending
+}
+
+// unnamedWildcards strips the names of the :param and *catchall segments of path
+func unnamedWildcards(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = segment[:1]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// unimplemented checks if one of the unregistered routes matches the request
+func unimplemented(pending []DeclaredRoute, method, path string) bool {
+	for _, route := range pending {
+		if route.Method == method && routeMatches(route.Path, path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"zionechainapi/internal/utils"
+)
+
+// Timeout returns a middleware that gives the rest of the chain d to respond.
+// At the deadline the client gets a 503 and the request context is cancelled,
+// so work bound to it, such as queries run through database.FromContext or a
+// service bound with WithContext, is aborted. Anything the handler writes
+// afterwards is discarded.
+//
+// Handlers write to a buffer that is copied to the client when they finish in
+// time. Streaming and upload routes must not be held in memory or cut off, so
+// the routes in exempt, given as registered paths such as /api/export, run
+// without a deadline and write to the client directly, flushes included. A zero
+// or negative d disables the timeout.
+func Timeout(d time.Duration, exempt ...string) gin.HandlerFunc {
+	exempted := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		exempted[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if d <= 0 || exempted[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+
+		original := c.Writer
+		buffered := &timeoutWriter{ResponseWriter: original, header: original.Header().Clone(), status: http.StatusOK}
+		c.Writer = buffered
+		c.Request = c.Request.WithContext(ctx)
+
+		done := make(chan struct{})
+		var panicked interface{}
+		go func() {
+			defer close(done)
+			defer func() {
+				panicked = recover()
+			}()
+			c.Next()
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				buffered.timeOut()
+				// The handler still owns the gin context, so respond on the
+				// underlying writer
+				writeTimeoutResponse(original, d)
+			}
+			// The gin context is reused once the middleware returns, so wait for
+			// the handler to give it up
+			<-done
+		}
+
+		c.Writer = original
+		if buffered.timedOut {
+			c.Abort()
+			return
+		}
+		if panicked != nil {
+			panic(panicked)
+		}
+		buffered.flushTo(original)
+	}
+}
+
+// writeTimeoutResponse sends the 503 of a request that took longer than d
+func writeTimeoutResponse(w gin.ResponseWriter, d time.Duration) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	json.NewEncoder(w).Encode(utils.Response{
+		Success: false,
+		Message: "Request timed out",
+		Code:    utils.CodeRequestTimeout,
+		Error:   fmt.Sprintf("the request took longer than %s", d),
+	})
+	w.Flush()
+}
+
+// timeoutWriter buffers a response until the handler finishes, after which it
+// is either copied to the client or discarded when the handler ran out of time
+type timeoutWriter struct {
+	gin.ResponseWriter
+	mu          sync.Mutex
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+// timeOut discards the buffered response and everything written after it
+func (w *timeoutWriter) timeOut() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timedOut = true
+	w.body.Reset()
+}
+
+// flushTo copies the buffered response to the client
+func (w *timeoutWriter) flushTo(dst gin.ResponseWriter) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	header := dst.Header()
+	for key := range header {
+		if _, ok := w.header[key]; !ok {
+			header.Del(key)
+		}
+	}
+	for key, values := range w.header {
+		header[key] = values
+	}
+
+	// Without a write the status is left for gin to send, as it would be without the middleware
+	dst.WriteHeader(w.status)
+	if w.wroteHeader {
+		dst.WriteHeaderNow()
+		dst.Write(w.body.Bytes())
+	}
+}
+
+// Header returns the buffered header map
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader records the status of the response
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if code > 0 && !w.wroteHeader {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow marks the header as written
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.wroteHeader = true
+}
+
+// Write buffers data unless the handler has run out of time
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.wroteHeader = true
+	return w.body.Write(data)
+}
+
+// WriteString buffers s unless the handler has run out of time
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Status returns the status recorded so far
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+// Size returns the number of buffered bytes, or -1 when nothing was written
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.wroteHeader {
+		return -1
+	}
+	return w.body.Len()
+}
+
+// Written reports whether the handler has written its header or body
+func (w *timeoutWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.wroteHeader
+}
+
+// Flush is a no-op, the response is only sent once the handler finishes
+func (w *timeoutWriter) Flush() {}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// AuditLog represents a recorded admin action on a resource
+type AuditLog struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ActorID      uint      `gorm:"index;not null" json:"actor_id"`
+	Action       string    `gorm:"size:20;not null" json:"action"` // create, update, delete, etc.
+	ResourceType string    `gorm:"size:50;not null;index" json:"resource_type"`
+	ResourceID   uint      `gorm:"index" json:"resource_id"`
+	Diff         string    `gorm:"type:text" json:"diff,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for AuditLog
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// Audit actions
+const (
+	AuditActionCreate   = "create"
+	AuditActionUpdate   = "update"
+	AuditActionDelete   = "delete"
+	AuditActionReassign = "reassign"
+	AuditActionMerge    = "merge"
+	AuditActionReorder  = "reorder"
+	AuditActionShare    = "share"
+	AuditActionApprove  = "approve"
+	AuditActionExport   = "export"
+	AuditActionImport   = "import"
+	AuditActionAssign   = "assign"
+	AuditActionUnassign = "unassign"
+)
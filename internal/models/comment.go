@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Comment represents a reader comment on a blog post. Comments are hidden
+// from the public until a moderator approves them.
+type Comment struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	BlogID      uint      `gorm:"not null;index" json:"blog_id"`
+	UserID      *uint     `json:"user_id"` // set when a signed-in user comments
+	AuthorName  string    `gorm:"size:100;not null" json:"author_name"`
+	AuthorEmail string    `gorm:"size:100" json:"author_email"`
+	Body        string    `gorm:"type:text;not null" json:"body"`
+	Approved    bool      `gorm:"default:false;index" json:"approved"`
+	IPAddress   string    `gorm:"size:45;index" json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Comment
+func (Comment) TableName() string {
+	return "comments"
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Message represents a message sent through the public contact form
+type Message struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	Email     string    `gorm:"size:100;not null" json:"email"`
+	Subject   string    `gorm:"size:200" json:"subject"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	Read      bool      `gorm:"default:false;index" json:"read"`
+	IPAddress string    `gorm:"size:45;index" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Message
+func (Message) TableName() string {
+	return "messages"
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Outbox event delivery states
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+// OutboxEvent is a content event waiting to be delivered to webhooks. It is
+// written in the same transaction as the change it describes, so an event is
+// recorded exactly when the change is committed.
+type OutboxEvent struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	EventID       string     `gorm:"size:32;not null;uniqueIndex" json:"event_id"` // sent as the delivery id of every attempt
+	Event         string     `gorm:"size:50;not null" json:"event"`
+	Payload       string     `gorm:"not null" json:"payload"` // JSON encoded event data
+	Status        string     `gorm:"size:20;not null;default:'pending';index:idx_outbox_events_due,priority:1" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index:idx_outbox_events_due,priority:2" json:"next_attempt_at"`
+	LastError     string     `gorm:"size:500" json:"last_error"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for OutboxEvent
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
@@ -4,21 +4,28 @@ import "time"
 
 // Project represents a project in the portfolio
 type Project struct {
-	ID          uint            `gorm:"primaryKey" json:"id"`
-	Title       string          `gorm:"size:200;not null" json:"title"`
-	Slug        string          `gorm:"size:200;not null;uniqueIndex" json:"slug"`
-	Description string          `gorm:"type:text" json:"description"`
-	Content     string          `gorm:"type:longtext" json:"content"`
-	CategoryID  uint            `json:"category_id"`
-	Category    ProjectCategory `gorm:"foreignKey:CategoryID" json:"category"`
-	Media       []ProjectMedia  `json:"media"`
-	Tags        []Tag           `gorm:"many2many:project_tags;" json:"tags"`
-	Featured    bool            `gorm:"default:false" json:"featured"`
-	Published   bool            `gorm:"default:true" json:"published"`
-	CreatedBy   uint            `json:"created_by"`
-	UpdatedBy   uint            `json:"updated_by"`
-	CreatedAt   time.Time       `json:"created_at"`
-	UpdatedAt   time.Time       `json:"updated_at"`
+	ID              uint            `gorm:"primaryKey" json:"id"`
+	Title           string          `gorm:"size:200;not null" json:"title"`
+	Slug            string          `gorm:"size:200;not null;uniqueIndex" json:"slug"`
+	Description     string          `gorm:"type:text" json:"description"`
+	Content         string          `gorm:"type:longtext" json:"content"`
+	DraftContent    *string         `gorm:"type:longtext" json:"draft_content"` // autosaved edits, promoted into Content on publish
+	MetaTitle       string          `gorm:"size:200" json:"meta_title"`
+	MetaDescription string          `gorm:"size:500" json:"meta_description"`
+	OGImage         string          `gorm:"size:255" json:"og_image"`
+	CategoryID      uint            `gorm:"index" json:"category_id"`
+	Category        ProjectCategory `gorm:"foreignKey:CategoryID" json:"category"`
+	Media           []ProjectMedia  `json:"media"`
+	Tags            []Tag           `gorm:"many2many:project_tags;" json:"tags"`
+	Technologies    []Technology    `gorm:"many2many:project_technologies;" json:"technologies"`
+	Featured        bool            `gorm:"default:false;index:idx_projects_published_featured,priority:2" json:"featured"`
+	FeaturedOrder   *int            `gorm:"index:idx_projects_published_featured,priority:3" json:"featured_order"`
+	Published       bool            `gorm:"default:true;index:idx_projects_published_created,priority:1;index:idx_projects_published_featured,priority:1" json:"published"`
+	Views           uint            `gorm:"not null;default:0" json:"views"`
+	CreatedBy       uint            `json:"created_by"`
+	UpdatedBy       uint            `json:"updated_by"`
+	CreatedAt       time.Time       `gorm:"index:idx_projects_published_created,priority:2" json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
 }
 
 // TableName specifies the table name for Project
@@ -56,4 +63,4 @@ type ProjectMedia struct {
 // TableName specifies the table name for ProjectMedia
 func (ProjectMedia) TableName() string {
 	return "project_media"
-} 
\ No newline at end of file
+}
@@ -20,6 +20,8 @@ type PersonalInfo struct {
 	Twitter      string         `json:"twitter"`
 	Summary      string         `json:"summary" binding:"required"`
 	ProfileImage string         `json:"profile_image"`
+	CreatedBy    uint           `json:"created_by"`
+	UpdatedBy    uint           `json:"updated_by"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `json:"deleted_at" gorm:"index"`
@@ -32,6 +34,8 @@ type Skill struct {
 	Proficiency int            `json:"proficiency" binding:"required,min=1,max=100"`
 	Category    string         `json:"category"`
 	IconURL     string         `json:"icon_url"`
+	CreatedBy   uint           `json:"created_by"`
+	UpdatedBy   uint           `json:"updated_by"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
@@ -50,6 +54,8 @@ type Experience struct {
 	Achievements string         `json:"achievements"`
 	Website      string         `json:"website"`
 	LogoURL      string         `json:"logo_url"`
+	CreatedBy    uint           `json:"created_by"`
+	UpdatedBy    uint           `json:"updated_by"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `json:"deleted_at" gorm:"index"`
@@ -68,13 +74,16 @@ type Education struct {
 	GPA         string         `json:"gpa"`
 	Description string         `json:"description"`
 	LogoURL     string         `json:"logo_url"`
+	CreatedBy   uint           `json:"created_by"`
+	UpdatedBy   uint           `json:"updated_by"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
-// Project represents project section in resume
-type Project struct {
+// ResumeProject represents project section in resume. It is stored apart
+// from the portfolio projects.
+type ResumeProject struct {
 	ID          uint           `json:"id" gorm:"primaryKey"`
 	Title       string         `json:"title" binding:"required"`
 	Description string         `json:"description" binding:"required"`
@@ -86,6 +95,8 @@ type Project struct {
 	StartDate   time.Time      `json:"start_date"`
 	EndDate     *time.Time     `json:"end_date"`
 	Ongoing     bool           `json:"ongoing"`
+	CreatedBy   uint           `json:"created_by"`
+	UpdatedBy   uint           `json:"updated_by"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
@@ -103,6 +114,8 @@ type Certificate struct {
 	CredentialURL string       `json:"credential_url"`
 	Description string         `json:"description"`
 	LogoURL     string         `json:"logo_url"`
+	CreatedBy   uint           `json:"created_by"`
+	UpdatedBy   uint           `json:"updated_by"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
@@ -113,6 +126,8 @@ type Language struct {
 	ID          uint           `json:"id" gorm:"primaryKey"`
 	Name        string         `json:"name" binding:"required"`
 	Proficiency string         `json:"proficiency" binding:"required"`
+	CreatedBy   uint           `json:"created_by"`
+	UpdatedBy   uint           `json:"updated_by"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
@@ -129,6 +144,8 @@ type Publication struct {
 	DOI         string         `json:"doi"`
 	Description string         `json:"description"`
 	ImageURL    string         `json:"image_url"`
+	CreatedBy   uint           `json:"created_by"`
+	UpdatedBy   uint           `json:"updated_by"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
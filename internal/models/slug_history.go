@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// SlugHistory maps a slug a project or blog post used to have to the record,
+// so that links to the old slug keep resolving after it changes
+type SlugHistory struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ResourceType string    `gorm:"size:50;not null;uniqueIndex:idx_slug_histories_resource_slug" json:"resource_type"`
+	OldSlug      string    `gorm:"size:200;not null;uniqueIndex:idx_slug_histories_resource_slug" json:"old_slug"`
+	ResourceID   uint      `gorm:"not null;index" json:"resource_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for SlugHistory
+func (SlugHistory) TableName() string {
+	return "slug_histories"
+}
+
+// Resource types with a slug history
+const (
+	SlugResourceProject  = "project"
+	SlugResourceBlogPost = "blog_post"
+)
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Technology represents a language, framework or tool used to build portfolio projects
+type Technology struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	Slug      string    `gorm:"size:100;not null;uniqueIndex" json:"slug"`
+	Icon      string    `gorm:"size:255" json:"icon"`
+	Category  string    `gorm:"size:50;index" json:"category"` // language, framework, database, tool, etc.
+	Projects  []Project `gorm:"many2many:project_technologies;" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Technology
+func (Technology) TableName() string {
+	return "technologies"
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Translation holds the localized fields of a project or blog post in one locale.
+// Description is the localized project description or blog post excerpt.
+type Translation struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ResourceType string    `gorm:"size:50;not null;uniqueIndex:idx_translations_resource_locale" json:"resource_type"`
+	ResourceID   uint      `gorm:"not null;uniqueIndex:idx_translations_resource_locale" json:"resource_id"`
+	Locale       string    `gorm:"size:20;not null;uniqueIndex:idx_translations_resource_locale" json:"locale"`
+	Title        string    `gorm:"size:200" json:"title"`
+	Description  string    `gorm:"type:text" json:"description"`
+	Content      string    `gorm:"type:longtext" json:"content"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Translation
+func (Translation) TableName() string {
+	return "translations"
+}
+
+// Translated resource types
+const (
+	TranslationResourceProject  = "project"
+	TranslationResourceBlogPost = "blog_post"
+)
@@ -16,8 +16,15 @@ type User struct {
 	Password  string    `gorm:"size:255;not null" json:"-"`
 	RoleID    uint      `gorm:"not null;default:3" json:"role_id"` // Default to user role (3)
 	Role      Role      `gorm:"foreignKey:RoleID" json:"role"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	// Email verification state. The token is stored as a SHA-256 hash.
+	EmailVerified              bool       `gorm:"not null;default:false" json:"email_verified"`
+	EmailVerificationToken     string     `gorm:"size:64;index" json:"-"`
+	EmailVerificationExpiresAt *time.Time `json:"-"`
+	// Login throttling state
+	FailedLoginCount int        `gorm:"not null;default:0" json:"-"`
+	LockedUntil      *time.Time `json:"-"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
 // TableName specifies the table name for User
@@ -53,6 +60,11 @@ func (u *User) BeforeUpdate(tx *gorm.DB) error {
 	return err
 }
 
+// IsLocked checks if the account is locked at the given time
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
 // CheckPassword checks if the provided password is correct
 func (u *User) CheckPassword(password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
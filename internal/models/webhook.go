@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Webhook represents a subscription that receives signed callbacks for content events
+type Webhook struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	URL       string    `gorm:"size:500;not null" json:"url"`
+	Secret    string    `gorm:"size:255;not null" json:"-"`
+	Events    string    `gorm:"size:500;not null" json:"events"` // comma separated event names
+	Active    bool      `gorm:"default:true" json:"active"`
+	CreatedBy uint      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Webhook
+func (Webhook) TableName() string {
+	return "webhooks"
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"zionechainapi/internal/database"
+)
+
+// Activity item types
+const (
+	ActivityTypeProject  = "project"
+	ActivityTypeBlogPost = "blog_post"
+	ActivityTypeComment  = "comment"
+)
+
+// Activity actions
+const (
+	ActivityActionCreated = "created"
+	ActivityActionUpdated = "updated"
+)
+
+// ActivityService builds the recent changes feed across content types
+type ActivityService struct{}
+
+// NewActivityService creates a new activity service
+func NewActivityService() *ActivityService {
+	return &ActivityService{}
+}
+
+// ActivityItem represents a recently created or updated item in the feed
+type ActivityItem struct {
+	Type      string    `json:"type"`
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"` // the post title for comments
+	Action    string    `json:"action"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// activityRow is a row read from one of the feed's tables
+type activityRow struct {
+	ID        uint
+	Title     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// RecentActivity returns the limit most recently changed projects, blog posts
+// and comments, newest first. Each table is queried for its own latest limit
+// rows, which is all the merged feed can need from it.
+func (s *ActivityService) RecentActivity(limit int) ([]ActivityItem, error) {
+	var projects, posts, comments []activityRow
+
+	if err := database.DB.Table("projects").
+		Select("id, title, created_at, updated_at").
+		Order("updated_at DESC").
+		Limit(limit).
+		Scan(&projects).Error; err != nil {
+		return nil, err
+	}
+
+	if err := database.DB.Table("blog_posts").
+		Select("id, title, created_at, updated_at").
+		Order("updated_at DESC").
+		Limit(limit).
+		Scan(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	if err := database.DB.Table("comments").
+		Select("comments.id, blog_posts.title, comments.created_at, comments.updated_at").
+		Joins("JOIN blog_posts ON blog_posts.id = comments.blog_id").
+		Order("comments.updated_at DESC").
+		Limit(limit).
+		Scan(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]ActivityItem, 0, len(projects)+len(posts)+len(comments))
+	items = appendActivity(items, ActivityTypeProject, projects)
+	items = appendActivity(items, ActivityTypeBlogPost, posts)
+	items = appendActivity(items, ActivityTypeComment, comments)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp.After(items[j].Timestamp)
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	return items, nil
+}
+
+// appendActivity adds rows of one type to the feed, marking rows changed
+// after their creation as updated
+func appendActivity(items []ActivityItem, itemType string, rows []activityRow) []ActivityItem {
+	for _, row := range rows {
+		action := ActivityActionCreated
+		if row.UpdatedAt.After(row.CreatedAt) {
+			action = ActivityActionUpdated
+		}
+		items = append(items, ActivityItem{
+			Type:      itemType,
+			ID:        row.ID,
+			Title:     row.Title,
+			Action:    action,
+			Timestamp: row.UpdatedAt,
+		})
+	}
+	return items
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"time"
+
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+)
+
+// Audit resource types
+const (
+	AuditResourceProject         = "project"
+	AuditResourceBlogPost        = "blog_post"
+	AuditResourceProjectCategory = "project_category"
+	AuditResourceBlogCategory    = "blog_category"
+	AuditResourceTag             = "tag"
+	AuditResourceTranslation     = "translation"
+	AuditResourceWebhook         = "webhook"
+	AuditResourceComment         = "comment"
+	AuditResourceTechnology      = "technology"
+	AuditResourceBackup          = "backup"
+	AuditResourceMedia           = "media"
+	AuditResourceProjectMedia    = "project_media"
+	AuditResourceBlogMedia       = "blog_media"
+)
+
+// AuditService handles audit log operations
+type AuditService struct {
+	ctx context.Context
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService() *AuditService {
+	return &AuditService{}
+}
+
+// WithContext returns a copy of the service whose queries are bound to ctx,
+// usually the request context, so they are cancelled along with it. It leaves
+// s unchanged, as s is shared between requests.
+func (s *AuditService) WithContext(ctx context.Context) *AuditService {
+	bound := *s
+	bound.ctx = ctx
+	return &bound
+}
+
+// AuditLogResponse represents the audit log response
+type AuditLogResponse struct {
+	ID           uint   `json:"id"`
+	ActorID      uint   `json:"actor_id"`
+	Action       string `json:"action"`
+	ResourceType string `json:"resource_type"`
+	ResourceID   uint   `json:"resource_id"`
+	Diff         string `json:"diff,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// AuditLogListResponse represents a page of audit log entries
+type AuditLogListResponse struct {
+	Logs     []AuditLogResponse `json:"logs"`
+	Metadata PageMetadata       `json:"metadata"`
+}
+
+// ListAuditLogs lists audit log entries with pagination, newest first
+func (s *AuditService) ListAuditLogs(page, limit int, actorID uint, resourceType string) ([]AuditLogResponse, int64, error) {
+	var logs []models.AuditLog
+	var total int64
+
+	// Base query
+	query := database.FromContext(s.ctx).Model(&models.AuditLog{})
+
+	// Apply filters
+	if actorID > 0 {
+		query = query.Where("actor_id = ?", actorID)
+	}
+
+	if resourceType != "" {
+		query = query.Where("resource_type = ?", resourceType)
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pagination
+	offset := (page - 1) * limit
+	if err := query.Limit(limit).Offset(offset).Order("created_at DESC, id DESC").Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Map to response
+	response := make([]AuditLogResponse, 0, len(logs))
+	for _, entry := range logs {
+		response = append(response, AuditLogResponse{
+			ID:           entry.ID,
+			ActorID:      entry.ActorID,
+			Action:       entry.Action,
+			ResourceType: entry.ResourceType,
+			ResourceID:   entry.ResourceID,
+			Diff:         entry.Diff,
+			CreatedAt:    entry.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	return response, total, nil
+}
+
+// recordAudit writes an audit log entry for a mutation. Failures are logged
+// rather than returned so that auditing never fails the main operation.
+func recordAudit(actorID uint, action, resourceType string, resourceID uint, diff interface{}) {
+	entry := models.AuditLog{
+		ActorID:      actorID,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+	}
+
+	if diff != nil {
+		if data, err := boundedAuditDiff(diff); err == nil {
+			entry.Diff = data
+		}
+	}
+
+	if err := database.DB.Create(&entry).Error; err != nil {
+		log.Printf("Failed to record audit log for %s %s %d: %v", action, resourceType, resourceID, err)
+	}
+}
+
+// Bounds of a recorded diff, which must fit the diff column whatever the
+// dialect; a MySQL TEXT column holds 65535 bytes
+const (
+	maxAuditValueLength = 1000
+	maxAuditDiffLength  = 60000
+)
+
+// boundedAuditDiff encodes diff as JSON with string values longer than
+// maxAuditValueLength characters truncated, so that a diff carrying the full
+// content of a long post still fits. A diff that is too long even then is
+// reduced to the names of its fields.
+func boundedAuditDiff(diff interface{}) (string, error) {
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return "", err
+	}
+	if len(data) <= maxAuditValueLength {
+		return string(data), nil
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return "", err
+	}
+	if data, err = json.Marshal(truncateAuditValue(value)); err != nil {
+		return "", err
+	}
+	if len(data) <= maxAuditDiffLength {
+		return string(data), nil
+	}
+
+	fields := []string{}
+	if object, ok := value.(map[string]interface{}); ok {
+		for field := range object {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+	}
+	data, err = json.Marshal(map[string]interface{}{"truncated": true, "fields": fields})
+	return string(data), err
+}
+
+// truncateAuditValue shortens the strings of a decoded JSON value to maxAuditValueLength characters
+func truncateAuditValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		if runes := []rune(value); len(runes) > maxAuditValueLength {
+			return string(runes[:maxAuditValueLength]) + "..."
+		}
+		return value
+	case map[string]interface{}:
+		for key, item := range value {
+			value[key] = truncateAuditValue(item)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = truncateAuditValue(item)
+		}
+		return value
+	default:
+		return value
+	}
+}
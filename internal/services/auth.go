@@ -3,28 +3,65 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"zionechainapi/configs"
+	"zionechainapi/internal/clock"
 	"zionechainapi/internal/database"
+	"zionechainapi/internal/mailer"
 	"zionechainapi/internal/models"
-	"golang.org/x/crypto/bcrypt"
+	"zionechainapi/internal/utils"
 	"gorm.io/gorm"
 )
 
+// ErrInvalidCredentials is returned when login is attempted with an unknown phone or a wrong password
+var ErrInvalidCredentials = errors.New("invalid phone or password")
+
+// ErrUserExists is returned when registering with an email or phone that is already taken
+var ErrUserExists = errors.New("user with this email or phone already exists")
+
+// ErrAccountLocked is returned for any login attempt on a temporarily locked account
+var ErrAccountLocked = errors.New("account is temporarily locked due to too many failed login attempts")
+
+// ErrUnknownSigningKey is returned when a token's kid does not match any configured key
+var ErrUnknownSigningKey = errors.New("token signed with an unknown key")
+
+// ErrTokenExpired is returned when a token is well-formed but past its expiry, so the client should refresh
+var ErrTokenExpired = errors.New("token has expired")
+
+// ErrTokenInvalid is returned when a token is malformed, tampered with or otherwise unacceptable
+var ErrTokenInvalid = errors.New("invalid token")
+
 // AuthService handles authentication and authorization
 type AuthService struct {
 	config *configs.Config
+	clock  clock.Clock
+	mailer mailer.Mailer
 }
 
 // NewAuthService creates a new auth service
 func NewAuthService(config *configs.Config) *AuthService {
 	return &AuthService{
 		config: config,
+		clock:  clock.Real{},
+		mailer: mailer.New(config),
 	}
 }
 
+// WithClock replaces the clock used for lockouts and token lifetimes
+func (s *AuthService) WithClock(c clock.Clock) *AuthService {
+	s.clock = c
+	return s
+}
+
+// WithMailer replaces the mailer selected by the configuration
+func (s *AuthService) WithMailer(m mailer.Mailer) *AuthService {
+	s.mailer = m
+	return s
+}
+
 // LoginRequest represents the login request
 type LoginRequest struct {
 	Phone    string `json:"phone" binding:"required"`
@@ -41,31 +78,49 @@ type RegisterRequest struct {
 
 // TokenResponse represents the token response
 type TokenResponse struct {
-	AccessToken  string    `json:"access_token"`
-	RefreshToken string    `json:"refresh_token"`
+	AccessToken  string    `json:"access_token,omitempty"` // omitted when tokens are only delivered as cookies
+	RefreshToken string    `json:"refresh_token,omitempty"`
 	ExpiresAt    time.Time `json:"expires_at"`
 	User         UserResponse `json:"user"`
 }
 
 // UserResponse represents the user response
 type UserResponse struct {
-	ID    uint   `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Phone string `json:"phone"`
-	Role  string `json:"role"`
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Phone         string `json:"phone"`
+	Role          string `json:"role"`
+}
+
+// VerifyTokenRequest represents the token introspection request
+type VerifyTokenRequest struct {
+	Token string `json:"token"`
+}
+
+// VerifyTokenResponse represents the token introspection response
+type VerifyTokenResponse struct {
+	Valid     bool       `json:"valid"`
+	Reason    string     `json:"reason,omitempty"` // AUTH_TOKEN_EXPIRED or AUTH_TOKEN_INVALID when not valid
+	UserID    uint       `json:"user_id,omitempty"`
+	Role      string     `json:"role,omitempty"`
+	Scope     string     `json:"scope,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 // Claims represents the JWT claims
 type Claims struct {
 	UserID uint   `json:"user_id"`
 	Role   string `json:"role"`
+	Scope  string `json:"scope,omitempty"` // set on scoped tokens, which carry no role
 	jwt.RegisteredClaims
 }
 
 // RefreshTokenClaims represents the refresh token claims
 type RefreshTokenClaims struct {
-	UserID uint `json:"user_id"`
+	UserID uint   `json:"user_id"`
+	Scope  string `json:"scope,omitempty"` // never set on refresh tokens, read to turn away scoped tokens
 	jwt.RegisteredClaims
 }
 
@@ -76,14 +131,35 @@ func (s *AuthService) Login(req LoginRequest) (*TokenResponse, error) {
 	// Find user by phone
 	if err := database.DB.Preload("Role").Where("phone = ?", req.Phone).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("invalid phone or password")
+			return nil, ErrInvalidCredentials
 		}
 		return nil, err
 	}
 
+	// Reject every attempt while the account is locked, before the password is
+	// checked, so that the answer does not depend on the password
+	now := s.clock.Now()
+	if user.IsLocked(now) {
+		log.Printf("Rejected login for locked user %d", user.ID)
+		return nil, ErrAccountLocked
+	}
+
 	// Check password
 	if !user.CheckPassword(req.Password) {
-		return nil, errors.New("invalid phone or password")
+		if err := s.recordFailedLogin(&user, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	// Reset throttling state after a successful login
+	if user.FailedLoginCount > 0 || user.LockedUntil != nil {
+		if err := database.DB.Model(&user).Updates(map[string]interface{}{
+			"failed_login_count": 0,
+			"locked_until":       nil,
+		}).Error; err != nil {
+			return nil, err
+		}
 	}
 
 	// Generate tokens
@@ -103,11 +179,12 @@ func (s *AuthService) Login(req LoginRequest) (*TokenResponse, error) {
 		RefreshToken: refreshToken,
 		ExpiresAt:    expiresAt,
 		User: UserResponse{
-			ID:    user.ID,
-			Name:  user.Name,
-			Email: user.Email,
-			Phone: user.Phone,
-			Role:  user.Role.Name,
+			ID:            user.ID,
+			Name:          user.Name,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			Phone:         user.Phone,
+			Role:          user.Role.Name,
 		},
 	}, nil
 }
@@ -121,7 +198,7 @@ func (s *AuthService) Register(req RegisterRequest) (*TokenResponse, error) {
 	}
 
 	if count > 0 {
-		return nil, errors.New("user with this email or phone already exists")
+		return nil, ErrUserExists
 	}
 
 	// Create user
@@ -137,6 +214,11 @@ func (s *AuthService) Register(req RegisterRequest) (*TokenResponse, error) {
 		return nil, err
 	}
 
+	// The account is usable right away, so a failed email does not fail the registration
+	if err := s.sendEmailVerification(&user); err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+	}
+
 	// Load role
 	if err := database.DB.Preload("Role").First(&user, user.ID).Error; err != nil {
 		return nil, err
@@ -159,11 +241,12 @@ func (s *AuthService) Register(req RegisterRequest) (*TokenResponse, error) {
 		RefreshToken: refreshToken,
 		ExpiresAt:    expiresAt,
 		User: UserResponse{
-			ID:    user.ID,
-			Name:  user.Name,
-			Email: user.Email,
-			Phone: user.Phone,
-			Role:  user.Role.Name,
+			ID:            user.ID,
+			Name:          user.Name,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			Phone:         user.Phone,
+			Role:          user.Role.Name,
 		},
 	}, nil
 }
@@ -171,18 +254,24 @@ func (s *AuthService) Register(req RegisterRequest) (*TokenResponse, error) {
 // RefreshToken refreshes the access token using a refresh token
 func (s *AuthService) RefreshToken(refreshToken string) (*TokenResponse, error) {
 	// Parse refresh token
-	token, err := jwt.ParseWithClaims(refreshToken, &RefreshTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.config.JWT.Secret), nil
-	})
+	token, err := jwt.ParseWithClaims(refreshToken, &RefreshTokenClaims{}, s.verificationKey, s.parserOptions()...)
 
 	if err != nil {
-		return nil, err
+		return nil, mapTokenError(err)
 	}
 
 	// Validate token
 	claims, ok := token.Claims.(*RefreshTokenClaims)
 	if !ok || !token.Valid {
-		return nil, errors.New("invalid refresh token")
+		return nil, ErrTokenInvalid
+	}
+	if err := s.checkLegacyClaims(claims.RegisteredClaims); err != nil {
+		return nil, err
+	}
+
+	// Scoped tokens cannot be traded for full access
+	if claims.Scope != "" {
+		return nil, ErrTokenInvalid
 	}
 
 	// Get user
@@ -208,33 +297,59 @@ func (s *AuthService) RefreshToken(refreshToken string) (*TokenResponse, error)
 		RefreshToken: newRefreshToken,
 		ExpiresAt:    expiresAt,
 		User: UserResponse{
-			ID:    user.ID,
-			Name:  user.Name,
-			Email: user.Email,
-			Phone: user.Phone,
-			Role:  user.Role.Name,
+			ID:            user.ID,
+			Name:          user.Name,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			Phone:         user.Phone,
+			Role:          user.Role.Name,
 		},
 	}, nil
 }
 
 // ValidateToken validates a JWT token and returns the claims
 func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.config.JWT.Secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, s.verificationKey, s.parserOptions()...)
 
 	if err != nil {
-		return nil, err
+		return nil, mapTokenError(err)
 	}
 
 	claims, ok := token.Claims.(*Claims)
 	if !ok || !token.Valid {
-		return nil, errors.New("invalid token")
+		return nil, ErrTokenInvalid
+	}
+	if err := s.checkLegacyClaims(claims.RegisteredClaims); err != nil {
+		return nil, err
 	}
 
 	return claims, nil
 }
 
+// VerifyToken reports whether an access token is valid without issuing new tokens
+func (s *AuthService) VerifyToken(tokenString string) VerifyTokenResponse {
+	claims, err := s.ValidateToken(tokenString)
+	if err != nil {
+		if errors.Is(err, ErrTokenExpired) {
+			return VerifyTokenResponse{Reason: utils.CodeAuthTokenExpired}
+		}
+		return VerifyTokenResponse{Reason: utils.CodeAuthTokenInvalid}
+	}
+
+	response := VerifyTokenResponse{
+		Valid:  true,
+		UserID: claims.UserID,
+		Role:   claims.Role,
+		Scope:  claims.Scope,
+	}
+	if claims.ExpiresAt != nil {
+		expiresAt := claims.ExpiresAt.Time.UTC()
+		response.ExpiresAt = &expiresAt
+	}
+
+	return response
+}
+
 // GetUserByID gets a user by ID
 func (s *AuthService) GetUserByID(id uint) (*models.User, error) {
 	var user models.User
@@ -262,37 +377,152 @@ func (s *AuthService) ChangePassword(userID uint, currentPassword, newPassword s
 }
 
 // Helper functions
+func (s *AuthService) recordFailedLogin(user *models.User, now time.Time) error {
+	if s.config.Auth.MaxFailedLogins <= 0 {
+		return database.DB.Model(user).UpdateColumn("failed_login_count", gorm.Expr("failed_login_count + 1")).Error
+	}
+
+	// Count the failure and, once the threshold is reached, lock the account
+	// and start counting again, all in one statement so that concurrent
+	// failures can neither skip the lock nor apply it twice. locked_until is
+	// assigned first because MySQL evaluates assignments against the row as
+	// updated so far.
+	return database.DB.Exec(`UPDATE users SET
+		locked_until = CASE WHEN failed_login_count + 1 >= ? THEN ? ELSE locked_until END,
+		failed_login_count = CASE WHEN failed_login_count + 1 >= ? THEN 0 ELSE failed_login_count + 1 END
+		WHERE id = ?`,
+		s.config.Auth.MaxFailedLogins, now.Add(s.config.Auth.LockoutDuration),
+		s.config.Auth.MaxFailedLogins, user.ID).Error
+}
+
 func (s *AuthService) generateAccessToken(user models.User) (string, time.Time, error) {
-	expiresAt := time.Now().Add(s.config.JWT.AccessTokenExpiry)
+	now := s.clock.Now()
+	expiresAt := now.Add(s.config.JWT.AccessTokenExpiry)
 
 	claims := &Claims{
 		UserID: user.ID,
 		Role:   user.Role.Name,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Subject:   fmt.Sprintf("%d", user.ID),
+			Issuer:    s.config.JWT.Issuer,
+			Audience:  s.audience(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.config.JWT.Secret))
+	tokenString, err := s.signToken(claims)
 
 	return tokenString, expiresAt, err
 }
 
 func (s *AuthService) generateRefreshToken(user models.User) (string, error) {
-	expiresAt := time.Now().Add(s.config.JWT.RefreshTokenExpiry)
+	now := s.clock.Now()
+	expiresAt := now.Add(s.config.JWT.RefreshTokenExpiry)
 
 	claims := &RefreshTokenClaims{
 		UserID: user.ID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Subject:   fmt.Sprintf("%d", user.ID),
+			Issuer:    s.config.JWT.Issuer,
+			Audience:  s.audience(),
 		},
 	}
 
+	return s.signToken(claims)
+}
+
+// signToken signs claims with the primary key, tagging the token with its key ID
+func (s *AuthService) signToken(claims jwt.Claims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if s.config.JWT.KeyID != "" {
+		token.Header["kid"] = s.config.JWT.KeyID
+	}
 	return token.SignedString([]byte(s.config.JWT.Secret))
-} 
\ No newline at end of file
+}
+
+// audience returns the configured audience claim, if any
+func (s *AuthService) audience() jwt.ClaimStrings {
+	if s.config.JWT.Audience == "" {
+		return nil
+	}
+	return jwt.ClaimStrings{s.config.JWT.Audience}
+}
+
+// acceptsLegacyTokens reports whether tokens without issuer and audience
+// claims are still accepted
+func (s *AuthService) acceptsLegacyTokens() bool {
+	return s.clock.Now().Before(s.config.JWT.AcceptLegacyUntil)
+}
+
+// parserOptions requires the configured issuer and audience on parsed tokens
+// and allows the configured clock skew. While legacy tokens are accepted the
+// claims are checked by checkLegacyClaims instead.
+func (s *AuthService) parserOptions() []jwt.ParserOption {
+	options := []jwt.ParserOption{
+		jwt.WithLeeway(s.config.JWT.Leeway),
+		jwt.WithTimeFunc(s.clock.Now),
+	}
+	if s.acceptsLegacyTokens() {
+		return options
+	}
+	if s.config.JWT.Issuer != "" {
+		options = append(options, jwt.WithIssuer(s.config.JWT.Issuer))
+	}
+	if s.config.JWT.Audience != "" {
+		options = append(options, jwt.WithAudience(s.config.JWT.Audience))
+	}
+	return options
+}
+
+// checkLegacyClaims rejects a token carrying an issuer or audience other than
+// the configured ones while tokens without them are accepted
+func (s *AuthService) checkLegacyClaims(claims jwt.RegisteredClaims) error {
+	if !s.acceptsLegacyTokens() {
+		return nil
+	}
+
+	if claims.Issuer != "" && s.config.JWT.Issuer != "" && claims.Issuer != s.config.JWT.Issuer {
+		return fmt.Errorf("%w: %w", ErrTokenInvalid, jwt.ErrTokenInvalidIssuer)
+	}
+
+	if len(claims.Audience) > 0 && s.config.JWT.Audience != "" {
+		for _, audience := range claims.Audience {
+			if audience == s.config.JWT.Audience {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %w", ErrTokenInvalid, jwt.ErrTokenInvalidAudience)
+	}
+
+	return nil
+}
+
+// verificationKey looks up the secret for a token's kid header. Tokens without a
+// kid predate key rotation and are verified with the primary key.
+func (s *AuthService) verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" || kid == s.config.JWT.KeyID {
+		return []byte(s.config.JWT.Secret), nil
+	}
+
+	if secret, ok := s.config.JWT.SecondaryKeys[kid]; ok {
+		return []byte(secret), nil
+	}
+
+	return nil, ErrUnknownSigningKey
+}
+
+// mapTokenError separates expired tokens from invalid ones while keeping the parser's cause
+func mapTokenError(err error) error {
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return ErrTokenExpired
+	}
+	return fmt.Errorf("%w: %w", ErrTokenInvalid, err)
+}
\ No newline at end of file
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// BackupVersion is the version of the backup document format written by Export
+const BackupVersion = 1
+
+// backupBatchSize is the number of rows loaded at a time while exporting
+const backupBatchSize = 100
+
+var (
+	// ErrInvalidBackup is returned when an import is not a well-formed backup document
+	ErrInvalidBackup = errors.New("invalid backup document")
+	// ErrUnsupportedBackupVersion is returned when a backup was written by an unknown format version
+	ErrUnsupportedBackupVersion = errors.New("unsupported backup version")
+)
+
+// backupSection describes one array of a backup document
+type backupSection struct {
+	name    string
+	model   interface{}                               // model value the rows are read into
+	preload []string                                  // associations written along with each row
+	restore func(tx *gorm.DB, item interface{}) error // defaults to upsertBackupRow
+}
+
+// backupSections lists the sections of a backup document. Import restores
+// sections in document order, so rows come after the rows they reference.
+var backupSections = []backupSection{
+	{name: "users", model: models.User{}, preload: []string{"Role"}, restore: restoreBackupUser},
+	{name: "project_categories", model: models.ProjectCategory{}},
+	{name: "blog_categories", model: models.BlogCategory{}},
+	{name: "tags", model: models.Tag{}},
+	{name: "technologies", model: models.Technology{}},
+	{name: "projects", model: models.Project{}, preload: []string{"Category", "Media", "Tags", "Technologies"}, restore: restoreBackupProject},
+	{name: "blog_posts", model: models.BlogPost{}, preload: []string{"Category", "Media", "Tags"}, restore: restoreBackupBlogPost},
+	{name: "personal_info", model: models.PersonalInfo{}},
+	{name: "skills", model: models.Skill{}},
+	{name: "experience", model: models.Experience{}},
+	{name: "education", model: models.Education{}},
+	{name: "certificates", model: models.Certificate{}},
+	{name: "languages", model: models.Language{}},
+	{name: "publications", model: models.Publication{}},
+	{name: "resume_projects", model: models.ResumeProject{}},
+}
+
+// BackupService exports all content as a JSON document and restores it
+type BackupService struct{}
+
+// NewBackupService creates a new backup service
+func NewBackupService() *BackupService {
+	return &BackupService{}
+}
+
+// ImportResponse represents the number of rows restored per section
+type ImportResponse struct {
+	Restored map[string]int `json:"restored"`
+}
+
+// Export writes every section as one JSON document to w. Rows are loaded in
+// batches and written as they are read, so the document is never held in
+// memory. Users are written without their password hashes.
+func (s *BackupService) Export(w io.Writer, userID uint) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `{"version":%d,"exported_at":%q`, BackupVersion, time.Now().UTC().Format(time.RFC3339))
+
+	for _, section := range backupSections {
+		fmt.Fprintf(out, ",%q:[", section.name)
+
+		query := database.DB
+		for _, association := range section.preload {
+			query = query.Preload(association)
+		}
+
+		written := 0
+		batch := reflect.New(reflect.SliceOf(reflect.TypeOf(section.model)))
+		err := query.FindInBatches(batch.Interface(), backupBatchSize, func(tx *gorm.DB, _ int) error {
+			rows := batch.Elem()
+			for i := 0; i < rows.Len(); i++ {
+				data, err := json.Marshal(rows.Index(i).Interface())
+				if err != nil {
+					return err
+				}
+				if written > 0 {
+					out.WriteByte(',')
+				}
+				out.Write(data)
+				written++
+			}
+			return nil
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		out.WriteByte(']')
+	}
+
+	out.WriteString("}\n")
+	if err := out.Flush(); err != nil {
+		return err
+	}
+
+	recordAudit(userID, models.AuditActionExport, AuditResourceBackup, 0, nil)
+
+	return nil
+}
+
+// Import restores a document written by Export in one transaction. Rows are
+// matched by ID: existing rows are overwritten and missing rows are created
+// with their original IDs, so relationships between them are preserved. Rows
+// that are not in the document are left alone. Users keep their current
+// passwords; users created by the import have none and must reset it.
+func (s *BackupService) Import(r io.Reader, userID uint) (*ImportResponse, error) {
+	sections := make(map[string]backupSection, len(backupSections))
+	for _, section := range backupSections {
+		sections[section.name] = section
+	}
+
+	restored := make(map[string]int)
+	dec := json.NewDecoder(r)
+
+	// Start transaction
+	tx := database.DB.Begin()
+
+	err := func() error {
+		if err := expectBackupDelim(dec, '{'); err != nil {
+			return err
+		}
+
+		for dec.More() {
+			token, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+			}
+			key, _ := token.(string)
+
+			if key == "version" {
+				var version int
+				if err := dec.Decode(&version); err != nil {
+					return fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+				}
+				if version != BackupVersion {
+					return ErrUnsupportedBackupVersion
+				}
+				continue
+			}
+
+			section, ok := sections[key]
+			if !ok {
+				// Skip exported_at and keys written by other tools
+				var skipped json.RawMessage
+				if err := dec.Decode(&skipped); err != nil {
+					return fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+				}
+				continue
+			}
+
+			if err := expectBackupDelim(dec, '['); err != nil {
+				return err
+			}
+
+			restore := section.restore
+			if restore == nil {
+				restore = upsertBackupRow
+			}
+
+			for dec.More() {
+				item := reflect.New(reflect.TypeOf(section.model)).Interface()
+				if err := dec.Decode(item); err != nil {
+					return fmt.Errorf("%w: %s: %v", ErrInvalidBackup, section.name, err)
+				}
+				if err := restore(tx, item); err != nil {
+					return err
+				}
+				restored[section.name]++
+			}
+
+			if err := expectBackupDelim(dec, ']'); err != nil {
+				return err
+			}
+		}
+
+		if err := expectBackupDelim(dec, '}'); err != nil {
+			return err
+		}
+
+		return resetBackupSequences(tx, restored)
+	}()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionImport, AuditResourceBackup, 0, restored)
+
+	return &ImportResponse{Restored: restored}, nil
+}
+
+// expectBackupDelim reads the next token and checks that it is the given delimiter
+func expectBackupDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	if token != delim {
+		return fmt.Errorf("%w: expected %q", ErrInvalidBackup, delim)
+	}
+	return nil
+}
+
+// resetBackupSequences moves the ID sequences of the restored tables past the
+// restored IDs on PostgreSQL, where rows written with explicit IDs leave the
+// sequence behind and the next row created without one would reuse an ID.
+// Other databases keep track of explicit IDs themselves.
+func resetBackupSequences(tx *gorm.DB, restored map[string]int) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	for _, section := range backupSections {
+		if restored[section.name] == 0 {
+			continue
+		}
+
+		stmt := &gorm.Statement{DB: tx}
+		if err := stmt.Parse(section.model); err != nil {
+			return err
+		}
+
+		// Media are restored along with their project or post
+		tables := []string{stmt.Schema.Table}
+		for _, association := range section.preload {
+			relation := stmt.Schema.Relationships.Relations[association]
+			if relation != nil && relation.Type == schema.HasMany {
+				tables = append(tables, relation.FieldSchema.Table)
+			}
+		}
+
+		for _, table := range tables {
+			if err := tx.Exec("SELECT setval(pg_get_serial_sequence(?, 'id'), MAX(id)) FROM ?", table, clause.Table{Name: table}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// upsertBackupRow creates a row with its original ID or overwrites the row with that ID.
+// Associations are restored separately.
+func upsertBackupRow(tx *gorm.DB, item interface{}) error {
+	return tx.Omit(clause.Associations).Clauses(clause.OnConflict{UpdateAll: true}).Create(item).Error
+}
+
+// restoreBackupUser restores a user without touching its password
+func restoreBackupUser(tx *gorm.DB, item interface{}) error {
+	user := item.(*models.User)
+	return tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"name", "email", "phone", "role_id", "updated_at"}),
+	}).Create(user).Error
+}
+
+// restoreBackupProject restores a project with its media, tags and technologies
+func restoreBackupProject(tx *gorm.DB, item interface{}) error {
+	project := item.(*models.Project)
+	// Published defaults to true, so a draft has to be written explicitly.
+	// Creating fills the zero value with the default, so keep it first.
+	published := project.Published
+	if err := upsertBackupRow(tx, project); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.Project{ID: project.ID}).UpdateColumn("published", published).Error; err != nil {
+		return err
+	}
+
+	if len(project.Media) > 0 {
+		if err := upsertBackupRow(tx, &project.Media); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(project).Association("Tags").Replace(project.Tags); err != nil {
+		return err
+	}
+
+	return tx.Model(project).Association("Technologies").Replace(project.Technologies)
+}
+
+// restoreBackupBlogPost restores a blog post with its media and tags
+func restoreBackupBlogPost(tx *gorm.DB, item interface{}) error {
+	blog := item.(*models.BlogPost)
+	// Published defaults to true, so a draft has to be written explicitly.
+	// Creating fills the zero value with the default, so keep it first.
+	published := blog.Published
+	if err := upsertBackupRow(tx, blog); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.BlogPost{ID: blog.ID}).UpdateColumn("published", published).Error; err != nil {
+		return err
+	}
+
+	if len(blog.Media) > 0 {
+		if err := upsertBackupRow(tx, &blog.Media); err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(blog).Association("Tags").Replace(blog.Tags)
+}
@@ -1,70 +1,170 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
+	"zionechainapi/internal/clock"
 	"zionechainapi/internal/database"
 	"zionechainapi/internal/models"
+	"zionechainapi/internal/storage"
+	"zionechainapi/internal/utils"
 	"gorm.io/gorm"
 )
 
+// ErrBlogNotFound is returned when a blog post does not exist or is not visible to the caller
+var ErrBlogNotFound = errors.New("blog post not found")
+
 // BlogService handles blog-related operations
-type BlogService struct{}
+type BlogService struct {
+	clock        clock.Clock
+	sanitizeHTML bool
+	webhooks     *WebhookDispatcher
+	storage      storage.Storage
+	ctx          context.Context
+}
 
 // NewBlogService creates a new blog service
 func NewBlogService() *BlogService {
-	return &BlogService{}
+	return &BlogService{
+		clock:        clock.Real{},
+		sanitizeHTML: true,
+	}
+}
+
+// WithClock replaces the clock used for slug timestamps
+func (s *BlogService) WithClock(c clock.Clock) *BlogService {
+	s.clock = c
+	return s
+}
+
+// WithContentSanitization enables or disables stripping unsafe HTML from content
+func (s *BlogService) WithContentSanitization(enabled bool) *BlogService {
+	s.sanitizeHTML = enabled
+	return s
+}
+
+// WithWebhooks sets the dispatcher that records blog post lifecycle events for webhooks
+func (s *BlogService) WithWebhooks(d *WebhookDispatcher) *BlogService {
+	s.webhooks = d
+	return s
+}
+
+// WithStorage sets the storage backend whose files are deleted along with the media using them
+func (s *BlogService) WithStorage(store storage.Storage) *BlogService {
+	s.storage = store
+	return s
+}
+
+// WithContext returns a copy of the service whose queries are bound to ctx,
+// usually the request context, so they are cancelled along with it. Unlike the
+// other builders it leaves s unchanged, as s is shared between requests.
+func (s *BlogService) WithContext(ctx context.Context) *BlogService {
+	bound := *s
+	bound.ctx = ctx
+	return &bound
+}
+
+// db returns the database handle for the queries of the service
+func (s *BlogService) db() *gorm.DB {
+	return database.FromContext(s.ctx)
+}
+
+// sanitizeContent strips unsafe HTML from content when sanitization is enabled
+func (s *BlogService) sanitizeContent(content string) string {
+	if !s.sanitizeHTML {
+		return content
+	}
+	return utils.SanitizeHTML(content)
 }
 
 // CreateBlogRequest represents the create blog request
 type CreateBlogRequest struct {
-	Title      string   `json:"title" binding:"required"`
-	Excerpt    string   `json:"excerpt" binding:"required"`
-	Content    string   `json:"content" binding:"required"`
-	CategoryID uint     `json:"category_id" binding:"required"`
-	TagIDs     []uint   `json:"tag_ids"`
-	Featured   bool     `json:"featured"`
-	Published  bool     `json:"published"`
+	Title           string   `json:"title" binding:"required"`
+	Excerpt         string   `json:"excerpt"` // generated from content when empty
+	Content         string   `json:"content" binding:"required"`
+	CategoryID      uint     `json:"category_id" binding:"required"`
+	TagIDs          []uint   `json:"tag_ids"`
+	Tags            []string `json:"tags"` // tag names, created if missing
+	Featured        bool     `json:"featured"`
+	Published       bool     `json:"published"`
+	MetaTitle       string   `json:"meta_title" binding:"max=200"`       // defaults to the title
+	MetaDescription string   `json:"meta_description" binding:"max=500"` // defaults to the excerpt
+	OGImage         string   `json:"og_image" binding:"max=255"`         // defaults to the first image
 }
 
 // UpdateBlogRequest represents the update blog request
+// Nil string fields are left unchanged, while non-nil fields are applied as-is,
+// which allows clearing a field by sending an empty string.
 type UpdateBlogRequest struct {
-	Title      string  `json:"title"`
-	Excerpt    string  `json:"excerpt"`
-	Content    string  `json:"content"`
-	CategoryID uint    `json:"category_id"`
-	TagIDs     []uint  `json:"tag_ids"`
-	Featured   *bool   `json:"featured"`
-	Published  *bool   `json:"published"`
+	Title           *string `json:"title"`
+	Excerpt         *string `json:"excerpt"`
+	Content         *string `json:"content"`
+	CategoryID      uint    `json:"category_id"`
+	TagIDs          []uint  `json:"tag_ids"`
+	Featured        *bool   `json:"featured"`
+	Published       *bool   `json:"published"`
+	MetaTitle       *string `json:"meta_title" binding:"omitempty,max=200"`
+	MetaDescription *string `json:"meta_description" binding:"omitempty,max=500"`
+	OGImage         *string `json:"og_image" binding:"omitempty,max=255"`
 }
 
 // BlogMediaRequest represents the blog media request
 type BlogMediaRequest struct {
-	Type      string `json:"type" binding:"required"`
+	Type      string `json:"type"` // image, video, embed or document; defaults to image
 	URL       string `json:"url" binding:"required"`
 	Caption   string `json:"caption"`
 	SortOrder int    `json:"sort_order"`
 }
 
+// UpdateBlogMediaRequest represents the update blog media request.
+// Nil fields are left unchanged.
+type UpdateBlogMediaRequest struct {
+	Type      *string `json:"type"`
+	URL       *string `json:"url"`
+	Caption   *string `json:"caption"`
+	SortOrder *int    `json:"sort_order"`
+}
+
 // BlogResponse represents the blog response
 type BlogResponse struct {
-	ID         uint                 `json:"id"`
-	Title      string               `json:"title"`
-	Slug       string               `json:"slug"`
-	Excerpt    string               `json:"excerpt"`
-	Content    string               `json:"content"`
-	CategoryID uint                 `json:"category_id"`
-	Category   BlogCategoryResponse `json:"category"`
-	Media      []BlogMediaResponse  `json:"media"`
-	Tags       []TagResponse        `json:"tags"`
-	Featured   bool                 `json:"featured"`
-	Published  bool                 `json:"published"`
-	CreatedBy  uint                 `json:"created_by"`
-	UpdatedBy  uint                 `json:"updated_by"`
-	CreatedAt  string               `json:"created_at"`
-	UpdatedAt  string               `json:"updated_at"`
+	ID              uint                 `json:"id"`
+	Title           string               `json:"title"`
+	Slug            string               `json:"slug"`
+	Excerpt         string               `json:"excerpt"`
+	Content         string               `json:"content"`
+	MetaTitle       string               `json:"meta_title"`
+	MetaDescription string               `json:"meta_description"`
+	OGImage         string               `json:"og_image"`
+	CategoryID      uint                 `json:"category_id"`
+	Category        BlogCategoryResponse `json:"category"`
+	Media           []BlogMediaResponse  `json:"media"`
+	MediaMetadata   *PageMetadata        `json:"media_metadata,omitempty"` // set when the media are paginated
+	Tags            []TagResponse        `json:"tags"`
+	Featured        bool                 `json:"featured"`
+	FeaturedOrder   *int                 `json:"featured_order"`
+	Published       bool                 `json:"published"`
+	Likes           uint                 `json:"likes"`
+	Views           uint                 `json:"views"`
+	CreatedBy       uint                 `json:"created_by"`
+	UpdatedBy       uint                 `json:"updated_by"`
+	CreatedAt       string               `json:"created_at"`
+	UpdatedAt       string               `json:"updated_at"`
+}
+
+// BlogListResponse represents a page of blog posts
+type BlogListResponse struct {
+	Blogs    []BlogResponse `json:"blogs"`
+	Metadata PageMetadata   `json:"metadata"`
+}
+
+// BlogCursorListResponse represents a page of blog posts fetched with a cursor
+type BlogCursorListResponse struct {
+	Blogs    []BlogResponse `json:"blogs"`
+	Metadata CursorMetadata `json:"metadata"`
 }
 
 // BlogCategoryResponse represents the blog category response
@@ -85,44 +185,64 @@ type BlogMediaResponse struct {
 
 // CreateBlog creates a new blog post
 func (s *BlogService) CreateBlog(req CreateBlogRequest, userID uint) (*BlogResponse, error) {
+	if err := checkCategoryExists(s.db(), &models.BlogCategory{}, req.CategoryID); err != nil {
+		return nil, err
+	}
+
 	// Create slug from title
-	slug := strings.ToLower(strings.ReplaceAll(req.Title, " ", "-"))
+	slug := utils.SanitizeSlug(req.Title)
 
 	// Check if slug already exists
 	var count int64
-	if err := database.DB.Model(&models.BlogPost{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+	if err := s.db().Model(&models.BlogPost{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
 		return nil, err
 	}
 
 	if count > 0 {
 		// Append timestamp to slug to make it unique
-		slug = slug + "-" + string(time.Now().Unix())
+		slug = fmt.Sprintf("%s-%d", slug, s.clock.Now().Unix())
+	}
+
+	content := s.sanitizeContent(req.Content)
+
+	// Generate an excerpt from the content when none is given
+	excerpt := strings.TrimSpace(req.Excerpt)
+	if excerpt == "" {
+		excerpt = utils.GenerateExcerpt(content, utils.DefaultExcerptLength)
 	}
 
 	// Create blog post
 	blog := models.BlogPost{
-		Title:      req.Title,
-		Slug:       slug,
-		Excerpt:    req.Excerpt,
-		Content:    req.Content,
-		CategoryID: req.CategoryID,
-		Featured:   req.Featured,
-		Published:  req.Published,
-		CreatedBy:  userID,
-		UpdatedBy:  userID,
+		Title:           req.Title,
+		Slug:            slug,
+		Excerpt:         excerpt,
+		Content:         content,
+		MetaTitle:       strings.TrimSpace(req.MetaTitle),
+		MetaDescription: strings.TrimSpace(req.MetaDescription),
+		OGImage:         strings.TrimSpace(req.OGImage),
+		CategoryID:      req.CategoryID,
+		Featured:        req.Featured,
+		Published:       req.Published,
+		CreatedBy:       userID,
+		UpdatedBy:       userID,
 	}
 
 	// Start transaction
-	tx := database.DB.Begin()
-	if err := tx.Create(&blog).Error; err != nil {
+	tx := s.db().Begin()
+	// A concurrent create with the same title may have taken the slug after
+	// the check above, so retry once with a random suffix
+	if err := writeSlug(tx, blog.Slug, utils.SanitizeSlug(req.Title), func(candidate string) error {
+		blog.Slug = candidate
+		return tx.Create(&blog).Error
+	}); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
-	// Add tags if any
-	if len(req.TagIDs) > 0 {
-		var tags []models.Tag
-		if err := tx.Where("id IN ?", req.TagIDs).Find(&tags).Error; err != nil {
+	// Add tags if any, creating named tags that do not exist yet
+	if len(req.TagIDs) > 0 || len(req.Tags) > 0 {
+		tags, err := resolveTags(tx, req.TagIDs, req.Tags)
+		if err != nil {
 			tx.Rollback()
 			return nil, err
 		}
@@ -133,65 +253,146 @@ func (s *BlogService) CreateBlog(req CreateBlogRequest, userID uint) (*BlogRespo
 		}
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
+	// Load blog with relationships
+	if err := tx.Preload("Category").Preload("Media").Preload("Tags").First(&blog, blog.ID).Error; err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
-	// Load blog with relationships
-	if err := database.DB.Preload("Category").Preload("Media").Preload("Tags").First(&blog, blog.ID).Error; err != nil {
+	// Map to response
+	response := s.mapBlogToResponse(blog)
+
+	// Record the events with the post so they are delivered exactly when it is created
+	if err := s.webhooks.Enqueue(tx, WebhookEventBlogCreated, response); err != nil {
+		tx.Rollback()
 		return nil, err
 	}
+	if response.Published {
+		if err := s.webhooks.Enqueue(tx, WebhookEventBlogPublished, response); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
 
-	// Map to response
-	return s.mapBlogToResponse(blog), nil
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionCreate, AuditResourceBlogPost, blog.ID, req)
+
+	return response, nil
 }
 
-// GetBlogByID gets a blog post by ID
-func (s *BlogService) GetBlogByID(id uint) (*BlogResponse, error) {
+// GetBlogByID gets a blog post by ID with the selected page of its media.
+// Unpublished blog posts are only found when drafts is set.
+func (s *BlogService) GetBlogByID(id uint, mediaPage MediaPage, drafts bool) (*BlogResponse, error) {
 	var blog models.BlogPost
-	if err := database.DB.Preload("Category").Preload("Media").Preload("Tags").First(&blog, id).Error; err != nil {
+	if err := s.detailQuery(mediaPage, drafts).First(&blog, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("blog post not found")
 		}
 		return nil, err
 	}
 
-	return s.mapBlogToResponse(blog), nil
+	return s.mapBlogDetailToResponse(blog, mediaPage)
 }
 
-// GetBlogBySlug gets a blog post by slug
-func (s *BlogService) GetBlogBySlug(slug string) (*BlogResponse, error) {
+// GetBlogBySlug gets a blog post by slug with the selected page of its media,
+// falling back to the slugs blog posts used to have. The slug of the returned
+// blog post is its current one. Unpublished blog posts are only found when
+// drafts is set.
+func (s *BlogService) GetBlogBySlug(slug string, mediaPage MediaPage, drafts bool) (*BlogResponse, error) {
 	var blog models.BlogPost
-	if err := database.DB.Preload("Category").Preload("Media").Preload("Tags").Where("slug = ?", slug).First(&blog).Error; err != nil {
+	err := s.detailQuery(mediaPage, drafts).Where("slug = ?", slug).First(&blog).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		var id uint
+		if id, err = previousSlugOwner(s.db(), models.SlugResourceBlogPost, slug); err == nil {
+			err = s.detailQuery(mediaPage, drafts).First(&blog, id).Error
+		}
+	}
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("blog post not found")
 		}
 		return nil, err
 	}
 
-	return s.mapBlogToResponse(blog), nil
+	return s.mapBlogDetailToResponse(blog, mediaPage)
 }
 
-// ListBlogs lists all blog posts with pagination
-func (s *BlogService) ListBlogs(page, limit int, categoryID uint, featured, published bool) ([]BlogResponse, int64, error) {
-	var blogs []models.BlogPost
-	var total int64
+// detailQuery preloads the associations of a blog post detail response,
+// leaving out unpublished blog posts unless drafts is set
+func (s *BlogService) detailQuery(mediaPage MediaPage, drafts bool) *gorm.DB {
+	query := preloadMedia(s.db().Preload("Category"), mediaPage).Preload("Tags")
+	if !drafts {
+		query = query.Where("published = ?", true)
+	}
+	return query
+}
 
-	// Base query
-	query := database.DB.Model(&models.BlogPost{})
+// mapBlogDetailToResponse maps a blog post detail to a response, describing its media page
+func (s *BlogService) mapBlogDetailToResponse(blog models.BlogPost, mediaPage MediaPage) (*BlogResponse, error) {
+	metadata, err := mediaMetadata(s.db(), &models.BlogMedia{}, "blog_id", blog.ID, mediaPage)
+	if err != nil {
+		return nil, err
+	}
 
-	// Apply filters
-	if categoryID > 0 {
-		query = query.Where("category_id = ?", categoryID)
+	response := s.mapBlogToResponse(blog)
+	response.MediaMetadata = metadata
+	return response, nil
+}
+
+// GetBlogMetaBySlug gets the SEO metadata of a blog post by slug, falling back
+// to the title, excerpt and first image for empty fields. Like GetBlogBySlug,
+// it also finds blog posts by a previous slug, and only finds unpublished
+// posts when drafts is set.
+func (s *BlogService) GetBlogMetaBySlug(slug string, drafts bool) (*SEOMetaResponse, error) {
+	query := func() *gorm.DB {
+		query := s.db().Select("id", "title", "slug", "excerpt", "meta_title", "meta_description", "og_image", "updated_at").
+			Preload("Media")
+		if !drafts {
+			query = query.Where("published = ?", true)
+		}
+		return query
 	}
 
-	if featured {
-		query = query.Where("featured = ?", featured)
+	var blog models.BlogPost
+	err := query().Where("slug = ?", slug).First(&blog).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		var id uint
+		if id, err = previousSlugOwner(s.db(), models.SlugResourceBlogPost, slug); err == nil {
+			err = query().First(&blog, id).Error
+		}
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBlogNotFound
+		}
+		return nil, err
 	}
 
-	// Default to published only
-	query = query.Where("published = ?", published)
+	meta := &SEOMetaResponse{
+		Slug:      blog.Slug,
+		UpdatedAt: blog.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	meta.MetaTitle, meta.MetaDescription, meta.OGImage = blogSEOFields(blog).resolve()
+
+	return meta, nil
+}
+
+// CheckSlugAvailability reports the slug generated from a title and whether no blog post uses it yet
+func (s *BlogService) CheckSlugAvailability(title string) (*SlugAvailabilityResponse, error) {
+	return checkSlugAvailability(s.db(), &models.BlogPost{}, title)
+}
+
+// ListBlogs lists all blog posts with pagination
+func (s *BlogService) ListBlogs(page, limit int, filter ListFilter) ([]BlogResponse, int64, error) {
+	var blogs []models.BlogPost
+	var total int64
+
+	// Base query
+	query := blogListTarget.apply(s.db().Model(&models.BlogPost{}), filter)
 
 	// Count total
 	if err := query.Count(&total).Error; err != nil {
@@ -199,16 +400,14 @@ func (s *BlogService) ListBlogs(page, limit int, categoryID uint, featured, publ
 	}
 
 	// Pagination
-	offset := (page - 1) * limit
-	if err := query.Preload("Category").Preload("Media").Preload("Tags").
-		Limit(limit).Offset(offset).
-		Order("created_at DESC").
+	if err := paginate(query, page, limit).Preload("Category").Preload("Media").Preload("Tags").
+		Order(listOrder(filter)).
 		Find(&blogs).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Map to response
-	var response []BlogResponse
+	response := make([]BlogResponse, 0, len(blogs))
 	for _, blog := range blogs {
 		response = append(response, *s.mapBlogToResponse(blog))
 	}
@@ -216,45 +415,176 @@ func (s *BlogService) ListBlogs(page, limit int, categoryID uint, featured, publ
 	return response, total, nil
 }
 
+// ListBlogsAfter lists blog posts using cursor pagination, returning the page
+// after the cursor and the cursor of the next page, which is empty on the last page
+func (s *BlogService) ListBlogsAfter(cursor string, limit int, filter ListFilter) ([]BlogResponse, string, error) {
+	var blogs []models.BlogPost
+
+	query, err := paginateAfter(blogListTarget.apply(s.db().Model(&models.BlogPost{}), filter), filter, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := query.Preload("Category").Preload("Media").Preload("Tags").Find(&blogs).Error; err != nil {
+		return nil, "", err
+	}
+
+	// The extra row only signals that another page exists
+	nextCursor := ""
+	if len(blogs) > limit {
+		blogs = blogs[:limit]
+		last := blogs[limit-1]
+		nextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	// Map to response
+	response := make([]BlogResponse, 0, len(blogs))
+	for _, blog := range blogs {
+		response = append(response, *s.mapBlogToResponse(blog))
+	}
+
+	return response, nextCursor, nil
+}
+
+// ReorderFeatured sets the display order of featured blog posts to the order of the given ids
+func (s *BlogService) ReorderFeatured(req ReorderFeaturedRequest, userID uint) ([]BlogResponse, error) {
+	tx := s.db().Begin()
+
+	if err := reorderFeatured(tx, &models.BlogPost{}, req.IDs); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionReorder, AuditResourceBlogPost, 0, req)
+
+	var blogs []models.BlogPost
+	if err := s.db().Preload("Category").Preload("Media").Preload("Tags").
+		Where("id IN ?", req.IDs).
+		Order("featured_order ASC").
+		Find(&blogs).Error; err != nil {
+		return nil, err
+	}
+
+	// Map to response
+	response := make([]BlogResponse, 0, len(blogs))
+	for _, blog := range blogs {
+		response = append(response, *s.mapBlogToResponse(blog))
+	}
+
+	return response, nil
+}
+
+// CountBlogs counts the blog posts matching the given filter
+func (s *BlogService) CountBlogs(filter ListFilter) (int64, error) {
+	var count int64
+	if err := blogListTarget.apply(s.db().Model(&models.BlogPost{}), filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// LastModifiedBlogs returns the latest update time of the published blog posts
+func (s *BlogService) LastModifiedBlogs() (*LastModifiedResponse, error) {
+	lastModified, err := publishedLastModified(s.db(), &models.BlogPost{})
+	if err != nil {
+		return nil, err
+	}
+	return &LastModifiedResponse{LastModified: lastModified}, nil
+}
+
 // UpdateBlog updates a blog post
 func (s *BlogService) UpdateBlog(id uint, req UpdateBlogRequest, userID uint) (*BlogResponse, error) {
 	var blog models.BlogPost
-	if err := database.DB.First(&blog, id).Error; err != nil {
+	if err := s.db().First(&blog, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("blog post not found")
 		}
 		return nil, err
 	}
 
+	if req.CategoryID > 0 && req.CategoryID != blog.CategoryID {
+		if err := checkCategoryExists(s.db(), &models.BlogCategory{}, req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Update fields if provided
-	tx := database.DB.Begin()
+	tx := s.db().Begin()
 
-	if req.Title != "" && req.Title != blog.Title {
+	response, err := s.applyBlogUpdate(tx, &blog, req, userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionUpdate, AuditResourceBlogPost, id, req)
+
+	return response, nil
+}
+
+// applyBlogUpdate applies an update request to a loaded blog post within tx and records
+// the lifecycle events. The caller rolls back tx on error and commits it.
+func (s *BlogService) applyBlogUpdate(tx *gorm.DB, blog *models.BlogPost, req UpdateBlogRequest, userID uint) (*BlogResponse, error) {
+	wasPublished := blog.Published
+
+	if req.Title != nil && *req.Title == "" {
+		return nil, errors.New("title cannot be empty")
+	}
+
+	if req.Title != nil && *req.Title != blog.Title {
 		// Create new slug from title
-		slug := strings.ToLower(strings.ReplaceAll(req.Title, " ", "-"))
+		slug := utils.SanitizeSlug(*req.Title)
 
 		// Check if slug already exists and is not this blog
 		var count int64
-		if err := tx.Model(&models.BlogPost{}).Where("slug = ? AND id != ?", slug, id).Count(&count).Error; err != nil {
-			tx.Rollback()
+		if err := tx.Model(&models.BlogPost{}).Where("slug = ? AND id != ?", slug, blog.ID).Count(&count).Error; err != nil {
 			return nil, err
 		}
 
 		if count > 0 {
 			// Append timestamp to slug to make it unique
-			slug = slug + "-" + string(time.Now().Unix())
+			slug = fmt.Sprintf("%s-%d", slug, s.clock.Now().Unix())
+		}
+
+		// Keep links to the old slug working
+		if slug != blog.Slug {
+			if err := recordSlugChange(tx, models.SlugResourceBlogPost, blog.Slug, blog.ID); err != nil {
+				return nil, err
+			}
 		}
 
-		blog.Title = req.Title
+		blog.Title = *req.Title
 		blog.Slug = slug
 	}
 
-	if req.Excerpt != "" {
-		blog.Excerpt = req.Excerpt
+	if req.Excerpt != nil {
+		blog.Excerpt = *req.Excerpt
 	}
 
-	if req.Content != "" {
-		blog.Content = req.Content
+	if req.Content != nil {
+		blog.Content = s.sanitizeContent(*req.Content)
+	}
+
+	if req.MetaTitle != nil {
+		blog.MetaTitle = strings.TrimSpace(*req.MetaTitle)
+	}
+
+	if req.MetaDescription != nil {
+		blog.MetaDescription = strings.TrimSpace(*req.MetaDescription)
+	}
+
+	if req.OGImage != nil {
+		blog.OGImage = strings.TrimSpace(*req.OGImage)
 	}
 
 	if req.CategoryID > 0 {
@@ -263,6 +593,9 @@ func (s *BlogService) UpdateBlog(id uint, req UpdateBlogRequest, userID uint) (*
 
 	if req.Featured != nil {
 		blog.Featured = *req.Featured
+		if !blog.Featured {
+			blog.FeaturedOrder = nil
+		}
 	}
 
 	if req.Published != nil {
@@ -271,8 +604,7 @@ func (s *BlogService) UpdateBlog(id uint, req UpdateBlogRequest, userID uint) (*
 
 	blog.UpdatedBy = userID
 
-	if err := tx.Save(&blog).Error; err != nil {
-		tx.Rollback()
+	if err := tx.Save(blog).Error; err != nil {
 		return nil, err
 	}
 
@@ -280,41 +612,62 @@ func (s *BlogService) UpdateBlog(id uint, req UpdateBlogRequest, userID uint) (*
 	if len(req.TagIDs) > 0 {
 		var tags []models.Tag
 		if err := tx.Where("id IN ?", req.TagIDs).Find(&tags).Error; err != nil {
-			tx.Rollback()
 			return nil, err
 		}
 
-		if err := tx.Model(&blog).Association("Tags").Replace(tags); err != nil {
-			tx.Rollback()
+		if err := tx.Model(blog).Association("Tags").Replace(tags); err != nil {
 			return nil, err
 		}
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
+	// Load blog with relationships
+	if err := tx.Preload("Category").Preload("Media").Preload("Tags").First(blog, blog.ID).Error; err != nil {
 		return nil, err
 	}
 
-	// Load blog with relationships
-	if err := database.DB.Preload("Category").Preload("Media").Preload("Tags").First(&blog, id).Error; err != nil {
+	response := s.mapBlogToResponse(*blog)
+
+	if err := s.webhooks.Enqueue(tx, WebhookEventBlogUpdated, response); err != nil {
 		return nil, err
 	}
+	if response.Published && !wasPublished {
+		if err := s.webhooks.Enqueue(tx, WebhookEventBlogPublished, response); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// enqueueBlogUpdated announces a change of the blog post, such as to its
+// slug or media, made inside tx
+func (s *BlogService) enqueueBlogUpdated(tx *gorm.DB, id uint) error {
+	var blog models.BlogPost
+	if err := tx.Preload("Category").Preload("Media").Preload("Tags").First(&blog, id).Error; err != nil {
+		return err
+	}
 
-	return s.mapBlogToResponse(blog), nil
+	return s.webhooks.Enqueue(tx, WebhookEventBlogUpdated, s.mapBlogToResponse(blog))
 }
 
 // DeleteBlog deletes a blog post
-func (s *BlogService) DeleteBlog(id uint) error {
+func (s *BlogService) DeleteBlog(id, userID uint) error {
 	var blog models.BlogPost
-	if err := database.DB.First(&blog, id).Error; err != nil {
+	if err := s.db().First(&blog, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("blog post not found")
 		}
 		return err
 	}
 
+	// Remember the media files to delete once the rows are gone
+	var mediaURLs []string
+	if err := s.db().Model(&models.BlogMedia{}).Where("blog_id = ?", id).Pluck("url", &mediaURLs).Error; err != nil {
+		return err
+	}
+
 	// Start transaction
-	tx := database.DB.Begin()
+	tx := s.db().Begin()
 
 	// Delete media
 	if err := tx.Where("blog_id = ?", id).Delete(&models.BlogMedia{}).Error; err != nil {
@@ -328,38 +681,175 @@ func (s *BlogService) DeleteBlog(id uint) error {
 		return err
 	}
 
+	// Delete translations
+	if err := deleteTranslations(tx, models.TranslationResourceBlogPost, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Delete comments
+	if err := deleteComments(tx, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Delete blog
 	if err := tx.Delete(&blog).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
 
+	if err := s.webhooks.Enqueue(tx, WebhookEventBlogDeleted, map[string]interface{}{"id": id, "slug": blog.Slug}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	recordAudit(userID, models.AuditActionDelete, AuditResourceBlogPost, id, nil)
+
+	for _, url := range mediaURLs {
+		deleteStoredFile(s.storage, url)
+	}
+
+	return nil
+}
+
+// DuplicateBlog copies a blog post with its tags and media into a new unpublished, unfeatured
+// draft owned by the given user
+func (s *BlogService) DuplicateBlog(id, userID uint) (*BlogResponse, error) {
+	var blog models.BlogPost
+	if err := s.db().Preload("Media").Preload("Tags").First(&blog, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBlogNotFound
+		}
+		return nil, err
+	}
+
+	// Start transaction
+	tx := s.db().Begin()
+
+	slug, err := utils.GenerateCopySlug(blog.Slug, func(slug string) (bool, error) {
+		var count int64
+		err := tx.Model(&models.BlogPost{}).Where("slug = ?", slug).Count(&count).Error
+		return count > 0, err
+	})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	duplicate := models.BlogPost{
+		Title:           blog.Title,
+		Slug:            slug,
+		Excerpt:         blog.Excerpt,
+		Content:         blog.Content,
+		MetaTitle:       blog.MetaTitle,
+		MetaDescription: blog.MetaDescription,
+		OGImage:         blog.OGImage,
+		CategoryID:      blog.CategoryID,
+		CreatedBy:       userID,
+		UpdatedBy:       userID,
+	}
+
+	if err := tx.Create(&duplicate).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// The column default would publish the draft on insert, so unpublish it explicitly
+	if err := tx.Model(&duplicate).Update("published", false).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Copy tags
+	if len(blog.Tags) > 0 {
+		if err := tx.Model(&duplicate).Association("Tags").Replace(blog.Tags); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// Copy media
+	for _, media := range blog.Media {
+		copied := models.BlogMedia{
+			BlogID:    duplicate.ID,
+			Type:      media.Type,
+			URL:       media.URL,
+			Caption:   media.Caption,
+			SortOrder: media.SortOrder,
+		}
+		if err := tx.Create(&copied).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// Load blog post with relationships
+	if err := tx.Preload("Category").Preload("Media").Preload("Tags").First(&duplicate, duplicate.ID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Map to response
+	response := s.mapBlogToResponse(duplicate)
+
+	// The copy is a new draft, announced like one created from scratch
+	if err := s.webhooks.Enqueue(tx, WebhookEventBlogCreated, response); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// Commit transaction
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionCreate, AuditResourceBlogPost, duplicate.ID, map[string]interface{}{"duplicated_from": id})
+
+	return response, nil
 }
 
 // AddBlogMedia adds media to a blog post
-func (s *BlogService) AddBlogMedia(blogID uint, req BlogMediaRequest) (*BlogMediaResponse, error) {
+func (s *BlogService) AddBlogMedia(blogID, userID uint, req BlogMediaRequest) (*BlogMediaResponse, error) {
 	var blog models.BlogPost
-	if err := database.DB.First(&blog, blogID).Error; err != nil {
+	if err := s.db().First(&blog, blogID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("blog post not found")
 		}
 		return nil, err
 	}
 
+	mediaType, err := validateMedia(req.Type, req.URL)
+	if err != nil {
+		return nil, err
+	}
+
 	media := models.BlogMedia{
 		BlogID:    blogID,
-		Type:      req.Type,
+		Type:      mediaType,
 		URL:       req.URL,
 		Caption:   req.Caption,
 		SortOrder: req.SortOrder,
 	}
 
-	if err := database.DB.Create(&media).Error; err != nil {
+	// The parent is announced as updated since its rendered media changed
+	err = s.db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&media).Error; err != nil {
+			return err
+		}
+		return s.enqueueBlogUpdated(tx, blogID)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	recordAudit(userID, models.AuditActionCreate, AuditResourceBlogMedia, media.ID, map[string]interface{}{"blog_id": blogID, "media": req})
+
 	return &BlogMediaResponse{
 		ID:        media.ID,
 		Type:      media.Type,
@@ -369,25 +859,89 @@ func (s *BlogService) AddBlogMedia(blogID uint, req BlogMediaRequest) (*BlogMedi
 	}, nil
 }
 
-// UpdateBlogMedia updates blog media
-func (s *BlogService) UpdateBlogMedia(mediaID uint, req BlogMediaRequest) (*BlogMediaResponse, error) {
+// ListBlogMedia lists the media of a blog post ordered by sort order. The media
+// of unpublished posts are only listed when drafts is set.
+func (s *BlogService) ListBlogMedia(blogID uint, drafts bool) ([]BlogMediaResponse, error) {
+	query := s.db()
+	if !drafts {
+		query = query.Where("published = ?", true)
+	}
+
+	var blog models.BlogPost
+	if err := query.First(&blog, blogID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBlogNotFound
+		}
+		return nil, err
+	}
+
+	var media []models.BlogMedia
+	if err := s.db().Where("blog_id = ?", blogID).Order("sort_order ASC, id ASC").Find(&media).Error; err != nil {
+		return nil, err
+	}
+
+	response := make([]BlogMediaResponse, 0, len(media))
+	for _, item := range media {
+		response = append(response, BlogMediaResponse{
+			ID:        item.ID,
+			Type:      item.Type,
+			URL:       item.URL,
+			Caption:   item.Caption,
+			SortOrder: item.SortOrder,
+		})
+	}
+
+	return response, nil
+}
+
+// UpdateBlogMedia updates the provided fields of blog media
+func (s *BlogService) UpdateBlogMedia(mediaID, userID uint, req UpdateBlogMediaRequest) (*BlogMediaResponse, error) {
 	var media models.BlogMedia
-	if err := database.DB.First(&media, mediaID).Error; err != nil {
+	if err := s.db().First(&media, mediaID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("media not found")
 		}
 		return nil, err
 	}
 
-	media.Type = req.Type
-	media.URL = req.URL
-	media.Caption = req.Caption
-	media.SortOrder = req.SortOrder
+	// Validate the resulting type and URL together
+	if req.Type != nil || req.URL != nil {
+		mediaType, mediaURL := media.Type, media.URL
+		if req.Type != nil {
+			mediaType = *req.Type
+		}
+		if req.URL != nil {
+			mediaURL = *req.URL
+		}
+
+		mediaType, err := validateMedia(mediaType, mediaURL)
+		if err != nil {
+			return nil, err
+		}
+		media.Type = mediaType
+		media.URL = mediaURL
+	}
+
+	if req.Caption != nil {
+		media.Caption = *req.Caption
+	}
 
-	if err := database.DB.Save(&media).Error; err != nil {
+	if req.SortOrder != nil {
+		media.SortOrder = *req.SortOrder
+	}
+
+	err := s.db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&media).Error; err != nil {
+			return err
+		}
+		return s.enqueueBlogUpdated(tx, media.BlogID)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	recordAudit(userID, models.AuditActionUpdate, AuditResourceBlogMedia, media.ID, map[string]interface{}{"blog_id": media.BlogID, "media": req})
+
 	return &BlogMediaResponse{
 		ID:        media.ID,
 		Type:      media.Type,
@@ -398,34 +952,73 @@ func (s *BlogService) UpdateBlogMedia(mediaID uint, req BlogMediaRequest) (*Blog
 }
 
 // DeleteBlogMedia deletes blog media
-func (s *BlogService) DeleteBlogMedia(mediaID uint) error {
+func (s *BlogService) DeleteBlogMedia(mediaID, userID uint) error {
 	var media models.BlogMedia
-	if err := database.DB.First(&media, mediaID).Error; err != nil {
+	if err := s.db().First(&media, mediaID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("media not found")
 		}
 		return err
 	}
 
-	return database.DB.Delete(&media).Error
+	err := s.db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&media).Error; err != nil {
+			return err
+		}
+		return s.enqueueBlogUpdated(tx, media.BlogID)
+	})
+	if err != nil {
+		return err
+	}
+
+	recordAudit(userID, models.AuditActionDelete, AuditResourceBlogMedia, media.ID, map[string]interface{}{"blog_id": media.BlogID, "url": media.URL})
+
+	deleteStoredFile(s.storage, media.URL)
+
+	return nil
 }
 
 // Helper functions
+// blogSEOFields collects the stored meta of a blog post and the content it falls back to
+func blogSEOFields(blog models.BlogPost) seoFields {
+	fields := seoFields{
+		metaTitle:       blog.MetaTitle,
+		metaDescription: blog.MetaDescription,
+		ogImage:         blog.OGImage,
+		title:           blog.Title,
+		summary:         blog.Excerpt,
+	}
+
+	// The cover is the first image in sort order
+	coverOrder := 0
+	for _, media := range blog.Media {
+		if media.Type == MediaTypeImage && (fields.coverImage == "" || media.SortOrder < coverOrder) {
+			fields.coverImage, coverOrder = media.URL, media.SortOrder
+		}
+	}
+
+	return fields
+}
+
 func (s *BlogService) mapBlogToResponse(blog models.BlogPost) *BlogResponse {
 	response := &BlogResponse{
-		ID:         blog.ID,
-		Title:      blog.Title,
-		Slug:       blog.Slug,
-		Excerpt:    blog.Excerpt,
-		Content:    blog.Content,
-		CategoryID: blog.CategoryID,
-		Featured:   blog.Featured,
-		Published:  blog.Published,
-		CreatedBy:  blog.CreatedBy,
-		UpdatedBy:  blog.UpdatedBy,
-		CreatedAt:  blog.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:  blog.UpdatedAt.Format(time.RFC3339),
+		ID:            blog.ID,
+		Title:         blog.Title,
+		Slug:          blog.Slug,
+		Excerpt:       blog.Excerpt,
+		Content:       blog.Content,
+		CategoryID:    blog.CategoryID,
+		Featured:      blog.Featured,
+		FeaturedOrder: blog.FeaturedOrder,
+		Published:     blog.Published,
+		Likes:         blog.Likes,
+		Views:         blog.Views,
+		CreatedBy:     blog.CreatedBy,
+		UpdatedBy:     blog.UpdatedBy,
+		CreatedAt:     blog.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     blog.UpdatedAt.UTC().Format(time.RFC3339),
 	}
+	response.MetaTitle, response.MetaDescription, response.OGImage = blogSEOFields(blog).resolve()
 
 	// Map category
 	if blog.Category.ID > 0 {
@@ -436,7 +1029,8 @@ func (s *BlogService) mapBlogToResponse(blog models.BlogPost) *BlogResponse {
 		}
 	}
 
-	// Map media
+	// Map media and tags, keeping empty collections as [] rather than null
+	response.Media = make([]BlogMediaResponse, 0, len(blog.Media))
 	for _, media := range blog.Media {
 		response.Media = append(response.Media, BlogMediaResponse{
 			ID:        media.ID,
@@ -447,7 +1041,7 @@ func (s *BlogService) mapBlogToResponse(blog models.BlogPost) *BlogResponse {
 		})
 	}
 
-	// Map tags
+	response.Tags = make([]TagResponse, 0, len(blog.Tags))
 	for _, tag := range blog.Tags {
 		response.Tags = append(response.Tags, TagResponse{
 			ID:   tag.ID,
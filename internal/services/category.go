@@ -1,27 +1,81 @@
 package services
 
 import (
+	"context"
 	"errors"
-	"strings"
 
 	"zionechainapi/internal/database"
 	"zionechainapi/internal/models"
+	"zionechainapi/internal/utils"
 	"gorm.io/gorm"
 )
 
+// ErrCategoryNameExists is returned when a category name resolves to a slug that is already taken
+var ErrCategoryNameExists = errors.New("category with this name already exists")
+
+// ErrCategoryNameInvalid is returned when a category name does not produce a usable slug
+var ErrCategoryNameInvalid = errors.New("category name must contain at least one letter or digit")
+
+// ErrCategoryNotFound is returned when content is assigned to a category that does not exist
+var ErrCategoryNotFound = errors.New("category not found")
+
 // CategoryService handles category-related operations
-type CategoryService struct{}
+type CategoryService struct {
+	ctx context.Context
+}
 
 // NewCategoryService creates a new category service
 func NewCategoryService() *CategoryService {
 	return &CategoryService{}
 }
 
+// WithContext returns a copy of the service whose queries are bound to ctx,
+// usually the request context, so they are cancelled along with it. It leaves
+// s unchanged, as s is shared between requests.
+func (s *CategoryService) WithContext(ctx context.Context) *CategoryService {
+	bound := *s
+	bound.ctx = ctx
+	return &bound
+}
+
+// db returns the database handle for the queries of the service
+func (s *CategoryService) db() *gorm.DB {
+	return database.FromContext(s.ctx)
+}
+
 // CategoryRequest represents the category request
 type CategoryRequest struct {
 	Name string `json:"name" binding:"required"`
 }
 
+// ReassignCategoryRequest represents the category reassignment request
+type ReassignCategoryRequest struct {
+	TargetCategoryID uint `json:"target_category_id" binding:"required"`
+	DeleteSource     bool `json:"delete_source"`
+}
+
+// ReassignCategoryResponse represents the result of a category reassignment
+type ReassignCategoryResponse struct {
+	SourceCategoryID uint  `json:"source_category_id"`
+	TargetCategoryID uint  `json:"target_category_id"`
+	Moved            int64 `json:"moved"`
+	SourceDeleted    bool  `json:"source_deleted"`
+}
+
+// ProjectCategoryItemsResponse represents a page of the published projects of a category
+type ProjectCategoryItemsResponse struct {
+	Category ProjectCategoryResponse `json:"category"`
+	Projects []ProjectResponse       `json:"projects"`
+	Metadata PageMetadata            `json:"metadata"`
+}
+
+// BlogCategoryItemsResponse represents a page of the published blog posts of a category
+type BlogCategoryItemsResponse struct {
+	Category BlogCategoryResponse `json:"category"`
+	Blogs    []BlogResponse       `json:"blogs"`
+	Metadata PageMetadata         `json:"metadata"`
+}
+
 // CategoryType represents the type of category
 type CategoryType string
 
@@ -33,18 +87,21 @@ const (
 )
 
 // CreateProjectCategory creates a new project category
-func (s *CategoryService) CreateProjectCategory(req CategoryRequest) (*ProjectCategoryResponse, error) {
+func (s *CategoryService) CreateProjectCategory(req CategoryRequest, userID uint) (*ProjectCategoryResponse, error) {
 	// Create slug from name
-	slug := strings.ToLower(strings.ReplaceAll(req.Name, " ", "-"))
+	slug := utils.SanitizeSlug(req.Name)
+	if slug == "" {
+		return nil, ErrCategoryNameInvalid
+	}
 
 	// Check if slug already exists
 	var count int64
-	if err := database.DB.Model(&models.ProjectCategory{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+	if err := s.db().Model(&models.ProjectCategory{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
 		return nil, err
 	}
 
 	if count > 0 {
-		return nil, errors.New("category with this name already exists")
+		return nil, ErrCategoryNameExists
 	}
 
 	// Create category
@@ -53,10 +110,12 @@ func (s *CategoryService) CreateProjectCategory(req CategoryRequest) (*ProjectCa
 		Slug: slug,
 	}
 
-	if err := database.DB.Create(&category).Error; err != nil {
+	if err := s.db().Create(&category).Error; err != nil {
 		return nil, err
 	}
 
+	recordAudit(userID, models.AuditActionCreate, AuditResourceProjectCategory, category.ID, req)
+
 	return &ProjectCategoryResponse{
 		ID:   category.ID,
 		Name: category.Name,
@@ -65,9 +124,9 @@ func (s *CategoryService) CreateProjectCategory(req CategoryRequest) (*ProjectCa
 }
 
 // UpdateProjectCategory updates a project category
-func (s *CategoryService) UpdateProjectCategory(id uint, req CategoryRequest) (*ProjectCategoryResponse, error) {
+func (s *CategoryService) UpdateProjectCategory(id uint, req CategoryRequest, userID uint) (*ProjectCategoryResponse, error) {
 	var category models.ProjectCategory
-	if err := database.DB.First(&category, id).Error; err != nil {
+	if err := s.db().First(&category, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("category not found")
 		}
@@ -75,26 +134,31 @@ func (s *CategoryService) UpdateProjectCategory(id uint, req CategoryRequest) (*
 	}
 
 	// Create slug from name
-	slug := strings.ToLower(strings.ReplaceAll(req.Name, " ", "-"))
+	slug := utils.SanitizeSlug(req.Name)
+	if slug == "" {
+		return nil, ErrCategoryNameInvalid
+	}
 
 	// Check if slug already exists and is not this category
 	var count int64
-	if err := database.DB.Model(&models.ProjectCategory{}).Where("slug = ? AND id != ?", slug, id).Count(&count).Error; err != nil {
+	if err := s.db().Model(&models.ProjectCategory{}).Where("slug = ? AND id != ?", slug, id).Count(&count).Error; err != nil {
 		return nil, err
 	}
 
 	if count > 0 {
-		return nil, errors.New("category with this name already exists")
+		return nil, ErrCategoryNameExists
 	}
 
 	// Update category
 	category.Name = req.Name
 	category.Slug = slug
 
-	if err := database.DB.Save(&category).Error; err != nil {
+	if err := s.db().Save(&category).Error; err != nil {
 		return nil, err
 	}
 
+	recordAudit(userID, models.AuditActionUpdate, AuditResourceProjectCategory, category.ID, req)
+
 	return &ProjectCategoryResponse{
 		ID:   category.ID,
 		Name: category.Name,
@@ -103,9 +167,9 @@ func (s *CategoryService) UpdateProjectCategory(id uint, req CategoryRequest) (*
 }
 
 // DeleteProjectCategory deletes a project category
-func (s *CategoryService) DeleteProjectCategory(id uint) error {
+func (s *CategoryService) DeleteProjectCategory(id, userID uint) error {
 	var category models.ProjectCategory
-	if err := database.DB.First(&category, id).Error; err != nil {
+	if err := s.db().First(&category, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("category not found")
 		}
@@ -114,25 +178,89 @@ func (s *CategoryService) DeleteProjectCategory(id uint) error {
 
 	// Check if category is used by any project
 	var count int64
-	if err := database.DB.Model(&models.Project{}).Where("category_id = ?", id).Count(&count).Error; err != nil {
+	if err := s.db().Model(&models.Project{}).Where("category_id = ?", id).Count(&count).Error; err != nil {
 		return err
 	}
 
 	if count > 0 {
-		return errors.New("category is used by projects and cannot be deleted")
+		return errors.New("category is used by projects and cannot be deleted, reassign its projects first")
 	}
 
-	return database.DB.Delete(&category).Error
+	if err := s.db().Delete(&category).Error; err != nil {
+		return err
+	}
+
+	recordAudit(userID, models.AuditActionDelete, AuditResourceProjectCategory, category.ID, nil)
+
+	return nil
+}
+
+// ReassignProjectCategory moves all projects from one category to another,
+// optionally deleting the source category once it is empty
+func (s *CategoryService) ReassignProjectCategory(id uint, req ReassignCategoryRequest, userID uint) (*ReassignCategoryResponse, error) {
+	if id == req.TargetCategoryID {
+		return nil, errors.New("target category must be different from the source category")
+	}
+
+	var source, target models.ProjectCategory
+	if err := s.db().First(&source, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+
+	if err := s.db().First(&target, req.TargetCategoryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("target category not found")
+		}
+		return nil, err
+	}
+
+	// Start transaction
+	tx := s.db().Begin()
+
+	// Move projects to the target category
+	result := tx.Model(&models.Project{}).Where("category_id = ?", source.ID).Update("category_id", target.ID)
+	if result.Error != nil {
+		tx.Rollback()
+		return nil, result.Error
+	}
+
+	// Delete the now-empty source category if requested
+	if req.DeleteSource {
+		if err := tx.Delete(&source).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionReassign, AuditResourceProjectCategory, source.ID, req)
+	if req.DeleteSource {
+		recordAudit(userID, models.AuditActionDelete, AuditResourceProjectCategory, source.ID, nil)
+	}
+
+	return &ReassignCategoryResponse{
+		SourceCategoryID: source.ID,
+		TargetCategoryID: target.ID,
+		Moved:            result.RowsAffected,
+		SourceDeleted:    req.DeleteSource,
+	}, nil
 }
 
 // ListProjectCategories lists all project categories
 func (s *CategoryService) ListProjectCategories() ([]ProjectCategoryResponse, error) {
 	var categories []models.ProjectCategory
-	if err := database.DB.Find(&categories).Error; err != nil {
+	if err := s.db().Find(&categories).Error; err != nil {
 		return nil, err
 	}
 
-	var response []ProjectCategoryResponse
+	response := make([]ProjectCategoryResponse, 0, len(categories))
 	for _, category := range categories {
 		response = append(response, ProjectCategoryResponse{
 			ID:   category.ID,
@@ -147,7 +275,24 @@ func (s *CategoryService) ListProjectCategories() ([]ProjectCategoryResponse, er
 // GetProjectCategoryByID gets a project category by ID
 func (s *CategoryService) GetProjectCategoryByID(id uint) (*ProjectCategoryResponse, error) {
 	var category models.ProjectCategory
-	if err := database.DB.First(&category, id).Error; err != nil {
+	if err := s.db().First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+
+	return &ProjectCategoryResponse{
+		ID:   category.ID,
+		Name: category.Name,
+		Slug: category.Slug,
+	}, nil
+}
+
+// GetProjectCategoryBySlug gets a project category by slug
+func (s *CategoryService) GetProjectCategoryBySlug(slug string) (*ProjectCategoryResponse, error) {
+	var category models.ProjectCategory
+	if err := s.db().Where("slug = ?", slug).First(&category).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("category not found")
 		}
@@ -162,18 +307,21 @@ func (s *CategoryService) GetProjectCategoryByID(id uint) (*ProjectCategoryRespo
 }
 
 // CreateBlogCategory creates a new blog category
-func (s *CategoryService) CreateBlogCategory(req CategoryRequest) (*BlogCategoryResponse, error) {
+func (s *CategoryService) CreateBlogCategory(req CategoryRequest, userID uint) (*BlogCategoryResponse, error) {
 	// Create slug from name
-	slug := strings.ToLower(strings.ReplaceAll(req.Name, " ", "-"))
+	slug := utils.SanitizeSlug(req.Name)
+	if slug == "" {
+		return nil, ErrCategoryNameInvalid
+	}
 
 	// Check if slug already exists
 	var count int64
-	if err := database.DB.Model(&models.BlogCategory{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+	if err := s.db().Model(&models.BlogCategory{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
 		return nil, err
 	}
 
 	if count > 0 {
-		return nil, errors.New("category with this name already exists")
+		return nil, ErrCategoryNameExists
 	}
 
 	// Create category
@@ -182,10 +330,12 @@ func (s *CategoryService) CreateBlogCategory(req CategoryRequest) (*BlogCategory
 		Slug: slug,
 	}
 
-	if err := database.DB.Create(&category).Error; err != nil {
+	if err := s.db().Create(&category).Error; err != nil {
 		return nil, err
 	}
 
+	recordAudit(userID, models.AuditActionCreate, AuditResourceBlogCategory, category.ID, req)
+
 	return &BlogCategoryResponse{
 		ID:   category.ID,
 		Name: category.Name,
@@ -194,9 +344,9 @@ func (s *CategoryService) CreateBlogCategory(req CategoryRequest) (*BlogCategory
 }
 
 // UpdateBlogCategory updates a blog category
-func (s *CategoryService) UpdateBlogCategory(id uint, req CategoryRequest) (*BlogCategoryResponse, error) {
+func (s *CategoryService) UpdateBlogCategory(id uint, req CategoryRequest, userID uint) (*BlogCategoryResponse, error) {
 	var category models.BlogCategory
-	if err := database.DB.First(&category, id).Error; err != nil {
+	if err := s.db().First(&category, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("category not found")
 		}
@@ -204,26 +354,31 @@ func (s *CategoryService) UpdateBlogCategory(id uint, req CategoryRequest) (*Blo
 	}
 
 	// Create slug from name
-	slug := strings.ToLower(strings.ReplaceAll(req.Name, " ", "-"))
+	slug := utils.SanitizeSlug(req.Name)
+	if slug == "" {
+		return nil, ErrCategoryNameInvalid
+	}
 
 	// Check if slug already exists and is not this category
 	var count int64
-	if err := database.DB.Model(&models.BlogCategory{}).Where("slug = ? AND id != ?", slug, id).Count(&count).Error; err != nil {
+	if err := s.db().Model(&models.BlogCategory{}).Where("slug = ? AND id != ?", slug, id).Count(&count).Error; err != nil {
 		return nil, err
 	}
 
 	if count > 0 {
-		return nil, errors.New("category with this name already exists")
+		return nil, ErrCategoryNameExists
 	}
 
 	// Update category
 	category.Name = req.Name
 	category.Slug = slug
 
-	if err := database.DB.Save(&category).Error; err != nil {
+	if err := s.db().Save(&category).Error; err != nil {
 		return nil, err
 	}
 
+	recordAudit(userID, models.AuditActionUpdate, AuditResourceBlogCategory, category.ID, req)
+
 	return &BlogCategoryResponse{
 		ID:   category.ID,
 		Name: category.Name,
@@ -232,9 +387,9 @@ func (s *CategoryService) UpdateBlogCategory(id uint, req CategoryRequest) (*Blo
 }
 
 // DeleteBlogCategory deletes a blog category
-func (s *CategoryService) DeleteBlogCategory(id uint) error {
+func (s *CategoryService) DeleteBlogCategory(id, userID uint) error {
 	var category models.BlogCategory
-	if err := database.DB.First(&category, id).Error; err != nil {
+	if err := s.db().First(&category, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("category not found")
 		}
@@ -243,25 +398,89 @@ func (s *CategoryService) DeleteBlogCategory(id uint) error {
 
 	// Check if category is used by any blog post
 	var count int64
-	if err := database.DB.Model(&models.BlogPost{}).Where("category_id = ?", id).Count(&count).Error; err != nil {
+	if err := s.db().Model(&models.BlogPost{}).Where("category_id = ?", id).Count(&count).Error; err != nil {
 		return err
 	}
 
 	if count > 0 {
-		return errors.New("category is used by blog posts and cannot be deleted")
+		return errors.New("category is used by blog posts and cannot be deleted, reassign its posts first")
+	}
+
+	if err := s.db().Delete(&category).Error; err != nil {
+		return err
+	}
+
+	recordAudit(userID, models.AuditActionDelete, AuditResourceBlogCategory, category.ID, nil)
+
+	return nil
+}
+
+// ReassignBlogCategory moves all blog posts from one category to another,
+// optionally deleting the source category once it is empty
+func (s *CategoryService) ReassignBlogCategory(id uint, req ReassignCategoryRequest, userID uint) (*ReassignCategoryResponse, error) {
+	if id == req.TargetCategoryID {
+		return nil, errors.New("target category must be different from the source category")
+	}
+
+	var source, target models.BlogCategory
+	if err := s.db().First(&source, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+
+	if err := s.db().First(&target, req.TargetCategoryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("target category not found")
+		}
+		return nil, err
+	}
+
+	// Start transaction
+	tx := s.db().Begin()
+
+	// Move blog posts to the target category
+	result := tx.Model(&models.BlogPost{}).Where("category_id = ?", source.ID).Update("category_id", target.ID)
+	if result.Error != nil {
+		tx.Rollback()
+		return nil, result.Error
 	}
 
-	return database.DB.Delete(&category).Error
+	// Delete the now-empty source category if requested
+	if req.DeleteSource {
+		if err := tx.Delete(&source).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionReassign, AuditResourceBlogCategory, source.ID, req)
+	if req.DeleteSource {
+		recordAudit(userID, models.AuditActionDelete, AuditResourceBlogCategory, source.ID, nil)
+	}
+
+	return &ReassignCategoryResponse{
+		SourceCategoryID: source.ID,
+		TargetCategoryID: target.ID,
+		Moved:            result.RowsAffected,
+		SourceDeleted:    req.DeleteSource,
+	}, nil
 }
 
 // ListBlogCategories lists all blog categories
 func (s *CategoryService) ListBlogCategories() ([]BlogCategoryResponse, error) {
 	var categories []models.BlogCategory
-	if err := database.DB.Find(&categories).Error; err != nil {
+	if err := s.db().Find(&categories).Error; err != nil {
 		return nil, err
 	}
 
-	var response []BlogCategoryResponse
+	response := make([]BlogCategoryResponse, 0, len(categories))
 	for _, category := range categories {
 		response = append(response, BlogCategoryResponse{
 			ID:   category.ID,
@@ -276,7 +495,24 @@ func (s *CategoryService) ListBlogCategories() ([]BlogCategoryResponse, error) {
 // GetBlogCategoryByID gets a blog category by ID
 func (s *CategoryService) GetBlogCategoryByID(id uint) (*BlogCategoryResponse, error) {
 	var category models.BlogCategory
-	if err := database.DB.First(&category, id).Error; err != nil {
+	if err := s.db().First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+
+	return &BlogCategoryResponse{
+		ID:   category.ID,
+		Name: category.Name,
+		Slug: category.Slug,
+	}, nil
+}
+
+// GetBlogCategoryBySlug gets a blog category by slug
+func (s *CategoryService) GetBlogCategoryBySlug(slug string) (*BlogCategoryResponse, error) {
+	var category models.BlogCategory
+	if err := s.db().Where("slug = ?", slug).First(&category).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("category not found")
 		}
@@ -288,4 +524,17 @@ func (s *CategoryService) GetBlogCategoryByID(id uint) (*BlogCategoryResponse, e
 		Name: category.Name,
 		Slug: category.Slug,
 	}, nil
-} 
\ No newline at end of file
+}
+
+// checkCategoryExists returns ErrCategoryNotFound unless the category table of
+// model has a category with the given id
+func checkCategoryExists(db *gorm.DB, model interface{}, id uint) error {
+	var count int64
+	if err := db.Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
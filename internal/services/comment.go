@@ -0,0 +1,251 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"zionechainapi/configs"
+	"zionechainapi/internal/clock"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCommentNotFound is returned when a comment does not exist
+	ErrCommentNotFound = errors.New("comment not found")
+	// ErrCommentBlogNotFound is returned when commenting on a blog post that does not exist or is not published
+	ErrCommentBlogNotFound = errors.New("blog post not found")
+	// ErrCommentEmpty is returned when a comment body is blank
+	ErrCommentEmpty = errors.New("comment body cannot be empty")
+	// ErrCommentTooLong is returned when a comment body exceeds the configured maximum length
+	ErrCommentTooLong = errors.New("comment body is too long")
+	// ErrCommentAuthorRequired is returned when an anonymous comment has no author name
+	ErrCommentAuthorRequired = errors.New("author name is required")
+	// ErrCommentRateLimited is returned when a client posts too many comments within the rate window
+	ErrCommentRateLimited = errors.New("too many comments, please try again later")
+)
+
+// IsCommentValidationError checks if an error was caused by invalid comment input
+func IsCommentValidationError(err error) bool {
+	return errors.Is(err, ErrCommentEmpty) || errors.Is(err, ErrCommentTooLong) || errors.Is(err, ErrCommentAuthorRequired)
+}
+
+// CommentService handles blog post comments and their moderation
+type CommentService struct {
+	config configs.CommentConfig
+	clock  clock.Clock
+}
+
+// NewCommentService creates a new comment service
+func NewCommentService(config configs.CommentConfig) *CommentService {
+	return &CommentService{
+		config: config,
+		clock:  clock.Real{},
+	}
+}
+
+// WithClock replaces the clock used for the rate limit window
+func (s *CommentService) WithClock(c clock.Clock) *CommentService {
+	s.clock = c
+	return s
+}
+
+// CreateCommentRequest represents the create comment request. Signed-in users
+// may omit the author fields, which default to their account.
+type CreateCommentRequest struct {
+	AuthorName  string `json:"author_name" binding:"max=100"`
+	AuthorEmail string `json:"author_email" binding:"omitempty,email,max=100"`
+	Body        string `json:"body" binding:"required"`
+}
+
+// CommentResponse represents the comment response. The author email is only
+// included for moderators.
+type CommentResponse struct {
+	ID          uint   `json:"id"`
+	BlogID      uint   `json:"blog_id"`
+	UserID      *uint  `json:"user_id"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email,omitempty"`
+	Body        string `json:"body"`
+	Approved    bool   `json:"approved"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// CommentListResponse represents a page of comments
+type CommentListResponse struct {
+	Comments []CommentResponse `json:"comments"`
+	Metadata PageMetadata      `json:"metadata"`
+}
+
+// CreateComment adds a pending comment to a published blog post. userID is zero
+// for anonymous readers, and ip identifies the client for rate limiting.
+func (s *CommentService) CreateComment(blogID uint, req CreateCommentRequest, userID uint, ip string) (*CommentResponse, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, ErrCommentEmpty
+	}
+	if s.config.MaxLength > 0 && utf8.RuneCountInString(body) > s.config.MaxLength {
+		return nil, ErrCommentTooLong
+	}
+
+	var count int64
+	if err := database.DB.Model(&models.BlogPost{}).Where("id = ? AND published = ?", blogID, true).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrCommentBlogNotFound
+	}
+
+	comment := models.Comment{
+		BlogID:      blogID,
+		AuthorName:  strings.TrimSpace(req.AuthorName),
+		AuthorEmail: strings.TrimSpace(req.AuthorEmail),
+		Body:        body,
+		IPAddress:   ip,
+	}
+
+	if userID > 0 {
+		var user models.User
+		if err := database.DB.Select("id", "name", "email").First(&user, userID).Error; err != nil {
+			return nil, err
+		}
+		comment.UserID = &user.ID
+		if comment.AuthorName == "" {
+			comment.AuthorName = user.Name
+		}
+		if comment.AuthorEmail == "" {
+			comment.AuthorEmail = user.Email
+		}
+	}
+
+	if comment.AuthorName == "" {
+		return nil, ErrCommentAuthorRequired
+	}
+
+	since := s.clock.Now().Add(-s.config.RateWindow)
+	if err := createRateLimited(&comment, ip, s.config.RateLimit, since, ErrCommentRateLimited); err != nil {
+		return nil, err
+	}
+
+	return mapCommentToResponse(comment, false), nil
+}
+
+// ListApprovedComments lists the approved comments of a blog post, oldest first
+func (s *CommentService) ListApprovedComments(blogID uint, page, limit int) ([]CommentResponse, int64, error) {
+	query := database.DB.Model(&models.Comment{}).Where("blog_id = ? AND approved = ?", blogID, true)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var comments []models.Comment
+	if err := paginate(query, page, limit).Order("created_at ASC, id ASC").Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	response := make([]CommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		response = append(response, *mapCommentToResponse(comment, false))
+	}
+
+	return response, total, nil
+}
+
+// ListComments lists comments for moderation, newest first. A nil approved
+// lists both pending and approved comments, and a zero blogID lists all posts.
+func (s *CommentService) ListComments(page, limit int, approved *bool, blogID uint) ([]CommentResponse, int64, error) {
+	query := database.DB.Model(&models.Comment{})
+
+	if approved != nil {
+		query = query.Where("approved = ?", *approved)
+	}
+
+	if blogID > 0 {
+		query = query.Where("blog_id = ?", blogID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var comments []models.Comment
+	if err := paginate(query, page, limit).Order("created_at DESC, id DESC").Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	response := make([]CommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		response = append(response, *mapCommentToResponse(comment, true))
+	}
+
+	return response, total, nil
+}
+
+// ApproveComment publishes a pending comment
+func (s *CommentService) ApproveComment(id, userID uint) (*CommentResponse, error) {
+	var comment models.Comment
+	if err := database.DB.First(&comment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	if !comment.Approved {
+		if err := database.DB.Model(&comment).Update("approved", true).Error; err != nil {
+			return nil, err
+		}
+		comment.Approved = true
+
+		recordAudit(userID, models.AuditActionApprove, AuditResourceComment, comment.ID, nil)
+	}
+
+	return mapCommentToResponse(comment, true), nil
+}
+
+// DeleteComment deletes a comment
+func (s *CommentService) DeleteComment(id, userID uint) error {
+	var comment models.Comment
+	if err := database.DB.First(&comment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCommentNotFound
+		}
+		return err
+	}
+
+	if err := database.DB.Delete(&comment).Error; err != nil {
+		return err
+	}
+
+	recordAudit(userID, models.AuditActionDelete, AuditResourceComment, comment.ID, map[string]interface{}{"blog_id": comment.BlogID})
+
+	return nil
+}
+
+// deleteComments removes all comments of a blog post
+func deleteComments(tx *gorm.DB, blogID uint) error {
+	return tx.Where("blog_id = ?", blogID).Delete(&models.Comment{}).Error
+}
+
+func mapCommentToResponse(comment models.Comment, includeEmail bool) *CommentResponse {
+	response := &CommentResponse{
+		ID:         comment.ID,
+		BlogID:     comment.BlogID,
+		UserID:     comment.UserID,
+		AuthorName: comment.AuthorName,
+		Body:       comment.Body,
+		Approved:   comment.Approved,
+		CreatedAt:  comment.CreatedAt.UTC().Format(time.RFC3339),
+	}
+
+	if includeEmail {
+		response.AuthorEmail = comment.AuthorEmail
+	}
+
+	return response
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"errors"
+
+	"zionechainapi/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrDraftResourceNotFound is returned when the project or blog post of a draft does not exist
+	ErrDraftResourceNotFound = errors.New("resource not found")
+	// ErrNoDraft is returned when publishing a draft that was never autosaved
+	ErrNoDraft = errors.New("there is no draft to publish")
+)
+
+// DraftRequest represents an autosaved draft of a resource's content
+type DraftRequest struct {
+	Content string `json:"content"`
+}
+
+// DraftResponse represents the draft of a resource. DraftContent is null when
+// there are no unpublished edits.
+type DraftResponse struct {
+	ID           uint    `json:"id"`
+	DraftContent *string `json:"draft_content"`
+}
+
+// getDraft reads the draft content of a project or blog post
+func getDraft(db *gorm.DB, model interface{}, id uint) (*DraftResponse, error) {
+	var draft DraftResponse
+	result := db.Model(model).Select("id, draft_content").Where("id = ?", id).Limit(1).Scan(&draft)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrDraftResourceNotFound
+	}
+	return &draft, nil
+}
+
+// saveDraft stores the draft content of a project or blog post. The column is
+// written directly so that neither the published content nor updated_at change.
+func saveDraft(db *gorm.DB, model interface{}, id uint, content string) (*DraftResponse, error) {
+	if _, err := getDraft(db, model, id); err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(model).Where("id = ?", id).UpdateColumn("draft_content", content).Error; err != nil {
+		return nil, err
+	}
+
+	return &DraftResponse{ID: id, DraftContent: &content}, nil
+}
+
+// loadDraft loads a project or blog post within tx for publishing its draft
+func loadDraft(tx *gorm.DB, model interface{}, id uint) error {
+	if err := tx.First(model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrDraftResourceNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// AutosaveBlogDraft saves draft content for a blog post without publishing it.
+// The draft is sanitized like published content since editors preview it.
+func (s *BlogService) AutosaveBlogDraft(id uint, req DraftRequest) (*DraftResponse, error) {
+	return saveDraft(s.db(), &models.BlogPost{}, id, s.sanitizeContent(req.Content))
+}
+
+// GetBlogDraft returns the autosaved draft of a blog post
+func (s *BlogService) GetBlogDraft(id uint) (*DraftResponse, error) {
+	return getDraft(s.db(), &models.BlogPost{}, id)
+}
+
+// PublishBlogDraft promotes the autosaved draft of a blog post into its content
+// as a regular update and discards the draft, in a single transaction
+func (s *BlogService) PublishBlogDraft(id, userID uint) (*BlogResponse, error) {
+	req := UpdateBlogRequest{}
+	var response *BlogResponse
+	err := s.db().Transaction(func(tx *gorm.DB) error {
+		var blog models.BlogPost
+		if err := loadDraft(tx, &blog, id); err != nil {
+			return err
+		}
+		if blog.DraftContent == nil {
+			return ErrNoDraft
+		}
+
+		// The draft is cleared by the same save that publishes it
+		req.Content = blog.DraftContent
+		blog.DraftContent = nil
+
+		var err error
+		response, err = s.applyBlogUpdate(tx, &blog, req, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionUpdate, AuditResourceBlogPost, id, req)
+
+	return response, nil
+}
+
+// AutosaveProjectDraft saves draft content for a project without publishing it.
+// The draft is sanitized like published content since editors preview it.
+func (s *ProjectService) AutosaveProjectDraft(id uint, req DraftRequest) (*DraftResponse, error) {
+	return saveDraft(s.db(), &models.Project{}, id, s.sanitizeContent(req.Content))
+}
+
+// GetProjectDraft returns the autosaved draft of a project
+func (s *ProjectService) GetProjectDraft(id uint) (*DraftResponse, error) {
+	return getDraft(s.db(), &models.Project{}, id)
+}
+
+// PublishProjectDraft promotes the autosaved draft of a project into its content
+// as a regular update and discards the draft, in a single transaction
+func (s *ProjectService) PublishProjectDraft(id, userID uint) (*ProjectResponse, error) {
+	req := UpdateProjectRequest{}
+	var response *ProjectResponse
+	err := s.db().Transaction(func(tx *gorm.DB) error {
+		var project models.Project
+		if err := loadDraft(tx, &project, id); err != nil {
+			return err
+		}
+		if project.DraftContent == nil {
+			return ErrNoDraft
+		}
+
+		// The draft is cleared by the same save that publishes it
+		req.Content = project.DraftContent
+		project.DraftContent = nil
+
+		var err error
+		response, err = s.applyProjectUpdate(tx, &project, req, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionUpdate, AuditResourceProject, id, req)
+
+	return response, nil
+}
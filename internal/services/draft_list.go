@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"zionechainapi/internal/database"
+	"gorm.io/gorm"
+)
+
+// ErrUnknownDraftType is returned when drafts are filtered by a type other than a project or blog post
+var ErrUnknownDraftType = errors.New("type must be project or blog_post")
+
+// DraftService lists unpublished content across content types
+type DraftService struct{}
+
+// NewDraftService creates a new draft service
+func NewDraftService() *DraftService {
+	return &DraftService{}
+}
+
+// DraftFilter narrows the drafts listing. Zero values match everything.
+type DraftFilter struct {
+	Type     string // ActivityTypeProject or ActivityTypeBlogPost
+	AuthorID uint
+}
+
+// DraftItem represents an unpublished project or blog post
+type DraftItem struct {
+	Type      string    `json:"type"`
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	CreatedBy uint      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DraftListResponse represents a page of drafts
+type DraftListResponse struct {
+	Drafts   []DraftItem  `json:"drafts"`
+	Metadata PageMetadata `json:"metadata"`
+}
+
+// draftTables maps draft types to the tables they are read from
+var draftTables = []struct {
+	itemType string
+	table    string
+}{
+	{ActivityTypeProject, "projects"},
+	{ActivityTypeBlogPost, "blog_posts"},
+}
+
+// ListDrafts lists unpublished projects and blog posts as one listing, most
+// recently updated first. The tables are merged with UNION ALL so that the
+// database paginates the merged set.
+func (s *DraftService) ListDrafts(page, limit int, filter DraftFilter) ([]DraftItem, int64, error) {
+	if filter.Type != "" && filter.Type != ActivityTypeProject && filter.Type != ActivityTypeBlogPost {
+		return nil, 0, ErrUnknownDraftType
+	}
+
+	var parts []interface{}
+	union := ""
+	for _, source := range draftTables {
+		if filter.Type != "" && filter.Type != source.itemType {
+			continue
+		}
+
+		part := database.DB.Table(source.table).
+			Select("? AS type, id, title, slug, created_by, created_at, updated_at", source.itemType).
+			Where("published = ?", false)
+		if filter.AuthorID > 0 {
+			part = part.Where("created_by = ?", filter.AuthorID)
+		}
+
+		if union != "" {
+			union += " UNION ALL "
+		}
+		// Unparenthesized, since SQLite rejects parentheses around the selects of a union
+		union += "?"
+		parts = append(parts, part)
+	}
+
+	drafts := func() *gorm.DB {
+		return database.DB.Table("(?) AS drafts", database.DB.Raw(union, parts...))
+	}
+
+	var total int64
+	if err := drafts().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := []DraftItem{}
+	if err := paginate(drafts(), page, limit).
+		Order("updated_at DESC, type ASC, id DESC").
+		Scan(&items).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"gorm.io/gorm"
+)
+
+// EmailVerificationTTL is how long an email verification token stays valid
+const EmailVerificationTTL = 24 * time.Hour
+
+var (
+	// ErrVerificationTokenInvalid is returned when a verification token does not belong to any user
+	ErrVerificationTokenInvalid = errors.New("invalid verification token")
+	// ErrVerificationTokenExpired is returned when a verification token is past its expiry
+	ErrVerificationTokenExpired = errors.New("verification token has expired")
+)
+
+// sendEmailVerification issues a new verification token for the user and
+// mails them the link to verify their email with it
+func (s *AuthService) sendEmailVerification(user *models.User) error {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(raw)
+
+	expiresAt := s.clock.Now().Add(EmailVerificationTTL)
+	if err := database.DB.Model(user).Updates(map[string]interface{}{
+		"email_verification_token":      hashVerificationToken(token),
+		"email_verification_expires_at": expiresAt,
+	}).Error; err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/api/auth/verify-email?token=%s", strings.TrimRight(s.config.App.URL, "/"), url.QueryEscape(token))
+	body := fmt.Sprintf("Hi %s,\n\nPlease verify your email address by opening the link below. It expires in 24 hours.\n\n%s\n", user.Name, link)
+	return s.mailer.Send(user.Email, "Verify your email address", body)
+}
+
+// VerifyEmail marks the email of the user the token was issued to as verified.
+// A token can only be used once.
+func (s *AuthService) VerifyEmail(token string) error {
+	var user models.User
+	if err := database.DB.Where("email_verification_token = ?", hashVerificationToken(token)).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrVerificationTokenInvalid
+		}
+		return err
+	}
+
+	if user.EmailVerificationExpiresAt == nil || !s.clock.Now().Before(*user.EmailVerificationExpiresAt) {
+		return ErrVerificationTokenExpired
+	}
+
+	return database.DB.Model(&user).Updates(map[string]interface{}{
+		"email_verified":                true,
+		"email_verification_token":      "",
+		"email_verification_expires_at": nil,
+	}).Error
+}
+
+// hashVerificationToken hashes a verification token for storage, so a leaked
+// users table cannot be used to verify addresses
+func hashVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidFeaturedOrder is returned when a reorder request does not list distinct featured items
+var ErrInvalidFeaturedOrder = errors.New("ids must list distinct featured items")
+
+// ReorderFeaturedRequest represents the featured items in their new display order
+type ReorderFeaturedRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1"`
+}
+
+// reorderFeatured numbers the featured items of the model's table in the order
+// of ids, starting at 1. Featured items missing from ids lose their position
+// and sort after the ordered ones. Columns are updated without touching
+// updated_at since the content itself does not change.
+func reorderFeatured(tx *gorm.DB, model interface{}, ids []uint) error {
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return ErrInvalidFeaturedOrder
+		}
+		seen[id] = true
+	}
+
+	var count int64
+	if err := tx.Model(model).Where("id IN ? AND featured = ?", ids, true).Count(&count).Error; err != nil {
+		return err
+	}
+	if count != int64(len(ids)) {
+		return ErrInvalidFeaturedOrder
+	}
+
+	if err := tx.Model(model).Where("featured = ?", true).UpdateColumn("featured_order", nil).Error; err != nil {
+		return err
+	}
+
+	for i, id := range ids {
+		if err := tx.Model(model).Where("id = ?", id).UpdateColumn("featured_order", i+1).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidCursor is returned when a list cursor cannot be decoded
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrFeaturedCursor is returned for cursor pagination of a featured
+	// listing, whose featured order a creation time cursor cannot follow
+	ErrFeaturedCursor = errors.New("cursor pagination is not supported for featured listings, use page instead")
+)
+
+// ListFilter represents the filters shared by the project and blog listings.
+// Zero values disable a filter, except Published which is applied unless
+// AnyStatus is set.
+type ListFilter struct {
+	CategoryID    uint      // only items in this category
+	CategoryIDs   []uint    // only items in any of these categories
+	Featured      bool      // only featured items when true
+	Published     bool      // published or unpublished items
+	AnyStatus     bool      // both published items and drafts, ignoring Published
+	CreatedBy     uint      // only items authored by this user
+	CreatedAfter  time.Time // only items created at or after this time
+	CreatedBefore time.Time // only items created at or before this time
+	Tag           string    // only items tagged with this tag slug
+	Technology    string    // only items built with this technology slug
+	Query         string    // free-text search on the searchable columns
+}
+
+// listTarget describes how a ListFilter maps onto a content table
+type listTarget struct {
+	table                string   // the content table
+	tagTable             string   // many2many join table between the content and tags
+	tagForeignKey        string   // column in the join table referencing the content
+	technologyTable      string   // many2many join table between the content and technologies, if any
+	technologyForeignKey string   // column in the technology join table referencing the content
+	searchColumns        []string // columns matched by the free-text query
+}
+
+var (
+	projectListTarget = listTarget{
+		table:                "projects",
+		tagTable:             "project_tags",
+		tagForeignKey:        "project_id",
+		technologyTable:      "project_technologies",
+		technologyForeignKey: "project_id",
+		searchColumns:        []string{"title", "description", "content"},
+	}
+
+	blogListTarget = listTarget{
+		table:         "blog_posts",
+		tagTable:      "blog_tags",
+		tagForeignKey: "blog_post_id",
+		searchColumns: []string{"title", "excerpt", "content"},
+	}
+)
+
+// apply adds the filter conditions to a query on the target's table
+func (t listTarget) apply(query *gorm.DB, filter ListFilter) *gorm.DB {
+	if filter.CategoryID > 0 {
+		query = query.Where("category_id = ?", filter.CategoryID)
+	}
+
+	if len(filter.CategoryIDs) > 0 {
+		query = query.Where("category_id IN ?", filter.CategoryIDs)
+	}
+
+	if filter.Featured {
+		query = query.Where("featured = ?", filter.Featured)
+	}
+
+	if filter.CreatedBy > 0 {
+		query = query.Where("created_by = ?", filter.CreatedBy)
+	}
+
+	switch {
+	case !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero():
+		query = query.Where("created_at BETWEEN ? AND ?", filter.CreatedAfter, filter.CreatedBefore)
+	case !filter.CreatedAfter.IsZero():
+		query = query.Where("created_at >= ?", filter.CreatedAfter)
+	case !filter.CreatedBefore.IsZero():
+		query = query.Where("created_at <= ?", filter.CreatedBefore)
+	}
+
+	if filter.Tag != "" {
+		query = query.Where("id IN (?)", query.Session(&gorm.Session{NewDB: true}).
+			Table(t.tagTable).
+			Select(t.tagTable+"."+t.tagForeignKey).
+			Joins("JOIN tags ON tags.id = "+t.tagTable+".tag_id").
+			Where("tags.slug = ?", filter.Tag))
+	}
+
+	if filter.Technology != "" && t.technologyTable != "" {
+		query = query.Where("id IN (?)", query.Session(&gorm.Session{NewDB: true}).
+			Table(t.technologyTable).
+			Select(t.technologyTable+"."+t.technologyForeignKey).
+			Joins("JOIN technologies ON technologies.id = "+t.technologyTable+".technology_id").
+			Where("technologies.slug = ?", filter.Technology))
+	}
+
+	if filter.Query != "" && len(t.searchColumns) > 0 {
+		like := ContainsPattern(filter.Query)
+		search := query.Session(&gorm.Session{NewDB: true})
+		for i, column := range t.searchColumns {
+			if i == 0 {
+				search = search.Where(LikeCondition(column), like)
+			} else {
+				search = search.Or(LikeCondition(column), like)
+			}
+		}
+		query = query.Where(search)
+	}
+
+	if filter.AnyStatus {
+		return query
+	}
+
+	// Default to published only
+	return query.Where("published = ?", filter.Published)
+}
+
+// likeEscaper escapes the LIKE wildcards in a search term. The escape
+// character is '!' rather than a backslash, which MySQL would read as a string
+// escape inside the ESCAPE clause.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// ContainsPattern returns a LIKE pattern matching values that contain term
+// literally, for use with LikeCondition
+func ContainsPattern(term string) string {
+	return "%" + likeEscaper.Replace(term) + "%"
+}
+
+// LikeCondition returns a LIKE condition on column for a pattern built by
+// ContainsPattern
+func LikeCondition(column string) string {
+	return column + " LIKE ? ESCAPE '!'"
+}
+
+// listOrder returns the ordering of a listing. Featured listings follow the
+// explicit featured order, with unordered items last, then newest first.
+func listOrder(filter ListFilter) string {
+	if filter.Featured {
+		return "featured_order IS NULL, featured_order ASC, created_at DESC"
+	}
+	return "created_at DESC"
+}
+
+// paginate applies limit and offset for a 1-based page number
+func paginate(query *gorm.DB, page, limit int) *gorm.DB {
+	offset := (page - 1) * limit
+	return query.Limit(limit).Offset(offset)
+}
+
+// PageMetadata describes one page of a page-numbered listing
+type PageMetadata struct {
+	Total      int64 `json:"total"`
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	TotalPages int64 `json:"total_pages"`
+}
+
+// NewPageMetadata creates the metadata of a page out of total matching items
+func NewPageMetadata(total int64, page, limit int) PageMetadata {
+	return PageMetadata{
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	}
+}
+
+// CursorMetadata describes one page of a cursor-paginated listing. NextCursor
+// is empty on the last page.
+type CursorMetadata struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// encodeCursor creates an opaque cursor pointing after the row with the given creation time and id
+func encodeCursor(createdAt time.Time, id uint) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatUint(uint64(id), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor parses a cursor created by encodeCursor
+func decodeCursor(cursor string) (time.Time, uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	return createdAt, uint(id), nil
+}
+
+// paginateAfter orders a query newest first by created_at and id and restricts
+// it to the rows after the cursor, fetching one extra row to detect a next page.
+// An empty cursor starts at the first row. Featured listings are rejected since
+// they are ordered by listOrder instead.
+func paginateAfter(query *gorm.DB, filter ListFilter, cursor string, limit int) (*gorm.DB, error) {
+	if filter.Featured {
+		return nil, ErrFeaturedCursor
+	}
+	if cursor != "" {
+		createdAt, id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", createdAt, createdAt, id)
+	}
+
+	return query.Order("created_at DESC, id DESC").Limit(limit + 1), nil
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LastModifiedResponse represents the last modification time of a collection
+type LastModifiedResponse struct {
+	LastModified *time.Time `json:"last_modified"` // nil when the collection is empty
+}
+
+// publishedLastModified returns the latest update time of the published items
+// of model, or nil when none is published
+func publishedLastModified(db *gorm.DB, model interface{}) (*time.Time, error) {
+	// Plucking the column rather than MAX keeps its time type on every driver
+	var updatedAt []time.Time
+	if err := db.Model(model).
+		Where("published = ?", true).
+		Order("updated_at DESC").
+		Limit(1).
+		Pluck("updated_at", &updatedAt).Error; err != nil {
+		return nil, err
+	}
+
+	if len(updatedAt) == 0 {
+		return nil, nil
+	}
+	return &updatedAt[0], nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"zionechainapi/internal/cache"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrLikedBlogNotFound is returned when liking a blog post that does not exist or is not published
+var ErrLikedBlogNotFound = errors.New("blog post not found")
+
+// Values remembered for a client's last reaction to a post
+const (
+	likeStateLiked   = "liked"
+	likeStateUnliked = "unliked"
+)
+
+// likeLocks serializes the reactions of each client to each post
+var likeLocks = keyedMutex{locks: make(map[string]*keyedLock)}
+
+// LikeService counts likes of blog posts. A client's last like or unlike of a
+// post is remembered for a window so repeating it is not counted again, and a
+// client can only take back a like it is remembered to have given.
+type LikeService struct {
+	recent cache.Cache
+	window time.Duration
+}
+
+// NewLikeService creates a new like service that remembers recent reactions in store for window
+func NewLikeService(store cache.Cache, window time.Duration) *LikeService {
+	return &LikeService{
+		recent: store,
+		window: window,
+	}
+}
+
+// LikeResponse represents the like count of a blog post after a reaction
+type LikeResponse struct {
+	Likes   uint `json:"likes"`
+	Counted bool `json:"counted"` // false when the reaction repeated a recent one and was ignored
+}
+
+// LikeBlog adds a like to a published blog post unless the client liked it within the window
+func (s *LikeService) LikeBlog(id uint, clientIP string) (*LikeResponse, error) {
+	return s.react(id, clientIP, likeStateLiked, gorm.Expr("likes + 1"))
+}
+
+// UnlikeBlog removes a like from a published blog post when the client liked it within the window.
+// The count never drops below zero.
+func (s *LikeService) UnlikeBlog(id uint, clientIP string) (*LikeResponse, error) {
+	return s.react(id, clientIP, likeStateUnliked, gorm.Expr("likes - 1"))
+}
+
+// react applies a like or unlike and remembers it for the client
+func (s *LikeService) react(id uint, clientIP, state string, change interface{}) (*LikeResponse, error) {
+	var blog models.BlogPost
+	if err := database.DB.Select("id", "likes").Where("published = ?", true).First(&blog, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLikedBlogNotFound
+		}
+		return nil, err
+	}
+
+	// Hold the client's lock from reading its last reaction until the new one
+	// is remembered, so concurrent requests of a client count only once
+	key := fmt.Sprintf("%d:%s", id, clientIP)
+	unlock := likeLocks.lock(key)
+	defer unlock()
+
+	previous, ok := s.recent.Get(key)
+	liked := ok && string(previous) == likeStateLiked
+	if (state == likeStateLiked) == liked {
+		return &LikeResponse{Likes: blog.Likes}, nil
+	}
+
+	// Update the counter in the database so concurrent reactions are not lost.
+	// UpdateColumn leaves updated_at alone since reactions do not edit the post.
+	query := database.DB.Model(&models.BlogPost{}).Where("id = ?", id)
+	if state == likeStateUnliked {
+		query = query.Where("likes > 0")
+	}
+	if err := query.UpdateColumn("likes", change).Error; err != nil {
+		return nil, err
+	}
+
+	s.recent.Set(key, []byte(state), s.window)
+
+	if err := database.DB.Select("likes").First(&blog, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &LikeResponse{Likes: blog.Likes, Counted: true}, nil
+}
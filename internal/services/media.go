@@ -0,0 +1,89 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Supported media types
+const (
+	MediaTypeImage    = "image"
+	MediaTypeVideo    = "video"
+	MediaTypeEmbed    = "embed"
+	MediaTypeDocument = "document"
+)
+
+var allowedMediaTypes = map[string]bool{
+	MediaTypeImage:    true,
+	MediaTypeVideo:    true,
+	MediaTypeEmbed:    true,
+	MediaTypeDocument: true,
+}
+
+// ErrInvalidMediaType is returned when a media type is not in the whitelist
+var ErrInvalidMediaType = errors.New("media type must be one of: image, video, embed, document")
+
+// ErrInvalidMediaURL is returned when a media URL is not a well-formed http(s) URL
+var ErrInvalidMediaURL = errors.New("media url must be a valid http or https URL")
+
+// IsMediaValidationError checks if an error was caused by invalid media input
+func IsMediaValidationError(err error) bool {
+	return errors.Is(err, ErrInvalidMediaType) || errors.Is(err, ErrInvalidMediaURL)
+}
+
+// validateMedia checks a media type and URL, returning the normalized type.
+// An empty type defaults to image, consistent with the model default.
+func validateMedia(mediaType, rawURL string) (string, error) {
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if mediaType == "" {
+		mediaType = MediaTypeImage
+	}
+
+	if !allowedMediaTypes[mediaType] {
+		return "", ErrInvalidMediaType
+	}
+
+	parsed, err := url.ParseRequestURI(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", ErrInvalidMediaURL
+	}
+
+	return mediaType, nil
+}
+
+// MediaPage selects one page of the media in a detail response, ordered by
+// sort order. A zero Limit selects all media.
+type MediaPage struct {
+	Page  int
+	Limit int
+}
+
+// preloadMedia preloads the media of the queried item, only the selected page when paginated
+func preloadMedia(query *gorm.DB, mediaPage MediaPage) *gorm.DB {
+	if mediaPage.Limit <= 0 {
+		return query.Preload("Media")
+	}
+
+	return query.Preload("Media", func(db *gorm.DB) *gorm.DB {
+		return paginate(db.Order("sort_order ASC, id ASC"), mediaPage.Page, mediaPage.Limit)
+	})
+}
+
+// mediaMetadata describes the selected page of the media of an item, counting
+// the rows of model whose foreignKey is id. It returns nil when all media are selected.
+func mediaMetadata(db *gorm.DB, model interface{}, foreignKey string, id uint, mediaPage MediaPage) (*PageMetadata, error) {
+	if mediaPage.Limit <= 0 {
+		return nil, nil
+	}
+
+	var total int64
+	if err := db.Model(model).Where(foreignKey+" = ?", id).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	metadata := NewPageMetadata(total, mediaPage.Page, mediaPage.Limit)
+	return &metadata, nil
+}
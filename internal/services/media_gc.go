@@ -0,0 +1,164 @@
+package services
+
+import (
+	"log"
+	"mime"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/imaging"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/storage"
+)
+
+// orphanGracePeriod keeps recently stored files out of garbage collection, as
+// a file is uploaded before the media that uses it is saved
+const orphanGracePeriod = 24 * time.Hour
+
+// mediaReferences are the columns holding URLs of stored files, which both
+// garbage collection and deleting media check. Soft-deleted resume entries
+// keep their files, since they can still be restored.
+var mediaReferences = []struct {
+	model  interface{}
+	column string
+}{
+	{&models.ProjectMedia{}, "url"},
+	{&models.BlogMedia{}, "url"},
+	{&models.Project{}, "og_image"},
+	{&models.BlogPost{}, "og_image"},
+	{&models.PersonalInfo{}, "profile_image"},
+	{&models.Skill{}, "icon_url"},
+	{&models.Experience{}, "logo_url"},
+	{&models.Education{}, "logo_url"},
+	{&models.Certificate{}, "logo_url"},
+	{&models.Publication{}, "image_url"},
+	{&models.ResumeProject{}, "image_url"},
+	{&models.Technology{}, "icon"},
+}
+
+// mediaContentReferences are the rich text columns that may embed stored files
+var mediaContentReferences = []struct {
+	model   interface{}
+	columns []string
+}{
+	{&models.Project{}, []string{"content", "draft_content"}},
+	{&models.BlogPost{}, []string{"content", "draft_content"}},
+	{&models.Translation{}, []string{"content"}},
+}
+
+// MediaGCResponse represents the result of collecting orphaned files
+type MediaGCResponse struct {
+	DryRun  bool     `json:"dry_run"`
+	Scanned int      `json:"scanned"` // stored files examined
+	Removed int      `json:"removed"` // orphaned files deleted, or that would be in a dry run
+	Keys    []string `json:"keys"`    // keys of the orphaned files
+}
+
+// CollectGarbage deletes the stored files that nothing references any more,
+// such as files of media deleted before their files were cleaned up along
+// with them. Files stored within the last day are kept. A dry run only
+// reports the files it would delete.
+func (s *UploadService) CollectGarbage(dryRun bool, userID uint) (*MediaGCResponse, error) {
+	files, err := s.storage.List()
+	if err != nil {
+		return nil, err
+	}
+
+	referenced, err := referencedStorageKeys(s.storage)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &MediaGCResponse{DryRun: dryRun, Scanned: len(files), Keys: []string{}}
+	cutoff := s.clock.Now().Add(-orphanGracePeriod)
+	for _, file := range files {
+		if referenced[file.Key] || file.ModTime.After(cutoff) {
+			continue
+		}
+
+		embedded, err := embeddedInContent(s.storage.URL(file.Key))
+		if err != nil {
+			return nil, err
+		}
+		if embedded {
+			continue
+		}
+
+		if !dryRun {
+			if err := s.storage.Delete(file.Key); err != nil {
+				log.Printf("Failed to delete orphaned file %s: %v", file.Key, err)
+				continue
+			}
+		}
+		response.Keys = append(response.Keys, file.Key)
+	}
+	sort.Strings(response.Keys)
+	response.Removed = len(response.Keys)
+
+	if !dryRun {
+		recordAudit(userID, models.AuditActionDelete, AuditResourceMedia, 0, response)
+	}
+
+	return response, nil
+}
+
+// referencedStorageKeys returns the keys of the stored files that a URL
+// column references, along with the WebP derivatives of referenced images
+func referencedStorageKeys(store storage.Storage) (map[string]bool, error) {
+	referenced := make(map[string]bool)
+	for _, reference := range mediaReferences {
+		var urls []string
+		if err := database.DB.Unscoped().Model(reference.model).
+			Where(reference.column+" <> ?", "").
+			Distinct().
+			Pluck(reference.column, &urls).Error; err != nil {
+			return nil, err
+		}
+
+		for _, url := range urls {
+			key, ok := storage.KeyFromURL(store, url)
+			if !ok {
+				continue
+			}
+			referenced[key] = true
+			if imaging.CanConvert(mime.TypeByExtension(strings.ToLower(filepath.Ext(key)))) {
+				referenced[webpDerivativeKey(key)] = true
+			}
+		}
+	}
+	return referenced, nil
+}
+
+// storedFileReferenced checks if a URL column or rich text content still
+// references url
+func storedFileReferenced(url string) (bool, error) {
+	for _, reference := range mediaReferences {
+		var count int64
+		if err := database.DB.Unscoped().Model(reference.model).Where(reference.column+" = ?", url).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return embeddedInContent(url)
+}
+
+// embeddedInContent checks if rich text content links to url
+func embeddedInContent(url string) (bool, error) {
+	for _, reference := range mediaContentReferences {
+		for _, column := range reference.columns {
+			var count int64
+			if err := database.DB.Model(reference.model).Where(LikeCondition(column), ContainsPattern(url)).Count(&count).Error; err != nil {
+				return false, err
+			}
+			if count > 0 {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"zionechainapi/configs"
+	"zionechainapi/internal/clock"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/mailer"
+	"zionechainapi/internal/models"
+	"gorm.io/gorm"
+)
+
+// maxMessageLinks is the most links a contact message may contain before it is taken for spam
+const maxMessageLinks = 3
+
+var (
+	// ErrMessageNotFound is returned when a contact message does not exist
+	ErrMessageNotFound = errors.New("message not found")
+	// ErrMessageEmpty is returned when a contact message body is blank
+	ErrMessageEmpty = errors.New("message body cannot be empty")
+	// ErrMessageTooLong is returned when a contact message body exceeds the configured maximum length
+	ErrMessageTooLong = errors.New("message body is too long")
+	// ErrMessageTooManyLinks is returned when a contact message contains more links than a person would send
+	ErrMessageTooManyLinks = errors.New("message contains too many links")
+	// ErrMessageHoneypot is returned when the hidden honeypot field of the contact form was filled in
+	ErrMessageHoneypot = errors.New("message was sent by a bot")
+	// ErrMessageRateLimited is returned when a client sends too many messages within the rate window
+	ErrMessageRateLimited = errors.New("too many messages, please try again later")
+)
+
+// IsMessageValidationError checks if an error was caused by invalid contact message input
+func IsMessageValidationError(err error) bool {
+	return errors.Is(err, ErrMessageEmpty) || errors.Is(err, ErrMessageTooLong) || errors.Is(err, ErrMessageTooManyLinks)
+}
+
+// MessageService handles contact form messages
+type MessageService struct {
+	config configs.ContactConfig
+	clock  clock.Clock
+	mailer mailer.Mailer
+}
+
+// NewMessageService creates a new message service
+func NewMessageService(config *configs.Config) *MessageService {
+	return &MessageService{
+		config: config.Contact,
+		clock:  clock.Real{},
+		mailer: mailer.New(config),
+	}
+}
+
+// WithClock replaces the clock used for the rate limit window
+func (s *MessageService) WithClock(c clock.Clock) *MessageService {
+	s.clock = c
+	return s
+}
+
+// WithMailer replaces the mailer selected by the configuration
+func (s *MessageService) WithMailer(m mailer.Mailer) *MessageService {
+	s.mailer = m
+	return s
+}
+
+// CreateMessageRequest represents the contact form. Website is a honeypot: the
+// form hides it from people, so only bots fill it in.
+type CreateMessageRequest struct {
+	Name    string `json:"name" binding:"required,max=100"`
+	Email   string `json:"email" binding:"required,email,max=100"`
+	Subject string `json:"subject" binding:"max=200"`
+	Body    string `json:"body" binding:"required"`
+	Website string `json:"website"`
+}
+
+// MessageResponse represents the contact message response
+type MessageResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Subject   string `json:"subject"`
+	Body      string `json:"body"`
+	Read      bool   `json:"read"`
+	CreatedAt string `json:"created_at"`
+}
+
+// MessageListResponse represents a page of contact messages
+type MessageListResponse struct {
+	Messages []MessageResponse `json:"messages"`
+	Metadata PageMetadata      `json:"metadata"`
+}
+
+// CreateMessage stores a contact message and notifies the configured address
+// about it. ip identifies the client for rate limiting.
+func (s *MessageService) CreateMessage(req CreateMessageRequest, ip string) (*MessageResponse, error) {
+	if strings.TrimSpace(req.Website) != "" {
+		return nil, ErrMessageHoneypot
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, ErrMessageEmpty
+	}
+	if s.config.MaxLength > 0 && utf8.RuneCountInString(body) > s.config.MaxLength {
+		return nil, ErrMessageTooLong
+	}
+	lower := strings.ToLower(body)
+	if strings.Count(lower, "http://")+strings.Count(lower, "https://") > maxMessageLinks {
+		return nil, ErrMessageTooManyLinks
+	}
+
+	message := models.Message{
+		Name:      strings.TrimSpace(req.Name),
+		Email:     strings.TrimSpace(req.Email),
+		Subject:   strings.TrimSpace(req.Subject),
+		Body:      body,
+		IPAddress: ip,
+	}
+	since := s.clock.Now().Add(-s.config.RateWindow)
+	if err := createRateLimited(&message, ip, s.config.RateLimit, since, ErrMessageRateLimited); err != nil {
+		return nil, err
+	}
+
+	s.notify(message)
+
+	return mapMessageToResponse(message), nil
+}
+
+// notify emails the configured address about a new message. The message is
+// already stored, so a failure is only logged.
+func (s *MessageService) notify(message models.Message) {
+	if s.config.NotifyEmail == "" {
+		return
+	}
+
+	subject := "New contact message"
+	if message.Subject != "" {
+		subject += ": " + message.Subject
+	}
+	body := fmt.Sprintf("From: %s <%s>\n\n%s", message.Name, message.Email, message.Body)
+	if err := s.mailer.Send(s.config.NotifyEmail, subject, body); err != nil {
+		log.Printf("Failed to send contact message %d notification: %v", message.ID, err)
+	}
+}
+
+// ListMessages lists contact messages, newest first. A nil read lists both
+// read and unread messages.
+func (s *MessageService) ListMessages(page, limit int, read *bool) ([]MessageResponse, int64, error) {
+	query := database.DB.Model(&models.Message{})
+	if read != nil {
+		// read is a reserved word, so let GORM quote it for the dialect in use
+		query = query.Where(map[string]interface{}{"read": *read})
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var messages []models.Message
+	if err := paginate(query, page, limit).Order("created_at DESC, id DESC").Find(&messages).Error; err != nil {
+		return nil, 0, err
+	}
+
+	response := make([]MessageResponse, 0, len(messages))
+	for _, message := range messages {
+		response = append(response, *mapMessageToResponse(message))
+	}
+
+	return response, total, nil
+}
+
+// MarkMessageRead marks a contact message as read or unread
+func (s *MessageService) MarkMessageRead(id uint, read bool) (*MessageResponse, error) {
+	var message models.Message
+	if err := database.DB.First(&message, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, err
+	}
+
+	if message.Read != read {
+		if err := database.DB.Model(&message).Update("read", read).Error; err != nil {
+			return nil, err
+		}
+		message.Read = read
+	}
+
+	return mapMessageToResponse(message), nil
+}
+
+func mapMessageToResponse(message models.Message) *MessageResponse {
+	return &MessageResponse{
+		ID:        message.ID,
+		Name:      message.Name,
+		Email:     message.Email,
+		Subject:   message.Subject,
+		Body:      message.Body,
+		Read:      message.Read,
+		CreatedAt: message.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"zionechainapi/internal/clock"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+)
+
+const (
+	// maxOutboxErrorLength bounds the delivery error kept on an outbox event
+	maxOutboxErrorLength = 500
+	// outboxClaimLease is how long a claimed event is left to its worker. An
+	// event whose worker stopped before recording the outcome is due again
+	// once the lease ends.
+	outboxClaimLease = 5 * time.Minute
+)
+
+// OutboxWorker delivers the events recorded in the outbox to webhooks. An
+// event is marked sent once every subscription received it; a failed event is
+// tried again with exponential backoff until the dispatcher's maximum number
+// of attempts, so each event is delivered at least once. Workers in several
+// instances share the outbox, each event being claimed by one of them.
+type OutboxWorker struct {
+	dispatcher *WebhookDispatcher
+	batchSize  int
+	clock      clock.Clock
+	mu         sync.Mutex
+	stop       chan struct{}
+	stopped    chan struct{}
+}
+
+// NewOutboxWorker creates an outbox worker that delivers up to batchSize events per run
+func NewOutboxWorker(dispatcher *WebhookDispatcher, batchSize int) *OutboxWorker {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	return &OutboxWorker{
+		dispatcher: dispatcher,
+		batchSize:  batchSize,
+		clock:      clock.Real{},
+	}
+}
+
+// WithClock replaces the clock that decides when events are due
+func (w *OutboxWorker) WithClock(c clock.Clock) *OutboxWorker {
+	w.clock = c
+	return w
+}
+
+// Start delivers due events every interval in the background until Stop is
+// called. A non-positive interval leaves delivery to Process.
+func (w *OutboxWorker) Start(interval time.Duration) *OutboxWorker {
+	if interval <= 0 || w.stop != nil {
+		return w
+	}
+
+	w.stop = make(chan struct{})
+	w.stopped = make(chan struct{})
+	go func() {
+		defer close(w.stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := w.Process(); err != nil {
+					log.Printf("Failed to process outbox events: %v", err)
+				}
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+	return w
+}
+
+// Stop ends background delivery. Undelivered events stay in the outbox for the next start.
+func (w *OutboxWorker) Stop() {
+	if w.stop != nil {
+		close(w.stop)
+		<-w.stopped
+		w.stop = nil
+	}
+}
+
+// Process delivers the pending events that are due, oldest first, and returns
+// how many it handled. Events claimed by another worker meanwhile are skipped.
+func (w *OutboxWorker) Process() (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.clock.Now()
+	var events []models.OutboxEvent
+	if err := database.DB.
+		Where("status = ? AND next_attempt_at <= ?", models.OutboxStatusPending, now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(w.batchSize).
+		Find(&events).Error; err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	var webhooks []models.Webhook
+	if err := database.DB.Where("active = ?", true).Find(&webhooks).Error; err != nil {
+		return 0, err
+	}
+
+	handled := 0
+	for _, event := range events {
+		claimed, err := w.claim(event, now)
+		if err != nil {
+			return handled, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := w.record(event, w.dispatcher.deliver(webhooks, event)); err != nil {
+			return handled, err
+		}
+		handled++
+	}
+	return handled, nil
+}
+
+// claim reserves event for this worker by moving its next attempt past the
+// claim lease. Only the worker whose update finds the event still due and
+// unchanged gets it, so concurrent workers do not deliver an event twice.
+func (w *OutboxWorker) claim(event models.OutboxEvent, now time.Time) (bool, error) {
+	result := database.DB.Model(&models.OutboxEvent{}).
+		Where("id = ? AND status = ? AND attempts = ? AND next_attempt_at <= ?", event.ID, models.OutboxStatusPending, event.Attempts, now).
+		UpdateColumn("next_attempt_at", now.Add(outboxClaimLease))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// record stores the outcome of a delivery attempt of event
+func (w *OutboxWorker) record(event models.OutboxEvent, deliveryErr error) error {
+	attempts := event.Attempts + 1
+	updates := map[string]interface{}{"attempts": attempts}
+
+	switch {
+	case deliveryErr == nil:
+		now := w.clock.Now()
+		updates["status"] = models.OutboxStatusSent
+		updates["sent_at"] = &now
+		updates["last_error"] = ""
+	case attempts >= w.dispatcher.maxAttempts:
+		log.Printf("Giving up delivery %s of %s after %d attempts: %v", event.EventID, event.Event, attempts, deliveryErr)
+		updates["status"] = models.OutboxStatusFailed
+		updates["last_error"] = truncateError(deliveryErr)
+	default:
+		updates["next_attempt_at"] = w.clock.Now().Add(w.dispatcher.retryDelay(attempts))
+		updates["last_error"] = truncateError(deliveryErr)
+	}
+
+	return database.DB.Model(&event).Updates(updates).Error
+}
+
+// truncateError returns the message of err cut to fit the last_error column
+func truncateError(err error) string {
+	message := err.Error()
+	if len(message) > maxOutboxErrorLength {
+		message = message[:maxOutboxErrorLength]
+	}
+	return message
+}
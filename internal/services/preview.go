@@ -0,0 +1,146 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"zionechainapi/configs"
+	"zionechainapi/internal/clock"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"gorm.io/gorm"
+)
+
+// previewAudience marks share link tokens so they are never accepted as access tokens
+const previewAudience = "preview"
+
+// ErrPreviewTokenInvalid is returned when a share link token is malformed, expired or for another resource
+var ErrPreviewTokenInvalid = errors.New("invalid or expired preview token")
+
+// ErrShareForbidden is returned when a user who is neither an admin nor the author shares content
+var ErrShareForbidden = errors.New("only the author or an admin can share this content")
+
+// PreviewService issues and validates signed share links for unpublished content
+type PreviewService struct {
+	config *configs.Config
+	clock  clock.Clock
+}
+
+// NewPreviewService creates a new preview service
+func NewPreviewService(config *configs.Config) *PreviewService {
+	return &PreviewService{
+		config: config,
+		clock:  clock.Real{},
+	}
+}
+
+// WithClock replaces the clock used for share link lifetimes
+func (s *PreviewService) WithClock(c clock.Clock) *PreviewService {
+	s.clock = c
+	return s
+}
+
+// ShareLinkResponse represents a signed share link
+type ShareLinkResponse struct {
+	URL       string `json:"url"`
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// ShareBlog issues a share link for a blog post. Only admins and the post's author may share it.
+func (s *PreviewService) ShareBlog(id, userID uint, role string) (*ShareLinkResponse, error) {
+	var blog models.BlogPost
+	if err := database.DB.Select("id", "created_by").First(&blog, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("blog post not found")
+		}
+		return nil, err
+	}
+
+	if role != "admin" && blog.CreatedBy != userID {
+		return nil, ErrShareForbidden
+	}
+
+	now := s.clock.Now()
+	expiresAt := now.Add(s.config.Content.PreviewExpiry)
+	claims := jwt.RegisteredClaims{
+		Subject:   previewSubject(models.TranslationResourceBlogPost, id),
+		Audience:  jwt.ClaimStrings{previewAudience},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.signingKey())
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionShare, AuditResourceBlogPost, id, map[string]interface{}{"expires_at": expiresAt.UTC().Format(time.RFC3339)})
+
+	return &ShareLinkResponse{
+		URL:       strings.TrimRight(s.config.App.URL, "/") + "/api/blog/preview?token=" + url.QueryEscape(token),
+		Token:     token,
+		ExpiresAt: expiresAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// PreviewBlog returns the blog post a share link token was issued for, whether or not it is published
+func (s *PreviewService) PreviewBlog(token string) (*BlogResponse, error) {
+	id, err := s.parseToken(token, models.TranslationResourceBlogPost)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBlogService().GetBlogByID(id, MediaPage{}, true)
+}
+
+// parseToken validates a share link token and returns the id of the resource it was issued for
+func (s *PreviewService) parseToken(token, resourceType string) (uint, error) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return s.signingKey(), nil
+	}, jwt.WithAudience(previewAudience), jwt.WithTimeFunc(s.clock.Now))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrPreviewTokenInvalid, err)
+	}
+
+	// Share links must always expire
+	if claims.ExpiresAt == nil {
+		return 0, ErrPreviewTokenInvalid
+	}
+
+	prefix := resourceType + ":"
+	if !strings.HasPrefix(claims.Subject, prefix) {
+		return 0, ErrPreviewTokenInvalid
+	}
+
+	id, err := strconv.ParseUint(strings.TrimPrefix(claims.Subject, prefix), 10, 64)
+	if err != nil {
+		return 0, ErrPreviewTokenInvalid
+	}
+
+	return uint(id), nil
+}
+
+// signingKey derives the share link key from the JWT secret so share links and
+// access tokens cannot be swapped for one another
+func (s *PreviewService) signingKey() []byte {
+	mac := hmac.New(sha256.New, []byte(s.config.JWT.Secret))
+	mac.Write([]byte(previewAudience))
+	return mac.Sum(nil)
+}
+
+// previewSubject identifies the single resource a share link grants access to
+func previewSubject(resourceType string, id uint) string {
+	return resourceType + ":" + strconv.FormatUint(uint64(id), 10)
+}
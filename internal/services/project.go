@@ -1,70 +1,177 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
+	"zionechainapi/internal/clock"
 	"zionechainapi/internal/database"
 	"zionechainapi/internal/models"
+	"zionechainapi/internal/storage"
+	"zionechainapi/internal/utils"
 	"gorm.io/gorm"
 )
 
+// ErrProjectNotFound is returned when a project does not exist or is not visible to the caller
+var ErrProjectNotFound = errors.New("project not found")
+
 // ProjectService handles project-related operations
-type ProjectService struct{}
+type ProjectService struct {
+	clock        clock.Clock
+	sanitizeHTML bool
+	webhooks     *WebhookDispatcher
+	storage      storage.Storage
+	ctx          context.Context
+}
 
 // NewProjectService creates a new project service
 func NewProjectService() *ProjectService {
-	return &ProjectService{}
+	return &ProjectService{
+		clock:        clock.Real{},
+		sanitizeHTML: true,
+	}
+}
+
+// WithClock replaces the clock used for slug timestamps
+func (s *ProjectService) WithClock(c clock.Clock) *ProjectService {
+	s.clock = c
+	return s
+}
+
+// WithContentSanitization enables or disables stripping unsafe HTML from content
+func (s *ProjectService) WithContentSanitization(enabled bool) *ProjectService {
+	s.sanitizeHTML = enabled
+	return s
+}
+
+// WithWebhooks sets the dispatcher that records project lifecycle events for webhooks
+func (s *ProjectService) WithWebhooks(d *WebhookDispatcher) *ProjectService {
+	s.webhooks = d
+	return s
+}
+
+// WithStorage sets the storage backend whose files are deleted along with the media using them
+func (s *ProjectService) WithStorage(store storage.Storage) *ProjectService {
+	s.storage = store
+	return s
+}
+
+// WithContext returns a copy of the service whose queries are bound to ctx,
+// usually the request context, so they are cancelled along with it. Unlike the
+// other builders it leaves s unchanged, as s is shared between requests.
+func (s *ProjectService) WithContext(ctx context.Context) *ProjectService {
+	bound := *s
+	bound.ctx = ctx
+	return &bound
+}
+
+// db returns the database handle for the queries of the service
+func (s *ProjectService) db() *gorm.DB {
+	return database.FromContext(s.ctx)
+}
+
+// sanitizeContent strips unsafe HTML from content when sanitization is enabled
+func (s *ProjectService) sanitizeContent(content string) string {
+	if !s.sanitizeHTML {
+		return content
+	}
+	return utils.SanitizeHTML(content)
 }
 
 // CreateProjectRequest represents the create project request
 type CreateProjectRequest struct {
-	Title       string   `json:"title" binding:"required"`
-	Description string   `json:"description" binding:"required"`
-	Content     string   `json:"content" binding:"required"`
-	CategoryID  uint     `json:"category_id" binding:"required"`
-	TagIDs      []uint   `json:"tag_ids"`
-	Featured    bool     `json:"featured"`
-	Published   bool     `json:"published"`
+	Title           string   `json:"title" binding:"required"`
+	Description     string   `json:"description" binding:"required"`
+	Content         string   `json:"content" binding:"required"`
+	CategoryID      uint     `json:"category_id" binding:"required"`
+	TagIDs          []uint   `json:"tag_ids"`
+	Tags            []string `json:"tags"` // tag names, created if missing
+	TechnologyIDs   []uint   `json:"technology_ids"`
+	Featured        bool     `json:"featured"`
+	Published       bool     `json:"published"`
+	MetaTitle       string   `json:"meta_title" binding:"max=200"`       // defaults to the title
+	MetaDescription string   `json:"meta_description" binding:"max=500"` // defaults to the description
+	OGImage         string   `json:"og_image" binding:"max=255"`         // defaults to the first image
 }
 
 // UpdateProjectRequest represents the update project request
+// Nil string fields are left unchanged, while non-nil fields are applied as-is,
+// which allows clearing a field by sending an empty string.
 type UpdateProjectRequest struct {
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	Content     string  `json:"content"`
-	CategoryID  uint    `json:"category_id"`
-	TagIDs      []uint  `json:"tag_ids"`
-	Featured    *bool   `json:"featured"`
-	Published   *bool   `json:"published"`
+	Title           *string `json:"title"`
+	Description     *string `json:"description"`
+	Content         *string `json:"content"`
+	CategoryID      uint    `json:"category_id"`
+	TagIDs          []uint  `json:"tag_ids"`
+	TechnologyIDs   []uint  `json:"technology_ids"` // replaces the technologies when set; [] removes all
+	Featured        *bool   `json:"featured"`
+	Published       *bool   `json:"published"`
+	MetaTitle       *string `json:"meta_title" binding:"omitempty,max=200"`
+	MetaDescription *string `json:"meta_description" binding:"omitempty,max=500"`
+	OGImage         *string `json:"og_image" binding:"omitempty,max=255"`
 }
 
 // ProjectMediaRequest represents the project media request
 type ProjectMediaRequest struct {
-	Type      string `json:"type" binding:"required"`
+	Type      string `json:"type"` // image, video, embed or document; defaults to image
 	URL       string `json:"url" binding:"required"`
 	Caption   string `json:"caption"`
 	SortOrder int    `json:"sort_order"`
 }
 
+// UpdateProjectMediaRequest represents the update project media request.
+// Nil fields are left unchanged.
+type UpdateProjectMediaRequest struct {
+	Type      *string `json:"type"`
+	URL       *string `json:"url"`
+	Caption   *string `json:"caption"`
+	SortOrder *int    `json:"sort_order"`
+}
+
+// BatchProjectMediaRequest represents a request to add several media items at once
+type BatchProjectMediaRequest struct {
+	Media []ProjectMediaRequest `json:"media" binding:"required,min=1,dive"`
+}
+
 // ProjectResponse represents the project response
 type ProjectResponse struct {
-	ID          uint                   `json:"id"`
-	Title       string                 `json:"title"`
-	Slug        string                 `json:"slug"`
-	Description string                 `json:"description"`
-	Content     string                 `json:"content"`
-	CategoryID  uint                   `json:"category_id"`
-	Category    ProjectCategoryResponse `json:"category"`
-	Media       []ProjectMediaResponse  `json:"media"`
-	Tags        []TagResponse           `json:"tags"`
-	Featured    bool                   `json:"featured"`
-	Published   bool                   `json:"published"`
-	CreatedBy   uint                   `json:"created_by"`
-	UpdatedBy   uint                   `json:"updated_by"`
-	CreatedAt   string                 `json:"created_at"`
-	UpdatedAt   string                 `json:"updated_at"`
+	ID              uint                    `json:"id"`
+	Title           string                  `json:"title"`
+	Slug            string                  `json:"slug"`
+	Description     string                  `json:"description"`
+	Content         string                  `json:"content"`
+	MetaTitle       string                  `json:"meta_title"`
+	MetaDescription string                  `json:"meta_description"`
+	OGImage         string                  `json:"og_image"`
+	CategoryID      uint                    `json:"category_id"`
+	Category        ProjectCategoryResponse `json:"category"`
+	Media           []ProjectMediaResponse  `json:"media"`
+	MediaMetadata   *PageMetadata           `json:"media_metadata,omitempty"` // set when the media are paginated
+	Tags            []TagResponse           `json:"tags"`
+	Technologies    []TechnologyResponse    `json:"technologies"`
+	Featured        bool                    `json:"featured"`
+	FeaturedOrder   *int                    `json:"featured_order"`
+	Published       bool                    `json:"published"`
+	Views           uint                    `json:"views"`
+	CreatedBy       uint                    `json:"created_by"`
+	UpdatedBy       uint                    `json:"updated_by"`
+	CreatedAt       string                  `json:"created_at"`
+	UpdatedAt       string                  `json:"updated_at"`
+}
+
+// ProjectListResponse represents a page of projects
+type ProjectListResponse struct {
+	Projects []ProjectResponse `json:"projects"`
+	Metadata PageMetadata      `json:"metadata"`
+}
+
+// ProjectCursorListResponse represents a page of projects fetched with a cursor
+type ProjectCursorListResponse struct {
+	Projects []ProjectResponse `json:"projects"`
+	Metadata CursorMetadata    `json:"metadata"`
 }
 
 // ProjectCategoryResponse represents the project category response
@@ -92,44 +199,56 @@ type TagResponse struct {
 
 // CreateProject creates a new project
 func (s *ProjectService) CreateProject(req CreateProjectRequest, userID uint) (*ProjectResponse, error) {
+	if err := checkCategoryExists(s.db(), &models.ProjectCategory{}, req.CategoryID); err != nil {
+		return nil, err
+	}
+
 	// Create slug from title
-	slug := strings.ToLower(strings.ReplaceAll(req.Title, " ", "-"))
+	slug := utils.SanitizeSlug(req.Title)
 
 	// Check if slug already exists
 	var count int64
-	if err := database.DB.Model(&models.Project{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+	if err := s.db().Model(&models.Project{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
 		return nil, err
 	}
 
 	if count > 0 {
 		// Append timestamp to slug to make it unique
-		slug = slug + "-" + string(time.Now().Unix())
+		slug = fmt.Sprintf("%s-%d", slug, s.clock.Now().Unix())
 	}
 
 	// Create project
 	project := models.Project{
-		Title:       req.Title,
-		Slug:        slug,
-		Description: req.Description,
-		Content:     req.Content,
-		CategoryID:  req.CategoryID,
-		Featured:    req.Featured,
-		Published:   req.Published,
-		CreatedBy:   userID,
-		UpdatedBy:   userID,
+		Title:           req.Title,
+		Slug:            slug,
+		Description:     req.Description,
+		Content:         s.sanitizeContent(req.Content),
+		MetaTitle:       strings.TrimSpace(req.MetaTitle),
+		MetaDescription: strings.TrimSpace(req.MetaDescription),
+		OGImage:         strings.TrimSpace(req.OGImage),
+		CategoryID:      req.CategoryID,
+		Featured:        req.Featured,
+		Published:       req.Published,
+		CreatedBy:       userID,
+		UpdatedBy:       userID,
 	}
 
 	// Start transaction
-	tx := database.DB.Begin()
-	if err := tx.Create(&project).Error; err != nil {
+	tx := s.db().Begin()
+	// A concurrent create with the same title may have taken the slug after
+	// the check above, so retry once with a random suffix
+	if err := writeSlug(tx, project.Slug, utils.SanitizeSlug(req.Title), func(candidate string) error {
+		project.Slug = candidate
+		return tx.Create(&project).Error
+	}); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
-	// Add tags if any
-	if len(req.TagIDs) > 0 {
-		var tags []models.Tag
-		if err := tx.Where("id IN ?", req.TagIDs).Find(&tags).Error; err != nil {
+	// Add tags if any, creating named tags that do not exist yet
+	if len(req.TagIDs) > 0 || len(req.Tags) > 0 {
+		tags, err := resolveTags(tx, req.TagIDs, req.Tags)
+		if err != nil {
 			tx.Rollback()
 			return nil, err
 		}
@@ -140,65 +259,116 @@ func (s *ProjectService) CreateProject(req CreateProjectRequest, userID uint) (*
 		}
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		return nil, err
+	// Add technologies if any
+	if len(req.TechnologyIDs) > 0 {
+		if err := replaceTechnologies(tx, &project, req.TechnologyIDs); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
 	}
 
 	// Load project with relationships
-	if err := database.DB.Preload("Category").Preload("Media").Preload("Tags").First(&project, project.ID).Error; err != nil {
+	if err := tx.Preload("Category").Preload("Media").Preload("Tags").Preload("Technologies").First(&project, project.ID).Error; err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
 	// Map to response
-	return s.mapProjectToResponse(project), nil
+	response := s.mapProjectToResponse(project)
+
+	// Record the events with the project so they are delivered exactly when it is created
+	if err := s.webhooks.Enqueue(tx, WebhookEventProjectCreated, response); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if response.Published {
+		if err := s.webhooks.Enqueue(tx, WebhookEventProjectPublished, response); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionCreate, AuditResourceProject, project.ID, req)
+
+	return response, nil
 }
 
-// GetProjectByID gets a project by ID
-func (s *ProjectService) GetProjectByID(id uint) (*ProjectResponse, error) {
+// GetProjectByID gets a project by ID with the selected page of its media.
+// Unpublished projects are only found when drafts is set.
+func (s *ProjectService) GetProjectByID(id uint, mediaPage MediaPage, drafts bool) (*ProjectResponse, error) {
 	var project models.Project
-	if err := database.DB.Preload("Category").Preload("Media").Preload("Tags").First(&project, id).Error; err != nil {
+	if err := s.detailQuery(mediaPage, drafts).First(&project, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("project not found")
 		}
 		return nil, err
 	}
 
-	return s.mapProjectToResponse(project), nil
+	return s.mapProjectDetailToResponse(project, mediaPage)
 }
 
-// GetProjectBySlug gets a project by slug
-func (s *ProjectService) GetProjectBySlug(slug string) (*ProjectResponse, error) {
+// GetProjectBySlug gets a project by slug with the selected page of its media,
+// falling back to the slugs projects used to have. The slug of the returned
+// project is its current one. Unpublished projects are only found when drafts
+// is set.
+func (s *ProjectService) GetProjectBySlug(slug string, mediaPage MediaPage, drafts bool) (*ProjectResponse, error) {
 	var project models.Project
-	if err := database.DB.Preload("Category").Preload("Media").Preload("Tags").Where("slug = ?", slug).First(&project).Error; err != nil {
+	err := s.detailQuery(mediaPage, drafts).Where("slug = ?", slug).First(&project).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		var id uint
+		if id, err = previousSlugOwner(s.db(), models.SlugResourceProject, slug); err == nil {
+			err = s.detailQuery(mediaPage, drafts).First(&project, id).Error
+		}
+	}
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("project not found")
 		}
 		return nil, err
 	}
 
-	return s.mapProjectToResponse(project), nil
+	return s.mapProjectDetailToResponse(project, mediaPage)
+}
+
+// detailQuery preloads the associations of a project detail response,
+// leaving out unpublished projects unless drafts is set
+func (s *ProjectService) detailQuery(mediaPage MediaPage, drafts bool) *gorm.DB {
+	query := preloadMedia(s.db().Preload("Category"), mediaPage).Preload("Tags").Preload("Technologies")
+	if !drafts {
+		query = query.Where("published = ?", true)
+	}
+	return query
+}
+
+// mapProjectDetailToResponse maps a project detail to a response, describing its media page
+func (s *ProjectService) mapProjectDetailToResponse(project models.Project, mediaPage MediaPage) (*ProjectResponse, error) {
+	metadata, err := mediaMetadata(s.db(), &models.ProjectMedia{}, "project_id", project.ID, mediaPage)
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.mapProjectToResponse(project)
+	response.MediaMetadata = metadata
+	return response, nil
+}
+
+// CheckSlugAvailability reports the slug generated from a title and whether no project uses it yet
+func (s *ProjectService) CheckSlugAvailability(title string) (*SlugAvailabilityResponse, error) {
+	return checkSlugAvailability(s.db(), &models.Project{}, title)
 }
 
 // ListProjects lists all projects with pagination
-func (s *ProjectService) ListProjects(page, limit int, categoryID uint, featured, published bool) ([]ProjectResponse, int64, error) {
+func (s *ProjectService) ListProjects(page, limit int, filter ListFilter) ([]ProjectResponse, int64, error) {
 	var projects []models.Project
 	var total int64
 
 	// Base query
-	query := database.DB.Model(&models.Project{})
-
-	// Apply filters
-	if categoryID > 0 {
-		query = query.Where("category_id = ?", categoryID)
-	}
-
-	if featured {
-		query = query.Where("featured = ?", featured)
-	}
-
-	// Default to published only
-	query = query.Where("published = ?", published)
+	query := projectListTarget.apply(s.db().Model(&models.Project{}), filter)
 
 	// Count total
 	if err := query.Count(&total).Error; err != nil {
@@ -206,16 +376,14 @@ func (s *ProjectService) ListProjects(page, limit int, categoryID uint, featured
 	}
 
 	// Pagination
-	offset := (page - 1) * limit
-	if err := query.Preload("Category").Preload("Media").Preload("Tags").
-		Limit(limit).Offset(offset).
-		Order("created_at DESC").
+	if err := paginate(query, page, limit).Preload("Category").Preload("Media").Preload("Tags").Preload("Technologies").
+		Order(listOrder(filter)).
 		Find(&projects).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Map to response
-	var response []ProjectResponse
+	response := make([]ProjectResponse, 0, len(projects))
 	for _, project := range projects {
 		response = append(response, *s.mapProjectToResponse(project))
 	}
@@ -223,45 +391,176 @@ func (s *ProjectService) ListProjects(page, limit int, categoryID uint, featured
 	return response, total, nil
 }
 
+// ListProjectsAfter lists projects using cursor pagination, returning the page
+// after the cursor and the cursor of the next page, which is empty on the last page
+func (s *ProjectService) ListProjectsAfter(cursor string, limit int, filter ListFilter) ([]ProjectResponse, string, error) {
+	var projects []models.Project
+
+	query, err := paginateAfter(projectListTarget.apply(s.db().Model(&models.Project{}), filter), filter, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := query.Preload("Category").Preload("Media").Preload("Tags").Preload("Technologies").Find(&projects).Error; err != nil {
+		return nil, "", err
+	}
+
+	// The extra row only signals that another page exists
+	nextCursor := ""
+	if len(projects) > limit {
+		projects = projects[:limit]
+		last := projects[limit-1]
+		nextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	// Map to response
+	response := make([]ProjectResponse, 0, len(projects))
+	for _, project := range projects {
+		response = append(response, *s.mapProjectToResponse(project))
+	}
+
+	return response, nextCursor, nil
+}
+
+// ReorderFeatured sets the display order of featured projects to the order of the given ids
+func (s *ProjectService) ReorderFeatured(req ReorderFeaturedRequest, userID uint) ([]ProjectResponse, error) {
+	tx := s.db().Begin()
+
+	if err := reorderFeatured(tx, &models.Project{}, req.IDs); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionReorder, AuditResourceProject, 0, req)
+
+	var projects []models.Project
+	if err := s.db().Preload("Category").Preload("Media").Preload("Tags").Preload("Technologies").
+		Where("id IN ?", req.IDs).
+		Order("featured_order ASC").
+		Find(&projects).Error; err != nil {
+		return nil, err
+	}
+
+	// Map to response
+	response := make([]ProjectResponse, 0, len(projects))
+	for _, project := range projects {
+		response = append(response, *s.mapProjectToResponse(project))
+	}
+
+	return response, nil
+}
+
+// CountProjects counts the projects matching the given filter
+func (s *ProjectService) CountProjects(filter ListFilter) (int64, error) {
+	var count int64
+	if err := projectListTarget.apply(s.db().Model(&models.Project{}), filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// LastModifiedProjects returns the latest update time of the published projects
+func (s *ProjectService) LastModifiedProjects() (*LastModifiedResponse, error) {
+	lastModified, err := publishedLastModified(s.db(), &models.Project{})
+	if err != nil {
+		return nil, err
+	}
+	return &LastModifiedResponse{LastModified: lastModified}, nil
+}
+
 // UpdateProject updates a project
 func (s *ProjectService) UpdateProject(id uint, req UpdateProjectRequest, userID uint) (*ProjectResponse, error) {
 	var project models.Project
-	if err := database.DB.First(&project, id).Error; err != nil {
+	if err := s.db().First(&project, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("project not found")
 		}
 		return nil, err
 	}
 
+	if req.CategoryID > 0 && req.CategoryID != project.CategoryID {
+		if err := checkCategoryExists(s.db(), &models.ProjectCategory{}, req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Update fields if provided
-	tx := database.DB.Begin()
+	tx := s.db().Begin()
+
+	response, err := s.applyProjectUpdate(tx, &project, req, userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionUpdate, AuditResourceProject, id, req)
+
+	return response, nil
+}
+
+// applyProjectUpdate applies an update request to a loaded project within tx and records
+// the lifecycle events. The caller rolls back tx on error and commits it.
+func (s *ProjectService) applyProjectUpdate(tx *gorm.DB, project *models.Project, req UpdateProjectRequest, userID uint) (*ProjectResponse, error) {
+	wasPublished := project.Published
 
-	if req.Title != "" && req.Title != project.Title {
+	if req.Title != nil && *req.Title == "" {
+		return nil, errors.New("title cannot be empty")
+	}
+
+	if req.Title != nil && *req.Title != project.Title {
 		// Create new slug from title
-		slug := strings.ToLower(strings.ReplaceAll(req.Title, " ", "-"))
+		slug := utils.SanitizeSlug(*req.Title)
 
 		// Check if slug already exists and is not this project
 		var count int64
-		if err := tx.Model(&models.Project{}).Where("slug = ? AND id != ?", slug, id).Count(&count).Error; err != nil {
-			tx.Rollback()
+		if err := tx.Model(&models.Project{}).Where("slug = ? AND id != ?", slug, project.ID).Count(&count).Error; err != nil {
 			return nil, err
 		}
 
 		if count > 0 {
 			// Append timestamp to slug to make it unique
-			slug = slug + "-" + string(time.Now().Unix())
+			slug = fmt.Sprintf("%s-%d", slug, s.clock.Now().Unix())
 		}
 
-		project.Title = req.Title
+		// Keep links to the old slug working
+		if slug != project.Slug {
+			if err := recordSlugChange(tx, models.SlugResourceProject, project.Slug, project.ID); err != nil {
+				return nil, err
+			}
+		}
+
+		project.Title = *req.Title
 		project.Slug = slug
 	}
 
-	if req.Description != "" {
-		project.Description = req.Description
+	if req.Description != nil {
+		project.Description = *req.Description
+	}
+
+	if req.Content != nil {
+		project.Content = s.sanitizeContent(*req.Content)
+	}
+
+	if req.MetaTitle != nil {
+		project.MetaTitle = strings.TrimSpace(*req.MetaTitle)
 	}
 
-	if req.Content != "" {
-		project.Content = req.Content
+	if req.MetaDescription != nil {
+		project.MetaDescription = strings.TrimSpace(*req.MetaDescription)
+	}
+
+	if req.OGImage != nil {
+		project.OGImage = strings.TrimSpace(*req.OGImage)
 	}
 
 	if req.CategoryID > 0 {
@@ -270,6 +569,9 @@ func (s *ProjectService) UpdateProject(id uint, req UpdateProjectRequest, userID
 
 	if req.Featured != nil {
 		project.Featured = *req.Featured
+		if !project.Featured {
+			project.FeaturedOrder = nil
+		}
 	}
 
 	if req.Published != nil {
@@ -278,8 +580,7 @@ func (s *ProjectService) UpdateProject(id uint, req UpdateProjectRequest, userID
 
 	project.UpdatedBy = userID
 
-	if err := tx.Save(&project).Error; err != nil {
-		tx.Rollback()
+	if err := tx.Save(project).Error; err != nil {
 		return nil, err
 	}
 
@@ -287,41 +588,69 @@ func (s *ProjectService) UpdateProject(id uint, req UpdateProjectRequest, userID
 	if len(req.TagIDs) > 0 {
 		var tags []models.Tag
 		if err := tx.Where("id IN ?", req.TagIDs).Find(&tags).Error; err != nil {
-			tx.Rollback()
 			return nil, err
 		}
 
-		if err := tx.Model(&project).Association("Tags").Replace(tags); err != nil {
-			tx.Rollback()
+		if err := tx.Model(project).Association("Tags").Replace(tags); err != nil {
 			return nil, err
 		}
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		return nil, err
+	// Update technologies if provided
+	if req.TechnologyIDs != nil {
+		if err := replaceTechnologies(tx, project, req.TechnologyIDs); err != nil {
+			return nil, err
+		}
 	}
 
 	// Load project with relationships
-	if err := database.DB.Preload("Category").Preload("Media").Preload("Tags").First(&project, id).Error; err != nil {
+	if err := tx.Preload("Category").Preload("Media").Preload("Tags").Preload("Technologies").First(project, project.ID).Error; err != nil {
 		return nil, err
 	}
 
-	return s.mapProjectToResponse(project), nil
+	response := s.mapProjectToResponse(*project)
+
+	if err := s.webhooks.Enqueue(tx, WebhookEventProjectUpdated, response); err != nil {
+		return nil, err
+	}
+	if response.Published && !wasPublished {
+		if err := s.webhooks.Enqueue(tx, WebhookEventProjectPublished, response); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// enqueueProjectUpdated announces a change of the project, such as to its
+// slug or media, made inside tx
+func (s *ProjectService) enqueueProjectUpdated(tx *gorm.DB, id uint) error {
+	var project models.Project
+	if err := tx.Preload("Category").Preload("Media").Preload("Tags").Preload("Technologies").First(&project, id).Error; err != nil {
+		return err
+	}
+
+	return s.webhooks.Enqueue(tx, WebhookEventProjectUpdated, s.mapProjectToResponse(project))
 }
 
 // DeleteProject deletes a project
-func (s *ProjectService) DeleteProject(id uint) error {
+func (s *ProjectService) DeleteProject(id, userID uint) error {
 	var project models.Project
-	if err := database.DB.First(&project, id).Error; err != nil {
+	if err := s.db().First(&project, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("project not found")
 		}
 		return err
 	}
 
+	// Remember the media files to delete once the rows are gone
+	var mediaURLs []string
+	if err := s.db().Model(&models.ProjectMedia{}).Where("project_id = ?", id).Pluck("url", &mediaURLs).Error; err != nil {
+		return err
+	}
+
 	// Start transaction
-	tx := database.DB.Begin()
+	tx := s.db().Begin()
 
 	// Delete media
 	if err := tx.Where("project_id = ?", id).Delete(&models.ProjectMedia{}).Error; err != nil {
@@ -335,38 +664,183 @@ func (s *ProjectService) DeleteProject(id uint) error {
 		return err
 	}
 
+	// Remove technology associations
+	if err := tx.Model(&project).Association("Technologies").Clear(); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Delete translations
+	if err := deleteTranslations(tx, models.TranslationResourceProject, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Delete project
 	if err := tx.Delete(&project).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
 
+	if err := s.webhooks.Enqueue(tx, WebhookEventProjectDeleted, map[string]interface{}{"id": id, "slug": project.Slug}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	recordAudit(userID, models.AuditActionDelete, AuditResourceProject, id, nil)
+
+	for _, url := range mediaURLs {
+		deleteStoredFile(s.storage, url)
+	}
+
+	return nil
+}
+
+// DuplicateProject copies a project with its tags and media into a new unpublished, unfeatured
+// draft owned by the given user
+func (s *ProjectService) DuplicateProject(id, userID uint) (*ProjectResponse, error) {
+	var project models.Project
+	if err := s.db().Preload("Media").Preload("Tags").Preload("Technologies").First(&project, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	// Start transaction
+	tx := s.db().Begin()
+
+	slug, err := utils.GenerateCopySlug(project.Slug, func(slug string) (bool, error) {
+		var count int64
+		err := tx.Model(&models.Project{}).Where("slug = ?", slug).Count(&count).Error
+		return count > 0, err
+	})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	duplicate := models.Project{
+		Title:           project.Title,
+		Slug:            slug,
+		Description:     project.Description,
+		Content:         project.Content,
+		MetaTitle:       project.MetaTitle,
+		MetaDescription: project.MetaDescription,
+		OGImage:         project.OGImage,
+		CategoryID:      project.CategoryID,
+		CreatedBy:       userID,
+		UpdatedBy:       userID,
+	}
+
+	if err := tx.Create(&duplicate).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// The column default would publish the draft on insert, so unpublish it explicitly
+	if err := tx.Model(&duplicate).Update("published", false).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Copy tags
+	if len(project.Tags) > 0 {
+		if err := tx.Model(&duplicate).Association("Tags").Replace(project.Tags); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// Copy technologies
+	if len(project.Technologies) > 0 {
+		if err := tx.Model(&duplicate).Association("Technologies").Replace(project.Technologies); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// Copy media
+	for _, media := range project.Media {
+		copied := models.ProjectMedia{
+			ProjectID: duplicate.ID,
+			Type:      media.Type,
+			URL:       media.URL,
+			Caption:   media.Caption,
+			SortOrder: media.SortOrder,
+		}
+		if err := tx.Create(&copied).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// Load project with relationships
+	if err := tx.Preload("Category").Preload("Media").Preload("Tags").Preload("Technologies").First(&duplicate, duplicate.ID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Map to response
+	response := s.mapProjectToResponse(duplicate)
+
+	// The copy is a new draft, announced like one created from scratch
+	if err := s.webhooks.Enqueue(tx, WebhookEventProjectCreated, response); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// Commit transaction
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionCreate, AuditResourceProject, duplicate.ID, map[string]interface{}{"duplicated_from": id})
+
+	return response, nil
 }
 
 // AddProjectMedia adds media to a project
-func (s *ProjectService) AddProjectMedia(projectID uint, req ProjectMediaRequest) (*ProjectMediaResponse, error) {
+func (s *ProjectService) AddProjectMedia(projectID, userID uint, req ProjectMediaRequest) (*ProjectMediaResponse, error) {
 	var project models.Project
-	if err := database.DB.First(&project, projectID).Error; err != nil {
+	if err := s.db().First(&project, projectID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("project not found")
 		}
 		return nil, err
 	}
 
+	mediaType, err := validateMedia(req.Type, req.URL)
+	if err != nil {
+		return nil, err
+	}
+
 	media := models.ProjectMedia{
 		ProjectID: projectID,
-		Type:      req.Type,
+		Type:      mediaType,
 		URL:       req.URL,
 		Caption:   req.Caption,
 		SortOrder: req.SortOrder,
 	}
 
-	if err := database.DB.Create(&media).Error; err != nil {
+	// The parent is announced as updated since its rendered media changed
+	err = s.db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&media).Error; err != nil {
+			return err
+		}
+		return s.enqueueProjectUpdated(tx, projectID)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	recordAudit(userID, models.AuditActionCreate, AuditResourceProjectMedia, media.ID, map[string]interface{}{"project_id": projectID, "media": req})
+
 	return &ProjectMediaResponse{
 		ID:        media.ID,
 		Type:      media.Type,
@@ -376,25 +850,161 @@ func (s *ProjectService) AddProjectMedia(projectID uint, req ProjectMediaRequest
 	}, nil
 }
 
-// UpdateProjectMedia updates project media
-func (s *ProjectService) UpdateProjectMedia(mediaID uint, req ProjectMediaRequest) (*ProjectMediaResponse, error) {
+// AddProjectMediaBatch adds several media items to a project in one transaction.
+// Sort orders are assigned incrementally after the project's existing media, and
+// an invalid entry rolls back the whole batch.
+func (s *ProjectService) AddProjectMediaBatch(projectID, userID uint, req BatchProjectMediaRequest) ([]ProjectMediaResponse, error) {
+	var project models.Project
+	if err := s.db().First(&project, projectID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("project not found")
+		}
+		return nil, err
+	}
+
+	// Start transaction
+	tx := s.db().Begin()
+
+	// Append after the current last media item
+	var maxSortOrder int
+	if err := tx.Model(&models.ProjectMedia{}).
+		Where("project_id = ?", projectID).
+		Select("COALESCE(MAX(sort_order), 0)").
+		Scan(&maxSortOrder).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	response := make([]ProjectMediaResponse, 0, len(req.Media))
+	for i, item := range req.Media {
+		mediaType, err := validateMedia(item.Type, item.URL)
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("media %d: %w", i, err)
+		}
+
+		media := models.ProjectMedia{
+			ProjectID: projectID,
+			Type:      mediaType,
+			URL:       item.URL,
+			Caption:   item.Caption,
+			SortOrder: maxSortOrder + i + 1,
+		}
+
+		if err := tx.Create(&media).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		response = append(response, ProjectMediaResponse{
+			ID:        media.ID,
+			Type:      media.Type,
+			URL:       media.URL,
+			Caption:   media.Caption,
+			SortOrder: media.SortOrder,
+		})
+	}
+
+	if err := s.enqueueProjectUpdated(tx, projectID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	for i, media := range response {
+		recordAudit(userID, models.AuditActionCreate, AuditResourceProjectMedia, media.ID, map[string]interface{}{"project_id": projectID, "media": req.Media[i]})
+	}
+
+	return response, nil
+}
+
+// ListProjectMedia lists the media of a project ordered by sort order. The media
+// of unpublished projects are only listed when drafts is set.
+func (s *ProjectService) ListProjectMedia(projectID uint, drafts bool) ([]ProjectMediaResponse, error) {
+	query := s.db()
+	if !drafts {
+		query = query.Where("published = ?", true)
+	}
+
+	var project models.Project
+	if err := query.First(&project, projectID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	var media []models.ProjectMedia
+	if err := s.db().Where("project_id = ?", projectID).Order("sort_order ASC, id ASC").Find(&media).Error; err != nil {
+		return nil, err
+	}
+
+	response := make([]ProjectMediaResponse, 0, len(media))
+	for _, item := range media {
+		response = append(response, ProjectMediaResponse{
+			ID:        item.ID,
+			Type:      item.Type,
+			URL:       item.URL,
+			Caption:   item.Caption,
+			SortOrder: item.SortOrder,
+		})
+	}
+
+	return response, nil
+}
+
+// UpdateProjectMedia updates the provided fields of project media
+func (s *ProjectService) UpdateProjectMedia(mediaID, userID uint, req UpdateProjectMediaRequest) (*ProjectMediaResponse, error) {
 	var media models.ProjectMedia
-	if err := database.DB.First(&media, mediaID).Error; err != nil {
+	if err := s.db().First(&media, mediaID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("media not found")
 		}
 		return nil, err
 	}
 
-	media.Type = req.Type
-	media.URL = req.URL
-	media.Caption = req.Caption
-	media.SortOrder = req.SortOrder
+	// Validate the resulting type and URL together
+	if req.Type != nil || req.URL != nil {
+		mediaType, mediaURL := media.Type, media.URL
+		if req.Type != nil {
+			mediaType = *req.Type
+		}
+		if req.URL != nil {
+			mediaURL = *req.URL
+		}
 
-	if err := database.DB.Save(&media).Error; err != nil {
+		mediaType, err := validateMedia(mediaType, mediaURL)
+		if err != nil {
+			return nil, err
+		}
+		media.Type = mediaType
+		media.URL = mediaURL
+	}
+
+	if req.Caption != nil {
+		media.Caption = *req.Caption
+	}
+
+	if req.SortOrder != nil {
+		media.SortOrder = *req.SortOrder
+	}
+
+	err := s.db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&media).Error; err != nil {
+			return err
+		}
+		return s.enqueueProjectUpdated(tx, media.ProjectID)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	recordAudit(userID, models.AuditActionUpdate, AuditResourceProjectMedia, media.ID, map[string]interface{}{"project_id": media.ProjectID, "media": req})
+
 	return &ProjectMediaResponse{
 		ID:        media.ID,
 		Type:      media.Type,
@@ -405,34 +1015,72 @@ func (s *ProjectService) UpdateProjectMedia(mediaID uint, req ProjectMediaReques
 }
 
 // DeleteProjectMedia deletes project media
-func (s *ProjectService) DeleteProjectMedia(mediaID uint) error {
+func (s *ProjectService) DeleteProjectMedia(mediaID, userID uint) error {
 	var media models.ProjectMedia
-	if err := database.DB.First(&media, mediaID).Error; err != nil {
+	if err := s.db().First(&media, mediaID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("media not found")
 		}
 		return err
 	}
 
-	return database.DB.Delete(&media).Error
+	err := s.db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&media).Error; err != nil {
+			return err
+		}
+		return s.enqueueProjectUpdated(tx, media.ProjectID)
+	})
+	if err != nil {
+		return err
+	}
+
+	recordAudit(userID, models.AuditActionDelete, AuditResourceProjectMedia, media.ID, map[string]interface{}{"project_id": media.ProjectID, "url": media.URL})
+
+	deleteStoredFile(s.storage, media.URL)
+
+	return nil
 }
 
 // Helper functions
+// projectSEOFields collects the stored meta of a project and the content it falls back to
+func projectSEOFields(project models.Project) seoFields {
+	fields := seoFields{
+		metaTitle:       project.MetaTitle,
+		metaDescription: project.MetaDescription,
+		ogImage:         project.OGImage,
+		title:           project.Title,
+		summary:         project.Description,
+	}
+
+	// The cover is the first image in sort order
+	coverOrder := 0
+	for _, media := range project.Media {
+		if media.Type == MediaTypeImage && (fields.coverImage == "" || media.SortOrder < coverOrder) {
+			fields.coverImage, coverOrder = media.URL, media.SortOrder
+		}
+	}
+
+	return fields
+}
+
 func (s *ProjectService) mapProjectToResponse(project models.Project) *ProjectResponse {
 	response := &ProjectResponse{
-		ID:          project.ID,
-		Title:       project.Title,
-		Slug:        project.Slug,
-		Description: project.Description,
-		Content:     project.Content,
-		CategoryID:  project.CategoryID,
-		Featured:    project.Featured,
-		Published:   project.Published,
-		CreatedBy:   project.CreatedBy,
-		UpdatedBy:   project.UpdatedBy,
-		CreatedAt:   project.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   project.UpdatedAt.Format(time.RFC3339),
+		ID:            project.ID,
+		Title:         project.Title,
+		Slug:          project.Slug,
+		Description:   project.Description,
+		Content:       project.Content,
+		CategoryID:    project.CategoryID,
+		Featured:      project.Featured,
+		FeaturedOrder: project.FeaturedOrder,
+		Published:     project.Published,
+		Views:         project.Views,
+		CreatedBy:     project.CreatedBy,
+		UpdatedBy:     project.UpdatedBy,
+		CreatedAt:     project.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     project.UpdatedAt.UTC().Format(time.RFC3339),
 	}
+	response.MetaTitle, response.MetaDescription, response.OGImage = projectSEOFields(project).resolve()
 
 	// Map category
 	if project.Category.ID > 0 {
@@ -443,7 +1091,8 @@ func (s *ProjectService) mapProjectToResponse(project models.Project) *ProjectRe
 		}
 	}
 
-	// Map media
+	// Map media and tags, keeping empty collections as [] rather than null
+	response.Media = make([]ProjectMediaResponse, 0, len(project.Media))
 	for _, media := range project.Media {
 		response.Media = append(response.Media, ProjectMediaResponse{
 			ID:        media.ID,
@@ -454,7 +1103,7 @@ func (s *ProjectService) mapProjectToResponse(project models.Project) *ProjectRe
 		})
 	}
 
-	// Map tags
+	response.Tags = make([]TagResponse, 0, len(project.Tags))
 	for _, tag := range project.Tags {
 		response.Tags = append(response.Tags, TagResponse{
 			ID:   tag.ID,
@@ -463,5 +1112,10 @@ func (s *ProjectService) mapProjectToResponse(project models.Project) *ProjectRe
 		})
 	}
 
+	response.Technologies = make([]TechnologyResponse, 0, len(project.Technologies))
+	for _, technology := range project.Technologies {
+		response.Technologies = append(response.Technologies, mapTechnologyToResponse(technology))
+	}
+
 	return response
 } 
\ No newline at end of file
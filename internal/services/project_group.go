@@ -0,0 +1,90 @@
+package services
+
+import (
+	"zionechainapi/internal/models"
+)
+
+// ProjectCategoryGroup represents a project category with its most recent published projects
+type ProjectCategoryGroup struct {
+	Category ProjectCategoryResponse `json:"category"`
+	Projects []ProjectResponse       `json:"projects"`
+}
+
+// ProjectWithSiblingsResponse represents a project with other published
+// projects of its category, for "more in this category" listings
+type ProjectWithSiblingsResponse struct {
+	*ProjectResponse
+	CategorySiblings []ProjectResponse `json:"category_siblings"`
+}
+
+// ListProjectsByCategory lists every project category with at most limit of its
+// most recent published projects. Categories without published projects are
+// left out when skipEmpty is set.
+//
+// The projects of all categories are read in one query: a project is kept when
+// fewer than limit published projects of its category are newer than it. This
+// avoids window functions, which MySQL 5.7 lacks.
+func (s *ProjectService) ListProjectsByCategory(limit int, skipEmpty bool) ([]ProjectCategoryGroup, error) {
+	var categories []models.ProjectCategory
+	if err := s.db().Order("id ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	var projects []models.Project
+	if err := s.db().Preload("Category").Preload("Media").Preload("Tags").Preload("Technologies").
+		Where("published = ?", true).
+		Where(`(SELECT COUNT(*) FROM projects AS newer
+			WHERE newer.category_id = projects.category_id AND newer.published = ?
+			AND (newer.created_at > projects.created_at OR (newer.created_at = projects.created_at AND newer.id > projects.id))) < ?`,
+			true, limit).
+		Order("created_at DESC, id DESC").
+		Find(&projects).Error; err != nil {
+		return nil, err
+	}
+
+	byCategory := make(map[uint][]ProjectResponse)
+	for _, project := range projects {
+		byCategory[project.CategoryID] = append(byCategory[project.CategoryID], *s.mapProjectToResponse(project))
+	}
+
+	groups := make([]ProjectCategoryGroup, 0, len(categories))
+	for _, category := range categories {
+		items := byCategory[category.ID]
+		if len(items) == 0 {
+			if skipEmpty {
+				continue
+			}
+			items = []ProjectResponse{}
+		}
+
+		groups = append(groups, ProjectCategoryGroup{
+			Category: ProjectCategoryResponse{
+				ID:   category.ID,
+				Name: category.Name,
+				Slug: category.Slug,
+			},
+			Projects: items,
+		})
+	}
+
+	return groups, nil
+}
+
+// ListCategorySiblings lists at most limit of the most recent published projects
+// in the category of project, leaving out project itself
+func (s *ProjectService) ListCategorySiblings(project *ProjectResponse, limit int) ([]ProjectResponse, error) {
+	var projects []models.Project
+	if err := s.db().Preload("Category").Preload("Media").Preload("Tags").Preload("Technologies").
+		Where("published = ? AND category_id = ? AND id <> ?", true, project.CategoryID, project.ID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&projects).Error; err != nil {
+		return nil, err
+	}
+
+	siblings := make([]ProjectResponse, 0, len(projects))
+	for _, sibling := range projects {
+		siblings = append(siblings, *s.mapProjectToResponse(sibling))
+	}
+	return siblings, nil
+}
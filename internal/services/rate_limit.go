@@ -0,0 +1,80 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"zionechainapi/internal/database"
+	"gorm.io/gorm"
+)
+
+// rateLimitLocks serializes the rate limited writes of each client
+var rateLimitLocks = keyedMutex{locks: make(map[string]*keyedLock)}
+
+// createRateLimited creates row, a model with an ip_address column, unless
+// the client at ip already created limit rows of it since the given time, in
+// which case limited is returned. The count and the insert run in one
+// transaction while holding a lock on the client, so concurrent requests of a
+// client cannot all pass the count. A zero limit or an unknown ip only creates
+// the row.
+func createRateLimited(row interface{}, ip string, limit int, since time.Time, limited error) error {
+	if limit <= 0 || ip == "" {
+		return database.DB.Create(row).Error
+	}
+
+	stmt := &gorm.Statement{DB: database.DB}
+	if err := stmt.Parse(row); err != nil {
+		return err
+	}
+	unlock := rateLimitLocks.lock(stmt.Schema.Table + " " + ip)
+	defer unlock()
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var recent int64
+		if err := tx.Table(stmt.Schema.Table).
+			Where("ip_address = ? AND created_at > ?", ip, since).
+			Count(&recent).Error; err != nil {
+			return err
+		}
+		if recent >= int64(limit) {
+			return limited
+		}
+		return tx.Create(row).Error
+	})
+}
+
+// keyedMutex is a set of mutexes by key, each kept only while it is in use
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+// keyedLock is the mutex of a key with the number of callers holding or waiting for it
+type keyedLock struct {
+	sync.Mutex
+	users int
+}
+
+// lock locks the mutex of key and returns the function unlocking it
+func (m *keyedMutex) lock(key string) func() {
+	m.mu.Lock()
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyedLock{}
+		m.locks[key] = l
+	}
+	l.users++
+	m.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+
+		m.mu.Lock()
+		l.users--
+		if l.users == 0 {
+			delete(m.locks, key)
+		}
+		m.mu.Unlock()
+	}
+}
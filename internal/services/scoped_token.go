@@ -0,0 +1,79 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ScopePreviewRead lets a token read unpublished content and nothing else
+const ScopePreviewRead = "preview:read"
+
+// MaxScopedTokenExpiry is the longest lifetime a scoped token can be minted with
+const MaxScopedTokenExpiry = 30 * 24 * time.Hour
+
+var (
+	// ErrUnknownScope is returned when minting a token with a scope the API does not know
+	ErrUnknownScope = errors.New("unknown token scope")
+	// ErrScopedTokenExpiry is returned when a scoped token lifetime is out of range
+	ErrScopedTokenExpiry = fmt.Errorf("expires_in must be between 60 and %d seconds", int(MaxScopedTokenExpiry.Seconds()))
+)
+
+// knownScopes are the scopes tokens can be minted with
+var knownScopes = map[string]bool{
+	ScopePreviewRead: true,
+}
+
+// ScopedTokenRequest represents a request to mint a scoped token
+type ScopedTokenRequest struct {
+	Scope     string `json:"scope" binding:"required"`
+	ExpiresIn int    `json:"expires_in" binding:"required"` // lifetime in seconds
+}
+
+// ScopedTokenResponse represents a minted scoped token
+type ScopedTokenResponse struct {
+	Token     string    `json:"token"`
+	Scope     string    `json:"scope"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// IssueScopedToken mints an access token limited to the given scope for
+// frontends that must not get full rights. The token carries no role, so
+// routes requiring one reject it, and it cannot be refreshed.
+func (s *AuthService) IssueScopedToken(userID uint, req ScopedTokenRequest) (*ScopedTokenResponse, error) {
+	if !knownScopes[req.Scope] {
+		return nil, ErrUnknownScope
+	}
+
+	expiry := time.Duration(req.ExpiresIn) * time.Second
+	if expiry < time.Minute || expiry > MaxScopedTokenExpiry {
+		return nil, ErrScopedTokenExpiry
+	}
+
+	now := s.clock.Now()
+	expiresAt := now.Add(expiry)
+	claims := &Claims{
+		UserID: userID,
+		Scope:  req.Scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Subject:   fmt.Sprintf("%d", userID),
+			Issuer:    s.config.JWT.Issuer,
+			Audience:  s.audience(),
+		},
+	}
+
+	token, err := s.signToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScopedTokenResponse{
+		Token:     token,
+		Scope:     req.Scope,
+		ExpiresAt: expiresAt.UTC(),
+	}, nil
+}
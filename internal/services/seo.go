@@ -0,0 +1,43 @@
+package services
+
+import (
+	"strings"
+
+	"zionechainapi/internal/utils"
+)
+
+// SEOMetaResponse represents the search and social sharing metadata of a page.
+// Empty meta fields are filled from the title, summary and cover image.
+type SEOMetaResponse struct {
+	Slug            string `json:"slug"`
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+	OGImage         string `json:"og_image"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+// seoFields holds the stored meta of an item along with the content it falls back to
+type seoFields struct {
+	metaTitle       string
+	metaDescription string
+	ogImage         string
+	title           string
+	summary         string
+	coverImage      string
+}
+
+// resolve fills empty meta fields from the item's content
+func (f seoFields) resolve() (title, description, image string) {
+	title = fallbackString(f.metaTitle, f.title)
+	description = fallbackString(f.metaDescription, utils.GenerateExcerpt(f.summary, utils.DefaultExcerptLength))
+	image = fallbackString(f.ogImage, f.coverImage)
+	return title, description, image
+}
+
+// fallbackString returns value, or fallback when value is blank
+func fallbackString(value, fallback string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strings"
+	"time"
+
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+)
+
+// SitemapNamespace is the XML namespace of the sitemap protocol
+const SitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// SitemapService builds the sitemap of published content
+type SitemapService struct {
+	baseURL string
+}
+
+// NewSitemapService creates a new sitemap service that builds locations under baseURL
+func NewSitemapService(baseURL string) *SitemapService {
+	return &SitemapService{
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// SitemapURLSet represents the urlset root element of a sitemap
+type SitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+// SitemapURL represents a single page in a sitemap
+type SitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// sitemapEntry is the slug and last modification time of a published item
+type sitemapEntry struct {
+	Slug      string
+	UpdatedAt time.Time
+}
+
+// sitemapCategoryRow pairs a category with one of its published items
+type sitemapCategoryRow struct {
+	Slug          string
+	UpdatedAt     time.Time
+	ItemUpdatedAt time.Time
+}
+
+// BuildSitemap lists published projects and blog posts, and the landing pages
+// of categories that have published content
+func (s *SitemapService) BuildSitemap() (*SitemapURLSet, error) {
+	urlSet := &SitemapURLSet{Xmlns: SitemapNamespace, URLs: []SitemapURL{}}
+
+	sections := []struct {
+		path          string
+		itemTable     string
+		categoryTable string
+	}{
+		{"projects", models.Project{}.TableName(), models.ProjectCategory{}.TableName()},
+		{"blog", models.BlogPost{}.TableName(), models.BlogCategory{}.TableName()},
+	}
+
+	for _, section := range sections {
+		var items []sitemapEntry
+		if err := database.DB.Table(section.itemTable).
+			Select("slug, updated_at").
+			Where("published = ?", true).
+			Order("updated_at DESC").
+			Scan(&items).Error; err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			urlSet.URLs = append(urlSet.URLs, s.sitemapURL(section.path+"/"+url.PathEscape(item.Slug), item.UpdatedAt))
+		}
+
+		// A category landing page changes whenever the category or one of its
+		// published items does. The latest time is picked here since not every
+		// database has GREATEST.
+		var rows []sitemapCategoryRow
+		if err := database.DB.Table(section.categoryTable+" AS c").
+			Select("c.slug, c.updated_at, i.updated_at AS item_updated_at").
+			Joins("JOIN "+section.itemTable+" AS i ON i.category_id = c.id AND i.published = ?", true).
+			Order("c.slug ASC").
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+
+		var categories []sitemapEntry
+		for _, row := range rows {
+			updatedAt := row.UpdatedAt
+			if row.ItemUpdatedAt.After(updatedAt) {
+				updatedAt = row.ItemUpdatedAt
+			}
+
+			last := len(categories) - 1
+			if last < 0 || categories[last].Slug != row.Slug {
+				categories = append(categories, sitemapEntry{Slug: row.Slug, UpdatedAt: updatedAt})
+			} else if updatedAt.After(categories[last].UpdatedAt) {
+				categories[last].UpdatedAt = updatedAt
+			}
+		}
+
+		for _, category := range categories {
+			urlSet.URLs = append(urlSet.URLs, s.sitemapURL(section.path+"/category/"+url.PathEscape(category.Slug), category.UpdatedAt))
+		}
+	}
+
+	return urlSet, nil
+}
+
+// sitemapURL builds the absolute location of a page
+func (s *SitemapService) sitemapURL(path string, updatedAt time.Time) SitemapURL {
+	return SitemapURL{
+		Loc:     s.baseURL + "/" + path,
+		LastMod: updatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSlugTitleInvalid is returned when a title does not produce a usable slug
+	ErrSlugTitleInvalid = errors.New("title does not produce a valid slug")
+	// ErrSlugResourceNotFound is returned when regenerating the slug of a project or blog post that does not exist
+	ErrSlugResourceNotFound = errors.New("resource not found")
+)
+
+// SlugAvailabilityResponse represents the slug generated from a title and whether it is free
+type SlugAvailabilityResponse struct {
+	Slug      string `json:"slug"`
+	Available bool   `json:"available"`
+}
+
+// checkSlugAvailability generates the slug for a title and checks it against the model's table
+func checkSlugAvailability(db *gorm.DB, model interface{}, title string) (*SlugAvailabilityResponse, error) {
+	slug := utils.SanitizeSlug(title)
+	if slug == "" {
+		return nil, ErrSlugTitleInvalid
+	}
+
+	var count int64
+	if err := db.Model(model).Where("slug = ?", slug).Count(&count).Error; err != nil {
+		return nil, err
+	}
+
+	return &SlugAvailabilityResponse{
+		Slug:      slug,
+		Available: count == 0,
+	}, nil
+}
+
+// retrySlug appends a random suffix to a slug that was taken by a concurrent
+// create between the availability check and the insert
+func retrySlug(slug string) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return slug + "-" + hex.EncodeToString(suffix), nil
+}
+
+// slugSavePoint names the savepoint a slug write is retried from
+const slugSavePoint = "slug_write"
+
+// writeSlug runs write to store slug inside tx. When a concurrent write
+// took the slug it runs write again with a random suffix added to base. The
+// first attempt runs under a savepoint that is rolled back to before the retry,
+// since PostgreSQL refuses any further statement in a transaction after a
+// failed one.
+func writeSlug(tx *gorm.DB, slug, base string, write func(slug string) error) error {
+	if err := tx.SavePoint(slugSavePoint).Error; err != nil {
+		return err
+	}
+
+	err := write(slug)
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		return err
+	}
+	if err := tx.RollbackTo(slugSavePoint).Error; err != nil {
+		return err
+	}
+
+	if slug, err = retrySlug(base); err != nil {
+		return err
+	}
+	return write(slug)
+}
+
+// SlugRegenerationResponse represents a slug recomputed from the current title.
+// Links to PreviousSlug keep resolving through the slug history.
+type SlugRegenerationResponse struct {
+	ID           uint   `json:"id"`
+	Slug         string `json:"slug"`
+	PreviousSlug string `json:"previous_slug"`
+	Changed      bool   `json:"changed"`
+}
+
+// sluggedRow is the title and slug of a project or blog post
+type sluggedRow struct {
+	ID    uint
+	Title string
+	Slug  string
+}
+
+// regenerateSlug recomputes the slug of a project or blog post from its title.
+// A slug taken by another record gets a timestamp suffix, as on create and
+// update, unless the current slug already is such a suffixed form of it.
+// A changed slug is written in one transaction with updated, which announces
+// the record's new URL.
+func regenerateSlug(db *gorm.DB, model interface{}, resourceType string, id uint, now time.Time, updated func(tx *gorm.DB, id uint) error) (*SlugRegenerationResponse, error) {
+	var row sluggedRow
+	result := db.Model(model).Select("id, title, slug").Where("id = ?", id).Limit(1).Scan(&row)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrSlugResourceNotFound
+	}
+
+	base := utils.SanitizeSlug(row.Title)
+	if base == "" {
+		return nil, ErrSlugTitleInvalid
+	}
+
+	response := &SlugRegenerationResponse{ID: row.ID, Slug: row.Slug, PreviousSlug: row.Slug}
+	if row.Slug == base {
+		return response, nil
+	}
+
+	var count int64
+	if err := db.Model(model).Where("slug = ? AND id != ?", base, id).Count(&count).Error; err != nil {
+		return nil, err
+	}
+
+	slug := base
+	if count > 0 {
+		if strings.HasPrefix(row.Slug, base+"-") {
+			return response, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, now.Unix())
+	}
+
+	tx := db.Begin()
+	err := writeSlug(tx, slug, base, func(candidate string) error {
+		slug = candidate
+		return tx.Model(model).Where("id = ?", id).Update("slug", candidate).Error
+	})
+	if err == nil {
+		// Keep links to the old slug working
+		err = recordSlugChange(tx, resourceType, row.Slug, id)
+	}
+	if err == nil {
+		err = updated(tx, id)
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	response.Slug = slug
+	response.Changed = true
+	return response, nil
+}
+
+// RegenerateProjectSlug recomputes the slug of a project from its current title
+func (s *ProjectService) RegenerateProjectSlug(id, userID uint) (*SlugRegenerationResponse, error) {
+	response, err := regenerateSlug(s.db(), &models.Project{}, models.SlugResourceProject, id, s.clock.Now(), s.enqueueProjectUpdated)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Changed {
+		recordAudit(userID, models.AuditActionUpdate, AuditResourceProject, id, map[string]interface{}{"slug": response.Slug, "previous_slug": response.PreviousSlug})
+	}
+
+	return response, nil
+}
+
+// RegenerateBlogSlug recomputes the slug of a blog post from its current title
+func (s *BlogService) RegenerateBlogSlug(id, userID uint) (*SlugRegenerationResponse, error) {
+	response, err := regenerateSlug(s.db(), &models.BlogPost{}, models.SlugResourceBlogPost, id, s.clock.Now(), s.enqueueBlogUpdated)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Changed {
+		recordAudit(userID, models.AuditActionUpdate, AuditResourceBlogPost, id, map[string]interface{}{"slug": response.Slug, "previous_slug": response.PreviousSlug})
+	}
+
+	return response, nil
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"zionechainapi/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SlugRedirectResponse points a request made with a previous slug to the current one
+type SlugRedirectResponse struct {
+	Slug      string `json:"slug"`
+	Canonical string `json:"canonical"`
+}
+
+// recordSlugChange remembers that a project or blog post used to have oldSlug.
+// A slug given up by one record and later by another points to the latest one.
+func recordSlugChange(tx *gorm.DB, resourceType, oldSlug string, resourceID uint) error {
+	if oldSlug == "" {
+		return nil
+	}
+
+	entry := models.SlugHistory{
+		ResourceType: resourceType,
+		OldSlug:      oldSlug,
+		ResourceID:   resourceID,
+	}
+	return tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"resource_id", "updated_at"}),
+	}).Create(&entry).Error
+}
+
+// previousSlugOwner returns the ID of the record that used to have slug. It
+// returns gorm.ErrRecordNotFound when no record had it.
+func previousSlugOwner(db *gorm.DB, resourceType, slug string) (uint, error) {
+	var entry models.SlugHistory
+	if err := db.Where("resource_type = ? AND old_slug = ?", resourceType, slug).First(&entry).Error; err != nil {
+		return 0, err
+	}
+	return entry.ResourceID, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"gorm.io/gorm"
+)
+
+// StatsService handles aggregate portfolio statistics
+type StatsService struct {
+	ctx context.Context
+}
+
+// NewStatsService creates a new stats service
+func NewStatsService() *StatsService {
+	return &StatsService{}
+}
+
+// WithContext returns a copy of the service whose queries are bound to ctx,
+// usually the request context, so they are cancelled along with it. It leaves
+// s unchanged, as s is shared between requests.
+func (s *StatsService) WithContext(ctx context.Context) *StatsService {
+	bound := *s
+	bound.ctx = ctx
+	return &bound
+}
+
+// db returns the database handle for the queries of the service
+func (s *StatsService) db() *gorm.DB {
+	return database.FromContext(s.ctx)
+}
+
+// StatusCounts represents the number of published and draft items
+type StatusCounts struct {
+	Published int64 `json:"published"`
+	Draft     int64 `json:"draft"`
+}
+
+// StatsResponse represents the aggregate portfolio statistics
+type StatsResponse struct {
+	Projects   StatusCounts `json:"projects"`
+	BlogPosts  StatusCounts `json:"blog_posts"`
+	Categories int64        `json:"categories"` // project and blog categories
+	Tags       int64        `json:"tags"`
+	Media      int64        `json:"media"` // project and blog media items
+}
+
+// GetStats counts content by publication status along with categories, tags and media
+func (s *StatsService) GetStats() (*StatsResponse, error) {
+	var stats StatsResponse
+
+	if err := countByStatus(s.db(), &models.Project{}, &stats.Projects); err != nil {
+		return nil, err
+	}
+
+	if err := countByStatus(s.db(), &models.BlogPost{}, &stats.BlogPosts); err != nil {
+		return nil, err
+	}
+
+	// Fetch the remaining totals in a single round trip
+	var totals struct {
+		Categories int64
+		Tags       int64
+		Media      int64
+	}
+	if err := s.db().Raw(`SELECT
+		(SELECT COUNT(*) FROM project_categories) + (SELECT COUNT(*) FROM blog_categories) AS categories,
+		(SELECT COUNT(*) FROM tags) AS tags,
+		(SELECT COUNT(*) FROM project_media) + (SELECT COUNT(*) FROM blog_media) AS media`).
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	stats.Categories = totals.Categories
+	stats.Tags = totals.Tags
+	stats.Media = totals.Media
+
+	return &stats, nil
+}
+
+// countByStatus counts the rows of a model grouped by their published flag
+func countByStatus(db *gorm.DB, model interface{}, counts *StatusCounts) error {
+	var rows []struct {
+		Published bool
+		Count     int64
+	}
+	if err := db.Model(model).
+		Select("published, COUNT(*) AS count").
+		Group("published").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if row.Published {
+			counts.Published = row.Count
+		} else {
+			counts.Draft = row.Count
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"strings"
 
@@ -10,26 +11,88 @@ import (
 )
 
 // TagService handles tag-related operations
-type TagService struct{}
+type TagService struct {
+	ctx context.Context
+}
 
 // NewTagService creates a new tag service
 func NewTagService() *TagService {
 	return &TagService{}
 }
 
+// WithContext returns a copy of the service whose queries are bound to ctx,
+// usually the request context, so they are cancelled along with it. It leaves
+// s unchanged, as s is shared between requests.
+func (s *TagService) WithContext(ctx context.Context) *TagService {
+	bound := *s
+	bound.ctx = ctx
+	return &bound
+}
+
+// db returns the database handle for the queries of the service
+func (s *TagService) db() *gorm.DB {
+	return database.FromContext(s.ctx)
+}
+
 // TagRequest represents the tag request
 type TagRequest struct {
 	Name string `json:"name" binding:"required"`
 }
 
+// tagSlug creates a tag slug from a tag name
+func tagSlug(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "-"))
+}
+
+// MergeTagRequest represents the tag merge request
+type MergeTagRequest struct {
+	TargetID uint `json:"target_id" binding:"required"`
+}
+
+// MergeTagResponse represents the result of a tag merge
+type MergeTagResponse struct {
+	SourceTagID uint  `json:"source_tag_id"`
+	TargetTagID uint  `json:"target_tag_id"`
+	Projects    int64 `json:"projects"`   // projects moved to the target tag
+	BlogPosts   int64 `json:"blog_posts"` // blog posts moved to the target tag
+}
+
+// AssignTagRequest represents the items to add a tag to
+type AssignTagRequest struct {
+	ProjectIDs []uint `json:"project_ids"`
+	BlogIDs    []uint `json:"blog_ids"`
+}
+
+// AssignTagResponse represents the result of a bulk tag assignment
+type AssignTagResponse struct {
+	TagID             uint   `json:"tag_id"`
+	Projects          int64  `json:"projects"`            // projects newly tagged
+	BlogPosts         int64  `json:"blog_posts"`          // blog posts newly tagged
+	InvalidProjectIDs []uint `json:"invalid_project_ids"` // requested projects that do not exist
+	InvalidBlogIDs    []uint `json:"invalid_blog_ids"`    // requested blog posts that do not exist
+}
+
+// UnassignTagRequest represents the items to remove a tag from
+type UnassignTagRequest struct {
+	ProjectIDs []uint `json:"project_ids"`
+	BlogIDs    []uint `json:"blog_ids"`
+}
+
+// UnassignTagResponse represents the result of a bulk tag removal
+type UnassignTagResponse struct {
+	TagID     uint  `json:"tag_id"`
+	Projects  int64 `json:"projects"`   // projects the tag was removed from
+	BlogPosts int64 `json:"blog_posts"` // blog posts the tag was removed from
+}
+
 // CreateTag creates a new tag
-func (s *TagService) CreateTag(req TagRequest) (*TagResponse, error) {
+func (s *TagService) CreateTag(req TagRequest, userID uint) (*TagResponse, error) {
 	// Create slug from name
-	slug := strings.ToLower(strings.ReplaceAll(req.Name, " ", "-"))
+	slug := tagSlug(req.Name)
 
 	// Check if slug already exists
 	var count int64
-	if err := database.DB.Model(&models.Tag{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+	if err := s.db().Model(&models.Tag{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
 		return nil, err
 	}
 
@@ -43,10 +106,12 @@ func (s *TagService) CreateTag(req TagRequest) (*TagResponse, error) {
 		Slug: slug,
 	}
 
-	if err := database.DB.Create(&tag).Error; err != nil {
+	if err := s.db().Create(&tag).Error; err != nil {
 		return nil, err
 	}
 
+	recordAudit(userID, models.AuditActionCreate, AuditResourceTag, tag.ID, req)
+
 	return &TagResponse{
 		ID:   tag.ID,
 		Name: tag.Name,
@@ -55,9 +120,9 @@ func (s *TagService) CreateTag(req TagRequest) (*TagResponse, error) {
 }
 
 // UpdateTag updates a tag
-func (s *TagService) UpdateTag(id uint, req TagRequest) (*TagResponse, error) {
+func (s *TagService) UpdateTag(id uint, req TagRequest, userID uint) (*TagResponse, error) {
 	var tag models.Tag
-	if err := database.DB.First(&tag, id).Error; err != nil {
+	if err := s.db().First(&tag, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("tag not found")
 		}
@@ -65,11 +130,11 @@ func (s *TagService) UpdateTag(id uint, req TagRequest) (*TagResponse, error) {
 	}
 
 	// Create slug from name
-	slug := strings.ToLower(strings.ReplaceAll(req.Name, " ", "-"))
+	slug := tagSlug(req.Name)
 
 	// Check if slug already exists and is not this tag
 	var count int64
-	if err := database.DB.Model(&models.Tag{}).Where("slug = ? AND id != ?", slug, id).Count(&count).Error; err != nil {
+	if err := s.db().Model(&models.Tag{}).Where("slug = ? AND id != ?", slug, id).Count(&count).Error; err != nil {
 		return nil, err
 	}
 
@@ -81,10 +146,12 @@ func (s *TagService) UpdateTag(id uint, req TagRequest) (*TagResponse, error) {
 	tag.Name = req.Name
 	tag.Slug = slug
 
-	if err := database.DB.Save(&tag).Error; err != nil {
+	if err := s.db().Save(&tag).Error; err != nil {
 		return nil, err
 	}
 
+	recordAudit(userID, models.AuditActionUpdate, AuditResourceTag, tag.ID, req)
+
 	return &TagResponse{
 		ID:   tag.ID,
 		Name: tag.Name,
@@ -92,10 +159,114 @@ func (s *TagService) UpdateTag(id uint, req TagRequest) (*TagResponse, error) {
 	}, nil
 }
 
+// GetOrCreate returns the tags with the given names, matched by slug, and
+// creates the ones that do not exist yet in a single transaction
+func (s *TagService) GetOrCreate(names []string) ([]TagResponse, error) {
+	tx := s.db().Begin()
+	tags, err := getOrCreateTags(tx, names)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	response := make([]TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		response = append(response, TagResponse{
+			ID:   tag.ID,
+			Name: tag.Name,
+			Slug: tag.Slug,
+		})
+	}
+
+	return response, nil
+}
+
+// getOrCreateTags resolves tag names to tags within tx, creating missing tags.
+// Blank names are skipped and names sharing a slug resolve to one tag.
+func getOrCreateTags(tx *gorm.DB, names []string) ([]models.Tag, error) {
+	var slugs []string
+	namesBySlug := make(map[string]string)
+	for _, name := range names {
+		slug := tagSlug(name)
+		if slug == "" {
+			continue
+		}
+		if _, ok := namesBySlug[slug]; ok {
+			continue
+		}
+		namesBySlug[slug] = strings.TrimSpace(name)
+		slugs = append(slugs, slug)
+	}
+
+	if len(slugs) == 0 {
+		return nil, nil
+	}
+
+	var existing []models.Tag
+	if err := tx.Where("slug IN ?", slugs).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+
+	tagsBySlug := make(map[string]models.Tag, len(existing))
+	for _, tag := range existing {
+		tagsBySlug[tag.Slug] = tag
+	}
+
+	tags := make([]models.Tag, 0, len(slugs))
+	for _, slug := range slugs {
+		tag, ok := tagsBySlug[slug]
+		if !ok {
+			tag = models.Tag{
+				Name: namesBySlug[slug],
+				Slug: slug,
+			}
+			if err := tx.Create(&tag).Error; err != nil {
+				return nil, err
+			}
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// resolveTags loads the tags with the given ids and resolves the given names
+// within tx, returning each tag once
+func resolveTags(tx *gorm.DB, ids []uint, names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(ids) > 0 {
+		if err := tx.Where("id IN ?", ids).Find(&tags).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	named, err := getOrCreateTags(tx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(tags))
+	for _, tag := range tags {
+		seen[tag.ID] = true
+	}
+	for _, tag := range named {
+		if !seen[tag.ID] {
+			seen[tag.ID] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags, nil
+}
+
 // DeleteTag deletes a tag
-func (s *TagService) DeleteTag(id uint) error {
+func (s *TagService) DeleteTag(id, userID uint) error {
 	var tag models.Tag
-	if err := database.DB.First(&tag, id).Error; err != nil {
+	if err := s.db().First(&tag, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("tag not found")
 		}
@@ -103,7 +274,7 @@ func (s *TagService) DeleteTag(id uint) error {
 	}
 
 	// Start transaction
-	tx := database.DB.Begin()
+	tx := s.db().Begin()
 
 	// Remove project associations
 	if err := tx.Model(&tag).Association("Projects").Clear(); err != nil {
@@ -124,17 +295,254 @@ func (s *TagService) DeleteTag(id uint) error {
 	}
 
 	// Commit transaction
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	recordAudit(userID, models.AuditActionDelete, AuditResourceTag, id, nil)
+
+	return nil
+}
+
+// MergeTag moves every project and blog association of a tag to the target
+// tag and deletes the source tag. Items already carrying the target tag keep
+// a single association.
+func (s *TagService) MergeTag(id uint, req MergeTagRequest, userID uint) (*MergeTagResponse, error) {
+	if id == req.TargetID {
+		return nil, errors.New("target tag must be different from the source tag")
+	}
+
+	var source, target models.Tag
+	if err := s.db().First(&source, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+
+	if err := s.db().First(&target, req.TargetID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("target tag not found")
+		}
+		return nil, err
+	}
+
+	// Start transaction
+	tx := s.db().Begin()
+
+	projects, err := mergeTagAssociations(tx, projectListTarget, source.ID, target.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	blogPosts, err := mergeTagAssociations(tx, blogListTarget, source.ID, target.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Delete the now-unused source tag
+	if err := tx.Delete(&source).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionMerge, AuditResourceTag, source.ID, req)
+	recordAudit(userID, models.AuditActionDelete, AuditResourceTag, source.ID, nil)
+
+	return &MergeTagResponse{
+		SourceTagID: source.ID,
+		TargetTagID: target.ID,
+		Projects:    projects,
+		BlogPosts:   blogPosts,
+	}, nil
+}
+
+// mergeTagAssociations re-points the target's tag join table from the source
+// tag to the target tag, dropping source rows of items already tagged with the
+// target, and returns the number of items moved
+func mergeTagAssociations(tx *gorm.DB, t listTarget, sourceID, targetID uint) (int64, error) {
+	var tagged []uint
+	if err := tx.Table(t.tagTable).Where("tag_id = ?", targetID).Pluck(t.tagForeignKey, &tagged).Error; err != nil {
+		return 0, err
+	}
+
+	if len(tagged) > 0 {
+		if err := tx.Exec("DELETE FROM "+t.tagTable+" WHERE tag_id = ? AND "+t.tagForeignKey+" IN ?", sourceID, tagged).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	result := tx.Exec("UPDATE "+t.tagTable+" SET tag_id = ? WHERE tag_id = ?", targetID, sourceID)
+	return result.RowsAffected, result.Error
+}
+
+// AssignTag adds a tag to the given projects and blog posts in one transaction.
+// Items already carrying the tag are left alone and ids of missing items are
+// reported rather than failing the assignment.
+func (s *TagService) AssignTag(id uint, req AssignTagRequest, userID uint) (*AssignTagResponse, error) {
+	if len(req.ProjectIDs) == 0 && len(req.BlogIDs) == 0 {
+		return nil, errors.New("at least one project or blog post id is required")
+	}
+
+	var tag models.Tag
+	if err := s.db().First(&tag, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+
+	response := &AssignTagResponse{TagID: tag.ID}
+
+	// Start transaction
+	tx := s.db().Begin()
+
+	var err error
+	response.Projects, response.InvalidProjectIDs, err = assignTagAssociations(tx, projectListTarget, tag.ID, req.ProjectIDs)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	response.BlogPosts, response.InvalidBlogIDs, err = assignTagAssociations(tx, blogListTarget, tag.ID, req.BlogIDs)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionAssign, AuditResourceTag, tag.ID, req)
+
+	return response, nil
+}
+
+// assignTagAssociations adds the tag to the target's items that exist and do
+// not carry it yet. It returns the number of items tagged and the ids of
+// missing items.
+func assignTagAssociations(tx *gorm.DB, t listTarget, tagID uint, ids []uint) (int64, []uint, error) {
+	invalid := []uint{}
+	if len(ids) == 0 {
+		return 0, invalid, nil
+	}
+
+	var existing []uint
+	if err := tx.Table(t.table).Where("id IN ?", ids).Pluck("id", &existing).Error; err != nil {
+		return 0, nil, err
+	}
+
+	var tagged []uint
+	if err := tx.Table(t.tagTable).Where("tag_id = ? AND "+t.tagForeignKey+" IN ?", tagID, ids).Pluck(t.tagForeignKey, &tagged).Error; err != nil {
+		return 0, nil, err
+	}
+
+	found := make(map[uint]bool, len(existing))
+	for _, itemID := range existing {
+		found[itemID] = true
+	}
+	// Ids already tagged, or requested more than once, are skipped
+	seen := make(map[uint]bool, len(tagged))
+	for _, itemID := range tagged {
+		seen[itemID] = true
+	}
+
+	var rows []map[string]interface{}
+	for _, itemID := range ids {
+		if seen[itemID] {
+			continue
+		}
+		seen[itemID] = true
+
+		if found[itemID] {
+			rows = append(rows, map[string]interface{}{t.tagForeignKey: itemID, "tag_id": tagID})
+		} else {
+			invalid = append(invalid, itemID)
+		}
+	}
+
+	if len(rows) == 0 {
+		return 0, invalid, nil
+	}
+
+	if err := tx.Table(t.tagTable).Create(&rows).Error; err != nil {
+		return 0, nil, err
+	}
+
+	return int64(len(rows)), invalid, nil
+}
+
+// UnassignTag removes a tag from the given projects and blog posts in one
+// transaction. Items that do not carry the tag, or do not exist, are ignored.
+func (s *TagService) UnassignTag(id uint, req UnassignTagRequest, userID uint) (*UnassignTagResponse, error) {
+	if len(req.ProjectIDs) == 0 && len(req.BlogIDs) == 0 {
+		return nil, errors.New("at least one project or blog post id is required")
+	}
+
+	var tag models.Tag
+	if err := s.db().First(&tag, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+
+	response := &UnassignTagResponse{TagID: tag.ID}
+
+	// Start transaction
+	tx := s.db().Begin()
+
+	var err error
+	response.Projects, err = unassignTagAssociations(tx, projectListTarget, tag.ID, req.ProjectIDs)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	response.BlogPosts, err = unassignTagAssociations(tx, blogListTarget, tag.ID, req.BlogIDs)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionUnassign, AuditResourceTag, tag.ID, req)
+
+	return response, nil
+}
+
+// unassignTagAssociations removes the tag from the target's items and returns
+// the number of items that carried it
+func unassignTagAssociations(tx *gorm.DB, t listTarget, tagID uint, ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result := tx.Exec("DELETE FROM "+t.tagTable+" WHERE tag_id = ? AND "+t.tagForeignKey+" IN ?", tagID, ids)
+	return result.RowsAffected, result.Error
 }
 
 // ListTags lists all tags
 func (s *TagService) ListTags() ([]TagResponse, error) {
 	var tags []models.Tag
-	if err := database.DB.Find(&tags).Error; err != nil {
+	if err := s.db().Find(&tags).Error; err != nil {
 		return nil, err
 	}
 
-	var response []TagResponse
+	response := make([]TagResponse, 0, len(tags))
 	for _, tag := range tags {
 		response = append(response, TagResponse{
 			ID:   tag.ID,
@@ -149,7 +557,7 @@ func (s *TagService) ListTags() ([]TagResponse, error) {
 // GetTagByID gets a tag by ID
 func (s *TagService) GetTagByID(id uint) (*TagResponse, error) {
 	var tag models.Tag
-	if err := database.DB.First(&tag, id).Error; err != nil {
+	if err := s.db().First(&tag, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("tag not found")
 		}
@@ -166,7 +574,7 @@ func (s *TagService) GetTagByID(id uint) (*TagResponse, error) {
 // GetTagBySlug gets a tag by slug
 func (s *TagService) GetTagBySlug(slug string) (*TagResponse, error) {
 	var tag models.Tag
-	if err := database.DB.Where("slug = ?", slug).First(&tag).Error; err != nil {
+	if err := s.db().Where("slug = ?", slug).First(&tag).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("tag not found")
 		}
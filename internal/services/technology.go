@@ -0,0 +1,300 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTechnologyNotFound is returned when a technology does not exist
+	ErrTechnologyNotFound = errors.New("technology not found")
+	// ErrTechnologyExists is returned when another technology already has the same slug
+	ErrTechnologyExists = errors.New("technology with this name already exists")
+	// ErrInvalidTechnologyName is returned when a technology name has no letters or digits
+	ErrInvalidTechnologyName = errors.New("technology name must contain letters or digits")
+)
+
+// technologySymbols spells out the symbols that tell technologies such as C, C++ and C# apart
+var technologySymbols = strings.NewReplacer("+", "-plus", "#", "-sharp", ".", "-")
+
+// TechnologyService handles the technologies used by portfolio projects
+type TechnologyService struct{}
+
+// NewTechnologyService creates a new technology service
+func NewTechnologyService() *TechnologyService {
+	return &TechnologyService{}
+}
+
+// TechnologyRequest represents the technology request
+type TechnologyRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Icon     string `json:"icon" binding:"max=255"`    // icon URL or icon set name
+	Category string `json:"category" binding:"max=50"` // language, framework, database, tool, etc.
+}
+
+// TechnologyResponse represents the technology response
+type TechnologyResponse struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	Icon     string `json:"icon"`
+	Category string `json:"category"`
+}
+
+// technologySlug creates a technology slug from a technology name
+func technologySlug(name string) string {
+	return utils.SanitizeSlug(technologySymbols.Replace(strings.ToLower(strings.TrimSpace(name))))
+}
+
+// ListTechnologies lists technologies ordered by name, optionally only those in a category
+func (s *TechnologyService) ListTechnologies(category string) ([]TechnologyResponse, error) {
+	query := database.DB.Order("name ASC")
+	if category != "" {
+		query = query.Where("category = ?", strings.ToLower(category))
+	}
+
+	var technologies []models.Technology
+	if err := query.Find(&technologies).Error; err != nil {
+		return nil, err
+	}
+
+	response := make([]TechnologyResponse, 0, len(technologies))
+	for _, technology := range technologies {
+		response = append(response, mapTechnologyToResponse(technology))
+	}
+
+	return response, nil
+}
+
+// GetTechnologyByID gets a technology by ID
+func (s *TechnologyService) GetTechnologyByID(id uint) (*TechnologyResponse, error) {
+	var technology models.Technology
+	if err := database.DB.First(&technology, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTechnologyNotFound
+		}
+		return nil, err
+	}
+
+	response := mapTechnologyToResponse(technology)
+	return &response, nil
+}
+
+// CreateTechnology creates a new technology
+func (s *TechnologyService) CreateTechnology(req TechnologyRequest, userID uint) (*TechnologyResponse, error) {
+	technology := models.Technology{}
+	if err := s.applyRequest(&technology, req); err != nil {
+		return nil, err
+	}
+
+	if err := database.DB.Create(&technology).Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionCreate, AuditResourceTechnology, technology.ID, req)
+
+	response := mapTechnologyToResponse(technology)
+	return &response, nil
+}
+
+// UpdateTechnology updates a technology
+func (s *TechnologyService) UpdateTechnology(id uint, req TechnologyRequest, userID uint) (*TechnologyResponse, error) {
+	var technology models.Technology
+	if err := database.DB.First(&technology, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTechnologyNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.applyRequest(&technology, req); err != nil {
+		return nil, err
+	}
+
+	if err := database.DB.Save(&technology).Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionUpdate, AuditResourceTechnology, technology.ID, req)
+
+	response := mapTechnologyToResponse(technology)
+	return &response, nil
+}
+
+// applyRequest copies a request onto a technology, checking that its slug is free
+func (s *TechnologyService) applyRequest(technology *models.Technology, req TechnologyRequest) error {
+	slug := technologySlug(req.Name)
+	if slug == "" {
+		return ErrInvalidTechnologyName
+	}
+
+	var count int64
+	if err := database.DB.Model(&models.Technology{}).Where("slug = ? AND id != ?", slug, technology.ID).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return ErrTechnologyExists
+	}
+
+	technology.Name = strings.TrimSpace(req.Name)
+	technology.Slug = slug
+	technology.Icon = strings.TrimSpace(req.Icon)
+	technology.Category = strings.ToLower(strings.TrimSpace(req.Category))
+
+	return nil
+}
+
+// DeleteTechnology deletes a technology and removes it from projects
+func (s *TechnologyService) DeleteTechnology(id, userID uint) error {
+	var technology models.Technology
+	if err := database.DB.First(&technology, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTechnologyNotFound
+		}
+		return err
+	}
+
+	// Start transaction
+	tx := database.DB.Begin()
+
+	// Remove project associations
+	if err := tx.Model(&technology).Association("Projects").Clear(); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Delete technology
+	if err := tx.Delete(&technology).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	recordAudit(userID, models.AuditActionDelete, AuditResourceTechnology, id, nil)
+
+	return nil
+}
+
+// MigrateResumeTechnologies moves the comma separated technologies of resume
+// projects, which share the projects table, into the technology catalog and
+// links each project to its technologies. It is safe to run repeatedly and
+// returns the number of projects that were linked.
+func (s *TechnologyService) MigrateResumeTechnologies() (int, error) {
+	if !database.DB.Migrator().HasColumn(models.Project{}.TableName(), "technologies") {
+		return 0, nil
+	}
+
+	var rows []struct {
+		ID           uint
+		Technologies string
+	}
+	if err := database.DB.Table(models.Project{}.TableName()).
+		Select("id, technologies").
+		Where("technologies IS NOT NULL AND technologies != ''").
+		Scan(&rows).Error; err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			technologies, err := getOrCreateTechnologies(tx, strings.Split(row.Technologies, ","))
+			if err != nil {
+				return err
+			}
+			if len(technologies) == 0 {
+				continue
+			}
+
+			// Append skips links that already exist
+			if err := tx.Model(&models.Project{ID: row.ID}).Association("Technologies").Append(technologies); err != nil {
+				return err
+			}
+			linked++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return linked, nil
+}
+
+// getOrCreateTechnologies resolves technology names to technologies within tx,
+// creating missing ones. Blank names are skipped and names sharing a slug
+// resolve to one technology.
+func getOrCreateTechnologies(tx *gorm.DB, names []string) ([]models.Technology, error) {
+	var slugs []string
+	namesBySlug := make(map[string]string)
+	for _, name := range names {
+		slug := technologySlug(name)
+		if slug == "" {
+			continue
+		}
+		if _, ok := namesBySlug[slug]; !ok {
+			slugs = append(slugs, slug)
+			namesBySlug[slug] = strings.TrimSpace(name)
+		}
+	}
+
+	if len(slugs) == 0 {
+		return nil, nil
+	}
+
+	var existing []models.Technology
+	if err := tx.Where("slug IN ?", slugs).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+
+	bySlug := make(map[string]models.Technology, len(existing))
+	for _, technology := range existing {
+		bySlug[technology.Slug] = technology
+	}
+
+	technologies := make([]models.Technology, 0, len(slugs))
+	for _, slug := range slugs {
+		technology, ok := bySlug[slug]
+		if !ok {
+			technology = models.Technology{Name: namesBySlug[slug], Slug: slug}
+			if err := tx.Create(&technology).Error; err != nil {
+				return nil, err
+			}
+		}
+		technologies = append(technologies, technology)
+	}
+
+	return technologies, nil
+}
+
+// replaceTechnologies sets the technologies of a project within tx
+func replaceTechnologies(tx *gorm.DB, project *models.Project, ids []uint) error {
+	var technologies []models.Technology
+	if len(ids) > 0 {
+		if err := tx.Where("id IN ?", ids).Find(&technologies).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(project).Association("Technologies").Replace(technologies)
+}
+
+func mapTechnologyToResponse(technology models.Technology) TechnologyResponse {
+	return TechnologyResponse{
+		ID:       technology.ID,
+		Name:     technology.Name,
+		Slug:     technology.Slug,
+		Icon:     technology.Icon,
+		Category: technology.Category,
+	}
+}
@@ -0,0 +1,30 @@
+package services
+
+// ToggleRequest represents the new value of a single flag, such as featured or published
+type ToggleRequest struct {
+	Value *bool `json:"value" binding:"required"`
+}
+
+// SetProjectFeatured features or unfeatures a project as a regular update that
+// leaves its other fields alone
+func (s *ProjectService) SetProjectFeatured(id uint, featured bool, userID uint) (*ProjectResponse, error) {
+	return s.UpdateProject(id, UpdateProjectRequest{Featured: &featured}, userID)
+}
+
+// SetProjectPublished publishes or unpublishes a project as a regular update
+// that leaves its other fields alone
+func (s *ProjectService) SetProjectPublished(id uint, published bool, userID uint) (*ProjectResponse, error) {
+	return s.UpdateProject(id, UpdateProjectRequest{Published: &published}, userID)
+}
+
+// SetBlogFeatured features or unfeatures a blog post as a regular update that
+// leaves its other fields alone
+func (s *BlogService) SetBlogFeatured(id uint, featured bool, userID uint) (*BlogResponse, error) {
+	return s.UpdateBlog(id, UpdateBlogRequest{Featured: &featured}, userID)
+}
+
+// SetBlogPublished publishes or unpublishes a blog post as a regular update
+// that leaves its other fields alone
+func (s *BlogService) SetBlogPublished(id uint, published bool, userID uint) (*BlogResponse, error) {
+	return s.UpdateBlog(id, UpdateBlogRequest{Published: &published}, userID)
+}
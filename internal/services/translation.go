@@ -0,0 +1,321 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidLocale is returned when a locale is not a language tag such as "fa" or "pt-BR"
+	ErrInvalidLocale = errors.New("locale must be a language code such as fa or pt-br")
+	// ErrTranslationNotFound is returned when a resource has no translation in a locale
+	ErrTranslationNotFound = errors.New("translation not found")
+	// ErrTranslatedResourceNotFound is returned when the project or blog post to translate does not exist
+	ErrTranslatedResourceNotFound = errors.New("resource not found")
+)
+
+// localePattern matches a language code with an optional region or script subtag
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// TranslationService handles localized project and blog post content
+type TranslationService struct {
+	defaultLocale string
+	sanitizeHTML  bool
+}
+
+// NewTranslationService creates a new translation service
+func NewTranslationService() *TranslationService {
+	return &TranslationService{
+		defaultLocale: "en",
+		sanitizeHTML:  true,
+	}
+}
+
+// WithDefaultLocale sets the locale of the base content, which is served without translations
+func (s *TranslationService) WithDefaultLocale(locale string) *TranslationService {
+	if normalized, err := normalizeLocale(locale); err == nil {
+		s.defaultLocale = normalized
+	}
+	return s
+}
+
+// WithContentSanitization enables or disables stripping unsafe HTML from translated content
+func (s *TranslationService) WithContentSanitization(enabled bool) *TranslationService {
+	s.sanitizeHTML = enabled
+	return s
+}
+
+// TranslationRequest represents the localized fields of a resource. Empty fields
+// fall back to the base content. Description translates a project description
+// or a blog post excerpt.
+type TranslationRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Content     string `json:"content"`
+}
+
+// TranslationResponse represents the translation response
+type TranslationResponse struct {
+	ID           uint   `json:"id"`
+	ResourceType string `json:"resource_type"`
+	ResourceID   uint   `json:"resource_id"`
+	Locale       string `json:"locale"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Content      string `json:"content"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+// normalizeLocale lowercases a locale and checks that it is a language tag
+func normalizeLocale(locale string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(strings.ReplaceAll(locale, "_", "-")))
+	if !localePattern.MatchString(normalized) {
+		return "", ErrInvalidLocale
+	}
+	return normalized, nil
+}
+
+// checkTranslatedResource checks that the project or blog post being translated exists
+func checkTranslatedResource(resourceType string, resourceID uint) error {
+	var model interface{}
+	switch resourceType {
+	case models.TranslationResourceProject:
+		model = &models.Project{}
+	case models.TranslationResourceBlogPost:
+		model = &models.BlogPost{}
+	default:
+		return ErrTranslatedResourceNotFound
+	}
+
+	var count int64
+	if err := database.DB.Model(model).Where("id = ?", resourceID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrTranslatedResourceNotFound
+	}
+	return nil
+}
+
+// ListTranslations lists the translations of a resource ordered by locale
+func (s *TranslationService) ListTranslations(resourceType string, resourceID uint) ([]TranslationResponse, error) {
+	if err := checkTranslatedResource(resourceType, resourceID); err != nil {
+		return nil, err
+	}
+
+	var translations []models.Translation
+	if err := database.DB.Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+		Order("locale ASC").
+		Find(&translations).Error; err != nil {
+		return nil, err
+	}
+
+	response := make([]TranslationResponse, 0, len(translations))
+	for _, translation := range translations {
+		response = append(response, s.mapTranslationToResponse(translation))
+	}
+
+	return response, nil
+}
+
+// SaveTranslation creates or replaces the translation of a resource in a locale
+func (s *TranslationService) SaveTranslation(resourceType string, resourceID uint, locale string, req TranslationRequest, userID uint) (*TranslationResponse, error) {
+	locale, err := normalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTranslatedResource(resourceType, resourceID); err != nil {
+		return nil, err
+	}
+
+	var translation models.Translation
+	err = database.DB.Where("resource_type = ? AND resource_id = ? AND locale = ?", resourceType, resourceID, locale).
+		First(&translation).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	action := models.AuditActionUpdate
+	if translation.ID == 0 {
+		action = models.AuditActionCreate
+		translation.ResourceType = resourceType
+		translation.ResourceID = resourceID
+		translation.Locale = locale
+	}
+
+	translation.Title = req.Title
+	translation.Description = req.Description
+	translation.Content = req.Content
+	if s.sanitizeHTML {
+		translation.Content = utils.SanitizeHTML(translation.Content)
+	}
+
+	if err := database.DB.Save(&translation).Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, action, AuditResourceTranslation, translation.ID, req)
+
+	response := s.mapTranslationToResponse(translation)
+	return &response, nil
+}
+
+// DeleteTranslation deletes the translation of a resource in a locale
+func (s *TranslationService) DeleteTranslation(resourceType string, resourceID uint, locale string, userID uint) error {
+	locale, err := normalizeLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	var translation models.Translation
+	if err := database.DB.Where("resource_type = ? AND resource_id = ? AND locale = ?", resourceType, resourceID, locale).
+		First(&translation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTranslationNotFound
+		}
+		return err
+	}
+
+	if err := database.DB.Delete(&translation).Error; err != nil {
+		return err
+	}
+
+	recordAudit(userID, models.AuditActionDelete, AuditResourceTranslation, translation.ID, nil)
+
+	return nil
+}
+
+// LocalizeProjects overlays the translations of a locale onto projects. Projects
+// or fields without a translation keep their base content, and the default
+// locale is served as is.
+func (s *TranslationService) LocalizeProjects(projects []ProjectResponse, locale string) error {
+	ids := make([]uint, 0, len(projects))
+	for _, project := range projects {
+		ids = append(ids, project.ID)
+	}
+
+	translations, err := s.translationsFor(models.TranslationResourceProject, ids, locale)
+	if err != nil {
+		return err
+	}
+
+	for i := range projects {
+		translation, ok := translations[projects[i].ID]
+		if !ok {
+			continue
+		}
+		overlayString(&projects[i].Title, translation.Title)
+		overlayString(&projects[i].Description, translation.Description)
+		overlayString(&projects[i].Content, translation.Content)
+	}
+
+	return nil
+}
+
+// LocalizeProject overlays the translation of a locale onto a single project
+func (s *TranslationService) LocalizeProject(project *ProjectResponse, locale string) error {
+	projects := []ProjectResponse{*project}
+	if err := s.LocalizeProjects(projects, locale); err != nil {
+		return err
+	}
+	*project = projects[0]
+	return nil
+}
+
+// LocalizeBlogs overlays the translations of a locale onto blog posts. Posts or
+// fields without a translation keep their base content, and the default locale
+// is served as is.
+func (s *TranslationService) LocalizeBlogs(blogs []BlogResponse, locale string) error {
+	ids := make([]uint, 0, len(blogs))
+	for _, blog := range blogs {
+		ids = append(ids, blog.ID)
+	}
+
+	translations, err := s.translationsFor(models.TranslationResourceBlogPost, ids, locale)
+	if err != nil {
+		return err
+	}
+
+	for i := range blogs {
+		translation, ok := translations[blogs[i].ID]
+		if !ok {
+			continue
+		}
+		overlayString(&blogs[i].Title, translation.Title)
+		overlayString(&blogs[i].Excerpt, translation.Description)
+		overlayString(&blogs[i].Content, translation.Content)
+	}
+
+	return nil
+}
+
+// LocalizeBlog overlays the translation of a locale onto a single blog post
+func (s *TranslationService) LocalizeBlog(blog *BlogResponse, locale string) error {
+	blogs := []BlogResponse{*blog}
+	if err := s.LocalizeBlogs(blogs, locale); err != nil {
+		return err
+	}
+	*blog = blogs[0]
+	return nil
+}
+
+// translationsFor loads the translations of resources in a locale keyed by resource id.
+// It returns nothing for the default locale.
+func (s *TranslationService) translationsFor(resourceType string, ids []uint, locale string) (map[uint]models.Translation, error) {
+	locale, err := normalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]models.Translation)
+	if locale == s.defaultLocale || len(ids) == 0 {
+		return result, nil
+	}
+
+	var translations []models.Translation
+	if err := database.DB.Where("resource_type = ? AND resource_id IN ? AND locale = ?", resourceType, ids, locale).
+		Find(&translations).Error; err != nil {
+		return nil, err
+	}
+
+	for _, translation := range translations {
+		result[translation.ResourceID] = translation
+	}
+
+	return result, nil
+}
+
+// overlayString replaces a base value with its translation unless the translation is empty
+func overlayString(base *string, translated string) {
+	if translated != "" {
+		*base = translated
+	}
+}
+
+// deleteTranslations removes all translations of a resource
+func deleteTranslations(tx *gorm.DB, resourceType string, resourceID uint) error {
+	return tx.Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).Delete(&models.Translation{}).Error
+}
+
+func (s *TranslationService) mapTranslationToResponse(translation models.Translation) TranslationResponse {
+	return TranslationResponse{
+		ID:           translation.ID,
+		ResourceType: translation.ResourceType,
+		ResourceID:   translation.ResourceID,
+		Locale:       translation.Locale,
+		Title:        translation.Title,
+		Description:  translation.Description,
+		Content:      translation.Content,
+		CreatedAt:    translation.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:    translation.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"image"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"zionechainapi/configs"
+	"zionechainapi/internal/clock"
+	"zionechainapi/internal/imaging"
+	"zionechainapi/internal/storage"
+)
+
+var (
+	// ErrUploadTooLarge is returned when an upload exceeds the configured maximum size
+	ErrUploadTooLarge = errors.New("file is too large")
+	// ErrUploadTypeNotAllowed is returned when an upload's extension or content is not whitelisted
+	ErrUploadTypeNotAllowed = errors.New("file type is not allowed")
+)
+
+// IsUploadValidationError checks if an error was caused by a rejected upload
+func IsUploadValidationError(err error) bool {
+	return errors.Is(err, ErrUploadTooLarge) || errors.Is(err, ErrUploadTypeNotAllowed) ||
+		errors.Is(err, imaging.ErrInvalidImage) || errors.Is(err, imaging.ErrImageTooLarge)
+}
+
+// UploadService validates uploaded files and saves them to the storage backend
+type UploadService struct {
+	storage             storage.Storage
+	maxSize             int64
+	allowedExtensions   map[string]bool
+	allowedContentTypes map[string]bool
+	webpDerivatives     bool
+	clock               clock.Clock
+}
+
+// NewUploadService creates a new upload service saving files to store
+func NewUploadService(store storage.Storage, config configs.StorageConfig) *UploadService {
+	s := &UploadService{
+		storage:             store,
+		maxSize:             config.MaxSize,
+		allowedExtensions:   make(map[string]bool),
+		allowedContentTypes: make(map[string]bool),
+		webpDerivatives:     config.WebPDerivatives,
+		clock:               clock.Real{},
+	}
+
+	for _, extension := range config.AllowedExtensions {
+		extension = strings.ToLower(strings.TrimSpace(extension))
+		if extension != "" && !strings.HasPrefix(extension, ".") {
+			extension = "." + extension
+		}
+		s.allowedExtensions[extension] = true
+	}
+	for _, contentType := range config.AllowedContentTypes {
+		s.allowedContentTypes[strings.ToLower(strings.TrimSpace(contentType))] = true
+	}
+
+	return s
+}
+
+// WithClock replaces the clock used to date upload keys
+func (s *UploadService) WithClock(c clock.Clock) *UploadService {
+	s.clock = c
+	return s
+}
+
+// MaxSize returns the largest file size accepted, or 0 when there is no limit
+func (s *UploadService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// UploadResponse represents a stored upload. WebPKey and WebPURL are set when
+// a WebP derivative of an image was saved alongside it.
+type UploadResponse struct {
+	Key         string `json:"key"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	WebPKey     string `json:"webp_key,omitempty"`
+	WebPURL     string `json:"webp_url,omitempty"`
+}
+
+// Upload checks a file against the size, extension and content type limits and
+// saves it under a new random key. The content type is detected from the file
+// content rather than trusted from the client, and images must decode as that
+// type. When enabled, a WebP derivative of PNG, JPEG and GIF images is saved
+// next to the original.
+func (s *UploadService) Upload(header *multipart.FileHeader) (*UploadResponse, error) {
+	if s.maxSize > 0 && header.Size > s.maxSize {
+		return nil, ErrUploadTooLarge
+	}
+
+	extension := strings.ToLower(filepath.Ext(header.Filename))
+	if !s.allowedExtensions[extension] {
+		return nil, ErrUploadTypeNotAllowed
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Sniff the content type from the first 512 bytes, then save them with the rest
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	// The standard sniffer does not know AVIF
+	if contentType == "application/octet-stream" && imaging.IsAVIF(head) {
+		contentType = "image/avif"
+	}
+	// Files named as images must really be images
+	if imaging.IsImage(mime.TypeByExtension(extension)) && !imaging.IsImage(contentType) {
+		return nil, imaging.ErrInvalidImage
+	}
+	if !s.allowedContentTypes[contentType] {
+		return nil, ErrUploadTypeNotAllowed
+	}
+
+	key, err := s.newKey(extension)
+	if err != nil {
+		return nil, err
+	}
+
+	if !imaging.IsImage(contentType) {
+		if err := s.storage.Save(key, io.MultiReader(bytes.NewReader(head), file), contentType); err != nil {
+			return nil, err
+		}
+		return &UploadResponse{
+			Key:         key,
+			URL:         s.storage.URL(key),
+			ContentType: contentType,
+			Size:        header.Size,
+		}, nil
+	}
+
+	// Images are decoded in full, so read them into memory
+	rest, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	data := append(head, rest...)
+
+	img, err := imaging.Validate(data, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Save(key, bytes.NewReader(data), contentType); err != nil {
+		return nil, err
+	}
+
+	upload := &UploadResponse{
+		Key:         key,
+		URL:         s.storage.URL(key),
+		ContentType: contentType,
+		Size:        header.Size,
+	}
+
+	if s.webpDerivatives && imaging.CanConvert(contentType) {
+		if webpKey, err := s.saveWebPDerivative(key, img); err != nil {
+			log.Printf("Failed to save WebP derivative of %s: %v", key, err)
+		} else {
+			upload.WebPKey = webpKey
+			upload.WebPURL = s.storage.URL(webpKey)
+		}
+	}
+
+	return upload, nil
+}
+
+// saveWebPDerivative saves img as WebP under the key of the original with a
+// .webp extension. A failed derivative does not fail the upload.
+func (s *UploadService) saveWebPDerivative(key string, img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := imaging.EncodeWebP(&buf, img); err != nil {
+		return "", err
+	}
+
+	webpKey := webpDerivativeKey(key)
+	if err := s.storage.Save(webpKey, &buf, "image/webp"); err != nil {
+		return "", err
+	}
+	return webpKey, nil
+}
+
+// webpDerivativeKey returns the key of the WebP derivative of the file under key
+func webpDerivativeKey(key string) string {
+	return strings.TrimSuffix(key, filepath.Ext(key)) + ".webp"
+}
+
+// newKey returns a random key below the current year and month
+func (s *UploadService) newKey(extension string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return s.clock.Now().UTC().Format("2006/01") + "/" + hex.EncodeToString(random) + extension, nil
+}
+
+// Delete removes a file saved by Upload given its URL, along with its WebP
+// derivative, unless media, images or content still use it. URLs of files
+// stored anywhere else are ignored.
+func (s *UploadService) Delete(url string) {
+	deleteStoredFile(s.storage, url)
+}
+
+// deleteStoredFile removes the file behind a deleted media URL when it was
+// uploaded to store and nothing still references it, as duplicated projects
+// and posts share their media files and an upload may also serve as an SEO
+// image, an avatar or be embedded in content. Failures are logged since the
+// media row is already gone.
+func deleteStoredFile(store storage.Storage, url string) {
+	if store == nil {
+		return
+	}
+
+	key, ok := storage.KeyFromURL(store, url)
+	if !ok {
+		return
+	}
+
+	referenced, err := storedFileReferenced(url)
+	if err != nil {
+		log.Printf("Failed to check uses of stored file %s: %v", key, err)
+		return
+	}
+	if referenced {
+		return
+	}
+
+	if err := store.Delete(key); err != nil {
+		log.Printf("Failed to delete stored file %s: %v", key, err)
+	}
+
+	// Uploaded images may have a WebP derivative saved next to them
+	if imaging.CanConvert(mime.TypeByExtension(strings.ToLower(filepath.Ext(key)))) {
+		deleteStoredFile(store, store.URL(webpDerivativeKey(key)))
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"zionechainapi/internal/database"
+	"gorm.io/gorm"
+)
+
+// ViewCounter counts views of the rows of a table in memory and adds them to
+// the views column in batches, so that a popular page does not cost a write
+// per read. A nil counter counts nothing.
+type ViewCounter struct {
+	table   string
+	mu      sync.Mutex
+	pending map[uint]uint
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewViewCounter creates a view counter for the rows of table
+func NewViewCounter(table string) *ViewCounter {
+	return &ViewCounter{
+		table:   table,
+		pending: make(map[uint]uint),
+	}
+}
+
+// Start flushes the counted views every interval in the background until Stop
+// is called. A non-positive interval leaves flushing to Flush and Stop.
+func (c *ViewCounter) Start(interval time.Duration) *ViewCounter {
+	if c == nil || interval <= 0 || c.stop != nil {
+		return c
+	}
+
+	c.stop = make(chan struct{})
+	c.stopped = make(chan struct{})
+	go func() {
+		defer close(c.stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.Flush(); err != nil {
+					log.Printf("Failed to flush %s views: %v", c.table, err)
+				}
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// Stop ends background flushing and flushes the views counted since the last flush
+func (c *ViewCounter) Stop() error {
+	if c == nil {
+		return nil
+	}
+	if c.stop != nil {
+		close(c.stop)
+		<-c.stopped
+		c.stop = nil
+	}
+	return c.Flush()
+}
+
+// Record counts a view of the row with id
+func (c *ViewCounter) Record(id uint) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.pending[id]++
+	c.mu.Unlock()
+}
+
+// Pending returns the views of the row with id counted since the last flush
+func (c *ViewCounter) Pending(id uint) uint {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pending[id]
+}
+
+// Flush adds the counted views to the table in a single UPDATE. Views that
+// fail to be written are kept for the next flush.
+func (c *ViewCounter) Flush() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	batch := c.pending
+	c.pending = make(map[uint]uint)
+	c.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(batch))
+	for id := range batch {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var increments strings.Builder
+	args := make([]interface{}, 0, 2*len(ids))
+	for _, id := range ids {
+		increments.WriteString(" WHEN ? THEN ?")
+		args = append(args, id, batch[id])
+	}
+
+	// UpdateColumn leaves updated_at alone since views do not edit the rows
+	err := database.DB.Table(c.table).
+		Where("id IN ?", ids).
+		UpdateColumn("views", gorm.Expr(fmt.Sprintf("views + CASE id%s END", increments.String()), args...)).Error
+	if err != nil {
+		c.mu.Lock()
+		for id, views := range batch {
+			c.pending[id] += views
+		}
+		c.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,390 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"zionechainapi/configs"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"gorm.io/gorm"
+)
+
+// Webhook events fired by the project and blog services
+const (
+	WebhookEventProjectCreated   = "project.created"
+	WebhookEventProjectUpdated   = "project.updated"
+	WebhookEventProjectPublished = "project.published"
+	WebhookEventProjectDeleted   = "project.deleted"
+	WebhookEventBlogCreated      = "blog.created"
+	WebhookEventBlogUpdated      = "blog.updated"
+	WebhookEventBlogPublished    = "blog.published"
+	WebhookEventBlogDeleted      = "blog.deleted"
+)
+
+// webhookEvents are the events a subscription can listen to
+var webhookEvents = map[string]bool{
+	WebhookEventProjectCreated:   true,
+	WebhookEventProjectUpdated:   true,
+	WebhookEventProjectPublished: true,
+	WebhookEventProjectDeleted:   true,
+	WebhookEventBlogCreated:      true,
+	WebhookEventBlogUpdated:      true,
+	WebhookEventBlogPublished:    true,
+	WebhookEventBlogDeleted:      true,
+}
+
+// Webhook delivery headers
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// ErrUnknownWebhookEvent is returned when a subscription lists an event that is never fired
+var ErrUnknownWebhookEvent = errors.New("unknown webhook event")
+
+// ErrWebhookNotFound is returned when a webhook subscription does not exist
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// WebhookService handles webhook subscriptions
+type WebhookService struct{}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService() *WebhookService {
+	return &WebhookService{}
+}
+
+// CreateWebhookRequest represents the create webhook request
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Secret string   `json:"secret" binding:"required,min=16"`
+	Events []string `json:"events" binding:"required,min=1"`
+	Active *bool    `json:"active"` // defaults to true
+}
+
+// UpdateWebhookRequest represents the update webhook request; nil fields are left unchanged
+type UpdateWebhookRequest struct {
+	URL    *string  `json:"url" binding:"omitempty,url"`
+	Secret *string  `json:"secret" binding:"omitempty,min=16"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+// WebhookResponse represents the webhook response; the secret is never returned
+type WebhookResponse struct {
+	ID        uint     `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Active    bool     `json:"active"`
+	CreatedBy uint     `json:"created_by"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// WebhookPayload represents the JSON body posted to subscribers
+type WebhookPayload struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	Timestamp string      `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// normalizeWebhookEvents validates event names and joins them for storage
+func normalizeWebhookEvents(events []string) (string, error) {
+	seen := make(map[string]bool, len(events))
+	normalized := make([]string, 0, len(events))
+	for _, event := range events {
+		event = strings.ToLower(strings.TrimSpace(event))
+		if !webhookEvents[event] {
+			return "", fmt.Errorf("%w: %s", ErrUnknownWebhookEvent, event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			normalized = append(normalized, event)
+		}
+	}
+	return strings.Join(normalized, ","), nil
+}
+
+// splitWebhookEvents splits stored event names
+func splitWebhookEvents(events string) []string {
+	if events == "" {
+		return []string{}
+	}
+	return strings.Split(events, ",")
+}
+
+// ListWebhooks lists all webhook subscriptions
+func (s *WebhookService) ListWebhooks() ([]WebhookResponse, error) {
+	var webhooks []models.Webhook
+	if err := database.DB.Order("id ASC").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+
+	response := make([]WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		response = append(response, s.mapWebhookToResponse(webhook))
+	}
+
+	return response, nil
+}
+
+// GetWebhookByID gets a webhook subscription by ID
+func (s *WebhookService) GetWebhookByID(id uint) (*WebhookResponse, error) {
+	var webhook models.Webhook
+	if err := database.DB.First(&webhook, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	response := s.mapWebhookToResponse(webhook)
+	return &response, nil
+}
+
+// CreateWebhook creates a new webhook subscription
+func (s *WebhookService) CreateWebhook(req CreateWebhookRequest, userID uint) (*WebhookResponse, error) {
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := models.Webhook{
+		URL:       req.URL,
+		Secret:    req.Secret,
+		Events:    events,
+		Active:    true,
+		CreatedBy: userID,
+	}
+
+	if err := database.DB.Create(&webhook).Error; err != nil {
+		return nil, err
+	}
+
+	// Active has a database default of true, so false must be written explicitly
+	if req.Active != nil && !*req.Active {
+		if err := database.DB.Model(&webhook).Update("active", false).Error; err != nil {
+			return nil, err
+		}
+		webhook.Active = false
+	}
+
+	recordAudit(userID, models.AuditActionCreate, AuditResourceWebhook, webhook.ID, s.mapWebhookToResponse(webhook))
+
+	response := s.mapWebhookToResponse(webhook)
+	return &response, nil
+}
+
+// UpdateWebhook updates a webhook subscription
+func (s *WebhookService) UpdateWebhook(id uint, req UpdateWebhookRequest, userID uint) (*WebhookResponse, error) {
+	var webhook models.Webhook
+	if err := database.DB.First(&webhook, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	if req.URL != nil {
+		webhook.URL = *req.URL
+	}
+
+	if req.Secret != nil {
+		webhook.Secret = *req.Secret
+	}
+
+	if req.Events != nil {
+		events, err := normalizeWebhookEvents(req.Events)
+		if err != nil {
+			return nil, err
+		}
+		if events == "" {
+			return nil, errors.New("events cannot be empty")
+		}
+		webhook.Events = events
+	}
+
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	if err := database.DB.Save(&webhook).Error; err != nil {
+		return nil, err
+	}
+
+	recordAudit(userID, models.AuditActionUpdate, AuditResourceWebhook, id, s.mapWebhookToResponse(webhook))
+
+	response := s.mapWebhookToResponse(webhook)
+	return &response, nil
+}
+
+// DeleteWebhook deletes a webhook subscription
+func (s *WebhookService) DeleteWebhook(id, userID uint) error {
+	result := database.DB.Delete(&models.Webhook{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+
+	recordAudit(userID, models.AuditActionDelete, AuditResourceWebhook, id, nil)
+
+	return nil
+}
+
+func (s *WebhookService) mapWebhookToResponse(webhook models.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    splitWebhookEvents(webhook.Events),
+		Active:    webhook.Active,
+		CreatedBy: webhook.CreatedBy,
+		CreatedAt: webhook.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: webhook.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// SignWebhookPayload returns the signature header value of a payload: the hex
+// encoded HMAC-SHA256 of the body keyed by the subscription secret
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookDispatcher records content events in the outbox and delivers them to
+// the subscribed webhooks
+type WebhookDispatcher struct {
+	client       *http.Client
+	maxAttempts  int
+	retryBackoff time.Duration
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher
+func NewWebhookDispatcher(config configs.WebhookConfig) *WebhookDispatcher {
+	maxAttempts := config.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &WebhookDispatcher{
+		client:       &http.Client{Timeout: config.Timeout},
+		maxAttempts:  maxAttempts,
+		retryBackoff: config.RetryBackoff,
+	}
+}
+
+// Enqueue records an event in the outbox as part of tx, so it is delivered by
+// the OutboxWorker exactly when tx commits. A nil dispatcher records nothing.
+func (d *WebhookDispatcher) Enqueue(tx *gorm.DB, event string, data interface{}) error {
+	if d == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event, err)
+	}
+
+	return tx.Create(&models.OutboxEvent{
+		EventID:       newDeliveryID(),
+		Event:         event,
+		Payload:       string(payload),
+		Status:        models.OutboxStatusPending,
+		NextAttemptAt: time.Now(),
+	}).Error
+}
+
+// deliver posts an outbox event to every active subscription listening to it.
+// It fails if any delivery fails, in which case the event is tried again later,
+// also at the subscriptions that already received it.
+func (d *WebhookDispatcher) deliver(webhooks []models.Webhook, outboxEvent models.OutboxEvent) error {
+	body, err := json.Marshal(WebhookPayload{
+		ID:        outboxEvent.EventID,
+		Event:     outboxEvent.Event,
+		Timestamp: outboxEvent.CreatedAt.UTC().Format(time.RFC3339),
+		Data:      json.RawMessage(outboxEvent.Payload),
+	})
+	if err != nil {
+		return err
+	}
+
+	var failed error
+	for _, webhook := range webhooks {
+		if !containsString(splitWebhookEvents(webhook.Events), outboxEvent.Event) {
+			continue
+		}
+
+		if err := d.post(webhook, outboxEvent.Event, outboxEvent.EventID, body); err != nil {
+			log.Printf("Webhook %d delivery %s of %s failed on attempt %d: %v", webhook.ID, outboxEvent.EventID, outboxEvent.Event, outboxEvent.Attempts+1, err)
+			if failed == nil {
+				failed = fmt.Errorf("webhook %d: %w", webhook.ID, err)
+			}
+		}
+	}
+	return failed
+}
+
+// retryDelay returns how long to wait before the next attempt after attempts
+// failed ones: the backoff, doubled for each further failure
+func (d *WebhookDispatcher) retryDelay(attempts int) time.Duration {
+	delay := d.retryBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+	}
+	return delay
+}
+
+// post sends a single signed delivery attempt
+func (d *WebhookDispatcher) post(webhook models.Webhook, event, deliveryID string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event)
+	req.Header.Set(WebhookDeliveryHeader, deliveryID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// newDeliveryID creates a random identifier for a delivery, shared by its retries
+func newDeliveryID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"zionechainapi/configs"
+	"zionechainapi/internal/clock"
+)
+
+// s3Timeout bounds a single request to the object store
+const s3Timeout = 30 * time.Second
+
+// S3Storage stores files in a bucket of an S3-compatible object store.
+// Requests are signed with AWS Signature Version 4.
+type S3Storage struct {
+	config  configs.S3Config
+	baseURL string
+	client  *http.Client
+	clock   clock.Clock
+}
+
+// NewS3Storage creates a storage backed by the configured bucket. Files are
+// served from baseURL, or from the bucket itself when baseURL is empty.
+func NewS3Storage(config configs.S3Config, baseURL string) *S3Storage {
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	if baseURL == "" {
+		baseURL = config.Endpoint + "/" + config.Bucket
+	}
+
+	return &S3Storage{
+		config:  config,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: s3Timeout},
+		clock:   clock.Real{},
+	}
+}
+
+// WithHTTPClient replaces the client used to reach the object store
+func (s *S3Storage) WithHTTPClient(client *http.Client) *S3Storage {
+	s.client = client
+	return s
+}
+
+// WithClock replaces the clock used to date request signatures
+func (s *S3Storage) WithClock(c clock.Clock) *S3Storage {
+	s.clock = c
+	return s
+}
+
+// Save uploads the content of r as the object of key
+func (s *S3Storage) Save(key string, r io.Reader, contentType string) error {
+	// The object store needs the content length and signed payload hash up front
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(key), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	_, err = s.do(req, body)
+	return err
+}
+
+// Delete removes the object of key
+func (s *S3Storage) Delete(key string) error {
+	req, err := http.NewRequest(http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.do(req, nil)
+	return err
+}
+
+// listBucketResult is the response of a ListObjectsV2 request
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List returns the objects of the bucket, following continuation tokens
+// until the listing is complete
+func (s *S3Storage) List() ([]File, error) {
+	files := []File{}
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		req, err := http.NewRequest(http.MethodGet, s.config.Endpoint+"/"+s.config.Bucket+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := s.do(req, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result listBucketResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse object listing: %w", err)
+		}
+		for _, object := range result.Contents {
+			files = append(files, File{Key: object.Key, Size: object.Size, ModTime: object.LastModified})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return files, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// URL returns the public URL of the object of key
+func (s *S3Storage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// objectURL returns the path-style API URL of the object of key
+func (s *S3Storage) objectURL(key string) string {
+	return s.config.Endpoint + "/" + s.config.Bucket + "/" + strings.TrimPrefix(key, "/")
+}
+
+// do signs and sends a request and returns the response body, treating any
+// non-2xx response as an error
+func (s *S3Storage) do(req *http.Request, payload []byte) ([]byte, error) {
+	s.sign(req, payload)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("object store returned %d for %s %s: %s", resp.StatusCode, req.Method, req.URL.Path, strings.TrimSpace(string(message)))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to req
+func (s *S3Storage) sign(req *http.Request, payload []byte) {
+	now := s.clock.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	// Canonical headers are the lowercase header names in order with their trimmed values
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.config.SecretKey), date)
+	signingKey = hmacSHA256(signingKey, s.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
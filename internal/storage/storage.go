@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"zionechainapi/configs"
+)
+
+// File describes a stored file
+type File struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Storage stores uploaded files under keys such as "2024/05/3f2a9c.png"
+type Storage interface {
+	// Save writes the content of r under key, replacing any file already stored there
+	Save(key string, r io.Reader, contentType string) error
+	// Delete removes the file stored under key. Deleting a missing file is not an error.
+	Delete(key string) error
+	// URL returns the public URL of the file stored under key
+	URL(key string) string
+	// List returns every stored file
+	List() ([]File, error)
+}
+
+// New creates the storage backend selected by the configured driver
+func New(config *configs.Config) Storage {
+	switch strings.ToLower(config.Storage.Driver) {
+	case "s3":
+		return NewS3Storage(config.Storage.S3, config.Storage.BaseURL)
+	case "", "local":
+		return NewLocalStorage(config.Storage.LocalPath, localBaseURL(config))
+	default:
+		log.Printf("Warning: unknown storage driver %q, using local storage", config.Storage.Driver)
+		return NewLocalStorage(config.Storage.LocalPath, localBaseURL(config))
+	}
+}
+
+// IsLocal reports whether uploads are stored on local disk and so must be served by the API
+func IsLocal(config *configs.Config) bool {
+	return strings.ToLower(config.Storage.Driver) != "s3"
+}
+
+// LocalRoute is the path the API serves locally stored uploads from
+const LocalRoute = "/uploads"
+
+// localBaseURL returns the configured base URL, defaulting to the API's own upload route
+func localBaseURL(config *configs.Config) string {
+	if config.Storage.BaseURL != "" {
+		return config.Storage.BaseURL
+	}
+	return strings.TrimSuffix(config.App.URL, "/") + LocalRoute
+}
+
+// KeyFromURL returns the key of a file when rawURL points into the storage,
+// and false for URLs of files stored anywhere else
+func KeyFromURL(s Storage, rawURL string) (string, bool) {
+	prefix := s.URL("")
+	if !strings.HasPrefix(rawURL, prefix) {
+		return "", false
+	}
+
+	key := strings.TrimPrefix(rawURL, prefix)
+	if key == "" {
+		return "", false
+	}
+	return key, true
+}
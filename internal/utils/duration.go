@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatSpan describes the whole calendar months between start and end in
+// years and months, such as "2 yrs 3 mos"
+func FormatSpan(start, end time.Time) string {
+	months := (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month())
+	if end.Day() < start.Day() {
+		months--
+	}
+	if months < 1 {
+		return "less than a month"
+	}
+
+	years, months := months/12, months%12
+	switch {
+	case years == 0:
+		return pluralize(months, "mo", "mos")
+	case months == 0:
+		return pluralize(years, "yr", "yrs")
+	default:
+		return pluralize(years, "yr", "yrs") + " " + pluralize(months, "mo", "mos")
+	}
+}
+
+// pluralize formats a count with the singular or plural unit
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
@@ -0,0 +1,58 @@
+package utils
+
+import "net/http"
+
+// Machine-readable error codes set on error responses, so clients can branch
+// on the kind of failure instead of parsing messages
+const (
+	CodeBadRequest           = "BAD_REQUEST"
+	CodeValidationFailed     = "VALIDATION_FAILED"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodeForbidden            = "FORBIDDEN"
+	CodeResourceNotFound     = "RESOURCE_NOT_FOUND"
+	CodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+	CodeConflict             = "CONFLICT"
+	CodePayloadTooLarge      = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	CodeRateLimited          = "RATE_LIMITED"
+	CodeRequestTimeout       = "REQUEST_TIMEOUT"
+	CodeInternalError        = "INTERNAL_ERROR"
+	CodeNotImplemented       = "NOT_IMPLEMENTED"
+
+	CodeAuthInvalidCredentials       = "AUTH_INVALID_CREDENTIALS"
+	CodeAuthAccountLocked            = "AUTH_ACCOUNT_LOCKED"
+	CodeAuthTokenExpired             = "AUTH_TOKEN_EXPIRED"
+	CodeAuthTokenInvalid             = "AUTH_TOKEN_INVALID"
+	CodeAuthInsufficientRole         = "AUTH_INSUFFICIENT_ROLE"
+	CodeAuthUserExists               = "AUTH_USER_EXISTS"
+	CodeAuthVerificationTokenInvalid = "AUTH_VERIFICATION_TOKEN_INVALID"
+	CodeAuthVerificationTokenExpired = "AUTH_VERIFICATION_TOKEN_EXPIRED"
+)
+
+// statusCodes are the default error codes of HTTP statuses
+var statusCodes = map[int]string{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeResourceNotFound,
+	http.StatusMethodNotAllowed:      CodeMethodNotAllowed,
+	http.StatusConflict:              CodeConflict,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusUnsupportedMediaType:  CodeUnsupportedMediaType,
+	http.StatusUnprocessableEntity:   CodeValidationFailed,
+	http.StatusLocked:                CodeAuthAccountLocked,
+	http.StatusTooManyRequests:       CodeRateLimited,
+	http.StatusInternalServerError:   CodeInternalError,
+	http.StatusNotImplemented:        CodeNotImplemented,
+}
+
+// CodeForStatus returns the default error code of an HTTP status
+func CodeForStatus(statusCode int) string {
+	if code, ok := statusCodes[statusCode]; ok {
+		return code
+	}
+	if statusCode >= http.StatusInternalServerError {
+		return CodeInternalError
+	}
+	return CodeBadRequest
+}
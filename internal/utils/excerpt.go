@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// DefaultExcerptLength is the maximum number of characters of a generated excerpt
+const DefaultExcerptLength = 160
+
+// htmlTagRegExp matches HTML tags
+var htmlTagRegExp = regexp.MustCompile(`<[^>]*>`)
+
+// GenerateExcerpt creates a plain-text excerpt of at most maxLength characters
+// from HTML content, cutting at a word boundary and appending an ellipsis when
+// the text is truncated
+func GenerateExcerpt(content string, maxLength int) string {
+	// Replace tags with spaces so words in adjacent elements stay apart
+	text := html.UnescapeString(htmlTagRegExp.ReplaceAllString(content, " "))
+	text = strings.Join(strings.Fields(text), " ")
+
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+
+	// Leave room for the ellipsis and cut back to the last space
+	cut := string(runes[:maxLength-3])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+
+	return strings.TrimRight(cut, " .,;:") + "..."
+}
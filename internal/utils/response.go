@@ -10,6 +10,7 @@ import (
 type Response struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message,omitempty"`
+	Code    string      `json:"code,omitempty"` // machine-readable error code, see error_codes.go
 	Data    interface{} `json:"data,omitempty"`
 	Error   interface{} `json:"error,omitempty"`
 }
@@ -23,11 +24,17 @@ func SuccessResponse(c *gin.Context, statusCode int, message string, data interf
 	})
 }
 
-// ErrorResponse returns an error response
+// ErrorResponse returns an error response with the default error code of the status
 func ErrorResponse(c *gin.Context, statusCode int, message string, err interface{}) {
+	CodedErrorResponse(c, statusCode, CodeForStatus(statusCode), message, err)
+}
+
+// CodedErrorResponse returns an error response with a specific error code
+func CodedErrorResponse(c *gin.Context, statusCode int, code, message string, err interface{}) {
 	c.JSON(statusCode, Response{
 		Success: false,
 		Message: message,
+		Code:    code,
 		Error:   err,
 	})
 }
@@ -52,6 +59,11 @@ func ForbiddenResponse(c *gin.Context, message string) {
 	ErrorResponse(c, http.StatusForbidden, message, nil)
 }
 
+// ConflictResponse returns a conflict response
+func ConflictResponse(c *gin.Context, message string, err interface{}) {
+	ErrorResponse(c, http.StatusConflict, message, err)
+}
+
 // InternalServerErrorResponse returns a internal server error response
 func InternalServerErrorResponse(c *gin.Context, err interface{}) {
 	ErrorResponse(c, http.StatusInternalServerError, "Internal server error", err)
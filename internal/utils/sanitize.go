@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// allowedHTML maps the tags kept by SanitizeHTML to their allowed attributes
+var allowedHTML = map[string][]string{
+	"a":          {"href", "title"},
+	"b":          nil,
+	"blockquote": nil,
+	"br":         nil,
+	"code":       nil,
+	"div":        nil,
+	"em":         nil,
+	"figcaption": nil,
+	"figure":     nil,
+	"h1":         nil,
+	"h2":         nil,
+	"h3":         nil,
+	"h4":         nil,
+	"h5":         nil,
+	"h6":         nil,
+	"hr":         nil,
+	"i":          nil,
+	"img":        {"src", "alt", "title", "width", "height"},
+	"li":         nil,
+	"ol":         nil,
+	"p":          nil,
+	"pre":        nil,
+	"s":          nil,
+	"span":       nil,
+	"strong":     nil,
+	"table":      nil,
+	"tbody":      nil,
+	"td":         nil,
+	"th":         nil,
+	"thead":      nil,
+	"tr":         nil,
+	"u":          nil,
+	"ul":         nil,
+}
+
+// droppedHTML are tags removed together with everything inside them
+var droppedHTML = map[string]bool{
+	"iframe":   true,
+	"noscript": true,
+	"object":   true,
+	"script":   true,
+	"style":    true,
+	"template": true,
+}
+
+// urlAttributes are attributes whose value must be a safe URL
+var urlAttributes = map[string]bool{
+	"href": true,
+	"src":  true,
+}
+
+// allowedURLSchemes are the URL schemes kept in links and images; relative URLs are always kept
+var allowedURLSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+// SanitizeHTML removes everything but a safe subset of tags and attributes
+// from HTML content. Disallowed tags are stripped while their text is kept,
+// except for script-like tags which are dropped with their content.
+func SanitizeHTML(content string) string {
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+
+	var out strings.Builder
+	dropDepth := 0
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return out.String()
+		}
+
+		token := tokenizer.Token()
+		switch tokenType {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedHTML[token.Data] {
+				if tokenType == html.StartTagToken {
+					dropDepth++
+				}
+				continue
+			}
+			if dropDepth > 0 {
+				continue
+			}
+			if attributes, ok := allowedHTML[token.Data]; ok {
+				writeSanitizedTag(&out, token, attributes)
+			}
+		case html.EndTagToken:
+			if droppedHTML[token.Data] {
+				if dropDepth > 0 {
+					dropDepth--
+				}
+				continue
+			}
+			if dropDepth > 0 {
+				continue
+			}
+			if _, ok := allowedHTML[token.Data]; ok {
+				out.WriteString("</" + token.Data + ">")
+			}
+		case html.TextToken:
+			if dropDepth == 0 {
+				out.WriteString(html.EscapeString(token.Data))
+			}
+		}
+	}
+}
+
+// writeSanitizedTag writes a start tag keeping only the allowed attributes
+func writeSanitizedTag(out *strings.Builder, token html.Token, allowed []string) {
+	out.WriteString("<" + token.Data)
+	for _, attr := range token.Attr {
+		if attr.Namespace != "" || !containsString(allowed, attr.Key) {
+			continue
+		}
+		if urlAttributes[attr.Key] && !isSafeURL(attr.Val) {
+			continue
+		}
+		out.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+	}
+	if token.Type == html.SelfClosingTagToken {
+		out.WriteString("/")
+	}
+	out.WriteString(">")
+}
+
+// isSafeURL reports whether a URL is relative or uses an allowed scheme
+func isSafeURL(raw string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return false
+	}
+	return parsed.Scheme == "" || allowedURLSchemes[strings.ToLower(parsed.Scheme)]
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -41,6 +41,27 @@ func GenerateUniqueSlug(str string) string {
 	return fmt.Sprintf("%s-%d", GenerateSlug(str), time.Now().Unix())
 }
 
+// copySuffixRegExp matches a copy suffix left by an earlier duplication
+var copySuffixRegExp = regexp.MustCompile(`-copy(-\d+)?$`)
+
+// GenerateCopySlug generates a slug for a copy of the given slug, appending
+// "-copy" and then a counter until exists reports the slug as free
+func GenerateCopySlug(slug string, exists func(string) (bool, error)) (string, error) {
+	base := copySuffixRegExp.ReplaceAllString(slug, "") + "-copy"
+
+	candidate := base
+	for n := 2; ; n++ {
+		taken, err := exists(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
 // RemoveAccents removes accents from string
 func RemoveAccents(s string) string {
 	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
@@ -51,8 +72,16 @@ func RemoveAccents(s string) string {
 	return output
 }
 
-// SanitizeSlug sanitizes a slug by handling unicode characters and spaces
+// SanitizeSlug sanitizes a slug by handling unicode characters and spaces.
+// Persian and Arabic text is transliterated, and text in other scripts that
+// leaves nothing usable falls back to a stable hash of the text. Text without
+// any letter or digit yields an empty slug.
 func SanitizeSlug(s string) string {
+	original := s
+
+	// Transliterate Persian and Arabic letters
+	s = Transliterate(s)
+
 	// Remove accents
 	s = RemoveAccents(s)
 	
@@ -60,5 +89,9 @@ func SanitizeSlug(s string) string {
 	s = strings.ReplaceAll(s, " ", "-")
 	
 	// Generate slug
-	return GenerateSlug(s)
+	slug := GenerateSlug(s)
+	if slug == "" && hasLetterOrDigit(original) {
+		return hashSlug(original)
+	}
+	return slug
 } 
\ No newline at end of file
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+// persianArabicLatin maps Persian and Arabic letters and digits to their common
+// Latin transliteration. Short vowels are not written in either script, so they
+// are not restored.
+var persianArabicLatin = map[rune]string{
+	'ا': "a", 'آ': "a", 'أ': "a", 'إ': "e", 'ٱ': "a",
+	'ب': "b", 'پ': "p", 'ت': "t", 'ث': "s", 'ج': "j", 'چ': "ch",
+	'ح': "h", 'خ': "kh", 'د': "d", 'ذ': "z", 'ر': "r", 'ز': "z",
+	'ژ': "zh", 'س': "s", 'ش': "sh", 'ص': "s", 'ض': "z", 'ط': "t",
+	'ظ': "z", 'ع': "", 'غ': "gh", 'ف': "f", 'ق': "q", 'ک': "k",
+	'ك': "k", 'گ': "g", 'ل': "l", 'م': "m", 'ن': "n", 'و': "v",
+	'ؤ': "o", 'ه': "h", 'ة': "h", 'ی': "y", 'ي': "y", 'ى': "a",
+	'ئ': "y", 'ء': "",
+
+	'\u0640': "", // tatweel
+	'\u200c': "", // zero-width non-joiner
+
+	'۰': "0", '۱': "1", '۲': "2", '۳': "3", '۴': "4",
+	'۵': "5", '۶': "6", '۷': "7", '۸': "8", '۹': "9",
+	'٠': "0", '١': "1", '٢': "2", '٣': "3", '٤': "4",
+	'٥': "5", '٦': "6", '٧': "7", '٨': "8", '٩': "9",
+}
+
+// Transliterate replaces Persian and Arabic letters and digits with Latin
+// characters, leaving all other characters unchanged
+func Transliterate(s string) string {
+	var out strings.Builder
+	for _, r := range s {
+		if latin, ok := persianArabicLatin[r]; ok {
+			out.WriteString(latin)
+			continue
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
+
+// hashSlug creates a stable slug from a hash of the text, used for titles in
+// scripts that cannot be transliterated
+func hashSlug(s string) string {
+	sum := sha1.Sum([]byte(strings.TrimSpace(s)))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// hasLetterOrDigit reports whether s contains any letter or digit in any script
+func hasLetterOrDigit(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
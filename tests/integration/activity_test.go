@@ -0,0 +1,70 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func TestActivityFeedMergesTypesByTime(t *testing.T) {
+	loginAndGetToken(t)
+
+	// Date the seeded items ahead of everything else so they lead the feed
+	base := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+
+	projectCategory := models.ProjectCategory{Name: "Activity Projects", Slug: "activity-projects"}
+	assert.NoError(t, database.DB.Create(&projectCategory).Error)
+	blogCategory := models.BlogCategory{Name: "Activity Posts", Slug: "activity-posts"}
+	assert.NoError(t, database.DB.Create(&blogCategory).Error)
+
+	project := models.Project{Title: "Activity Project", Slug: "activity-project", Content: "Content", CategoryID: projectCategory.ID,
+		CreatedAt: base, UpdatedAt: base.Add(3 * time.Minute)}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	post := models.BlogPost{Title: "Activity Post", Slug: "activity-post", Content: "Content", CategoryID: blogCategory.ID, Published: true,
+		CreatedAt: base.Add(time.Minute), UpdatedAt: base.Add(time.Minute)}
+	assert.NoError(t, database.DB.Create(&post).Error)
+
+	comment := models.Comment{BlogID: post.ID, AuthorName: "Reader", Body: "Nice post",
+		CreatedAt: base.Add(2 * time.Minute), UpdatedAt: base.Add(2 * time.Minute)}
+	assert.NoError(t, database.DB.Create(&comment).Error)
+
+	req, err := http.NewRequest("GET", "/api/activity?limit=3", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data []services.ActivityItem `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	// Newest first across resource types
+	expected := []services.ActivityItem{
+		{Type: services.ActivityTypeProject, ID: project.ID, Title: "Activity Project", Action: services.ActivityActionUpdated},
+		{Type: services.ActivityTypeComment, ID: comment.ID, Title: "Activity Post", Action: services.ActivityActionCreated},
+		{Type: services.ActivityTypeBlogPost, ID: post.ID, Title: "Activity Post", Action: services.ActivityActionCreated},
+	}
+	if assert.Len(t, response.Data, len(expected)) {
+		for i, item := range response.Data {
+			assert.Equal(t, expected[i].Type, item.Type)
+			assert.Equal(t, expected[i].ID, item.ID)
+			assert.Equal(t, expected[i].Title, item.Title)
+			assert.Equal(t, expected[i].Action, item.Action)
+			if i > 0 {
+				assert.True(t, item.Timestamp.Before(response.Data[i-1].Timestamp))
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func TestCreateProjectRecordsAudit(t *testing.T) {
+	// Login to get an access token
+	loginAndGetToken(t)
+
+	var actor models.User
+	assert.NoError(t, database.DB.Where("phone = ?", fixtureAdminPhone).First(&actor).Error)
+
+	category := models.ProjectCategory{Name: "Audit Category", Slug: "audit-category"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	// Create a project
+	jsonData, err := json.Marshal(services.CreateProjectRequest{
+		Title:       "Audited Project",
+		Description: "Project used to check audit logging",
+		Content:     "Content",
+		CategoryID:  category.ID,
+		Published:   true,
+	})
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest("POST", "/api/projects", bytes.NewBuffer(jsonData))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	id := uint(response["data"].(map[string]interface{})["id"].(float64))
+
+	// The create should have emitted an audit row with the correct actor
+	var entry models.AuditLog
+	err = database.DB.Where("resource_type = ? AND resource_id = ? AND action = ?",
+		services.AuditResourceProject, id, models.AuditActionCreate).First(&entry).Error
+	assert.NoError(t, err)
+	assert.Equal(t, actor.ID, entry.ActorID)
+	assert.Contains(t, entry.Diff, "Audited Project")
+}
+
+func TestMediaChangesRecordAudit(t *testing.T) {
+	loginAndGetToken(t)
+
+	var actor models.User
+	assert.NoError(t, database.DB.Where("phone = ?", fixtureAdminPhone).First(&actor).Error)
+
+	category := models.ProjectCategory{Name: "Audited Media", Slug: "audited-media"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	project := models.Project{Title: "Audited Media Project", Slug: "audited-media-project", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	w := doJSON(t, "POST", fmt.Sprintf("/api/projects/%d/media", project.ID), accessToken,
+		services.ProjectMediaRequest{URL: "https://example.com/audited.png", Caption: "Audited"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response struct {
+		Data services.ProjectMediaResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	mediaID := response.Data.ID
+
+	w = doJSON(t, "PATCH", fmt.Sprintf("/api/projects/media/%d", mediaID), accessToken, []byte(`{"caption":"Recaptioned"}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doJSON(t, "DELETE", fmt.Sprintf("/api/projects/media/%d", mediaID), accessToken, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	// Every change is recorded with the actor and the project it belongs to
+	for _, action := range []string{models.AuditActionCreate, models.AuditActionUpdate, models.AuditActionDelete} {
+		var entry models.AuditLog
+		assert.NoError(t, database.DB.Where("resource_type = ? AND resource_id = ? AND action = ?",
+			services.AuditResourceProjectMedia, mediaID, action).First(&entry).Error, action)
+		assert.Equal(t, actor.ID, entry.ActorID)
+		assert.Contains(t, entry.Diff, fmt.Sprintf(`"project_id":%d`, project.ID))
+	}
+}
+
+func TestAuditDiffOfLongContentIsBounded(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := models.BlogCategory{Name: "Long Audited Posts", Slug: "long-audited-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	// Content far longer than a MySQL TEXT column
+	blog := createBlog(t, services.CreateBlogRequest{
+		Title:      "Long Audited Post",
+		Excerpt:    "Long excerpt",
+		Content:    "<p>" + strings.Repeat("Long content ", 10000) + "</p>",
+		CategoryID: category.ID,
+	})
+
+	var entry models.AuditLog
+	assert.NoError(t, database.DB.Where("resource_type = ? AND resource_id = ? AND action = ?",
+		services.AuditResourceBlogPost, uint(blog["id"].(float64)), models.AuditActionCreate).First(&entry).Error)
+	assert.Less(t, len(entry.Diff), 65535)
+	assert.Contains(t, entry.Diff, "Long Audited Post")
+
+	var diff map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(entry.Diff), &diff))
+	assert.True(t, strings.HasPrefix(diff["content"].(string), "<p>Long content"))
+}
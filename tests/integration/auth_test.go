@@ -6,9 +6,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
 	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
 )
 
 func TestRegister(t *testing.T) {
@@ -16,7 +20,7 @@ func TestRegister(t *testing.T) {
 	registerRequest := services.RegisterRequest{
 		Name:     "Test User",
 		Email:    "test@example.com",
-		Phone:    "+1234567890",
+		Phone:    "+1234567893",
 		Password: "password123",
 	}
 
@@ -59,14 +63,14 @@ func TestRegister(t *testing.T) {
 	user := data["user"].(map[string]interface{})
 	assert.Equal(t, "Test User", user["name"])
 	assert.Equal(t, "test@example.com", user["email"])
-	assert.Equal(t, "+1234567890", user["phone"])
+	assert.Equal(t, "+1234567893", user["phone"])
 	assert.Equal(t, "user", user["role"])
 }
 
 func TestLogin(t *testing.T) {
 	// Create a login request
 	loginRequest := services.LoginRequest{
-		Phone:    "+1234567890",
+		Phone:    "+1234567893",
 		Password: "password123",
 	}
 
@@ -109,6 +113,113 @@ func TestLogin(t *testing.T) {
 	user := data["user"].(map[string]interface{})
 	assert.Equal(t, "Test User", user["name"])
 	assert.Equal(t, "test@example.com", user["email"])
-	assert.Equal(t, "+1234567890", user["phone"])
+	assert.Equal(t, "+1234567893", user["phone"])
 	assert.Equal(t, "user", user["role"])
-}
\ No newline at end of file
+}
+
+func TestLoginLockout(t *testing.T) {
+	// Use a small threshold for the duration of this test
+	previous := config.Auth
+	config.Auth.MaxFailedLogins = 3
+	config.Auth.LockoutDuration = time.Hour
+	defer func() { config.Auth = previous }()
+
+	// Register a dedicated user
+	registerRequest := services.RegisterRequest{
+		Name:     "Lockout User",
+		Email:    "lockout@example.com",
+		Phone:    "+1234567891",
+		Password: "password123",
+	}
+	w := doJSON(t, "POST", "/api/auth/register", "", registerRequest)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Fail enough times to lock the account
+	wrongLogin := services.LoginRequest{Phone: "+1234567891", Password: "wrong-password"}
+	for i := 0; i < 3; i++ {
+		w = doJSON(t, "POST", "/api/auth/login", "", wrongLogin)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+	var locked models.User
+	assert.NoError(t, database.DB.Where("phone = ?", "+1234567891").First(&locked).Error)
+	assert.NotNil(t, locked.LockedUntil)
+	assert.Equal(t, 0, locked.FailedLoginCount)
+
+	// While locked every attempt gets the same answer, whatever the password
+	w = doJSON(t, "POST", "/api/auth/login", "", wrongLogin)
+	assert.Equal(t, http.StatusLocked, w.Code)
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, utils.CodeAuthAccountLocked, response["code"])
+
+	correctLogin := services.LoginRequest{Phone: "+1234567891", Password: "password123"}
+	correct := doJSON(t, "POST", "/api/auth/login", "", correctLogin)
+	assert.Equal(t, w.Code, correct.Code)
+	assert.Equal(t, w.Body.String(), correct.Body.String())
+
+	// Attempts during the lock do not count towards the next one
+	assert.NoError(t, database.DB.Where("phone = ?", "+1234567891").First(&locked).Error)
+	assert.Equal(t, 0, locked.FailedLoginCount)
+
+	// Move the lockout window into the past to simulate it expiring
+	err := database.DB.Model(&models.User{}).Where("phone = ?", "+1234567891").
+		Update("locked_until", time.Now().Add(-time.Minute)).Error
+	assert.NoError(t, err)
+
+	// The account unlocks automatically once the window has passed
+	w = doJSON(t, "POST", "/api/auth/login", "", correctLogin)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// A successful login resets the throttling state
+	var user models.User
+	assert.NoError(t, database.DB.Where("phone = ?", "+1234567891").First(&user).Error)
+	assert.Equal(t, 0, user.FailedLoginCount)
+	assert.Nil(t, user.LockedUntil)
+}
+
+func TestLoginInvalidCredentialsErrorCode(t *testing.T) {
+	w := doJSON(t, "POST", "/api/auth/login", "", services.LoginRequest{Phone: "+1999999999", Password: "password123"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, false, response["success"])
+	assert.Equal(t, utils.CodeAuthInvalidCredentials, response["code"])
+}
+
+func TestRegisterValidationFieldErrors(t *testing.T) {
+	w := doJSON(t, "POST", "/api/auth/register", "", services.RegisterRequest{
+		Name:     "Invalid User",
+		Email:    "not-an-email",
+		Phone:    "+1234567899",
+		Password: "abc",
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, false, response["success"])
+	assert.Equal(t, utils.CodeValidationFailed, response["code"])
+
+	// Each failed field is reported under its JSON name
+	fields, ok := response["error"].(map[string]interface{})
+	if assert.True(t, ok) {
+		assert.Len(t, fields, 2)
+		assert.Equal(t, "must be a valid email address", fields["email"])
+		assert.Equal(t, "is too short, must be at least 6 characters", fields["password"])
+	}
+}
+
+func TestRegisterMalformedJSONError(t *testing.T) {
+	req, err := http.NewRequest("POST", "/api/auth/register", bytes.NewBufferString("{"))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.IsType(t, "", response["error"])
+}
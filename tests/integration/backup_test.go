@@ -0,0 +1,151 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	loginAndGetToken(t)
+
+	// Seed related content directly in the database
+	tag := models.Tag{Name: "Backup Tag", Slug: "backup-tag"}
+	assert.NoError(t, database.DB.Create(&tag).Error)
+	technology := models.Technology{Name: "Backup Technology", Slug: "backup-technology"}
+	assert.NoError(t, database.DB.Create(&technology).Error)
+	projectCategory := models.ProjectCategory{Name: "Backup Projects", Slug: "backup-projects"}
+	assert.NoError(t, database.DB.Create(&projectCategory).Error)
+	blogCategory := models.BlogCategory{Name: "Backup Posts", Slug: "backup-posts"}
+	assert.NoError(t, database.DB.Create(&blogCategory).Error)
+
+	project := models.Project{
+		Title:        "Backup Project",
+		Slug:         "backup-project",
+		Content:      "Content",
+		CategoryID:   projectCategory.ID,
+		Media:        []models.ProjectMedia{{Type: "image", URL: "https://example.com/backup-project.png"}},
+		Tags:         []models.Tag{tag},
+		Technologies: []models.Technology{technology},
+		Published:    true,
+	}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	blog := models.BlogPost{
+		Title:      "Backup Draft",
+		Slug:       "backup-draft",
+		Content:    "Content",
+		CategoryID: blogCategory.ID,
+		Media:      []models.BlogMedia{{Type: "image", URL: "https://example.com/backup-draft.png"}},
+		Tags:       []models.Tag{tag},
+	}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+	assert.NoError(t, database.DB.Model(&blog).Update("published", false).Error)
+
+	skill := models.Skill{Name: "Backups", Proficiency: 80, Category: "Operations"}
+	assert.NoError(t, database.DB.Create(&skill).Error)
+
+	resumeProject := models.ResumeProject{Title: "Backup Resume Project", Description: "Restored with the resume", StartDate: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)}
+	assert.NoError(t, database.DB.Create(&resumeProject).Error)
+
+	// Export everything
+	w := doJSON(t, "GET", "/api/export", accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	backup := w.Body.Bytes()
+
+	var document map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(backup, &document))
+	for _, section := range []string{"users", "projects", "blog_posts", "tags", "skills", "resume_projects"} {
+		assert.Contains(t, document, section)
+	}
+	assert.NotContains(t, string(document["users"]), "password")
+
+	// Lose the content, then restore it from the backup
+	assert.Equal(t, http.StatusNoContent, doJSON(t, "DELETE", fmt.Sprintf("/api/projects/%d", project.ID), accessToken, nil).Code)
+	assert.Equal(t, http.StatusNoContent, doJSON(t, "DELETE", fmt.Sprintf("/api/blog/%d", blog.ID), accessToken, nil).Code)
+	assert.NoError(t, database.DB.Unscoped().Delete(&skill).Error)
+	assert.NoError(t, database.DB.Unscoped().Delete(&resumeProject).Error)
+
+	w = doJSON(t, "POST", "/api/import", accessToken, backup)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Relationships come back under the original IDs
+	var restoredProject models.Project
+	assert.NoError(t, database.DB.Preload("Media").Preload("Tags").Preload("Technologies").First(&restoredProject, project.ID).Error)
+	assert.Equal(t, projectCategory.ID, restoredProject.CategoryID)
+	assert.True(t, restoredProject.Published)
+	if assert.Len(t, restoredProject.Media, 1) {
+		assert.Equal(t, "https://example.com/backup-project.png", restoredProject.Media[0].URL)
+	}
+	if assert.Len(t, restoredProject.Tags, 1) {
+		assert.Equal(t, tag.ID, restoredProject.Tags[0].ID)
+	}
+	if assert.Len(t, restoredProject.Technologies, 1) {
+		assert.Equal(t, technology.ID, restoredProject.Technologies[0].ID)
+	}
+
+	var restoredBlog models.BlogPost
+	assert.NoError(t, database.DB.Preload("Media").Preload("Tags").First(&restoredBlog, blog.ID).Error)
+	assert.False(t, restoredBlog.Published)
+	assert.Len(t, restoredBlog.Media, 1)
+	assert.Len(t, restoredBlog.Tags, 1)
+
+	var restoredSkill models.Skill
+	assert.NoError(t, database.DB.First(&restoredSkill, skill.ID).Error)
+	assert.Equal(t, "Operations", restoredSkill.Category)
+
+	var restoredResumeProject models.ResumeProject
+	assert.NoError(t, database.DB.First(&restoredResumeProject, resumeProject.ID).Error)
+	assert.Equal(t, "Backup Resume Project", restoredResumeProject.Title)
+	assert.Equal(t, "Restored with the resume", restoredResumeProject.Description)
+
+	// The signed-in admin can still log in after the import
+	loginAndGetToken(t)
+
+	// Malformed documents are rejected without changing anything
+	w = doJSON(t, "POST", "/api/import", accessToken, []byte(`{"version":1,"tags":[{"id":"oops"}]}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	w = doJSON(t, "POST", "/api/import", accessToken, []byte(`{"version":99}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestImportKeepsNewIDsClearOfRestoredRows(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := createProjectCategory(t, "Sequence Projects")
+	categoryID := uint(category["id"].(float64))
+
+	// Restore a project under the ID the next created project would take
+	// (TEST_DB_DRIVER=postgres checks the sequence is moved past it)
+	placeholder := models.Project{Title: "Sequence Placeholder", Slug: "sequence-placeholder", Content: "Content", CategoryID: categoryID}
+	assert.NoError(t, database.DB.Create(&placeholder).Error)
+	assert.NoError(t, database.DB.Delete(&placeholder).Error)
+	restoredID := placeholder.ID + 1
+
+	backup := fmt.Sprintf(`{"version":1,"projects":[{"id":%d,"title":"Sequence Restored","slug":"sequence-restored","content":"Content","category_id":%d,"published":true,"media":[],"tags":[],"technologies":[]}]}`, restoredID, categoryID)
+	w := doJSON(t, "POST", "/api/import", accessToken, []byte(backup))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doJSON(t, "POST", "/api/projects", accessToken, services.CreateProjectRequest{
+		Title:       "Sequence Created",
+		Description: "Description",
+		Content:     "Content",
+		CategoryID:  categoryID,
+		Published:   true,
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var created struct {
+		Data services.ProjectResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Greater(t, created.Data.ID, restoredID)
+	assert.Equal(t, "sequence-created", created.Data.Slug)
+}
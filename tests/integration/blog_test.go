@@ -0,0 +1,278 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+// createBlog creates a blog post as the logged in test user and returns the response data
+func createBlog(t *testing.T, createRequest services.CreateBlogRequest) map[string]interface{} {
+	w := doJSON(t, "POST", "/api/blog", accessToken, createRequest)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	return response["data"].(map[string]interface{})
+}
+
+func TestCreateBlogGeneratesExcerpt(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := models.BlogCategory{Name: "Excerpt Posts", Slug: "excerpt-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	content := "<p>" + strings.Repeat("Generated excerpts are cut on whole words. ", 10) + "</p>"
+	blog := createBlog(t, services.CreateBlogRequest{
+		Title:      "Excerpt Generated",
+		Content:    content,
+		CategoryID: category.ID,
+	})
+
+	excerpt := blog["excerpt"].(string)
+	assert.True(t, strings.HasPrefix(excerpt, "Generated excerpts are cut on whole words."))
+	assert.True(t, strings.HasSuffix(excerpt, "..."))
+	assert.LessOrEqual(t, len([]rune(excerpt)), 160)
+	assert.NotContains(t, excerpt, "<p>")
+}
+
+func TestCreateBlogPreservesExcerpt(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := models.BlogCategory{Name: "Excerpt Kept Posts", Slug: "excerpt-kept-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	blog := createBlog(t, services.CreateBlogRequest{
+		Title:      "Excerpt Provided",
+		Excerpt:    "Hand written excerpt",
+		Content:    "<p>Some content</p>",
+		CategoryID: category.ID,
+	})
+
+	assert.Equal(t, "Hand written excerpt", blog["excerpt"])
+}
+
+func TestCreateBlogSanitizesContent(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := models.BlogCategory{Name: "Sanitized Posts", Slug: "sanitized-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	blog := createBlog(t, services.CreateBlogRequest{
+		Title:      "Sanitized Post",
+		Excerpt:    "Excerpt",
+		Content:    `<p>Safe <a href="https://example.com">link</a></p><script>alert(1)</script><img src="/a.png">`,
+		CategoryID: category.ID,
+	})
+
+	assert.Equal(t, `<p>Safe <a href="https://example.com">link</a></p><img src="/a.png">`, blog["content"])
+}
+
+func TestCreateBlogWithoutSanitization(t *testing.T) {
+	category := models.BlogCategory{Name: "Trusted Posts", Slug: "trusted-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	// Trusted deployments can store content verbatim
+	content := `<p>Embed</p><script src="https://widgets.example/embed.js"></script>`
+	blog, err := services.NewBlogService().WithContentSanitization(false).CreateBlog(services.CreateBlogRequest{
+		Title:      "Trusted Post",
+		Excerpt:    "Excerpt",
+		Content:    content,
+		CategoryID: category.ID,
+	}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, content, blog.Content)
+}
+
+func TestPatchBlogMediaKeepsOmittedFields(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := models.BlogCategory{Name: "Media Posts", Slug: "media-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	post := models.BlogPost{Title: "Media Post", Slug: "media-post", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&post).Error)
+	media := models.BlogMedia{BlogID: post.ID, Type: "image", URL: "https://example.com/post.png", Caption: "Kept caption", SortOrder: 1}
+	assert.NoError(t, database.DB.Create(&media).Error)
+
+	w := doJSON(t, "PATCH", fmt.Sprintf("/api/blog/media/%d", media.ID), accessToken, []byte(`{"sort_order":3}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var stored models.BlogMedia
+	assert.NoError(t, database.DB.First(&stored, media.ID).Error)
+	assert.Equal(t, 3, stored.SortOrder)
+	assert.Equal(t, "Kept caption", stored.Caption)
+	assert.Equal(t, "https://example.com/post.png", stored.URL)
+}
+
+func TestToggleBlogFlagsKeepsOtherFields(t *testing.T) {
+	loginAndGetToken(t)
+
+	var admin models.User
+	assert.NoError(t, database.DB.Where("phone = ?", fixtureAdminPhone).First(&admin).Error)
+
+	category := models.BlogCategory{Name: "Toggle Posts", Slug: "toggle-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	blog := models.BlogPost{
+		Title:      "Toggle Post",
+		Slug:       "toggle-post",
+		Excerpt:    "Toggle excerpt",
+		Content:    "<p>Toggle content</p>",
+		CategoryID: category.ID,
+		CreatedBy:  7,
+		UpdatedBy:  7,
+	}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+	assert.NoError(t, database.DB.Model(&blog).Update("published", false).Error)
+
+	w := doJSON(t, "PATCH", fmt.Sprintf("/api/blog/%d/publish", blog.ID), accessToken, []byte(`{"value":true}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doJSON(t, "PATCH", fmt.Sprintf("/api/blog/%d/featured", blog.ID), accessToken, []byte(`{"value":true}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var stored models.BlogPost
+	assert.NoError(t, database.DB.First(&stored, blog.ID).Error)
+	assert.True(t, stored.Published)
+	assert.True(t, stored.Featured)
+	assert.Equal(t, admin.ID, stored.UpdatedBy)
+
+	// Nothing but the flags and the editor changed
+	assert.Equal(t, blog.Title, stored.Title)
+	assert.Equal(t, blog.Slug, stored.Slug)
+	assert.Equal(t, blog.Excerpt, stored.Excerpt)
+	assert.Equal(t, blog.Content, stored.Content)
+	assert.Equal(t, blog.CategoryID, stored.CategoryID)
+	assert.Equal(t, uint(7), stored.CreatedBy)
+
+	w = doJSON(t, "PATCH", fmt.Sprintf("/api/blog/%d/publish", blog.ID), accessToken, []byte(`{"value":"yes"}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestPatchBlogClearsSentFieldsAndKeepsOmittedOnes(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := models.BlogCategory{Name: "Patched Posts", Slug: "patched-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	blog := createBlog(t, services.CreateBlogRequest{
+		Title:           "Patched Post",
+		Excerpt:         "Patched excerpt",
+		Content:         "<p>Patched content</p>",
+		CategoryID:      category.ID,
+		MetaTitle:       "Patched meta title",
+		MetaDescription: "Patched meta description",
+	})
+	blogID := uint(blog["id"].(float64))
+
+	// Explicitly sending empty values clears them, omitted fields stay as they were
+	w := doJSON(t, "PATCH", fmt.Sprintf("/api/blog/%d", blogID), accessToken, services.UpdateBlogRequest{
+		Excerpt:   stringPtr(""),
+		MetaTitle: stringPtr(""),
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var stored models.BlogPost
+	assert.NoError(t, database.DB.First(&stored, blogID).Error)
+	assert.Equal(t, "", stored.Excerpt)
+	assert.Equal(t, "", stored.MetaTitle)
+	assert.Equal(t, "Patched Post", stored.Title)
+	assert.Equal(t, "<p>Patched content</p>", stored.Content)
+	assert.Equal(t, "Patched meta description", stored.MetaDescription)
+	assert.Equal(t, category.ID, stored.CategoryID)
+}
+
+func TestDuplicateBlogIsFreshDraft(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := models.BlogCategory{Name: "Duplicated Posts", Slug: "duplicated-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	blog := models.BlogPost{Title: "Duplicated Post", Slug: "duplicated-post", Content: "<p>Content</p>", CategoryID: category.ID, Published: true, Featured: true}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	w := doJSON(t, "POST", fmt.Sprintf("/api/blog/%d/duplicate", blog.ID), accessToken, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response struct {
+		Data services.BlogResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	duplicate := response.Data
+
+	// The copy is an unpublished, unfeatured draft announced like a new post
+	assert.NotEqual(t, blog.ID, duplicate.ID)
+	assert.False(t, duplicate.Published)
+	assert.False(t, duplicate.Featured)
+	assert.NotNil(t, outboxEventFor(t, services.WebhookEventBlogCreated, duplicate.ID))
+}
+
+func TestBlogWithUnknownCategoryIsRejected(t *testing.T) {
+	loginAndGetToken(t)
+
+	w := doJSON(t, "POST", "/api/blog", accessToken, services.CreateBlogRequest{
+		Title:      "Uncategorized Post",
+		Content:    "<p>Content</p>",
+		CategoryID: 999999,
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "category not found")
+
+	category := models.BlogCategory{Name: "Known Posts", Slug: "known-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	blog := models.BlogPost{Title: "Known Post", Slug: "known-post", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	w = doJSON(t, "PATCH", fmt.Sprintf("/api/blog/%d", blog.ID), accessToken, []byte(`{"category_id":999999}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var stored models.BlogPost
+	assert.NoError(t, database.DB.First(&stored, blog.ID).Error)
+	assert.Equal(t, category.ID, stored.CategoryID)
+}
+
+// detailStatus requests a detail route, signed in when token is set, and returns the status
+func detailStatus(t *testing.T, path, token string) int {
+	req, err := http.NewRequest("GET", path, nil)
+	assert.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestUnpublishedBlogPostIsHiddenFromPublicDetail(t *testing.T) {
+	loginAndGetToken(t)
+	previewToken := mintPreviewToken(t)
+	author := registerAuthor(t, "Hidden Blog Author")
+
+	category := models.BlogCategory{Name: "Hidden Detail", Slug: "hidden-detail"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	blog := models.BlogPost{Title: "Hidden Detail", Slug: "hidden-detail", Content: "<p>Draft</p>", CategoryID: category.ID, CreatedBy: 1}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+	assert.NoError(t, database.DB.Model(&blog).Update("published", false).Error)
+
+	for _, path := range []string{fmt.Sprintf("/api/blog/%d", blog.ID), "/api/blog/slug/hidden-detail", "/api/blog/slug/hidden-detail/meta", fmt.Sprintf("/api/blog/%d/media", blog.ID)} {
+		// Anonymous and regular users do not find the draft
+		assert.Equal(t, http.StatusNotFound, detailStatus(t, path, ""), path)
+		assert.Equal(t, http.StatusNotFound, detailStatus(t, path, author.AccessToken), path)
+
+		// Admins, editors and preview tokens do
+		assert.Equal(t, http.StatusOK, detailStatus(t, path, accessToken), path)
+		assert.Equal(t, http.StatusOK, detailStatus(t, path, previewToken), path)
+	}
+
+	// Once published it is public
+	assert.NoError(t, database.DB.Model(&blog).Update("published", true).Error)
+	assert.Equal(t, http.StatusOK, detailStatus(t, fmt.Sprintf("/api/blog/%d", blog.ID), ""))
+}
@@ -0,0 +1,79 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+)
+
+// siblingIDs returns the ids of the category siblings in a project response, in order
+func siblingIDs(data map[string]interface{}) []uint {
+	ids := []uint{}
+	for _, item := range data["category_siblings"].([]interface{}) {
+		ids = append(ids, uint(item.(map[string]interface{})["id"].(float64)))
+	}
+	return ids
+}
+
+func TestProjectDetailIncludesCategorySiblings(t *testing.T) {
+	category := models.ProjectCategory{Name: "Siblings", Slug: "siblings"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	other := models.ProjectCategory{Name: "Other Siblings", Slug: "other-siblings"}
+	assert.NoError(t, database.DB.Create(&other).Error)
+
+	// Six published projects, newest last, then a draft and a project of another category
+	created := time.Now().Add(-time.Hour)
+	projects := make([]models.Project, 6)
+	for i := range projects {
+		projects[i] = models.Project{
+			Title:      fmt.Sprintf("Sibling %d", i),
+			Slug:       fmt.Sprintf("sibling-%d", i),
+			Content:    "Content",
+			CategoryID: category.ID,
+			CreatedAt:  created.Add(time.Duration(i) * time.Minute),
+		}
+		assert.NoError(t, database.DB.Create(&projects[i]).Error)
+	}
+	draft := models.Project{Title: "Sibling Draft", Slug: "sibling-draft", Content: "Content", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&draft).Error)
+	assert.NoError(t, database.DB.Model(&draft).Update("published", false).Error)
+	stranger := models.Project{Title: "Sibling Stranger", Slug: "sibling-stranger", Content: "Content", CategoryID: other.ID}
+	assert.NoError(t, database.DB.Create(&stranger).Error)
+
+	// The default response is unchanged
+	current := projects[5]
+	data := getResponseData(t, fmt.Sprintf("/api/projects/%d", current.ID))
+	assert.Equal(t, float64(current.ID), data["id"])
+	assert.NotContains(t, data, "category_siblings")
+
+	// Siblings are the newest other published projects of the category
+	data = getResponseData(t, fmt.Sprintf("/api/projects/%d?include=category_siblings", current.ID))
+	assert.Equal(t, float64(current.ID), data["id"])
+	assert.Equal(t, "Sibling 5", data["title"])
+	assert.Equal(t, []uint{projects[4].ID, projects[3].ID, projects[2].ID, projects[1].ID}, siblingIDs(data))
+
+	data = getResponseData(t, fmt.Sprintf("/api/projects/%d?include=category_siblings&siblings_limit=10", projects[0].ID))
+	assert.Equal(t, []uint{projects[5].ID, projects[4].ID, projects[3].ID, projects[2].ID, projects[1].ID}, siblingIDs(data))
+
+	data = getResponseData(t, "/api/projects/slug/sibling-2?include=category_siblings&siblings_limit=2")
+	assert.Equal(t, []uint{projects[5].ID, projects[4].ID}, siblingIDs(data))
+
+	// A project alone in its category has no siblings
+	data = getResponseData(t, fmt.Sprintf("/api/projects/%d?include=category_siblings", stranger.ID))
+	assert.Equal(t, []uint{}, siblingIDs(data))
+
+	// Unknown includes and malformed limits are rejected
+	for _, query := range []string{"include=comments", "include=category_siblings&siblings_limit=0", "include=category_siblings&siblings_limit=two"} {
+		req, err := http.NewRequest("GET", fmt.Sprintf("/api/projects/%d?%s", current.ID, query), nil)
+		assert.NoError(t, err)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
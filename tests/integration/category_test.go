@@ -0,0 +1,284 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func TestUpdateProjectCategoryNameCollision(t *testing.T) {
+	// Login to get an access token
+	loginAndGetToken(t)
+
+	// Create two categories with distinct names
+	first := createProjectCategory(t, "Web Development")
+	second := createProjectCategory(t, "Mobile Apps")
+
+	// Rename the second category to a name that sanitizes to the first one's slug
+	updateRequest := services.CategoryRequest{Name: "  Wéb   Development! "}
+
+	jsonData, err := json.Marshal(updateRequest)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/categories/projects/%d", uint(second["id"].(float64))), bytes.NewBuffer(jsonData))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert the rename was rejected as a conflict
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, false, response["success"])
+	assert.Equal(t, services.ErrCategoryNameExists.Error(), response["error"])
+
+	// Renaming a category to its own name is not a collision
+	updateRequest = services.CategoryRequest{Name: first["name"].(string)}
+
+	jsonData, err = json.Marshal(updateRequest)
+	assert.NoError(t, err)
+
+	req, err = http.NewRequest("PUT", fmt.Sprintf("/api/categories/projects/%d", uint(first["id"].(float64))), bytes.NewBuffer(jsonData))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestReassignProjectCategory(t *testing.T) {
+	// Login to get an access token
+	loginAndGetToken(t)
+
+	source := createProjectCategory(t, "Reassign Source")
+	target := createProjectCategory(t, "Reassign Target")
+	sourceID := uint(source["id"].(float64))
+	targetID := uint(target["id"].(float64))
+
+	// Create a project in the source category
+	jsonData, err := json.Marshal(services.CreateProjectRequest{
+		Title:       "Reassigned Project",
+		Description: "Project that will move between categories",
+		Content:     "Content",
+		CategoryID:  sourceID,
+		Published:   true,
+	})
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest("POST", "/api/projects", bytes.NewBuffer(jsonData))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	project := response["data"].(map[string]interface{})
+
+	// Deleting the source category fails while it is in use
+	req, err = http.NewRequest("DELETE", fmt.Sprintf("/api/categories/projects/%d", sourceID), nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Reassign the projects to the target category
+	jsonData, err = json.Marshal(services.ReassignCategoryRequest{TargetCategoryID: targetID})
+	assert.NoError(t, err)
+
+	req, err = http.NewRequest("POST", fmt.Sprintf("/api/categories/projects/%d/reassign", sourceID), bytes.NewBuffer(jsonData))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	result := response["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), result["moved"])
+	assert.Equal(t, false, result["source_deleted"])
+
+	// The project now belongs to the target category
+	req, err = http.NewRequest("GET", fmt.Sprintf("/api/projects/%d", uint(project["id"].(float64))), nil)
+	assert.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	project = response["data"].(map[string]interface{})
+	assert.Equal(t, float64(targetID), project["category_id"])
+
+	// The now-empty source category can be deleted
+	req, err = http.NewRequest("DELETE", fmt.Sprintf("/api/categories/projects/%d", sourceID), nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func createProjectCategory(t *testing.T, name string) map[string]interface{} {
+	// Convert to JSON
+	jsonData, err := json.Marshal(services.CategoryRequest{Name: name})
+	assert.NoError(t, err)
+
+	// Create a request
+	req, err := http.NewRequest("POST", "/api/categories/projects", bytes.NewBuffer(jsonData))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	// Serve the request
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Parse the response
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	return response["data"].(map[string]interface{})
+}
+
+// getCategoryItems lists the items of a category by slug and returns the response data
+func getCategoryItems(t *testing.T, path string) map[string]interface{} {
+	req, err := http.NewRequest("GET", path, nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	return response["data"].(map[string]interface{})
+}
+
+func TestListProjectCategoryItemsBySlug(t *testing.T) {
+	category := models.ProjectCategory{Name: "Landing Projects", Slug: "landing-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	other := models.ProjectCategory{Name: "Other Landing Projects", Slug: "other-landing-projects"}
+	assert.NoError(t, database.DB.Create(&other).Error)
+
+	projects := []models.Project{
+		{Title: "Landing One", Slug: "landing-one", CategoryID: category.ID, Published: true},
+		{Title: "Landing Two", Slug: "landing-two", CategoryID: category.ID, Published: true},
+		{Title: "Landing Draft", Slug: "landing-draft", CategoryID: category.ID},
+		{Title: "Landing Elsewhere", Slug: "landing-elsewhere", CategoryID: other.ID, Published: true},
+	}
+	for i := range projects {
+		assert.NoError(t, database.DB.Create(&projects[i]).Error)
+	}
+	// Create replaces a false Published with the column default, so unpublish the draft afterwards
+	assert.NoError(t, database.DB.Model(&projects[2]).Update("published", false).Error)
+
+	data := getCategoryItems(t, "/api/categories/projects/landing-projects/items?limit=1")
+	assert.Equal(t, "Landing Projects", data["category"].(map[string]interface{})["name"])
+	assert.Equal(t, float64(2), data["metadata"].(map[string]interface{})["total"])
+	assert.Len(t, data["projects"], 1)
+
+	// Only the published projects of the category are listed
+	data = getCategoryItems(t, "/api/categories/projects/landing-projects/items")
+	slugs := []string{}
+	for _, item := range data["projects"].([]interface{}) {
+		slugs = append(slugs, item.(map[string]interface{})["slug"].(string))
+	}
+	assert.ElementsMatch(t, []string{"landing-one", "landing-two"}, slugs)
+}
+
+func TestListBlogCategoryItemsBySlug(t *testing.T) {
+	category := models.BlogCategory{Name: "Landing Posts", Slug: "landing-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	other := models.BlogCategory{Name: "Other Landing Posts", Slug: "other-landing-posts"}
+	assert.NoError(t, database.DB.Create(&other).Error)
+
+	posts := []models.BlogPost{
+		{Title: "Landing Post", Slug: "landing-post", CategoryID: category.ID, Published: true},
+		{Title: "Landing Post Elsewhere", Slug: "landing-post-elsewhere", CategoryID: other.ID, Published: true},
+	}
+	for i := range posts {
+		assert.NoError(t, database.DB.Create(&posts[i]).Error)
+	}
+
+	data := getCategoryItems(t, "/api/categories/blog/landing-posts/items")
+	blogs := data["blogs"].([]interface{})
+	if assert.Len(t, blogs, 1) {
+		assert.Equal(t, "landing-post", blogs[0].(map[string]interface{})["slug"])
+	}
+}
+
+func TestListCategoryItemsUnknownSlug(t *testing.T) {
+	for _, path := range []string{"/api/categories/projects/no-such-category/items", "/api/categories/blog/no-such-category/items"} {
+		req, err := http.NewRequest("GET", path, nil)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+}
+
+func TestDeleteCategoryReassignRequiresAdmin(t *testing.T) {
+	// Login to get an access token
+	loginAndGetToken(t)
+
+	source := createProjectCategory(t, "Editor Reassign Source")
+	target := createProjectCategory(t, "Editor Reassign Target")
+	sourceID := uint(source["id"].(float64))
+	targetID := uint(target["id"].(float64))
+
+	// Promote a registered user to editor and login again for a token with that role
+	editor := registerAuthor(t, "Category Editor")
+	assert.NoError(t, database.DB.Model(&models.User{}).Where("id = ?", editor.User.ID).Update("role_id", models.RoleEditor).Error)
+	tokens, err := services.NewAuthService(config).Login(services.LoginRequest{Phone: editor.User.Phone, Password: "password123"})
+	assert.NoError(t, err)
+
+	// Editors cannot delete a category while reassigning its items
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("/api/categories/projects/%d?reassign_to=%d", sourceID, targetID), nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tokens.AccessToken))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Editors can still delete an empty category
+	req, err = http.NewRequest("DELETE", fmt.Sprintf("/api/categories/projects/%d", sourceID), nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tokens.AccessToken))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -0,0 +1,185 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+	"gorm.io/gorm"
+)
+
+// postComment submits an anonymous comment from the given client address
+func postComment(t *testing.T, blogID uint, clientIP string, req services.CreateCommentRequest) *httptest.ResponseRecorder {
+	jsonData, err := json.Marshal(req)
+	assert.NoError(t, err)
+
+	httpReq, err := http.NewRequest("POST", fmt.Sprintf("/api/blog/%d/comments", blogID), bytes.NewBuffer(jsonData))
+	assert.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.RemoteAddr = clientIP + ":40000"
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+
+	return w
+}
+
+func publicComments(t *testing.T, blogID uint) []interface{} {
+	data := getResponseData(t, fmt.Sprintf("/api/blog/%d/comments", blogID))
+	return data["comments"].([]interface{})
+}
+
+func createCommentableBlog(t *testing.T, slug string) models.BlogPost {
+	category := models.BlogCategory{Name: slug, Slug: slug + "-category"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	blog := models.BlogPost{Title: slug, Slug: slug, Content: "<p>Content</p>", CategoryID: category.ID, Published: true}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	return blog
+}
+
+func TestCommentAppearsAfterApproval(t *testing.T) {
+	loginAndGetToken(t)
+
+	blog := createCommentableBlog(t, "commented-post")
+
+	w := postComment(t, blog.ID, "198.51.100.10", services.CreateCommentRequest{
+		AuthorName:  "Reader",
+		AuthorEmail: "reader@example.com",
+		Body:        "Great post!",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var created struct {
+		Data services.CommentResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.False(t, created.Data.Approved)
+	assert.Empty(t, created.Data.AuthorEmail)
+
+	// Pending comments are hidden from readers but listed for moderators
+	assert.Len(t, publicComments(t, blog.ID), 0)
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/comments?approved=false&blog_id=%d", blog.ID), nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var pending struct {
+		Data struct {
+			Comments []services.CommentResponse `json:"comments"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &pending))
+	if assert.Len(t, pending.Data.Comments, 1) {
+		assert.Equal(t, "reader@example.com", pending.Data.Comments[0].AuthorEmail)
+	}
+
+	w = doJSON(t, "PUT", fmt.Sprintf("/api/comments/%d/approve", created.Data.ID), accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	comments := publicComments(t, blog.ID)
+	if assert.Len(t, comments, 1) {
+		comment := comments[0].(map[string]interface{})
+		assert.Equal(t, "Great post!", comment["body"])
+		assert.Equal(t, true, comment["approved"])
+		assert.NotContains(t, comment, "author_email")
+	}
+
+	// Deleting the comment removes it from the post
+	req, err = http.NewRequest("DELETE", fmt.Sprintf("/api/comments/%d", created.Data.ID), nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Len(t, publicComments(t, blog.ID), 0)
+}
+
+func TestCommentSpamGuards(t *testing.T) {
+	blog := createCommentableBlog(t, "guarded-post")
+	comment := services.CreateCommentRequest{AuthorName: "Reader", Body: "Nice"}
+
+	// Overlong and anonymous comments without a name are rejected
+	tooLong := comment
+	tooLong.Body = strings.Repeat("a", config.Comment.MaxLength+1)
+	assert.Equal(t, http.StatusUnprocessableEntity, postComment(t, blog.ID, "198.51.100.20", tooLong).Code)
+
+	anonymous := comment
+	anonymous.AuthorName = ""
+	assert.Equal(t, http.StatusUnprocessableEntity, postComment(t, blog.ID, "198.51.100.20", anonymous).Code)
+
+	// A client is limited to a number of comments per window
+	for i := 0; i < config.Comment.RateLimit; i++ {
+		assert.Equal(t, http.StatusCreated, postComment(t, blog.ID, "198.51.100.21", comment).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, postComment(t, blog.ID, "198.51.100.21", comment).Code)
+	assert.Equal(t, http.StatusCreated, postComment(t, blog.ID, "198.51.100.22", comment).Code)
+
+	// Without trusted proxies a forged X-Forwarded-For does not escape the limit
+	body, err := json.Marshal(comment)
+	assert.NoError(t, err)
+	spoofed, err := http.NewRequest("POST", fmt.Sprintf("/api/blog/%d/comments", blog.ID), bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	spoofed.Header.Set("Content-Type", "application/json")
+	spoofed.Header.Set("X-Forwarded-For", "203.0.113.99")
+	spoofed.RemoteAddr = "198.51.100.21:40000"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, spoofed)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	// Drafts cannot be commented on
+	assert.NoError(t, database.DB.Model(&blog).Update("published", false).Error)
+	assert.Equal(t, http.StatusNotFound, postComment(t, blog.ID, "198.51.100.23", comment).Code)
+}
+
+func TestCommentRateLimitHoldsForConcurrentRequests(t *testing.T) {
+	blog := createCommentableBlog(t, "concurrent-post")
+	comment := services.CreateCommentRequest{AuthorName: "Reader", Body: "Nice"}
+
+	// Slow counting down so that requests arriving together would all pass the
+	// count before any of them is stored
+	slowCount := func(db *gorm.DB) {
+		if db.Statement.Table == "comments" {
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+	assert.NoError(t, database.DB.Callback().Query().After("gorm:query").Register("test:slow_count", slowCount))
+	defer database.DB.Callback().Query().Remove("test:slow_count")
+
+	var wg sync.WaitGroup
+	codes := make(chan int, config.Comment.RateLimit*3)
+	for i := 0; i < cap(codes); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- postComment(t, blog.ID, "198.51.100.30", comment).Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	created := 0
+	for code := range codes {
+		if code == http.StatusCreated {
+			created++
+		} else {
+			assert.Equal(t, http.StatusTooManyRequests, code)
+		}
+	}
+	assert.Equal(t, config.Comment.RateLimit, created)
+	assert.Equal(t, int64(config.Comment.RateLimit), countRows(t, &models.Comment{}, "ip_address = ?", "198.51.100.30"))
+}
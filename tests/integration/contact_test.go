@@ -0,0 +1,153 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/mailer"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+// postContact sends a contact message from the given client address
+func postContact(t *testing.T, clientIP string, req services.CreateMessageRequest) *httptest.ResponseRecorder {
+	jsonData, err := json.Marshal(req)
+	assert.NoError(t, err)
+
+	httpReq, err := http.NewRequest("POST", "/api/contact", bytes.NewBuffer(jsonData))
+	assert.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.RemoteAddr = clientIP + ":40000"
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+
+	return w
+}
+
+func contactMessage(subject string) services.CreateMessageRequest {
+	return services.CreateMessageRequest{
+		Name:    "Visitor",
+		Email:   "visitor@example.com",
+		Subject: subject,
+		Body:    "I would like to work with you.",
+	}
+}
+
+func TestContactMessageLifecycle(t *testing.T) {
+	loginAndGetToken(t)
+	assert.NoError(t, database.DB.Where("1 = 1").Delete(&models.Message{}).Error)
+
+	w := postContact(t, "198.51.100.30", contactMessage("Hello"))
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var created struct {
+		Data services.MessageResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "Hello", created.Data.Subject)
+	assert.False(t, created.Data.Read)
+
+	// Admins see the message until it is marked read
+	w = doJSON(t, "GET", "/api/messages?read=false", accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var listed struct {
+		Data services.MessageListResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	if assert.Len(t, listed.Data.Messages, 1) {
+		assert.Equal(t, created.Data.ID, listed.Data.Messages[0].ID)
+		assert.Equal(t, "visitor@example.com", listed.Data.Messages[0].Email)
+	}
+
+	w = doJSON(t, "PUT", fmt.Sprintf("/api/messages/%d/read", created.Data.ID), accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doJSON(t, "GET", "/api/messages?read=false", accessToken, nil)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	assert.Len(t, listed.Data.Messages, 0)
+	assert.Equal(t, int64(0), listed.Data.Metadata.Total)
+
+	w = doJSON(t, "PUT", fmt.Sprintf("/api/messages/%d/unread", created.Data.ID), accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var stored models.Message
+	assert.NoError(t, database.DB.First(&stored, created.Data.ID).Error)
+	assert.False(t, stored.Read)
+
+	w = doJSON(t, "PUT", "/api/messages/999999/read", accessToken, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestContactMessageSpamGuards(t *testing.T) {
+	assert.NoError(t, database.DB.Where("1 = 1").Delete(&models.Message{}).Error)
+
+	// Invalid and link-stuffed messages are rejected
+	invalid := contactMessage("Invalid")
+	invalid.Email = "not-an-email"
+	assert.Equal(t, http.StatusUnprocessableEntity, postContact(t, "198.51.100.40", invalid).Code)
+
+	links := contactMessage("Links")
+	links.Body = strings.Repeat("https://spam.example.com ", 4)
+	assert.Equal(t, http.StatusUnprocessableEntity, postContact(t, "198.51.100.40", links).Code)
+
+	// A filled honeypot looks accepted but nothing is stored
+	bot := contactMessage("Bot")
+	bot.Website = "https://spam.example.com"
+	assert.Equal(t, http.StatusCreated, postContact(t, "198.51.100.41", bot).Code)
+
+	var count int64
+	assert.NoError(t, database.DB.Model(&models.Message{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+
+	// A client is limited to a number of messages per window
+	for i := 0; i < config.Contact.RateLimit; i++ {
+		assert.Equal(t, http.StatusCreated, postContact(t, "198.51.100.42", contactMessage("Limited")).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, postContact(t, "198.51.100.42", contactMessage("Limited")).Code)
+	assert.Equal(t, http.StatusCreated, postContact(t, "198.51.100.43", contactMessage("Limited")).Code)
+}
+
+func TestContactMessagesAreAdminOnly(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/messages", nil)
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	author := registerAuthor(t, "Message Reader")
+	w = doJSON(t, "GET", "/api/messages", author.AccessToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestContactMessageNotifiesAdmin(t *testing.T) {
+	notifyConfig := *config
+	notifyConfig.Contact.NotifyEmail = "owner@example.com"
+
+	mail := mailer.NewRecording()
+	message, err := services.NewMessageService(&notifyConfig).
+		WithMailer(mail).
+		CreateMessage(contactMessage("Project inquiry"), "")
+	assert.NoError(t, err)
+
+	messages := mail.Messages()
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, "owner@example.com", messages[0].To)
+		assert.Equal(t, "New contact message: Project inquiry", messages[0].Subject)
+		assert.Contains(t, messages[0].Body, "visitor@example.com")
+		assert.Contains(t, messages[0].Body, message.Body)
+	}
+
+	// Without an address nothing is sent
+	mail = mailer.NewRecording()
+	_, err = services.NewMessageService(config).WithMailer(mail).CreateMessage(contactMessage("Quiet"), "")
+	assert.NoError(t, err)
+	assert.Len(t, mail.Messages(), 0)
+}
@@ -0,0 +1,125 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+// getDrafts requests the drafts listing with the given query string
+func getDrafts(t *testing.T, query, token string) (int, services.DraftListResponse) {
+	req, err := http.NewRequest("GET", "/api/drafts?"+query, nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Data services.DraftListResponse `json:"data"`
+	}
+	if w.Code == http.StatusOK {
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	}
+	return w.Code, response.Data
+}
+
+// createDraftFixtures creates two unpublished and one published project and
+// blog post by author, returning the keys of the unpublished ones
+func createDraftFixtures(t *testing.T, author uint) map[string]bool {
+	suffix := fmt.Sprint(time.Now().UnixNano())
+	projectCategory := models.ProjectCategory{Name: "Drafts " + suffix, Slug: "drafts-" + suffix}
+	assert.NoError(t, database.DB.Create(&projectCategory).Error)
+	blogCategory := models.BlogCategory{Name: "Drafts " + suffix, Slug: "drafts-" + suffix}
+	assert.NoError(t, database.DB.Create(&blogCategory).Error)
+
+	drafts := make(map[string]bool)
+	for i, published := range []bool{false, false, true} {
+		slug := fmt.Sprintf("draft-listing-%s-%d", suffix, i)
+
+		project := models.Project{Title: "Listed " + slug, Slug: slug, Content: "Content", CategoryID: projectCategory.ID, CreatedBy: author}
+		assert.NoError(t, database.DB.Create(&project).Error)
+		assert.NoError(t, database.DB.Model(&project).Update("published", published).Error)
+
+		post := models.BlogPost{Title: "Listed " + slug, Slug: slug, Content: "Content", CategoryID: blogCategory.ID, CreatedBy: author}
+		assert.NoError(t, database.DB.Create(&post).Error)
+		assert.NoError(t, database.DB.Model(&post).Update("published", published).Error)
+
+		if !published {
+			drafts[fmt.Sprintf("%s:%d", services.ActivityTypeProject, project.ID)] = true
+			drafts[fmt.Sprintf("%s:%d", services.ActivityTypeBlogPost, post.ID)] = true
+		}
+	}
+	return drafts
+}
+
+func TestDraftsListOnlyUnpublishedItems(t *testing.T) {
+	loginAndGetToken(t)
+	author := registerAuthor(t, "Listing Author")
+	drafts := createDraftFixtures(t, author.User.ID)
+
+	status, page := getDrafts(t, fmt.Sprintf("author_id=%d", author.User.ID), accessToken)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int64(4), page.Metadata.Total)
+	assert.Len(t, page.Drafts, 4)
+	for _, draft := range page.Drafts {
+		assert.True(t, drafts[fmt.Sprintf("%s:%d", draft.Type, draft.ID)], "unexpected draft %s %d", draft.Type, draft.ID)
+		assert.Equal(t, author.User.ID, draft.CreatedBy)
+	}
+
+	// Filtering by type keeps only that half of the merged set
+	status, page = getDrafts(t, fmt.Sprintf("author_id=%d&type=blog_post", author.User.ID), accessToken)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int64(2), page.Metadata.Total)
+	for _, draft := range page.Drafts {
+		assert.Equal(t, services.ActivityTypeBlogPost, draft.Type)
+	}
+
+	status, _ = getDrafts(t, "type=comment", accessToken)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestDraftsPaginateAcrossTypes(t *testing.T) {
+	loginAndGetToken(t)
+	author := registerAuthor(t, "Paging Author")
+	drafts := createDraftFixtures(t, author.User.ID)
+
+	query := fmt.Sprintf("author_id=%d&limit=3", author.User.ID)
+	status, first := getDrafts(t, query+"&page=1", accessToken)
+	assert.Equal(t, http.StatusOK, status)
+	status, second := getDrafts(t, query+"&page=2", accessToken)
+	assert.Equal(t, http.StatusOK, status)
+
+	assert.Len(t, first.Drafts, 3)
+	assert.Len(t, second.Drafts, 1)
+	assert.Equal(t, int64(4), first.Metadata.Total)
+	assert.Equal(t, int64(2), first.Metadata.TotalPages)
+
+	// The pages hold every draft exactly once, most recently updated first
+	seen := make(map[string]bool)
+	merged := append(first.Drafts, second.Drafts...)
+	for i, draft := range merged {
+		key := fmt.Sprintf("%s:%d", draft.Type, draft.ID)
+		assert.False(t, seen[key], "draft %s listed twice", key)
+		seen[key] = true
+		if i > 0 {
+			assert.False(t, draft.UpdatedAt.After(merged[i-1].UpdatedAt))
+		}
+	}
+	assert.Equal(t, drafts, seen)
+}
+
+func TestDraftsRequireEditor(t *testing.T) {
+	author := registerAuthor(t, "Curious Author")
+
+	status, _ := getDrafts(t, "", author.AccessToken)
+	assert.Equal(t, http.StatusForbidden, status)
+}
@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+	"gorm.io/gorm"
+)
+
+// getDraft fetches the autosaved draft at path as the logged in test user
+func getDraft(t *testing.T, path string) services.DraftResponse {
+	w := doJSON(t, "GET", path, accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data services.DraftResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	return response.Data
+}
+
+func TestAutosaveLeavesPublicContentUntilPublished(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := models.BlogCategory{Name: "Draft Posts", Slug: "draft-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	lastUpdate := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	blog := models.BlogPost{Title: "Autosaved Post", Slug: "autosaved-post", Content: "<p>Published</p>", CategoryID: category.ID, Published: true,
+		CreatedAt: lastUpdate, UpdatedAt: lastUpdate}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	// Publishing before anything was autosaved is refused
+	w := doJSON(t, "POST", fmt.Sprintf("/api/blog/%d/publish-draft", blog.ID), accessToken, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = doJSON(t, "PUT", fmt.Sprintf("/api/blog/%d/autosave", blog.ID), accessToken, services.DraftRequest{Content: "<p>Work in progress</p>"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// The public post and its update time are unchanged
+	public := getResponseData(t, fmt.Sprintf("/api/blog/%d", blog.ID))
+	assert.Equal(t, "<p>Published</p>", public["content"])
+	assert.Equal(t, lastUpdate.Format(time.RFC3339), public["updated_at"])
+
+	// The editor can load the draft back
+	draft := getDraft(t, fmt.Sprintf("/api/blog/%d/draft", blog.ID))
+	if assert.NotNil(t, draft.DraftContent) {
+		assert.Equal(t, "<p>Work in progress</p>", *draft.DraftContent)
+	}
+
+	w = doJSON(t, "POST", fmt.Sprintf("/api/blog/%d/publish-draft", blog.ID), accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var published struct {
+		Data services.BlogResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &published))
+	assert.Equal(t, "<p>Work in progress</p>", published.Data.Content)
+
+	public = getResponseData(t, fmt.Sprintf("/api/blog/%d", blog.ID))
+	assert.Equal(t, "<p>Work in progress</p>", public["content"])
+
+	// Publishing discards the draft
+	assert.Nil(t, getDraft(t, fmt.Sprintf("/api/blog/%d/draft", blog.ID)).DraftContent)
+}
+
+func TestPublishDraftIsAtomic(t *testing.T) {
+	loginAndGetToken(t)
+
+	projectID := createOutboxProject(t, "Atomic Draft Project")
+	w := doJSON(t, "PUT", fmt.Sprintf("/api/projects/%d/autosave", projectID), accessToken, services.DraftRequest{Content: "Draft content"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// When the webhook event cannot be recorded, neither the content nor the draft change
+	failOutbox := func(db *gorm.DB) {
+		if db.Statement.Table == (models.OutboxEvent{}).TableName() {
+			db.AddError(errors.New("outbox unavailable"))
+		}
+	}
+	assert.NoError(t, database.DB.Callback().Create().Before("gorm:create").Register("test:fail_outbox", failOutbox))
+	w = doJSON(t, "POST", fmt.Sprintf("/api/projects/%d/publish-draft", projectID), accessToken, nil)
+	assert.NoError(t, database.DB.Callback().Create().Remove("test:fail_outbox"))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var project models.Project
+	assert.NoError(t, database.DB.First(&project, projectID).Error)
+	assert.Equal(t, "Content", project.Content)
+	if assert.NotNil(t, project.DraftContent) {
+		assert.Equal(t, "Draft content", *project.DraftContent)
+	}
+
+	// Otherwise the content, the draft and the event change together
+	w = doJSON(t, "POST", fmt.Sprintf("/api/projects/%d/publish-draft", projectID), accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.NoError(t, database.DB.First(&project, projectID).Error)
+	assert.Equal(t, "Draft content", project.Content)
+	assert.Nil(t, project.DraftContent)
+	outboxEvent := outboxEventFor(t, services.WebhookEventProjectUpdated, projectID)
+	if assert.NotNil(t, outboxEvent) {
+		assert.Contains(t, outboxEvent.Payload, "Draft content")
+	}
+}
+
+func TestAutosaveSanitizesDraft(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := models.BlogCategory{Name: "Sanitized Drafts", Slug: "sanitized-drafts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	blog := models.BlogPost{Title: "Sanitized Draft", Slug: "sanitized-draft", Content: "<p>Published</p>", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+	project := models.Project{Title: "Sanitized Draft", Slug: "sanitized-draft", Content: "<p>Published</p>", CategoryID: fixtureCategoryID}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	// Drafts are previewed by editors, so unsafe HTML never reaches the column
+	content := `<p>Draft</p><script>alert(1)</script><img src="/a.png" onerror="alert(2)">`
+	for _, path := range []string{fmt.Sprintf("/api/projects/%d", project.ID), fmt.Sprintf("/api/blog/%d", blog.ID)} {
+		w := doJSON(t, "PUT", path+"/autosave", accessToken, services.DraftRequest{Content: content})
+		assert.Equal(t, http.StatusOK, w.Code, path)
+
+		draft := getDraft(t, path+"/draft")
+		if assert.NotNil(t, draft.DraftContent, path) {
+			assert.Equal(t, `<p>Draft</p><img src="/a.png">`, *draft.DraftContent, path)
+		}
+	}
+}
+
+func TestAutosaveUnknownResource(t *testing.T) {
+	loginAndGetToken(t)
+
+	w := doJSON(t, "PUT", "/api/projects/999999/autosave", accessToken, services.DraftRequest{Content: "Draft"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
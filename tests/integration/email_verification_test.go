@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/clock"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/mailer"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+var verificationTokenPattern = regexp.MustCompile(`token=([0-9a-f]+)`)
+
+// registerForVerification registers a user at the given time and returns their
+// id and the verification token mailed to them
+func registerForVerification(t *testing.T, now time.Time) (uint, string) {
+	mail := mailer.NewRecording()
+	suffix := fmt.Sprint(time.Now().UnixNano())
+	tokens, err := services.NewAuthService(config).
+		WithClock(clock.NewFake(now)).
+		WithMailer(mail).
+		Register(services.RegisterRequest{
+			Name:     "Verify User",
+			Email:    "verify-" + suffix + "@example.com",
+			Phone:    "+1" + suffix[len(suffix)-10:],
+			Password: "password123",
+		})
+	assert.NoError(t, err)
+	assert.False(t, tokens.User.EmailVerified)
+	messages := mail.Messages()
+	if !assert.Len(t, messages, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, tokens.User.Email, messages[0].To)
+
+	match := verificationTokenPattern.FindStringSubmatch(messages[0].Body)
+	if !assert.Len(t, match, 2) {
+		t.FailNow()
+	}
+	return tokens.User.ID, match[1]
+}
+
+func verifyEmail(token string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/api/auth/verify-email?token="+token, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestVerifyEmail(t *testing.T) {
+	userID, token := registerForVerification(t, time.Now())
+
+	w := verifyEmail(token)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var user models.User
+	assert.NoError(t, database.DB.First(&user, userID).Error)
+	assert.True(t, user.EmailVerified)
+
+	// The token cannot be used again
+	w = verifyEmail(token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestVerifyEmailRejectsExpiredToken(t *testing.T) {
+	// Registered more than a day ago, so the token has expired
+	userID, token := registerForVerification(t, time.Now().Add(-25*time.Hour))
+
+	w := verifyEmail(token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var user models.User
+	assert.NoError(t, database.DB.First(&user, userID).Error)
+	assert.False(t, user.EmailVerified)
+}
+
+func TestVerifyEmailRequiresToken(t *testing.T) {
+	w := verifyEmail("")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,145 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func TestReorderFeaturedProjects(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := models.ProjectCategory{Name: "Featured Order Projects", Slug: "featured-order-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	// Seed three featured projects and a newer non-featured one
+	base := time.Date(2023, time.May, 1, 12, 0, 0, 0, time.UTC)
+	ids := make([]uint, 0, 4)
+	for i, featured := range []bool{true, true, true, false} {
+		project := models.Project{
+			Title:      fmt.Sprintf("Featured Order %d", i),
+			Slug:       fmt.Sprintf("featured-order-%d", i),
+			CategoryID: category.ID,
+			Featured:   featured,
+			Published:  true,
+			CreatedAt:  base.Add(time.Duration(i) * time.Hour),
+		}
+		assert.NoError(t, database.DB.Create(&project).Error)
+		ids = append(ids, project.ID)
+	}
+	first, second, third, plain := ids[0], ids[1], ids[2], ids[3]
+
+	featuredPath := fmt.Sprintf("/api/projects?category_id=%d&featured=true", category.ID)
+	allPath := fmt.Sprintf("/api/projects?category_id=%d", category.ID)
+
+	// Without an explicit order featured projects are listed newest first
+	assert.Equal(t, []uint{third, second, first}, listedProjectIDs(t, featuredPath))
+
+	w := doJSON(t, "PUT", "/api/projects/featured/reorder", accessToken, services.ReorderFeaturedRequest{IDs: []uint{first, third, second}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []uint{first, third, second}, listedProjectIDs(t, featuredPath))
+
+	// Featured projects left out of the order are listed after the ordered ones
+	w = doJSON(t, "PUT", "/api/projects/featured/reorder", accessToken, services.ReorderFeaturedRequest{IDs: []uint{second}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []uint{second, third, first}, listedProjectIDs(t, featuredPath))
+
+	// The non-featured project keeps no position and the full listing stays newest first
+	var project models.Project
+	assert.NoError(t, database.DB.First(&project, plain).Error)
+	assert.Nil(t, project.FeaturedOrder)
+	assert.Equal(t, []uint{plain, third, second, first}, listedProjectIDs(t, allPath))
+
+	// Non-featured and repeated ids are rejected without changing the order
+	for _, reorder := range [][]uint{{plain, first}, {first, first}} {
+		w = doJSON(t, "PUT", "/api/projects/featured/reorder", accessToken, services.ReorderFeaturedRequest{IDs: reorder})
+		assert.Equal(t, http.StatusBadRequest, w.Code, reorder)
+	}
+	assert.Equal(t, []uint{second, third, first}, listedProjectIDs(t, featuredPath))
+
+	// Unfeaturing a project clears its position
+	patchPath := fmt.Sprintf("/api/projects/%d", second)
+	unfeatured := false
+	w = doJSON(t, "PUT", patchPath, accessToken, services.UpdateProjectRequest{Featured: &unfeatured})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var updated models.Project
+	assert.NoError(t, database.DB.First(&updated, second).Error)
+	assert.Nil(t, updated.FeaturedOrder)
+}
+
+func TestReorderFeaturedBlogPosts(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := models.BlogCategory{Name: "Featured Order Posts", Slug: "featured-order-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	base := time.Date(2023, time.May, 1, 12, 0, 0, 0, time.UTC)
+	ids := make([]uint, 0, 2)
+	for i := 0; i < 2; i++ {
+		blog := models.BlogPost{
+			Title:      fmt.Sprintf("Featured Order Post %d", i),
+			Slug:       fmt.Sprintf("featured-order-post-%d", i),
+			CategoryID: category.ID,
+			Featured:   true,
+			Published:  true,
+			CreatedAt:  base.Add(time.Duration(i) * time.Hour),
+		}
+		assert.NoError(t, database.DB.Create(&blog).Error)
+		ids = append(ids, blog.ID)
+	}
+
+	w := doJSON(t, "PUT", "/api/blog/featured/reorder", accessToken, services.ReorderFeaturedRequest{IDs: ids})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data []struct {
+			ID            uint `json:"id"`
+			FeaturedOrder *int `json:"featured_order"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response.Data, 2) {
+		assert.Equal(t, ids[0], response.Data[0].ID)
+		assert.Equal(t, 1, *response.Data[0].FeaturedOrder)
+		assert.Equal(t, 2, *response.Data[1].FeaturedOrder)
+	}
+}
+
+func TestReorderFeaturedRequiresIDs(t *testing.T) {
+	loginAndGetToken(t)
+	w := doJSON(t, "PUT", "/api/projects/featured/reorder", accessToken, services.ReorderFeaturedRequest{})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func listedProjectIDs(t *testing.T, path string) []uint {
+	req, err := http.NewRequest("GET", path, nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data struct {
+			Projects []struct {
+				ID uint `json:"id"`
+			} `json:"projects"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	ids := make([]uint, 0, len(response.Data.Projects))
+	for _, project := range response.Data.Projects {
+		ids = append(ids, project.ID)
+	}
+	return ids
+}
+
@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+)
+
+func TestFieldsTrimProjectResponses(t *testing.T) {
+	category := models.ProjectCategory{Name: "Fields Projects", Slug: "fields-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	project := models.Project{
+		Title:       "Fields Project",
+		Slug:        "fields-project",
+		Description: "Description",
+		Content:     "Long content mobile clients do not need",
+		CategoryID:  category.ID,
+		Published:   true,
+	}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	expected := []string{"id", "slug", "title"}
+
+	// Unknown fields and blanks are ignored
+	data := getResponseData(t, fmt.Sprintf("/api/projects/%d?fields=id,title,,slug,unknown", project.ID))
+	assert.Equal(t, expected, sortedKeys(data))
+	assert.Equal(t, "fields-project", data["slug"])
+
+	data = getResponseData(t, "/api/projects/slug/fields-project?fields=id,title,slug")
+	assert.Equal(t, expected, sortedKeys(data))
+
+	// List items are trimmed while the metadata is kept
+	for _, path := range []string{
+		"/api/projects?q=Fields+Project&fields=id,title,slug",
+		"/api/projects?q=Fields+Project&cursor=&fields=id,title,slug",
+	} {
+		data = getResponseData(t, path)
+		assert.Contains(t, data, "metadata")
+		projects := data["projects"].([]interface{})
+		if assert.NotEmpty(t, projects) {
+			for _, item := range projects {
+				assert.Equal(t, expected, sortedKeys(item.(map[string]interface{})))
+			}
+		}
+	}
+
+	// Without fields the full object is returned
+	data = getResponseData(t, fmt.Sprintf("/api/projects/%d", project.ID))
+	assert.Contains(t, data, "content")
+	assert.Contains(t, data, "description")
+}
+
+func TestFieldsTrimBlogResponses(t *testing.T) {
+	category := models.BlogCategory{Name: "Fields Posts", Slug: "fields-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	blog := models.BlogPost{
+		Title:      "Fields Post",
+		Slug:       "fields-post",
+		Content:    "Long content mobile clients do not need",
+		CategoryID: category.ID,
+		Published:  true,
+	}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	expected := []string{"id", "slug", "title"}
+
+	data := getResponseData(t, fmt.Sprintf("/api/blog/%d?fields=id,title,slug", blog.ID))
+	assert.Equal(t, expected, sortedKeys(data))
+
+	data = getResponseData(t, "/api/blog?q=Fields+Post&fields=id,title,slug")
+	assert.Contains(t, data, "metadata")
+	blogs := data["blogs"].([]interface{})
+	if assert.NotEmpty(t, blogs) {
+		for _, item := range blogs {
+			assert.Equal(t, expected, sortedKeys(item.(map[string]interface{})))
+		}
+	}
+}
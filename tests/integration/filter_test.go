@@ -0,0 +1,268 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func TestListFilterParity(t *testing.T) {
+	// Create a tag shared by a project and a blog post
+	tag := models.Tag{Name: "Parity Tag", Slug: "parity-tag"}
+	assert.NoError(t, database.DB.Create(&tag).Error)
+
+	projectCategory := models.ProjectCategory{Name: "Parity Projects", Slug: "parity-projects"}
+	assert.NoError(t, database.DB.Create(&projectCategory).Error)
+	blogCategory := models.BlogCategory{Name: "Parity Posts", Slug: "parity-posts"}
+	assert.NoError(t, database.DB.Create(&blogCategory).Error)
+
+	// Create a matching project and blog post directly in the database
+	project := models.Project{
+		Title:       "Parity Project",
+		Slug:        "parity-project",
+		Description: "Searchable parityneedle description",
+		Content:     "Content",
+		CategoryID:  projectCategory.ID,
+		Tags:        []models.Tag{tag},
+		Featured:    true,
+		Published:   true,
+	}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	blog := models.BlogPost{
+		Title:      "Parity Post",
+		Slug:       "parity-post",
+		Excerpt:    "Searchable parityneedle excerpt",
+		Content:    "Content",
+		CategoryID: blogCategory.ID,
+		Tags:       []models.Tag{tag},
+		Featured:   true,
+		Published:  true,
+	}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	// The same filters select the same content on both listings
+	for _, query := range []string{
+		"?tag=parity-tag",
+		"?q=parityneedle",
+		"?tag=parity-tag&featured=true&q=parityneedle",
+	} {
+		assert.Equal(t, float64(1), listTotal(t, "/api/projects"+query), query)
+		assert.Equal(t, float64(1), listTotal(t, "/api/blog"+query), query)
+	}
+
+	// Filters that match nothing return nothing on both listings
+	for _, query := range []string{"?tag=missing-tag", "?q=parityneedle&tag=missing-tag"} {
+		assert.Equal(t, float64(0), listTotal(t, "/api/projects"+query), query)
+		assert.Equal(t, float64(0), listTotal(t, "/api/blog"+query), query)
+	}
+}
+
+func TestListCreatedAtRange(t *testing.T) {
+	category := models.ProjectCategory{Name: "Range Projects", Slug: "range-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	// Seed projects created on different days
+	for _, day := range []int{1, 10, 20} {
+		slug := fmt.Sprintf("range-project-%d", day)
+		project := models.Project{
+			Title:      slug,
+			Slug:       slug,
+			CategoryID: category.ID,
+			Published:  true,
+			CreatedAt:  time.Date(2020, time.March, day, 12, 0, 0, 0, time.UTC),
+		}
+		assert.NoError(t, database.DB.Create(&project).Error)
+	}
+
+	base := fmt.Sprintf("/api/projects?category_id=%d", category.ID)
+	for query, expected := range map[string]float64{
+		"":                                     3,
+		"&created_after=2020-03-05T00:00:00Z":  2,
+		"&created_before=2020-03-15T00:00:00Z": 2,
+		"&created_after=2020-03-05T00:00:00Z&created_before=2020-03-15T00:00:00Z": 1,
+		"&created_after=2020-04-01T00:00:00Z":                                     0,
+	} {
+		assert.Equal(t, expected, listTotal(t, base+query), query)
+	}
+
+	// Invalid dates are rejected on both the list and count endpoints
+	for _, path := range []string{"/api/projects?created_after=yesterday", "/api/blog/count?created_before=2020-03-15"} {
+		req, err := http.NewRequest("GET", path, nil)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
+
+func TestFilterByMultipleCategories(t *testing.T) {
+	var categories []models.ProjectCategory
+	for _, name := range []string{"Multi Web", "Multi Mobile", "Multi Games"} {
+		category := models.ProjectCategory{Name: name, Slug: strings.ToLower(strings.ReplaceAll(name, " ", "-"))}
+		assert.NoError(t, database.DB.Create(&category).Error)
+		categories = append(categories, category)
+
+		// Two projects per category, one of them featured
+		for i, featured := range []bool{true, false} {
+			slug := fmt.Sprintf("%s-project-%d", category.Slug, i)
+			project := models.Project{Title: slug, Slug: slug, CategoryID: category.ID, Published: true, Featured: featured}
+			assert.NoError(t, database.DB.Create(&project).Error)
+		}
+	}
+	web, mobile, games := categories[0].ID, categories[1].ID, categories[2].ID
+
+	// Repeated category_id and comma-separated category_ids return the union
+	for path, expected := range map[string]float64{
+		fmt.Sprintf("/api/projects?category_id=%d", web):                           2,
+		fmt.Sprintf("/api/projects?category_id=%d&category_id=%d", web, mobile):    4,
+		fmt.Sprintf("/api/projects?category_ids=%d,%d,%d", web, mobile, games):     6,
+		fmt.Sprintf("/api/projects?category_id=%d&category_ids=%d", web, games):    4,
+		fmt.Sprintf("/api/projects?category_ids=%d,%d&featured=true", web, mobile): 2,
+		fmt.Sprintf("/api/projects?category_ids=%d,%d&q=multi-games", mobile, web): 0,
+	} {
+		assert.Equal(t, expected, listTotal(t, path), path)
+	}
+
+	// Every ID has to be a number
+	for _, path := range []string{
+		fmt.Sprintf("/api/projects?category_ids=%d,web", web),
+		fmt.Sprintf("/api/projects?category_id=%d&category_id=web", web),
+	} {
+		req, err := http.NewRequest("GET", path, nil)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
+
+func listTotal(t *testing.T, path string) interface{} {
+	return authorizedListTotal(t, path, "")
+}
+
+func authorizedListTotal(t *testing.T, path, token string) interface{} {
+	req, err := http.NewRequest("GET", path, nil)
+	assert.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	return response["data"].(map[string]interface{})["metadata"].(map[string]interface{})["total"]
+}
+
+// registerAuthor registers a user with the default role and returns their token
+func registerAuthor(t *testing.T, name string) *services.TokenResponse {
+	suffix := fmt.Sprint(time.Now().UnixNano())
+	tokens, err := services.NewAuthService(config).Register(services.RegisterRequest{
+		Name:     name,
+		Email:    "author-" + suffix + "@example.com",
+		Phone:    "+1" + suffix[len(suffix)-10:],
+		Password: "password123",
+	})
+	assert.NoError(t, err)
+	return tokens
+}
+
+func TestAuthorsListOnlyTheirOwnDrafts(t *testing.T) {
+	author := registerAuthor(t, "Draft Author")
+	other := registerAuthor(t, "Other Author")
+
+	category := models.BlogCategory{Name: "Author Drafts", Slug: "author-drafts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	for _, owner := range []*services.TokenResponse{author, other} {
+		blog := models.BlogPost{
+			Title:      owner.User.Name + " Draft",
+			Slug:       "author-draft-" + fmt.Sprint(owner.User.ID),
+			Content:    "<p>Draft</p>",
+			CategoryID: category.ID,
+			CreatedBy:  owner.User.ID,
+		}
+		assert.NoError(t, database.DB.Create(&blog).Error)
+		assert.NoError(t, database.DB.Model(&blog).Update("published", false).Error)
+	}
+
+	// The author sees their own draft and not the other author's
+	assert.Equal(t, float64(1), authorizedListTotal(t, "/api/blog?published=false", author.AccessToken))
+
+	req, err := http.NewRequest("GET", "/api/blog?published=false", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", author.AccessToken))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Data struct {
+			Blogs []services.BlogResponse `json:"blogs"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response.Data.Blogs, 1) {
+		assert.Equal(t, "Draft Author Draft", response.Data.Blogs[0].Title)
+	}
+
+	// Admins still see every draft
+	loginAndGetToken(t)
+	assert.GreaterOrEqual(t, authorizedListTotal(t, "/api/blog?published=false", accessToken), float64(2))
+
+	// Anonymous requests only ever see published posts
+	assert.Equal(t, listTotal(t, "/api/blog"), listTotal(t, "/api/blog?published=false"))
+}
+
+func TestDefaultListingIncludesDraftsForEditors(t *testing.T) {
+	loginAndGetToken(t)
+
+	projectCategory := models.ProjectCategory{Name: "Default Status", Slug: "default-status"}
+	assert.NoError(t, database.DB.Create(&projectCategory).Error)
+	blogCategory := models.BlogCategory{Name: "Default Status", Slug: "default-status"}
+	assert.NoError(t, database.DB.Create(&blogCategory).Error)
+
+	for i, published := range []bool{true, false} {
+		slug := fmt.Sprintf("default-status-%d", i)
+		project := models.Project{Title: "Default Status", Slug: slug, Content: "Content", CategoryID: projectCategory.ID, CreatedBy: 1}
+		assert.NoError(t, database.DB.Create(&project).Error)
+		assert.NoError(t, database.DB.Model(&project).Update("published", published).Error)
+
+		post := models.BlogPost{Title: "Default Status", Slug: slug, Content: "Content", CategoryID: blogCategory.ID, CreatedBy: 1}
+		assert.NoError(t, database.DB.Create(&post).Error)
+		assert.NoError(t, database.DB.Model(&post).Update("published", published).Error)
+	}
+
+	projects := fmt.Sprintf("/api/projects?category_id=%d", projectCategory.ID)
+	blogs := fmt.Sprintf("/api/blog?category_id=%d", blogCategory.ID)
+	for _, path := range []string{projects, blogs} {
+		// Anonymous listings hold the published item only, even after an editor listed drafts
+		assert.Equal(t, float64(1), listTotal(t, path), path)
+
+		// Admins and editors see the draft as well unless they ask for one status
+		assert.Equal(t, float64(2), authorizedListTotal(t, path, accessToken), path)
+		assert.Equal(t, float64(1), authorizedListTotal(t, path+"&published=true", accessToken), path)
+		assert.Equal(t, float64(1), authorizedListTotal(t, path+"&published=false", accessToken), path)
+		assert.Equal(t, float64(1), listTotal(t, path), path)
+	}
+
+	// Signed-in users without an editor role still default to published items
+	author := registerAuthor(t, "Default Status Author")
+	assert.Equal(t, float64(1), authorizedListTotal(t, projects, author.AccessToken))
+	assert.Equal(t, float64(1), authorizedListTotal(t, blogs, author.AccessToken))
+}
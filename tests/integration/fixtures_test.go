@@ -0,0 +1,100 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+// Fixtures that tests refer to by ID or log in with
+const (
+	fixtureAdminPhone    = "+1234567890"
+	fixtureAdminPassword = "password123"
+	fixtureCategoryID    = 1
+	fixtureTagID         = 1
+)
+
+// seedFixtures creates the roles, the admin account, and the project category
+// and tag the tests assume. Rows that already exist are left alone, so seeding
+// a database twice changes nothing.
+func seedFixtures() error {
+	roles := []models.Role{
+		{ID: models.RoleAdmin, Name: "admin"},
+		{ID: models.RoleEditor, Name: "editor"},
+		{ID: models.RoleUser, Name: "user"},
+	}
+	for _, role := range roles {
+		if err := database.DB.Where(models.Role{ID: role.ID}).FirstOrCreate(&role).Error; err != nil {
+			return err
+		}
+	}
+
+	admin := models.User{
+		Name:          "Admin",
+		Email:         "admin@example.com",
+		Phone:         fixtureAdminPhone,
+		Password:      fixtureAdminPassword,
+		RoleID:        models.RoleAdmin,
+		EmailVerified: true,
+	}
+	if err := database.DB.Where(models.User{Phone: admin.Phone}).FirstOrCreate(&admin).Error; err != nil {
+		return err
+	}
+
+	category := models.ProjectCategory{ID: fixtureCategoryID, Name: "Test Category", Slug: "test-category"}
+	if err := database.DB.Where(models.ProjectCategory{ID: category.ID}).FirstOrCreate(&category).Error; err != nil {
+		return err
+	}
+
+	tag := models.Tag{ID: fixtureTagID, Name: "Test Tag", Slug: "test-tag"}
+	return database.DB.Where(models.Tag{ID: tag.ID}).FirstOrCreate(&tag).Error
+}
+
+// cleanupFixtures drops the tables the suite created, so the next run starts
+// from an empty database. An in-memory database goes away on its own.
+func cleanupFixtures() error {
+	if config.Database.Name == ":memory:" {
+		return nil
+	}
+
+	tables, err := database.DB.Migrator().GetTables()
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if err := database.DB.Migrator().DropTable(table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestSeedFixturesIsIdempotent(t *testing.T) {
+	// TestMain already seeded the database once
+	assert.NoError(t, seedFixtures())
+	assert.NoError(t, seedFixtures())
+
+	var roles int64
+	assert.NoError(t, database.DB.Model(&models.Role{}).Count(&roles).Error)
+	assert.Equal(t, int64(3), roles)
+
+	var admins int64
+	assert.NoError(t, database.DB.Model(&models.User{}).Where("phone = ?", fixtureAdminPhone).Count(&admins).Error)
+	assert.Equal(t, int64(1), admins)
+
+	var categories int64
+	assert.NoError(t, database.DB.Model(&models.ProjectCategory{}).Where("slug = ?", "test-category").Count(&categories).Error)
+	assert.Equal(t, int64(1), categories)
+
+	var tags int64
+	assert.NoError(t, database.DB.Model(&models.Tag{}).Where("slug = ?", "test-tag").Count(&tags).Error)
+	assert.Equal(t, int64(1), tags)
+
+	// The admin can still log in with the seeded password
+	w := doJSON(t, "POST", "/api/auth/login", "", services.LoginRequest{Phone: fixtureAdminPhone, Password: fixtureAdminPassword})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,84 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func TestDeleteProjectRemovesTagRows(t *testing.T) {
+	// Login to get an access token
+	loginAndGetToken(t)
+
+	// Create a tag and a category directly in the database
+	tag := models.Tag{Name: "Integrity Tag", Slug: "integrity-tag"}
+	assert.NoError(t, database.DB.Create(&tag).Error)
+	category := models.ProjectCategory{Name: "Integrity Category", Slug: "integrity-category"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	// Create a tagged project
+	jsonData, err := json.Marshal(services.CreateProjectRequest{
+		Title:       "Integrity Project",
+		Description: "Project used to check join-table cleanup",
+		Content:     "Content",
+		CategoryID:  category.ID,
+		TagIDs:      []uint{tag.ID},
+		Published:   true,
+	})
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest("POST", "/api/projects", bytes.NewBuffer(jsonData))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	id := uint(response["data"].(map[string]interface{})["id"].(float64))
+
+	var count int64
+	database.DB.Table("project_tags").Where("project_id = ?", id).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	// Delete the project
+	req, err = http.NewRequest("DELETE", fmt.Sprintf("/api/projects/%d", id), nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	// No join rows should remain for the deleted project
+	database.DB.Table("project_tags").Where("project_id = ?", id).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestRepairOrphanedJoinRows(t *testing.T) {
+	// Insert orphaned rows pointing at a project that does not exist
+	assert.NoError(t, database.DB.Exec("INSERT INTO project_tags (project_id, tag_id) VALUES (?, ?)", 999999, 999999).Error)
+	assert.NoError(t, database.DB.Exec("INSERT INTO project_technologies (project_id, technology_id) VALUES (?, ?)", 999999, 999999).Error)
+
+	removed, err := database.RepairOrphanedJoinRows()
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, removed, int64(2))
+
+	var count int64
+	database.DB.Table("project_tags").Where("project_id = ?", 999999).Count(&count)
+	assert.Equal(t, int64(0), count)
+	database.DB.Table("project_technologies").Where("project_id = ?", 999999).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
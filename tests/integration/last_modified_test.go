@@ -0,0 +1,72 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+)
+
+// getLastModified requests a last-modified endpoint with an optional If-Modified-Since header
+func getLastModified(t *testing.T, method, path, ifModifiedSince string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, path, nil)
+	assert.NoError(t, err)
+	if ifModifiedSince != "" {
+		req.Header.Set("If-Modified-Since", ifModifiedSince)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestLastModifiedFollowsEdits(t *testing.T) {
+	loginAndGetToken(t)
+
+	blogCategory := models.BlogCategory{Name: "Last Modified Posts", Slug: "last-modified-posts"}
+	assert.NoError(t, database.DB.Create(&blogCategory).Error)
+	post := models.BlogPost{Title: "Last Modified Post", Slug: "last-modified-post", Content: "Content", CategoryID: blogCategory.ID, Published: true}
+	assert.NoError(t, database.DB.Create(&post).Error)
+
+	project := models.Project{Title: "Last Modified Project", Slug: "last-modified-project", CategoryID: fixtureCategoryID, Published: true}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	tests := []struct {
+		path   string
+		model  interface{}
+		update string
+	}{
+		{"/api/blog/last-modified", &models.BlogPost{}, fmt.Sprintf("/api/blog/%d", post.ID)},
+		{"/api/projects/last-modified", &models.Project{}, fmt.Sprintf("/api/projects/%d", project.ID)},
+	}
+
+	for _, tt := range tests {
+		// Date every published item back, so the edit below is the latest change
+		before := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+		assert.NoError(t, database.DB.Model(tt.model).Where("published = ?", true).UpdateColumn("updated_at", before).Error)
+
+		w := getLastModified(t, "GET", tt.path, "")
+		assert.Equal(t, http.StatusOK, w.Code, tt.path)
+		assert.Equal(t, before.Format(http.TimeFormat), w.Header().Get("Last-Modified"), tt.path)
+
+		// A client holding the current list does not need to refetch it
+		w = getLastModified(t, "GET", tt.path, before.Format(http.TimeFormat))
+		assert.Equal(t, http.StatusNotModified, w.Code, tt.path)
+
+		title := "Edited " + tt.path
+		w = doJSON(t, "PUT", tt.update, accessToken, map[string]interface{}{"title": title})
+		assert.Equal(t, http.StatusOK, w.Code, tt.path)
+
+		// HEAD reports the edit without a body
+		w = getLastModified(t, "HEAD", tt.path, before.Format(http.TimeFormat))
+		assert.Equal(t, http.StatusOK, w.Code, tt.path)
+		lastModified, err := http.ParseTime(w.Header().Get("Last-Modified"))
+		assert.NoError(t, err, tt.path)
+		assert.True(t, lastModified.After(before), tt.path)
+	}
+}
@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+	"gorm.io/gorm"
+)
+
+// reactToBlog likes or unlikes a blog post from the given client address
+func reactToBlog(t *testing.T, method string, blogID uint, clientIP string) (int, services.LikeResponse) {
+	req, err := http.NewRequest(method, fmt.Sprintf("/api/blog/%d/like", blogID), nil)
+	assert.NoError(t, err)
+	req.RemoteAddr = clientIP + ":40000"
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Data services.LikeResponse `json:"data"`
+	}
+	if w.Code == http.StatusOK {
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	}
+
+	return w.Code, response.Data
+}
+
+func TestRepeatedLikeFromSameClientIsIgnored(t *testing.T) {
+	category := models.BlogCategory{Name: "Liked Posts", Slug: "liked-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	blog := models.BlogPost{Title: "Liked Post", Slug: "liked-post", Content: "<p>Content</p>", CategoryID: category.ID, Published: true}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	code, likes := reactToBlog(t, "POST", blog.ID, "203.0.113.10")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, services.LikeResponse{Likes: 1, Counted: true}, likes)
+
+	// A second like from the same client within the window is not counted
+	code, likes = reactToBlog(t, "POST", blog.ID, "203.0.113.10")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, services.LikeResponse{Likes: 1, Counted: false}, likes)
+
+	// Another client is counted
+	_, likes = reactToBlog(t, "POST", blog.ID, "203.0.113.11")
+	assert.Equal(t, uint(2), likes.Likes)
+
+	// Unlikes are guarded the same way
+	_, likes = reactToBlog(t, "DELETE", blog.ID, "203.0.113.10")
+	assert.Equal(t, services.LikeResponse{Likes: 1, Counted: true}, likes)
+	_, likes = reactToBlog(t, "DELETE", blog.ID, "203.0.113.10")
+	assert.Equal(t, services.LikeResponse{Likes: 1, Counted: false}, likes)
+
+	// The count is part of the blog response
+	detail := getResponseData(t, fmt.Sprintf("/api/blog/%d", blog.ID))
+	assert.Equal(t, float64(1), detail["likes"])
+
+	// Drafts cannot be liked
+	assert.NoError(t, database.DB.Model(&blog).Update("published", false).Error)
+	code, _ = reactToBlog(t, "POST", blog.ID, "203.0.113.12")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestUnlikeWithoutRememberedLikeIsIgnored(t *testing.T) {
+	category := models.BlogCategory{Name: "Unliked Posts", Slug: "unliked-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	blog := models.BlogPost{Title: "Unliked Post", Slug: "unliked-post", Content: "<p>Content</p>", CategoryID: category.ID, Published: true}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	_, likes := reactToBlog(t, "POST", blog.ID, "203.0.113.20")
+	assert.Equal(t, services.LikeResponse{Likes: 1, Counted: true}, likes)
+
+	// Clients that did not like the post cannot take away other readers' likes
+	for _, clientIP := range []string{"203.0.113.21", "203.0.113.22"} {
+		code, likes := reactToBlog(t, "DELETE", blog.ID, clientIP)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, services.LikeResponse{Likes: 1, Counted: false}, likes)
+	}
+}
+
+func TestConcurrentLikesFromSameClientCountOnce(t *testing.T) {
+	category := models.BlogCategory{Name: "Raced Posts", Slug: "raced-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	blog := models.BlogPost{Title: "Raced Post", Slug: "raced-post", Content: "<p>Content</p>", CategoryID: category.ID, Published: true}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	// Slow the counter update down so that likes arriving together would all
+	// pass the remembered reaction check before any of them is remembered
+	slowUpdate := func(db *gorm.DB) {
+		if db.Statement.Table == "blog_posts" {
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+	assert.NoError(t, database.DB.Callback().Update().After("gorm:update").Register("test:slow_like", slowUpdate))
+	defer database.DB.Callback().Update().Remove("test:slow_like")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			code, _ := reactToBlog(t, "POST", blog.ID, "203.0.113.30")
+			assert.Equal(t, http.StatusOK, code)
+		}()
+	}
+	wg.Wait()
+
+	var stored models.BlogPost
+	assert.NoError(t, database.DB.First(&stored, blog.ID).Error)
+	assert.Equal(t, uint(1), stored.Likes)
+}
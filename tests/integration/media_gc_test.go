@@ -0,0 +1,145 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/storage"
+)
+
+// collectGarbage runs the media garbage collection and returns its result
+func collectGarbage(t *testing.T, query string) services.MediaGCResponse {
+	w := doJSON(t, "POST", "/api/media/gc"+query, accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data services.MediaGCResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response.Data
+}
+
+func TestCollectGarbageRemovesOrphanedFiles(t *testing.T) {
+	if config.Storage.Driver != "local" {
+		t.Skip("requires the local storage driver")
+	}
+	loginAndGetToken(t)
+
+	store := storage.New(config)
+	stored := map[string]string{
+		"media":    "2020/01/gc-media.png",
+		"webp":     "2020/01/gc-media.webp",
+		"og":       "2020/01/gc-og.png",
+		"content":  "2020/01/gc-content.pdf",
+		"orphan":   "2020/01/gc-orphan.png",
+		"recent":   "2020/01/gc-recent.png",
+		"external": "2020/01/gc-external.png",
+		"wildcard": "2020/01/gc_wildcard.png",
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	for name, key := range stored {
+		assert.NoError(t, store.Save(key, strings.NewReader(name), ""))
+		if name != "recent" {
+			path := filepath.Join(config.Storage.LocalPath, filepath.FromSlash(key))
+			assert.NoError(t, os.Chtimes(path, old, old))
+		}
+	}
+
+	// Media, SEO images and content keep their files. The WebP derivative
+	// belongs to the media image.
+	category := models.BlogCategory{Name: "GC Posts", Slug: "gc-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	post := models.BlogPost{
+		Title:      "GC Post",
+		Slug:       "gc-post",
+		Content:    `<a href="` + store.URL(stored["content"]) + `">Slides</a><img src="` + store.URL("2020/01/gcXwildcard.png") + `">`,
+		OGImage:    store.URL(stored["og"]),
+		CategoryID: category.ID,
+	}
+	assert.NoError(t, database.DB.Create(&post).Error)
+	assert.NoError(t, database.DB.Create(&models.BlogMedia{BlogID: post.ID, URL: store.URL(stored["media"])}).Error)
+
+	// LIKE wildcards in a key match literally, so the image above does not keep
+	// the wildcard file. A URL of another host does not reference the stored
+	// file of the same name either.
+	assert.NoError(t, database.DB.Create(&models.BlogMedia{BlogID: post.ID, URL: "https://cdn.example.com/" + stored["external"]}).Error)
+
+	// A dry run reports the orphans without deleting them
+	result := collectGarbage(t, "?dry_run=true")
+	assert.True(t, result.DryRun)
+	assert.Equal(t, []string{stored["external"], stored["orphan"], stored["wildcard"]}, result.Keys)
+	assert.Equal(t, 3, result.Removed)
+	assert.GreaterOrEqual(t, result.Scanned, len(stored))
+	for _, key := range stored {
+		_, err := os.Stat(filepath.Join(config.Storage.LocalPath, filepath.FromSlash(key)))
+		assert.NoError(t, err, key)
+	}
+
+	result = collectGarbage(t, "")
+	assert.False(t, result.DryRun)
+	assert.Equal(t, 3, result.Removed)
+	for name, key := range stored {
+		_, err := os.Stat(filepath.Join(config.Storage.LocalPath, filepath.FromSlash(key)))
+		if name == "orphan" || name == "external" || name == "wildcard" {
+			assert.True(t, os.IsNotExist(err), key)
+		} else {
+			assert.NoError(t, err, key)
+		}
+	}
+
+	// Nothing is left to collect
+	assert.Equal(t, 0, collectGarbage(t, "").Removed)
+
+	// Malformed flags are rejected
+	w := doJSON(t, "POST", "/api/media/gc?dry_run=maybe", accessToken, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCollectGarbageKeepsResumeAndTechnologyImages(t *testing.T) {
+	if config.Storage.Driver != "local" {
+		t.Skip("requires the local storage driver")
+	}
+	loginAndGetToken(t)
+
+	store := storage.New(config)
+	stored := map[string]string{
+		"resume":     "2020/02/gc-resume-project.png",
+		"technology": "2020/02/gc-technology.svg",
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	for name, key := range stored {
+		assert.NoError(t, store.Save(key, strings.NewReader(name), ""))
+		path := filepath.Join(config.Storage.LocalPath, filepath.FromSlash(key))
+		assert.NoError(t, os.Chtimes(path, old, old))
+	}
+
+	// Each file is referenced only by the image of a resume project or the
+	// icon of a technology
+	assert.NoError(t, database.DB.Create(&models.ResumeProject{
+		Title:       "GC Resume Project",
+		Description: "Keeps its image",
+		ImageURL:    store.URL(stored["resume"]),
+		StartDate:   time.Now(),
+	}).Error)
+	assert.NoError(t, database.DB.Create(&models.Technology{
+		Name: "GC Technology",
+		Slug: "gc-technology",
+		Icon: store.URL(stored["technology"]),
+	}).Error)
+
+	result := collectGarbage(t, "")
+	for _, key := range stored {
+		assert.NotContains(t, result.Keys, key)
+		_, err := os.Stat(filepath.Join(config.Storage.LocalPath, filepath.FromSlash(key)))
+		assert.NoError(t, err, key)
+	}
+}
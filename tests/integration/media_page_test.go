@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+)
+
+// mediaIDs returns the ids of the media in a detail response, in order
+func mediaIDs(data map[string]interface{}) []uint {
+	ids := []uint{}
+	for _, item := range data["media"].([]interface{}) {
+		ids = append(ids, uint(item.(map[string]interface{})["id"].(float64)))
+	}
+	return ids
+}
+
+func TestProjectDetailPaginatesMedia(t *testing.T) {
+	project := models.Project{Title: "Media Page Project", Slug: "media-page-project", CategoryID: fixtureCategoryID, Published: true}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	// Media are paged in sort order, not in insertion order
+	media := make([]models.ProjectMedia, 5)
+	for i := range media {
+		media[i] = models.ProjectMedia{ProjectID: project.ID, URL: fmt.Sprintf("https://example.com/%d.png", i), SortOrder: len(media) - i}
+		assert.NoError(t, database.DB.Create(&media[i]).Error)
+	}
+
+	data := getResponseData(t, fmt.Sprintf("/api/projects/%d?media_limit=2&media_page=2", project.ID))
+	assert.Equal(t, []uint{media[2].ID, media[1].ID}, mediaIDs(data))
+	assert.Equal(t, map[string]interface{}{
+		"total":       float64(5),
+		"page":        float64(2),
+		"limit":       float64(2),
+		"total_pages": float64(3),
+	}, data["media_metadata"])
+
+	// The last page holds the rest
+	data = getResponseData(t, "/api/projects/slug/media-page-project?media_limit=2&media_page=3")
+	assert.Equal(t, []uint{media[0].ID}, mediaIDs(data))
+
+	// Without media_limit every media item is returned, as before
+	data = getResponseData(t, fmt.Sprintf("/api/projects/%d", project.ID))
+	assert.Len(t, mediaIDs(data), 5)
+	assert.NotContains(t, data, "media_metadata")
+
+	// Malformed parameters are rejected
+	for _, query := range []string{"media_limit=0", "media_limit=two", "media_limit=2&media_page=-1"} {
+		req, err := http.NewRequest("GET", fmt.Sprintf("/api/projects/%d?%s", project.ID, query), nil)
+		assert.NoError(t, err)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestBlogDetailPaginatesMedia(t *testing.T) {
+	category := models.BlogCategory{Name: "Media Page Posts", Slug: "media-page-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	blog := models.BlogPost{Title: "Media Page Post", Slug: "media-page-post", Content: "Content", CategoryID: category.ID, Published: true}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	media := make([]models.BlogMedia, 3)
+	for i := range media {
+		media[i] = models.BlogMedia{BlogID: blog.ID, URL: fmt.Sprintf("https://example.com/post-%d.png", i), SortOrder: i}
+		assert.NoError(t, database.DB.Create(&media[i]).Error)
+	}
+
+	data := getResponseData(t, "/api/blog/slug/media-page-post?media_limit=2")
+	assert.Equal(t, []uint{media[0].ID, media[1].ID}, mediaIDs(data))
+	metadata := data["media_metadata"].(map[string]interface{})
+	assert.Equal(t, float64(3), metadata["total"])
+	assert.Equal(t, float64(1), metadata["page"])
+	assert.Equal(t, float64(2), metadata["total_pages"])
+
+	data = getResponseData(t, fmt.Sprintf("/api/blog/%d?media_limit=2&media_page=2", blog.ID))
+	assert.Equal(t, []uint{media[2].ID}, mediaIDs(data))
+}
@@ -0,0 +1,32 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+)
+
+func TestAutoMigrateCreatesListingIndexes(t *testing.T) {
+	// Migrating again is a no-op on an up to date schema
+	assert.NoError(t, database.AutoMigrate())
+
+	migrator := database.DB.Migrator()
+	for model, indexes := range map[interface{}][]string{
+		&models.Project{}: {
+			"idx_projects_category_id",
+			"idx_projects_published_featured",
+			"idx_projects_published_created",
+		},
+		&models.BlogPost{}: {
+			"idx_blog_posts_category_id",
+			"idx_blog_posts_published_featured",
+			"idx_blog_posts_published_created",
+		},
+	} {
+		for _, index := range indexes {
+			assert.True(t, migrator.HasIndex(model, index), "missing index %s", index)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package integration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/clock"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+	"gorm.io/gorm"
+)
+
+// createOutboxProject creates a project through the API and returns its ID
+func createOutboxProject(t *testing.T, title string) uint {
+	w := doJSON(t, "POST", "/api/projects", accessToken, services.CreateProjectRequest{
+		Title:       title,
+		Description: "Description",
+		Content:     "Content",
+		CategoryID:  fixtureCategoryID,
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response struct {
+		Data services.ProjectResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response.Data.ID
+}
+
+// outboxEventFor finds the outbox event of a project, or nil if there is none
+func outboxEventFor(t *testing.T, event string, projectID uint) *models.OutboxEvent {
+	var events []models.OutboxEvent
+	assert.NoError(t, database.DB.Where("event = ?", event).Order("id DESC").Find(&events).Error)
+
+	for _, outboxEvent := range events {
+		var payload struct {
+			ID uint `json:"id"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(outboxEvent.Payload), &payload))
+		if payload.ID == projectID {
+			found := outboxEvent
+			return &found
+		}
+	}
+	return nil
+}
+
+// outboxStatus reloads an outbox event
+func outboxStatus(t *testing.T, id uint) models.OutboxEvent {
+	var outboxEvent models.OutboxEvent
+	assert.NoError(t, database.DB.First(&outboxEvent, id).Error)
+	return outboxEvent
+}
+
+func TestOutboxEventIsWrittenWithTheContentChange(t *testing.T) {
+	loginAndGetToken(t)
+
+	projectID := createOutboxProject(t, "Outbox Project")
+	outboxEvent := outboxEventFor(t, services.WebhookEventProjectCreated, projectID)
+	if assert.NotNil(t, outboxEvent) {
+		assert.Len(t, outboxEvent.EventID, 32)
+		assert.Contains(t, outboxEvent.Payload, "Outbox Project")
+	}
+
+	// When the event cannot be recorded the project is not created either
+	failOutbox := func(db *gorm.DB) {
+		if db.Statement.Table == (models.OutboxEvent{}).TableName() {
+			db.AddError(errors.New("outbox unavailable"))
+		}
+	}
+	assert.NoError(t, database.DB.Callback().Create().Before("gorm:create").Register("test:fail_outbox", failOutbox))
+	w := doJSON(t, "POST", "/api/projects", accessToken, services.CreateProjectRequest{
+		Title:       "Outbox Rolled Back",
+		Description: "Description",
+		Content:     "Content",
+		CategoryID:  fixtureCategoryID,
+	})
+	assert.NoError(t, database.DB.Callback().Create().Remove("test:fail_outbox"))
+	assert.NotEqual(t, http.StatusCreated, w.Code)
+
+	var count int64
+	assert.NoError(t, database.DB.Model(&models.Project{}).Where("title = ?", "Outbox Rolled Back").Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestMediaChangesAnnounceTheParentUpdate(t *testing.T) {
+	loginAndGetToken(t)
+
+	projectID := createOutboxProject(t, "Outbox Media Project")
+
+	// Each media change is followed by an updated event carrying the new media
+	w := doJSON(t, "POST", fmt.Sprintf("/api/projects/%d/media", projectID), accessToken,
+		services.ProjectMediaRequest{URL: "https://example.com/outbox.png", Caption: "Added caption"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response struct {
+		Data services.ProjectMediaResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	outboxEvent := outboxEventFor(t, services.WebhookEventProjectUpdated, projectID)
+	if assert.NotNil(t, outboxEvent) {
+		assert.Contains(t, outboxEvent.Payload, "Added caption")
+	}
+
+	w = doJSON(t, "PATCH", fmt.Sprintf("/api/projects/media/%d", response.Data.ID), accessToken, []byte(`{"caption":"Changed caption"}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+	outboxEvent = outboxEventFor(t, services.WebhookEventProjectUpdated, projectID)
+	if assert.NotNil(t, outboxEvent) {
+		assert.Contains(t, outboxEvent.Payload, "Changed caption")
+	}
+
+	w = doJSON(t, "DELETE", fmt.Sprintf("/api/projects/media/%d", response.Data.ID), accessToken, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	outboxEvent = outboxEventFor(t, services.WebhookEventProjectUpdated, projectID)
+	if assert.NotNil(t, outboxEvent) {
+		assert.NotContains(t, outboxEvent.Payload, "https://example.com/outbox.png")
+	}
+
+	w = doJSON(t, "POST", fmt.Sprintf("/api/projects/%d/media/batch", projectID), accessToken, services.BatchProjectMediaRequest{
+		Media: []services.ProjectMediaRequest{{URL: "https://example.com/outbox-batch.png"}},
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	outboxEvent = outboxEventFor(t, services.WebhookEventProjectUpdated, projectID)
+	if assert.NotNil(t, outboxEvent) {
+		assert.Contains(t, outboxEvent.Payload, "https://example.com/outbox-batch.png")
+	}
+
+	// Blog posts are announced the same way
+	category := models.BlogCategory{Name: "Outbox Media Posts", Slug: "outbox-media-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	blog := models.BlogPost{Title: "Outbox Media Post", Slug: "outbox-media-post", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	w = doJSON(t, "POST", fmt.Sprintf("/api/blog/%d/media", blog.ID), accessToken,
+		services.BlogMediaRequest{URL: "https://example.com/outbox-post.png"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	outboxEvent = outboxEventFor(t, services.WebhookEventBlogUpdated, blog.ID)
+	if assert.NotNil(t, outboxEvent) {
+		assert.Contains(t, outboxEvent.Payload, "https://example.com/outbox-post.png")
+	}
+}
+
+func TestOutboxWorkerMarksDeliveredEventsSent(t *testing.T) {
+	loginAndGetToken(t)
+
+	server, deliveries := webhookReceiver(t, 0)
+	subscribeWebhook(t, server.URL, "outbox-delivered-secret", services.WebhookEventProjectCreated)
+
+	projectID := createOutboxProject(t, "Outbox Delivered")
+	outboxEvent := outboxEventFor(t, services.WebhookEventProjectCreated, projectID)
+	if !assert.NotNil(t, outboxEvent) {
+		return
+	}
+
+	// The delivery carries the event's id, and the event is then marked sent
+	delivery := nextDelivery(t, deliveries)
+	assert.Equal(t, outboxEvent.EventID, delivery.header.Get(services.WebhookDeliveryHeader))
+
+	assert.Eventually(t, func() bool {
+		return outboxStatus(t, outboxEvent.ID).Status == models.OutboxStatusSent
+	}, 5*time.Second, 20*time.Millisecond)
+
+	sent := outboxStatus(t, outboxEvent.ID)
+	assert.NotNil(t, sent.SentAt)
+	assert.Equal(t, 1, sent.Attempts)
+	assert.Empty(t, sent.LastError)
+}
+
+func TestOutboxWorkerGivesUpAfterMaxAttempts(t *testing.T) {
+	loginAndGetToken(t)
+
+	server, _ := webhookReceiver(t, 1000)
+	subscribeWebhook(t, server.URL, "outbox-failing-secret", services.WebhookEventProjectCreated)
+
+	projectID := createOutboxProject(t, "Outbox Failing")
+	outboxEvent := outboxEventFor(t, services.WebhookEventProjectCreated, projectID)
+	if !assert.NotNil(t, outboxEvent) {
+		return
+	}
+
+	assert.Eventually(t, func() bool {
+		return outboxStatus(t, outboxEvent.ID).Status == models.OutboxStatusFailed
+	}, 5*time.Second, 20*time.Millisecond)
+
+	failed := outboxStatus(t, outboxEvent.ID)
+	assert.Equal(t, config.Webhook.MaxAttempts, failed.Attempts)
+	assert.Contains(t, failed.LastError, "unexpected status 500")
+	assert.Nil(t, failed.SentAt)
+}
+
+// dueOutboxEvent records a project event that falls due an hour from now, out
+// of reach of the background worker
+func dueOutboxEvent(t *testing.T, eventID string) models.OutboxEvent {
+	outboxEvent := models.OutboxEvent{
+		EventID:       eventID,
+		Event:         services.WebhookEventProjectCreated,
+		Payload:       `{"id":0}`,
+		Status:        models.OutboxStatusPending,
+		NextAttemptAt: time.Now().Add(time.Hour),
+	}
+	assert.NoError(t, database.DB.Create(&outboxEvent).Error)
+	return outboxEvent
+}
+
+func TestOutboxWorkerUsesItsClock(t *testing.T) {
+	loginAndGetToken(t)
+
+	server, deliveries := webhookReceiver(t, 0)
+	subscribeWebhook(t, server.URL, "outbox-clock-secret", services.WebhookEventProjectCreated)
+	outboxEvent := dueOutboxEvent(t, "outboxclock00000000000000000000a")
+
+	fake := clock.NewFake(time.Now())
+	worker := services.NewOutboxWorker(services.NewWebhookDispatcher(config.Webhook), 100).WithClock(fake)
+
+	// The event is not due yet by the worker's clock
+	_, err := worker.Process()
+	assert.NoError(t, err)
+	assert.Equal(t, models.OutboxStatusPending, outboxStatus(t, outboxEvent.ID).Status)
+
+	fake.Advance(2 * time.Hour)
+	_, err = worker.Process()
+	assert.NoError(t, err)
+
+	delivery := nextDelivery(t, deliveries)
+	assert.Equal(t, outboxEvent.EventID, delivery.header.Get(services.WebhookDeliveryHeader))
+	sent := outboxStatus(t, outboxEvent.ID)
+	assert.Equal(t, models.OutboxStatusSent, sent.Status)
+	if assert.NotNil(t, sent.SentAt) {
+		assert.WithinDuration(t, fake.Now(), *sent.SentAt, time.Second)
+	}
+}
+
+func TestOutboxWorkersClaimEachEventOnce(t *testing.T) {
+	loginAndGetToken(t)
+
+	// The first delivery is held until the other workers have run
+	var delivered int32
+	received := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&delivered, 1) == 1 {
+			close(received)
+			<-release
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	subscribeWebhook(t, server.URL, "outbox-claim-secret", services.WebhookEventProjectCreated)
+	outboxEvent := dueOutboxEvent(t, "outboxclaim00000000000000000000a")
+
+	fake := clock.NewFake(time.Now().Add(2 * time.Hour))
+	newWorker := func() *services.OutboxWorker {
+		return services.NewOutboxWorker(services.NewWebhookDispatcher(config.Webhook), 100).WithClock(fake)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := newWorker().Process()
+		assert.NoError(t, err)
+	}()
+	<-received
+
+	// Workers of other instances find the event claimed while it is delivered
+	for i := 0; i < 3; i++ {
+		_, err := newWorker().Process()
+		assert.NoError(t, err)
+	}
+	close(release)
+	<-done
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&delivered))
+	claimed := outboxStatus(t, outboxEvent.ID)
+	assert.Equal(t, models.OutboxStatusSent, claimed.Status)
+	assert.Equal(t, 1, claimed.Attempts)
+}
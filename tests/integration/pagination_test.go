@@ -0,0 +1,181 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/configs"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func TestPaginationLimits(t *testing.T) {
+	// Use distinct limits for the duration of this test
+	previous := config.Pagination
+	config.Pagination = configs.PaginationConfig{
+		PageLimits: configs.PageLimits{DefaultLimit: 7, MaxLimit: 20},
+		Resources: map[string]configs.PageLimits{
+			"blog": {DefaultLimit: 3, MaxLimit: 5},
+		},
+	}
+	defer func() { config.Pagination = previous }()
+
+	for path, expected := range map[string]float64{
+		"/api/projects":            7,  // global default
+		"/api/projects?limit=1000": 20, // capped at the global max
+		"/api/projects?limit=12":   12, // within bounds
+		"/api/projects?limit=0":    7,  // invalid limits fall back to the default
+		"/api/blog":                3,  // resource default
+		"/api/blog?limit=1000":     5,  // capped at the resource max
+	} {
+		assert.Equal(t, expected, listLimit(t, path), path)
+	}
+}
+
+func TestProjectCursorPagination(t *testing.T) {
+	category := models.ProjectCategory{Name: "Cursor Projects", Slug: "cursor-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	// Several projects share a creation time so the id tie-breaker is exercised
+	base := time.Date(2022, time.March, 1, 12, 0, 0, 0, time.UTC)
+	expected := make(map[float64]bool)
+	for i := 0; i < 7; i++ {
+		project := models.Project{
+			Title:      fmt.Sprintf("Cursor Project %d", i),
+			Slug:       fmt.Sprintf("cursor-project-%d", i),
+			CategoryID: category.ID,
+			Published:  true,
+			CreatedAt:  base.Add(time.Duration(i/3) * time.Hour),
+		}
+		assert.NoError(t, database.DB.Create(&project).Error)
+		expected[float64(project.ID)] = true
+	}
+
+	seen := make(map[float64]bool)
+	cursor := ""
+	for pages := 0; pages < 10; pages++ {
+		path := fmt.Sprintf("/api/projects?category_id=%d&limit=3&cursor=%s", category.ID, url.QueryEscape(cursor))
+		req, err := http.NewRequest("GET", path, nil)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data struct {
+				Projects []struct {
+					ID float64 `json:"id"`
+				} `json:"projects"`
+				Metadata struct {
+					NextCursor string `json:"next_cursor"`
+				} `json:"metadata"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		for _, project := range response.Data.Projects {
+			assert.False(t, seen[project.ID], "duplicate project %v", project.ID)
+			seen[project.ID] = true
+		}
+
+		cursor = response.Data.Metadata.NextCursor
+		if cursor == "" {
+			break
+		}
+	}
+
+	// Every project was returned exactly once
+	assert.Equal(t, expected, seen)
+}
+
+func TestProjectCursorPaginationInvalidCursor(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/projects?cursor=not-a-cursor", nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCursorPaginationRejectsFeaturedListings(t *testing.T) {
+	// A creation time cursor would skip or repeat items in the featured order
+	for _, path := range []string{"/api/projects?featured=true&cursor=", "/api/blog?featured=true&cursor="} {
+		w := doJSON(t, "GET", path, "", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+		assert.Contains(t, w.Body.String(), services.ErrFeaturedCursor.Error(), path)
+	}
+}
+
+func listLimit(t *testing.T, path string) interface{} {
+	req, err := http.NewRequest("GET", path, nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	return response["data"].(map[string]interface{})["metadata"].(map[string]interface{})["limit"]
+}
+
+// responseDataKeys fetches a list endpoint and returns the keys of its data and metadata objects
+func responseDataKeys(t *testing.T, path string) ([]string, []string) {
+	req, err := http.NewRequest("GET", path, nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	metadata, _ := response.Data["metadata"].(map[string]interface{})
+	return sortedKeys(response.Data), sortedKeys(metadata)
+}
+
+// sortedKeys returns the keys of a JSON object in alphabetical order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestListResponseShape(t *testing.T) {
+	loginAndGetToken(t)
+
+	page := []string{"limit", "page", "total", "total_pages"}
+	for path, expected := range map[string][2][]string{
+		"/api/projects":         {{"metadata", "projects"}, page},
+		"/api/projects?cursor=": {{"metadata", "projects"}, {"limit", "next_cursor"}},
+		"/api/blog":             {{"blogs", "metadata"}, page},
+		"/api/blog?cursor=":     {{"blogs", "metadata"}, {"limit", "next_cursor"}},
+		"/api/users/1/projects": {{"metadata", "projects"}, page},
+		"/api/users/1/blog":     {{"blogs", "metadata"}, page},
+		"/api/audit":            {{"logs", "metadata"}, page},
+		"/api/comments":         {{"comments", "metadata"}, page},
+		"/api/blog/1/comments":  {{"comments", "metadata"}, page},
+	} {
+		data, metadata := responseDataKeys(t, path)
+		assert.Equal(t, expected[0], data, path)
+		assert.Equal(t, expected[1], metadata, path)
+	}
+}
@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/clock"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func getPreview(t *testing.T, token string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest("GET", "/api/blog/preview?token="+url.QueryEscape(token), nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func createDraftBlog(t *testing.T, title string) models.BlogPost {
+	category := models.BlogCategory{Name: title + " Category", Slug: "preview-" + fmt.Sprint(time.Now().UnixNano())}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	blog := models.BlogPost{
+		Title:      title,
+		Slug:       "preview-draft-" + fmt.Sprint(time.Now().UnixNano()),
+		Content:    "<p>Draft content</p>",
+		CategoryID: category.ID,
+		CreatedBy:  1,
+	}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+	assert.NoError(t, database.DB.Model(&blog).Update("published", false).Error)
+
+	return blog
+}
+
+func TestShareLinkRendersDraft(t *testing.T) {
+	loginAndGetToken(t)
+
+	blog := createDraftBlog(t, "Shared Draft")
+
+	w := doJSON(t, "POST", fmt.Sprintf("/api/blog/%d/share", blog.ID), accessToken, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response struct {
+		Data services.ShareLinkResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Contains(t, response.Data.URL, "/api/blog/preview?token=")
+	assert.NotEmpty(t, response.Data.ExpiresAt)
+
+	w = getPreview(t, response.Data.Token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	var preview map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	data := preview["data"].(map[string]interface{})
+	assert.Equal(t, "Shared Draft", data["title"])
+	assert.Equal(t, false, data["published"])
+}
+
+func TestExpiredShareLinkIsForbidden(t *testing.T) {
+	blog := createDraftBlog(t, "Expired Draft")
+
+	// Issue a link long enough ago that it has already expired
+	issued := time.Now().Add(-config.Content.PreviewExpiry - time.Hour)
+	link, err := services.NewPreviewService(config).WithClock(clock.NewFake(issued)).ShareBlog(blog.ID, 1, "admin")
+	assert.NoError(t, err)
+
+	w := getPreview(t, link.Token)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Missing and tampered tokens are rejected the same way
+	assert.Equal(t, http.StatusForbidden, getPreview(t, "").Code)
+	assert.Equal(t, http.StatusForbidden, getPreview(t, link.Token+"x").Code)
+}
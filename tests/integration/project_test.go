@@ -2,14 +2,25 @@ package integration
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/clock"
+	"zionechainapi/internal/controllers"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/models"
 	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+	"gorm.io/gorm"
 )
 
 var projectID uint
@@ -24,8 +35,8 @@ func TestCreateProject(t *testing.T) {
 		Title:       "Test Project",
 		Description: "This is a test project description",
 		Content:     "This is the content of the test project",
-		CategoryID:  1, // Assumes category ID 1 exists
-		TagIDs:      []uint{1}, // Assumes tag ID 1 exists
+		CategoryID:  fixtureCategoryID,
+		TagIDs:      []uint{fixtureTagID},
 		Featured:    true,
 		Published:   true,
 	}
@@ -107,9 +118,9 @@ func TestGetProject(t *testing.T) {
 func TestUpdateProject(t *testing.T) {
 	// Create an update request
 	updateRequest := services.UpdateProjectRequest{
-		Title:       "Updated Test Project",
-		Description: "This is an updated test project description",
-		Content:     "This is the updated content of the test project",
+		Title:       stringPtr("Updated Test Project"),
+		Description: stringPtr("This is an updated test project description"),
+		Content:     stringPtr("This is the updated content of the test project"),
 	}
 
 	// Convert to JSON
@@ -149,6 +160,317 @@ func TestUpdateProject(t *testing.T) {
 	assert.Equal(t, "This is the updated content of the test project", project["content"])
 }
 
+func TestUpdateProjectOmitDescription(t *testing.T) {
+	// Omitting description should leave it unchanged
+	updateRequest := services.UpdateProjectRequest{
+		Content: stringPtr("Content changed without touching the description"),
+	}
+
+	project := patchProject(t, updateRequest)
+	assert.Equal(t, "This is an updated test project description", project["description"])
+	assert.Equal(t, "Content changed without touching the description", project["content"])
+}
+
+func TestUpdateProjectClearDescription(t *testing.T) {
+	// Explicitly sending an empty description should clear it
+	updateRequest := services.UpdateProjectRequest{
+		Description: stringPtr(""),
+	}
+
+	project := patchProject(t, updateRequest)
+	assert.Equal(t, "", project["description"])
+
+	// Fetch the project again to make sure the change was persisted
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/projects/%d", projectID), nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	project = response["data"].(map[string]interface{})
+	assert.Equal(t, "", project["description"])
+	assert.Equal(t, "Content changed without touching the description", project["content"])
+}
+
+func TestCountProjectsMatchesListTotal(t *testing.T) {
+	for _, query := range []string{"", "?featured=true", "?q=Updated", "?category_id=1&featured=true", "?category_ids=1,2&featured=true"} {
+		// Get the total from the list endpoint
+		req, err := http.NewRequest("GET", "/api/projects"+query, nil)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var listResponse map[string]interface{}
+		err = json.Unmarshal(w.Body.Bytes(), &listResponse)
+		assert.NoError(t, err)
+		metadata := listResponse["data"].(map[string]interface{})["metadata"].(map[string]interface{})
+
+		// Get the count with the same filters
+		req, err = http.NewRequest("GET", "/api/projects/count"+query, nil)
+		assert.NoError(t, err)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var countResponse map[string]interface{}
+		err = json.Unmarshal(w.Body.Bytes(), &countResponse)
+		assert.NoError(t, err)
+		count := countResponse["data"].(map[string]interface{})["count"]
+
+		assert.Equal(t, metadata["total"], count, query)
+	}
+}
+
+func TestListProjectMedia(t *testing.T) {
+	// Add media out of order
+	for _, media := range []services.ProjectMediaRequest{
+		{Type: "image", URL: "https://example.com/second.png", SortOrder: 2},
+		{Type: "image", URL: "https://example.com/first.png", SortOrder: 1},
+	} {
+		w := addProjectMedia(t, media)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	// List the media
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/projects/%d/media", projectID), nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	// Media should be ordered by sort order
+	media := response["data"].([]interface{})
+	assert.Len(t, media, 2)
+	assert.Equal(t, "https://example.com/first.png", media[0].(map[string]interface{})["url"])
+	assert.Equal(t, "https://example.com/second.png", media[1].(map[string]interface{})["url"])
+}
+
+func TestListProjectMediaNotFound(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/projects/999999/media", nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetProjectNotFoundErrorCode(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/projects/999999", nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, utils.CodeResourceNotFound, response["code"])
+}
+
+func TestAddProjectMediaInvalidType(t *testing.T) {
+	w := addProjectMedia(t, services.ProjectMediaRequest{Type: "hologram", URL: "https://example.com/image.png"})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), services.ErrInvalidMediaType.Error())
+}
+
+func TestAddProjectMediaMalformedURL(t *testing.T) {
+	for _, url := range []string{"not a url", "ftp://example.com/file.png", "https://"} {
+		w := addProjectMedia(t, services.ProjectMediaRequest{Type: "image", URL: url})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, url)
+		assert.Contains(t, w.Body.String(), services.ErrInvalidMediaURL.Error())
+	}
+}
+
+func TestAddProjectMediaDefaultType(t *testing.T) {
+	w := addProjectMedia(t, services.ProjectMediaRequest{URL: "https://example.com/default.png"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "image", response["data"].(map[string]interface{})["type"])
+}
+
+func TestAddProjectMediaBatch(t *testing.T) {
+	existing := listProjectMedia(t)
+
+	w := addProjectMediaBatch(t, services.BatchProjectMediaRequest{Media: []services.ProjectMediaRequest{
+		{Type: "image", URL: "https://example.com/batch-1.png"},
+		{Type: "video", URL: "https://example.com/batch-2.mp4"},
+		{URL: "https://example.com/batch-3.png", Caption: "Third"},
+	}})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	// Media is appended after the existing media in request order
+	created := response["data"].([]interface{})
+	assert.Len(t, created, 3)
+
+	lastSortOrder := float64(0)
+	for _, item := range existing {
+		if sortOrder := item.(map[string]interface{})["sort_order"].(float64); sortOrder > lastSortOrder {
+			lastSortOrder = sortOrder
+		}
+	}
+	for i, item := range created {
+		assert.Equal(t, lastSortOrder+float64(i+1), item.(map[string]interface{})["sort_order"])
+	}
+
+	assert.Len(t, listProjectMedia(t), len(existing)+3)
+}
+
+func TestAddProjectMediaBatchRollsBackOnInvalidEntry(t *testing.T) {
+	existing := listProjectMedia(t)
+
+	w := addProjectMediaBatch(t, services.BatchProjectMediaRequest{Media: []services.ProjectMediaRequest{
+		{Type: "image", URL: "https://example.com/valid.png"},
+		{Type: "hologram", URL: "https://example.com/invalid.png"},
+	}})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), services.ErrInvalidMediaType.Error())
+
+	// The valid entry was not kept
+	assert.Len(t, listProjectMedia(t), len(existing))
+}
+
+func TestPatchProjectMediaKeepsOmittedFields(t *testing.T) {
+	w := addProjectMedia(t, services.ProjectMediaRequest{
+		Type:      "image",
+		URL:       "https://example.com/captioned.png",
+		Caption:   "Kept caption",
+		SortOrder: 1,
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	mediaID := uint(response["data"].(map[string]interface{})["id"].(float64))
+
+	// Only the sort order is sent
+	w = doJSON(t, "PATCH", fmt.Sprintf("/api/projects/media/%d", mediaID), accessToken, []byte(`{"sort_order":7}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	media := response["data"].(map[string]interface{})
+	assert.Equal(t, float64(7), media["sort_order"])
+	assert.Equal(t, "Kept caption", media["caption"])
+	assert.Equal(t, "https://example.com/captioned.png", media["url"])
+	assert.Equal(t, "image", media["type"])
+
+	// An explicit empty caption still clears it
+	w = doJSON(t, "PUT", fmt.Sprintf("/api/projects/media/%d", mediaID), accessToken, []byte(`{"caption":""}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var stored models.ProjectMedia
+	assert.NoError(t, database.DB.First(&stored, mediaID).Error)
+	assert.Equal(t, "", stored.Caption)
+	assert.Equal(t, 7, stored.SortOrder)
+
+	// A provided URL is still validated
+	w = doJSON(t, "PATCH", fmt.Sprintf("/api/projects/media/%d", mediaID), accessToken, []byte(`{"url":"not a url"}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestDuplicateProject(t *testing.T) {
+	// Tag the project so there is something to copy
+	tag := models.Tag{Name: "Duplicate Tag", Slug: "duplicate-tag"}
+	assert.NoError(t, database.DB.Create(&tag).Error)
+	project := models.Project{ID: projectID}
+	assert.NoError(t, database.DB.Model(&project).Association("Tags").Append(&tag))
+	assert.NoError(t, database.DB.First(&project, projectID).Error)
+
+	// Feature the original, which the copies must not inherit
+	assert.NoError(t, database.DB.Model(&project).Update("featured", true).Error)
+	defer database.DB.Model(&project).Update("featured", project.Featured)
+
+	slugs := map[string]bool{project.Slug: true}
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest("POST", fmt.Sprintf("/api/projects/%d/duplicate", projectID), nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response map[string]interface{}
+		err = json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		duplicate := response["data"].(map[string]interface{})
+
+		// The copy is a distinct unpublished record
+		assert.NotEqual(t, float64(projectID), duplicate["id"])
+		assert.Equal(t, project.Title, duplicate["title"])
+		assert.Equal(t, false, duplicate["published"])
+		assert.Equal(t, false, duplicate["featured"])
+
+		// The copy is announced like a newly created project
+		assert.NotNil(t, outboxEventFor(t, services.WebhookEventProjectCreated, uint(duplicate["id"].(float64))))
+
+		// Tags are copied
+		assert.NotEmpty(t, duplicate["tags"])
+		assert.Contains(t, w.Body.String(), `"slug":"duplicate-tag"`)
+
+		// Every copy gets its own slug
+		slug := duplicate["slug"].(string)
+		assert.False(t, slugs[slug], slug)
+		assert.Contains(t, slug, "-copy")
+		slugs[slug] = true
+	}
+
+	// Missing content is not found rather than a bad request
+	for _, path := range []string{"/api/projects/999999/duplicate", "/api/blog/999999/duplicate"} {
+		w := doJSON(t, "POST", path, accessToken, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+}
+
+func TestProjectTimestampsAreUTC(t *testing.T) {
+	category := models.ProjectCategory{Name: "Timezone Projects", Slug: "timezone-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	// Store a project with a timestamp in a non-UTC zone
+	createdAt := time.Date(2021, time.June, 1, 9, 30, 0, 0, time.FixedZone("UTC+5", 5*60*60))
+	project := models.Project{
+		Title:      "Timezone Project",
+		Slug:       "timezone-project",
+		CategoryID: category.ID,
+		Published:  true,
+		CreatedAt:  createdAt,
+	}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/projects/%d", project.ID), nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	// The same instant is rendered in UTC
+	assert.Equal(t, "2021-06-01T04:30:00Z", response["data"].(map[string]interface{})["created_at"])
+}
+
 func TestDeleteProject(t *testing.T) {
 	// Create a request
 	req, err := http.NewRequest("DELETE", fmt.Sprintf("/api/projects/%d", projectID), nil)
@@ -168,8 +490,8 @@ func TestDeleteProject(t *testing.T) {
 func loginAndGetToken(t *testing.T) {
 	// Create a login request
 	loginRequest := services.LoginRequest{
-		Phone:    "+1234567890",
-		Password: "password123",
+		Phone:    fixtureAdminPhone,
+		Password: fixtureAdminPassword,
 	}
 
 	// Convert to JSON
@@ -198,4 +520,438 @@ func loginAndGetToken(t *testing.T) {
 	// Extract the token from the response
 	data := response["data"].(map[string]interface{})
 	accessToken = data["access_token"].(string)
-}
\ No newline at end of file
+}
+
+func patchProject(t *testing.T, updateRequest services.UpdateProjectRequest) map[string]interface{} {
+	// Convert to JSON
+	jsonData, err := json.Marshal(updateRequest)
+	assert.NoError(t, err)
+
+	// Create a request
+	req, err := http.NewRequest("PATCH", fmt.Sprintf("/api/projects/%d", projectID), bytes.NewBuffer(jsonData))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	// Serve the request
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Parse the response
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	return response["data"].(map[string]interface{})
+}
+
+func addProjectMedia(t *testing.T, media services.ProjectMediaRequest) *httptest.ResponseRecorder {
+	return doJSON(t, "POST", fmt.Sprintf("/api/projects/%d/media", projectID), accessToken, media)
+}
+
+func addProjectMediaBatch(t *testing.T, batch services.BatchProjectMediaRequest) *httptest.ResponseRecorder {
+	return doJSON(t, "POST", fmt.Sprintf("/api/projects/%d/media/batch", projectID), accessToken, batch)
+}
+
+func listProjectMedia(t *testing.T) []interface{} {
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/projects/%d/media", projectID), nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	return response["data"].([]interface{})
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestEmptyCollectionsSerializeAsArrays(t *testing.T) {
+	category := models.ProjectCategory{Name: "Empty Collections", Slug: "empty-collections"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	project := models.Project{Title: "Bare Project", Slug: "bare-project", CategoryID: category.ID, Published: true}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	// A project with no media or tags
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/projects/%d", project.ID), nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "[]", string(response.Data["media"]))
+	assert.Equal(t, "[]", string(response.Data["tags"]))
+
+	// A list page with no matches
+	req, err = http.NewRequest("GET", "/api/projects?q=no-project-matches-this", nil)
+	assert.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "[]", string(response.Data["projects"]))
+}
+
+func TestCreateProjectSlugTimestampUsesClock(t *testing.T) {
+	category := models.ProjectCategory{Name: "Clock Projects", Slug: "clock-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	existing := models.Project{Title: "Clock Project", Slug: "clock-project", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&existing).Error)
+
+	fake := clock.NewFake(time.Date(2024, time.February, 29, 8, 0, 0, 0, time.UTC))
+	projectService := services.NewProjectService().WithClock(fake)
+
+	// A colliding title gets the frozen timestamp appended
+	project, err := projectService.CreateProject(services.CreateProjectRequest{
+		Title:       "Clock Project",
+		Description: "Description",
+		Content:     "Content",
+		CategoryID:  category.ID,
+	}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("clock-project-%d", fake.Now().Unix()), project.Slug)
+}
+
+func TestCreateProjectRetriesSlugOnDuplicateKey(t *testing.T) {
+	category := models.ProjectCategory{Name: "Race Projects", Slug: "race-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	fake := clock.NewFake(time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC))
+	projectService := services.NewProjectService().WithClock(fake)
+
+	// Take both the plain and the timestamped slug, as a concurrent create would
+	timestamped := fmt.Sprintf("race-project-%d", fake.Now().Unix())
+	for _, slug := range []string{"race-project", timestamped} {
+		existing := models.Project{Title: "Race Project", Slug: slug, CategoryID: category.ID}
+		assert.NoError(t, database.DB.Create(&existing).Error)
+	}
+
+	// Record the statements run outside the query builders, such as savepoints
+	var raw []string
+	recordRaw := func(db *gorm.DB) {
+		raw = append(raw, db.Statement.SQL.String())
+	}
+	assert.NoError(t, database.DB.Callback().Raw().After("gorm:raw").Register("test:record_raw", recordRaw))
+
+	project, err := projectService.CreateProject(services.CreateProjectRequest{
+		Title:       "Race Project",
+		Description: "Description",
+		Content:     "Content",
+		CategoryID:  category.ID,
+	}, 1)
+	assert.NoError(t, database.DB.Callback().Raw().Remove("test:record_raw"))
+	assert.NoError(t, err)
+	if assert.NotNil(t, project) {
+		assert.Regexp(t, `^race-project-[0-9a-f]{8}$`, project.Slug)
+	}
+
+	// The retry runs after rolling back the failed insert, as PostgreSQL
+	// rejects any other statement in the transaction until then
+	assert.Contains(t, raw, "ROLLBACK TO SAVEPOINT slug_write")
+}
+
+func TestCreateProjectRejectsFormEncodedBody(t *testing.T) {
+	loginAndGetToken(t)
+
+	req, err := http.NewRequest("POST", "/api/projects", strings.NewReader("title=Form+Project&content=Content"))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
+
+func getProjectsByCategory(t *testing.T, query string) map[uint]map[string]interface{} {
+	req, err := http.NewRequest("GET", "/api/projects/by-category?"+query, nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	groups := make(map[uint]map[string]interface{})
+	for _, item := range response["data"].([]interface{}) {
+		group := item.(map[string]interface{})
+		category := group["category"].(map[string]interface{})
+		groups[uint(category["id"].(float64))] = group
+	}
+	return groups
+}
+
+func TestListProjectsByCategory(t *testing.T) {
+	grouped := models.ProjectCategory{Name: "Grouped Projects", Slug: "grouped-projects"}
+	assert.NoError(t, database.DB.Create(&grouped).Error)
+	single := models.ProjectCategory{Name: "Single Grouped Project", Slug: "single-grouped-project"}
+	assert.NoError(t, database.DB.Create(&single).Error)
+	empty := models.ProjectCategory{Name: "Empty Grouped Projects", Slug: "empty-grouped-projects"}
+	assert.NoError(t, database.DB.Create(&empty).Error)
+
+	base := time.Now().Add(-time.Hour)
+	projects := []models.Project{
+		{Title: "Grouped Oldest", Slug: "grouped-oldest", CategoryID: grouped.ID, Published: true, CreatedAt: base},
+		{Title: "Grouped Middle", Slug: "grouped-middle", CategoryID: grouped.ID, Published: true, CreatedAt: base.Add(time.Minute)},
+		{Title: "Grouped Newest", Slug: "grouped-newest", CategoryID: grouped.ID, Published: true, CreatedAt: base.Add(2 * time.Minute)},
+		{Title: "Grouped Draft", Slug: "grouped-draft", CategoryID: grouped.ID, CreatedAt: base.Add(3 * time.Minute)},
+		{Title: "Grouped Single", Slug: "grouped-single", CategoryID: single.ID, Published: true, CreatedAt: base},
+		{Title: "Grouped Hidden", Slug: "grouped-hidden", CategoryID: empty.ID, CreatedAt: base},
+	}
+	for i := range projects {
+		assert.NoError(t, database.DB.Create(&projects[i]).Error)
+	}
+	// Creating with false leaves the published column default in place
+	assert.NoError(t, database.DB.Model(&models.Project{}).
+		Where("id IN ?", []uint{projects[3].ID, projects[5].ID}).
+		Update("published", false).Error)
+
+	titles := func(group map[string]interface{}) []string {
+		var result []string
+		for _, item := range group["projects"].([]interface{}) {
+			result = append(result, item.(map[string]interface{})["title"].(string))
+		}
+		return result
+	}
+
+	// Each category keeps its newest published projects up to the limit
+	groups := getProjectsByCategory(t, "limit=2")
+	if assert.Contains(t, groups, grouped.ID) {
+		assert.Equal(t, []string{"Grouped Newest", "Grouped Middle"}, titles(groups[grouped.ID]))
+	}
+	if assert.Contains(t, groups, single.ID) {
+		assert.Equal(t, []string{"Grouped Single"}, titles(groups[single.ID]))
+	}
+	if assert.Contains(t, groups, empty.ID) {
+		assert.Empty(t, groups[empty.ID]["projects"])
+	}
+
+	// Categories without published projects can be left out
+	groups = getProjectsByCategory(t, "limit=2&skip_empty=true")
+	assert.NotContains(t, groups, empty.ID)
+	assert.Contains(t, groups, grouped.ID)
+}
+
+func TestToggleProjectFlagsKeepsOtherFields(t *testing.T) {
+	loginAndGetToken(t)
+
+	var admin models.User
+	assert.NoError(t, database.DB.Where("phone = ?", fixtureAdminPhone).First(&admin).Error)
+
+	category := models.ProjectCategory{Name: "Toggle Projects", Slug: "toggle-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	tag := models.Tag{Name: "Toggle Tag", Slug: "toggle-tag"}
+	assert.NoError(t, database.DB.Create(&tag).Error)
+
+	order := 1
+	project := models.Project{
+		Title:         "Toggle Project",
+		Slug:          "toggle-project",
+		Description:   "Toggle description",
+		Content:       "<p>Toggle content</p>",
+		MetaTitle:     "Toggle meta",
+		CategoryID:    category.ID,
+		Tags:          []models.Tag{tag},
+		Featured:      true,
+		FeaturedOrder: &order,
+		Published:     true,
+		CreatedBy:     7,
+		UpdatedBy:     7,
+	}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	w := doJSON(t, "PATCH", fmt.Sprintf("/api/projects/%d/featured", project.ID), accessToken, []byte(`{"value":false}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var stored models.Project
+	assert.NoError(t, database.DB.Preload("Tags").First(&stored, project.ID).Error)
+	assert.False(t, stored.Featured)
+	assert.Nil(t, stored.FeaturedOrder)
+	assert.True(t, stored.Published)
+	assert.Equal(t, admin.ID, stored.UpdatedBy)
+
+	w = doJSON(t, "PATCH", fmt.Sprintf("/api/projects/%d/publish", project.ID), accessToken, []byte(`{"value":false}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.NoError(t, database.DB.Preload("Tags").First(&stored, project.ID).Error)
+	assert.False(t, stored.Published)
+	assert.False(t, stored.Featured)
+
+	// Nothing but the flags and the editor changed
+	assert.Equal(t, project.Title, stored.Title)
+	assert.Equal(t, project.Slug, stored.Slug)
+	assert.Equal(t, project.Description, stored.Description)
+	assert.Equal(t, project.Content, stored.Content)
+	assert.Equal(t, project.MetaTitle, stored.MetaTitle)
+	assert.Equal(t, project.CategoryID, stored.CategoryID)
+	assert.Equal(t, uint(7), stored.CreatedBy)
+	if assert.Len(t, stored.Tags, 1) {
+		assert.Equal(t, tag.ID, stored.Tags[0].ID)
+	}
+
+	// The value is required
+	w = doJSON(t, "PATCH", fmt.Sprintf("/api/projects/%d/featured", project.ID), accessToken, []byte(`{}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestProjectWithUnknownCategoryIsRejected(t *testing.T) {
+	loginAndGetToken(t)
+
+	w := doJSON(t, "POST", "/api/projects", accessToken, services.CreateProjectRequest{
+		Title:       "Uncategorized Project",
+		Description: "Description",
+		Content:     "Content",
+		CategoryID:  999999,
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "category not found")
+
+	var count int64
+	assert.NoError(t, database.DB.Model(&models.Project{}).Where("title = ?", "Uncategorized Project").Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+
+	category := models.ProjectCategory{Name: "Known Projects", Slug: "known-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	project := models.Project{Title: "Known Project", Slug: "known-project", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	w = doJSON(t, "PUT", fmt.Sprintf("/api/projects/%d", project.ID), accessToken, []byte(`{"category_id":999999}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "category not found")
+
+	var stored models.Project
+	assert.NoError(t, database.DB.First(&stored, project.ID).Error)
+	assert.Equal(t, category.ID, stored.CategoryID)
+}
+
+func TestUnpublishedProjectIsHiddenFromPublicDetail(t *testing.T) {
+	loginAndGetToken(t)
+	previewToken := mintPreviewToken(t)
+	author := registerAuthor(t, "Hidden Project Author")
+
+	project := models.Project{Title: "Hidden Detail", Slug: "hidden-detail-project", Content: "Content", CategoryID: fixtureCategoryID, CreatedBy: 1}
+	assert.NoError(t, database.DB.Create(&project).Error)
+	assert.NoError(t, database.DB.Model(&project).Update("published", false).Error)
+
+	for _, path := range []string{fmt.Sprintf("/api/projects/%d", project.ID), "/api/projects/slug/hidden-detail-project", fmt.Sprintf("/api/projects/%d/media", project.ID)} {
+		// Anonymous and regular users do not find the draft
+		assert.Equal(t, http.StatusNotFound, detailStatus(t, path, ""), path)
+		assert.Equal(t, http.StatusNotFound, detailStatus(t, path, author.AccessToken), path)
+
+		// Admins, editors and preview tokens do
+		assert.Equal(t, http.StatusOK, detailStatus(t, path, accessToken), path)
+		assert.Equal(t, http.StatusOK, detailStatus(t, path, previewToken), path)
+	}
+
+	// Once published it is public
+	assert.NoError(t, database.DB.Model(&project).Update("published", true).Error)
+	assert.Equal(t, http.StatusOK, detailStatus(t, fmt.Sprintf("/api/projects/%d", project.ID), ""))
+}
+
+func TestRequestTimeoutAbortsProjectQueries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	timed := gin.New()
+	timed.Use(middleware.Timeout(50 * time.Millisecond))
+	projectController := controllers.NewProjectController(config)
+	defer projectController.Close()
+	projectController.Routes(timed.Group("/api"), middleware.Auth(config))
+
+	// Stands in for a slow query: it only returns once its context is done
+	cancelled := make(chan error, 1)
+	slowQuery := func(db *gorm.DB) {
+		if db.Statement.Table != "projects" {
+			return
+		}
+		select {
+		case <-db.Statement.Context.Done():
+			cancelled <- db.Statement.Context.Err()
+			db.AddError(db.Statement.Context.Err())
+		case <-time.After(5 * time.Second):
+			cancelled <- nil
+		}
+	}
+	assert.NoError(t, database.DB.Callback().Query().Before("gorm:query").Register("test:slow_query", slowQuery))
+	defer database.DB.Callback().Query().Remove("test:slow_query")
+
+	req, err := http.NewRequest("GET", "/api/projects?page=1&limit=5", nil)
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	timed.ServeHTTP(w, req)
+
+	// The query is aborted at the deadline rather than running to completion
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.ErrorIs(t, <-cancelled, context.DeadlineExceeded)
+}
+
+func TestRequestTimeoutAbortsQueriesOfEveryService(t *testing.T) {
+	loginAndGetToken(t)
+
+	gin.SetMode(gin.TestMode)
+	timed := gin.New()
+	timed.Use(middleware.Timeout(50 * time.Millisecond))
+	setupRoutes(timed)
+
+	tests := []struct {
+		path  string
+		table string // table whose queries stand in for a slow query
+		token string
+	}{
+		{"/api/categories/projects", "project_categories", ""},
+		{"/api/tags", "tags", ""},
+		{"/api/resume/skills", "skills", ""},
+		{"/api/projects/last-modified", "projects", ""},
+		{"/api/blog/last-modified", "blog_posts", ""},
+		{"/api/stats", "projects", accessToken},
+		{"/api/audit", "audit_logs", accessToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			// Only returns once the context of the query is done
+			cancelled := make(chan error, 1)
+			slowQuery := func(db *gorm.DB) {
+				if db.Statement.Table != tt.table {
+					return
+				}
+				select {
+				case <-db.Statement.Context.Done():
+					cancelled <- db.Statement.Context.Err()
+					db.AddError(db.Statement.Context.Err())
+				case <-time.After(5 * time.Second):
+					cancelled <- nil
+				}
+			}
+			assert.NoError(t, database.DB.Callback().Query().Before("gorm:query").Register("test:slow_query", slowQuery))
+			defer database.DB.Callback().Query().Remove("test:slow_query")
+			assert.NoError(t, database.DB.Callback().Row().Before("gorm:row").Register("test:slow_row", slowQuery))
+			defer database.DB.Callback().Row().Remove("test:slow_row")
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			assert.NoError(t, err)
+			if tt.token != "" {
+				req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tt.token))
+			}
+			w := httptest.NewRecorder()
+			timed.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+			assert.ErrorIs(t, <-cancelled, context.DeadlineExceeded)
+		})
+	}
+}
@@ -0,0 +1,507 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/storage"
+)
+
+func TestSkillsSummary(t *testing.T) {
+	// Start from a known set of skills
+	assert.NoError(t, database.DB.Unscoped().Where("1 = 1").Delete(&models.Skill{}).Error)
+
+	skills := []models.Skill{
+		{Name: "Go", Proficiency: 90, Category: "Backend"},
+		{Name: "SQL", Proficiency: 70, Category: "Backend"},
+		{Name: "React", Proficiency: 60, Category: "Frontend"},
+		{Name: "CSS", Proficiency: 50, Category: "Frontend"},
+		{Name: "Vue", Proficiency: 85, Category: "Frontend"},
+		{Name: "Git", Proficiency: 95},
+		{Name: "Docker", Proficiency: 40, Category: "  "},
+	}
+	assert.NoError(t, database.DB.Create(&skills).Error)
+
+	req, err := http.NewRequest("GET", "/api/resume/skills/summary", nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Categories []struct {
+			Category           string  `json:"category"`
+			Count              int64   `json:"count"`
+			AverageProficiency float64 `json:"average_proficiency"`
+		} `json:"categories"`
+		TopSkills []models.Skill `json:"top_skills"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	// Skills without a category are grouped under "Other"
+	averages := map[string]float64{}
+	counts := map[string]int64{}
+	for _, category := range response.Categories {
+		averages[category.Category] = category.AverageProficiency
+		counts[category.Category] = category.Count
+	}
+	assert.Equal(t, map[string]int64{"Backend": 2, "Frontend": 3, "Other": 2}, counts)
+	assert.InDelta(t, 80, averages["Backend"], 0.01)
+	assert.InDelta(t, 65, averages["Frontend"], 0.01)
+	assert.InDelta(t, 67.5, averages["Other"], 0.01)
+
+	// The top five skills are ordered by proficiency
+	var names []string
+	for _, skill := range response.TopSkills {
+		names = append(names, skill.Name)
+	}
+	assert.Equal(t, []string{"Git", "Go", "Vue", "SQL", "React"}, names)
+}
+
+func TestSkillCategories(t *testing.T) {
+	// Start from a known set of skills
+	assert.NoError(t, database.DB.Unscoped().Where("1 = 1").Delete(&models.Skill{}).Error)
+
+	skills := []models.Skill{
+		{Name: "Vue", Proficiency: 85, Category: "Frontend"},
+		{Name: "Go", Proficiency: 90, Category: "Backend"},
+		{Name: "SQL", Proficiency: 70, Category: "Backend"},
+		{Name: "Terraform", Proficiency: 55, Category: "DevOps"},
+		{Name: "Git", Proficiency: 95},
+		{Name: "Docker", Proficiency: 40, Category: "  "},
+	}
+	assert.NoError(t, database.DB.Create(&skills).Error)
+
+	// Categories of deleted skills are not listed
+	assert.NoError(t, database.DB.Delete(&skills[3]).Error)
+
+	req, err := http.NewRequest("GET", "/api/resume/skills/categories", nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var categories []string
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &categories))
+	assert.Equal(t, []string{"Backend", "Frontend"}, categories)
+}
+
+func TestGetSkillByID(t *testing.T) {
+	skill := models.Skill{Name: "Kubernetes", Proficiency: 60, Category: "DevOps"}
+	assert.NoError(t, database.DB.Create(&skill).Error)
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/resume/skills/%d", skill.ID), nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.Skill
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, skill.ID, response.ID)
+	assert.Equal(t, "Kubernetes", response.Name)
+	assert.Equal(t, "DevOps", response.Category)
+}
+
+func TestGetSkillByIDNotFound(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/resume/skills/999999", nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestExperienceChronologicalOrder(t *testing.T) {
+	assert.NoError(t, database.DB.Unscoped().Where("1 = 1").Delete(&models.Experience{}).Error)
+
+	date := func(year int) time.Time { return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC) }
+	end := date(2021)
+	experiences := []models.Experience{
+		{JobTitle: "Junior", Company: "A", StartDate: date(2015), EndDate: &end, Description: "First job"},
+		{JobTitle: "Lead", Company: "C", StartDate: date(2019), CurrentJob: true, Description: "Current job"},
+		{JobTitle: "Senior", Company: "B", StartDate: date(2020), EndDate: &end, Description: "Second job"},
+	}
+	assert.NoError(t, database.DB.Create(&experiences).Error)
+
+	req, err := http.NewRequest("GET", "/api/resume/experience", nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []struct {
+		JobTitle string `json:"job_title"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	var titles []string
+	for _, experience := range response {
+		titles = append(titles, experience.JobTitle)
+	}
+	// The ongoing job comes first, then the rest by start date, newest first
+	assert.Equal(t, []string{"Lead", "Senior", "Junior"}, titles)
+}
+
+// publicationTitles lists publications and returns their titles in order, with
+// the pagination metadata of the page
+func publicationTitles(t *testing.T, path string) ([]string, services.PageMetadata) {
+	req, err := http.NewRequest("GET", path, nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data struct {
+			Publications []models.Publication `json:"publications"`
+			Metadata     services.PageMetadata `json:"metadata"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	titles := []string{}
+	for _, publication := range response.Data.Publications {
+		titles = append(titles, publication.Title)
+	}
+	return titles, response.Data.Metadata
+}
+
+func TestPublicationsChronologicalOrder(t *testing.T) {
+	assert.NoError(t, database.DB.Unscoped().Where("1 = 1").Delete(&models.Publication{}).Error)
+
+	publications := []models.Publication{
+		{Title: "Middle", Publisher: "P", PublishDate: time.Date(2019, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{Title: "Newest", Publisher: "P", PublishDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{Title: "Oldest", Publisher: "P", PublishDate: time.Date(2012, time.May, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	assert.NoError(t, database.DB.Create(&publications).Error)
+
+	titles := func(path string) []string {
+		titles, _ := publicationTitles(t, path)
+		return titles
+	}
+
+	assert.Equal(t, []string{"Newest", "Middle", "Oldest"}, titles("/api/resume/publications"))
+	assert.Equal(t, []string{"Newest", "Middle"}, titles("/api/resume/publications?limit=2"))
+	assert.Equal(t, []string{"Oldest"}, titles("/api/resume/publications?limit=2&page=2"))
+
+	// Every list carries the standard metadata, with the configured page size by default
+	_, metadata := publicationTitles(t, "/api/resume/publications?limit=2")
+	assert.Equal(t, services.NewPageMetadata(3, 1, 2), metadata)
+	_, metadata = publicationTitles(t, "/api/resume/publications")
+	assert.Equal(t, services.NewPageMetadata(3, 1, 10), metadata)
+
+	// The page size is capped at the configured maximum
+	_, metadata = publicationTitles(t, "/api/resume/publications?limit=100000")
+	assert.Equal(t, services.NewPageMetadata(3, 1, 100), metadata)
+}
+
+func trashedSkillIDs(t *testing.T) []uint {
+	w := doJSON(t, "GET", "/api/resume/skills/trash", accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.Skill
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	ids := []uint{}
+	for _, skill := range response {
+		ids = append(ids, skill.ID)
+	}
+	return ids
+}
+
+func TestResumeTrashRestoreAndPurge(t *testing.T) {
+	loginAndGetToken(t)
+
+	skill := models.Skill{Name: "Trashed Skill", Proficiency: 30}
+	assert.NoError(t, database.DB.Create(&skill).Error)
+	kept := models.Skill{Name: "Kept Skill", Proficiency: 40}
+	assert.NoError(t, database.DB.Create(&kept).Error)
+
+	// Deleted entries show up in the trash
+	w := doJSON(t, "DELETE", fmt.Sprintf("/api/resume/skills/%d", skill.ID), accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, trashedSkillIDs(t), skill.ID)
+	assert.NotContains(t, trashedSkillIDs(t), kept.ID)
+
+	// Restoring brings the entry back
+	w = doJSON(t, "POST", fmt.Sprintf("/api/resume/skills/%d/restore", skill.ID), accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, trashedSkillIDs(t), skill.ID)
+	w = doJSON(t, "GET", fmt.Sprintf("/api/resume/skills/%d", skill.ID), accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Only trashed entries can be restored or purged
+	w = doJSON(t, "POST", fmt.Sprintf("/api/resume/skills/%d/restore", kept.ID), accessToken, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = doJSON(t, "DELETE", fmt.Sprintf("/api/resume/skills/%d/purge", skill.ID), accessToken, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Purging removes the entry for good
+	w = doJSON(t, "DELETE", fmt.Sprintf("/api/resume/skills/%d", skill.ID), accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doJSON(t, "DELETE", fmt.Sprintf("/api/resume/skills/%d/purge", skill.ID), accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, trashedSkillIDs(t), skill.ID)
+
+	var count int64
+	assert.NoError(t, database.DB.Unscoped().Model(&models.Skill{}).Where("id = ?", skill.ID).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestResumeTrashListsEachSection(t *testing.T) {
+	loginAndGetToken(t)
+
+	language := models.Language{Name: "Trashed Language", Proficiency: "Basic"}
+	assert.NoError(t, database.DB.Create(&language).Error)
+	assert.NoError(t, database.DB.Delete(&language).Error)
+
+	w := doJSON(t, "GET", "/api/resume/languages/trash", accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.Language
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.NotEmpty(t, response) {
+		assert.Equal(t, language.ID, response[0].ID)
+	}
+
+	// The trash of a section only holds its own entries
+	w = doJSON(t, "GET", "/api/resume/publications/trash", accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "Trashed Language")
+}
+
+func TestResumeEntriesRecordTheirEditors(t *testing.T) {
+	loginAndGetToken(t)
+
+	var admin models.User
+	assert.NoError(t, database.DB.Where("phone = ?", fixtureAdminPhone).First(&admin).Error)
+
+	// The creator comes from the token, not the body
+	w := doJSON(t, "POST", "/api/resume/languages", accessToken, []byte(`{"name":"Attributed","proficiency":"Fluent","created_by":999,"updated_by":999}`))
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var created models.Language
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, admin.ID, created.CreatedBy)
+	assert.Equal(t, admin.ID, created.UpdatedBy)
+
+	// Updates keep the creator and record the editor
+	assert.NoError(t, database.DB.Model(&created).Updates(map[string]interface{}{"created_by": 7, "updated_by": 7}).Error)
+	w = doJSON(t, "PUT", fmt.Sprintf("/api/resume/languages/%d", created.ID), accessToken, []byte(`{"name":"Attributed","proficiency":"Native","created_by":999}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var updated models.Language
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, uint(7), updated.CreatedBy)
+	assert.Equal(t, admin.ID, updated.UpdatedBy)
+
+	var stored models.Language
+	assert.NoError(t, database.DB.First(&stored, created.ID).Error)
+	assert.Equal(t, uint(7), stored.CreatedBy)
+	assert.Equal(t, admin.ID, stored.UpdatedBy)
+	assert.Equal(t, "Native", stored.Proficiency)
+}
+
+func TestResumeChangesRequireAuthentication(t *testing.T) {
+	req, err := http.NewRequest("POST", "/api/resume/languages", bytes.NewBufferString(`{"name":"Anonymous","proficiency":"Basic"}`))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestPublicationsFilterByYearAndAuthor(t *testing.T) {
+	assert.NoError(t, database.DB.Unscoped().Where("1 = 1").Delete(&models.Publication{}).Error)
+
+	date := func(year int, month time.Month) time.Time { return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC) }
+	publications := []models.Publication{
+		{Title: "Smith Early", Publisher: "P", Authors: "J. Smith, A. Jones", PublishDate: date(2023, time.January)},
+		{Title: "Smith Late", Publisher: "P", Authors: "A. Jones, J. Smith", PublishDate: date(2023, time.December)},
+		{Title: "Jones Only", Publisher: "P", Authors: "A. Jones", PublishDate: date(2023, time.June)},
+		{Title: "Smith Before", Publisher: "P", Authors: "J. Smith", PublishDate: date(2022, time.December)},
+		{Title: "Smith After", Publisher: "P", Authors: "J. Smith", PublishDate: date(2024, time.January)},
+	}
+	assert.NoError(t, database.DB.Create(&publications).Error)
+
+	titles := func(path string) []string {
+		titles, _ := publicationTitles(t, path)
+		return titles
+	}
+
+	// The year covers January through December
+	assert.Equal(t, []string{"Smith Late", "Jones Only", "Smith Early"}, titles("/api/resume/publications?year=2023"))
+	// Authors match anywhere in the list
+	assert.Equal(t, []string{"Smith After", "Smith Late", "Smith Early", "Smith Before"}, titles("/api/resume/publications?author=Smith"))
+	// The filters compose with each other and with pagination
+	assert.Equal(t, []string{"Smith Late", "Smith Early"}, titles("/api/resume/publications?year=2023&author=Smith"))
+	assert.Equal(t, []string{"Smith Early"}, titles("/api/resume/publications?year=2023&author=Smith&limit=1&page=2"))
+	assert.Empty(t, titles("/api/resume/publications?year=2021"))
+	// LIKE wildcards in the author match literally
+	assert.Empty(t, titles("/api/resume/publications?author=J_+Smith"))
+	assert.Empty(t, titles("/api/resume/publications?author=%25"))
+
+	req, err := http.NewRequest("GET", "/api/resume/publications?year=recent", nil)
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestResumeOverview(t *testing.T) {
+	for _, model := range []interface{}{
+		&models.PersonalInfo{}, &models.Skill{}, &models.Experience{}, &models.Education{},
+		&models.ResumeProject{}, &models.Certificate{}, &models.Language{}, &models.Publication{},
+	} {
+		assert.NoError(t, database.DB.Unscoped().Where("1 = 1").Delete(model).Error)
+	}
+
+	getOverview := func() *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", "/api/resume/overview", nil)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// There is no overview without personal info
+	assert.Equal(t, http.StatusNotFound, getOverview().Code)
+
+	now := time.Now()
+	personal := []models.PersonalInfo{
+		{FullName: "Older Me", JobTitle: "Developer", Email: "old@example.com", Phone: "1", Summary: "Old", CreatedAt: now.Add(-time.Hour)},
+		{FullName: "Current Me", JobTitle: "Lead", Email: "new@example.com", Phone: "2", Summary: "New", CreatedAt: now},
+	}
+	assert.NoError(t, database.DB.Create(&personal).Error)
+
+	skills := []models.Skill{
+		{Name: "Go", Proficiency: 90},
+		{Name: "SQL", Proficiency: 70},
+		{Name: "Removed", Proficiency: 10},
+	}
+	assert.NoError(t, database.DB.Create(&skills).Error)
+	assert.NoError(t, database.DB.Delete(&skills[2]).Error)
+	assert.NoError(t, database.DB.Create(&models.Language{Name: "English", Proficiency: "Native"}).Error)
+
+	w := getOverview()
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		PersonalInfo models.PersonalInfo `json:"personal_info"`
+		Counts       map[string]int64    `json:"counts"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	// The most recent personal info is the resume's
+	assert.Equal(t, personal[1].ID, response.PersonalInfo.ID)
+	assert.Equal(t, "Current Me", response.PersonalInfo.FullName)
+
+	// Deleted entries are not counted
+	assert.Equal(t, map[string]int64{
+		"skills":       2,
+		"experience":   0,
+		"education":    0,
+		"projects":     0,
+		"certificates": 0,
+		"languages":    1,
+		"publications": 0,
+	}, response.Counts)
+}
+
+func TestPersonalInfoIsASingleton(t *testing.T) {
+	loginAndGetToken(t)
+	assert.NoError(t, database.DB.Unscoped().Where("1 = 1").Delete(&models.PersonalInfo{}).Error)
+
+	body := []byte(`{"full_name":"Only Me","job_title":"Developer","email":"me@example.com","phone":"+100","summary":"Summary"}`)
+	w := doJSON(t, "POST", "/api/resume/personal", accessToken, body)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var first models.PersonalInfo
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+
+	// A second person is rejected in favour of updating the first
+	w = doJSON(t, "POST", "/api/resume/personal", accessToken, body)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var conflict map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &conflict))
+	assert.Equal(t, float64(first.ID), conflict["id"])
+	assert.Contains(t, conflict["error"], "PUT /api/resume/personal/:id")
+
+	var count int64
+	assert.NoError(t, database.DB.Model(&models.PersonalInfo{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	// Once deleted, personal info can be created again, but the old one can no
+	// longer be restored next to it
+	w = doJSON(t, "DELETE", fmt.Sprintf("/api/resume/personal/%d", first.ID), accessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doJSON(t, "POST", "/api/resume/personal", accessToken, body)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = doJSON(t, "POST", fmt.Sprintf("/api/resume/personal/%d/restore", first.ID), accessToken, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestPersonalInfoAvatarUpload(t *testing.T) {
+	loginAndGetToken(t)
+	assert.NoError(t, database.DB.Unscoped().Where("1 = 1").Delete(&models.PersonalInfo{}).Error)
+
+	personalInfo := models.PersonalInfo{FullName: "Avatar Owner", JobTitle: "Developer", Email: "avatar@example.com", Phone: "+100", Summary: "Summary"}
+	assert.NoError(t, database.DB.Create(&personalInfo).Error)
+	path := fmt.Sprintf("/api/resume/personal/%d/avatar", personalInfo.ID)
+
+	w := uploadFileTo(t, path, "avatar.png", pngFile(t))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var updated models.PersonalInfo
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.NotEmpty(t, updated.ProfileImage)
+
+	var stored models.PersonalInfo
+	assert.NoError(t, database.DB.First(&stored, personalInfo.ID).Error)
+	assert.Equal(t, updated.ProfileImage, stored.ProfileImage)
+
+	// Replacing the avatar removes the previous file
+	w = uploadFileTo(t, path, "avatar.png", pngFile(t))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, database.DB.First(&stored, personalInfo.ID).Error)
+	assert.NotEqual(t, updated.ProfileImage, stored.ProfileImage)
+	if config.Storage.Driver == "local" {
+		key, ok := storage.KeyFromURL(storage.New(config), updated.ProfileImage)
+		assert.True(t, ok)
+		_, err := os.Stat(filepath.Join(config.Storage.LocalPath, filepath.FromSlash(key)))
+		assert.True(t, os.IsNotExist(err))
+	}
+
+	// Files that are not images are rejected and leave the avatar alone
+	w = uploadFileTo(t, path, "cv.pdf", []byte("%PDF-1.4\n%%EOF\n"))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var unchanged models.PersonalInfo
+	assert.NoError(t, database.DB.First(&unchanged, personalInfo.ID).Error)
+	assert.Equal(t, stored.ProfileImage, unchanged.ProfileImage)
+
+	w = uploadFileTo(t, "/api/resume/personal/999999/avatar", "avatar.png", pngFile(t))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
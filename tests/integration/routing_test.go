@@ -0,0 +1,37 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/utils"
+)
+
+func TestDeleteProjectsWithoutIDIsMethodNotAllowed(t *testing.T) {
+	req, err := http.NewRequest("DELETE", "/api/projects", nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, POST", w.Header().Get("Allow"))
+
+	var response utils.Response
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Success)
+}
+
+func TestTrailingSlashRedirects(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/projects/", nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/api/projects", w.Header().Get("Location"))
+}
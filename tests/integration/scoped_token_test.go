@@ -0,0 +1,93 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+// mintPreviewToken mints a preview:read token as the admin
+func mintPreviewToken(t *testing.T) string {
+	loginAndGetToken(t)
+
+	w := doJSON(t, "POST", "/api/tokens/scoped", accessToken, services.ScopedTokenRequest{
+		Scope:     services.ScopePreviewRead,
+		ExpiresIn: 3600,
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response struct {
+		Data services.ScopedTokenResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, services.ScopePreviewRead, response.Data.Scope)
+	assert.NotEmpty(t, response.Data.Token)
+
+	return response.Data.Token
+}
+
+func TestPreviewTokenCanReadDrafts(t *testing.T) {
+	previewToken := mintPreviewToken(t)
+
+	category := models.BlogCategory{Name: "Preview Scope", Slug: "preview-scope"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	draftContent := "<p>Unpublished edits</p>"
+	blog := models.BlogPost{Title: "Preview Scope Draft", Slug: "preview-scope-draft", Content: "<p>Draft</p>",
+		DraftContent: &draftContent, CategoryID: category.ID, CreatedBy: 1}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+	assert.NoError(t, database.DB.Model(&blog).Update("published", false).Error)
+
+	// Unpublished posts are listed for the preview token
+	total := authorizedListTotal(t, "/api/blog?published=false&q=Preview+Scope+Draft", previewToken)
+	assert.Equal(t, float64(1), total)
+
+	// And so is the autosaved draft
+	w := doJSON(t, "GET", fmt.Sprintf("/api/blog/%d/draft", blog.ID), previewToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestPreviewTokenCannotWrite(t *testing.T) {
+	previewToken := mintPreviewToken(t)
+
+	category := models.BlogCategory{Name: "Preview Writes", Slug: "preview-writes"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	blog := models.BlogPost{Title: "Preview Protected", Slug: "preview-protected", Content: "<p>Content</p>", CategoryID: category.ID, Published: true}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	w := doJSON(t, "POST", "/api/blog", previewToken, services.CreateBlogRequest{
+		Title:      "Preview Created",
+		Content:    "<p>Content</p>",
+		CategoryID: category.ID,
+	})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = doJSON(t, "DELETE", fmt.Sprintf("/api/blog/%d", blog.ID), previewToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NoError(t, database.DB.First(&models.BlogPost{}, blog.ID).Error)
+
+	// Minting more tokens and trading it for a full token are refused too
+	w = doJSON(t, "POST", "/api/tokens/scoped", previewToken, services.ScopedTokenRequest{
+		Scope:     services.ScopePreviewRead,
+		ExpiresIn: 3600,
+	})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = doJSON(t, "POST", "/api/auth/refresh", "", map[string]string{"refresh_token": previewToken})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestMintScopedTokenRejectsUnknownScope(t *testing.T) {
+	loginAndGetToken(t)
+
+	w := doJSON(t, "POST", "/api/tokens/scoped", accessToken, services.ScopedTokenRequest{Scope: "admin:all", ExpiresIn: 3600})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = doJSON(t, "POST", "/api/tokens/scoped", accessToken, services.ScopedTokenRequest{Scope: services.ScopePreviewRead, ExpiresIn: 10})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func TestBlogMetaFallsBackToContent(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := models.BlogCategory{Name: "SEO Posts", Slug: "seo-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	blog := createBlog(t, services.CreateBlogRequest{
+		Title:      "Search Friendly Post",
+		Excerpt:    "A short summary for search results",
+		Content:    "<p>Body</p>",
+		CategoryID: category.ID,
+		Published:  true,
+	})
+	id := uint(blog["id"].(float64))
+	slug := blog["slug"].(string)
+
+	// The first image in sort order is the cover, skipping other media types
+	for _, media := range []services.BlogMediaRequest{
+		{Type: "video", URL: "https://example.com/intro.mp4", SortOrder: 0},
+		{URL: "https://example.com/second.png", SortOrder: 2},
+		{URL: "https://example.com/cover.png", SortOrder: 1},
+	} {
+		w := doJSON(t, "POST", fmt.Sprintf("/api/blog/%d/media", id), accessToken, media)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	meta := getResponseData(t, "/api/blog/slug/"+slug+"/meta")
+	assert.Equal(t, slug, meta["slug"])
+	assert.Equal(t, "Search Friendly Post", meta["meta_title"])
+	assert.Equal(t, "A short summary for search results", meta["meta_description"])
+	assert.Equal(t, "https://example.com/cover.png", meta["og_image"])
+
+	// The full response carries the same resolved meta
+	detail := getResponseData(t, fmt.Sprintf("/api/blog/%d", id))
+	assert.Equal(t, "Search Friendly Post", detail["meta_title"])
+	assert.Equal(t, "https://example.com/cover.png", detail["og_image"])
+
+	// Stored meta takes precedence, and clearing a field restores its fallback
+	metaTitle := "Custom Meta Title"
+	ogImage := "https://example.com/social.png"
+	w := doJSON(t, "PUT", fmt.Sprintf("/api/blog/%d", id), accessToken, services.UpdateBlogRequest{MetaTitle: &metaTitle, OGImage: &ogImage})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	meta = getResponseData(t, "/api/blog/slug/"+slug+"/meta")
+	assert.Equal(t, "Custom Meta Title", meta["meta_title"])
+	assert.Equal(t, "A short summary for search results", meta["meta_description"])
+	assert.Equal(t, "https://example.com/social.png", meta["og_image"])
+
+	empty := ""
+	w = doJSON(t, "PUT", fmt.Sprintf("/api/blog/%d", id), accessToken, services.UpdateBlogRequest{MetaTitle: &empty})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	meta = getResponseData(t, "/api/blog/slug/"+slug+"/meta")
+	assert.Equal(t, "Search Friendly Post", meta["meta_title"])
+}
+
+func TestProjectMetaFallsBackToContent(t *testing.T) {
+	category := models.ProjectCategory{Name: "SEO Projects", Slug: "seo-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	project := models.Project{
+		Title:       "Search Friendly Project",
+		Slug:        "search-friendly-project",
+		Description: "<p>A project <strong>description</strong></p>",
+		CategoryID:  category.ID,
+		Published:   true,
+	}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	// Without media there is no image to fall back to
+	detail := getResponseData(t, fmt.Sprintf("/api/projects/%d", project.ID))
+	assert.Equal(t, "Search Friendly Project", detail["meta_title"])
+	assert.Equal(t, "A project description", detail["meta_description"])
+	assert.Equal(t, "", detail["og_image"])
+}
+
+func TestBlogMetaNotFound(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/blog/slug/missing-seo-post/meta", nil)
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
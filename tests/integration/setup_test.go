@@ -1,20 +1,29 @@
 package integration
 
 import (
+	"bytes"
+	"encoding/json"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
 	"zionechainapi/configs"
 	"zionechainapi/internal/controllers"
 	"zionechainapi/internal/database"
 	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
 )
 
 var (
-	router *gin.Engine
-	config *configs.Config
+	router       *gin.Engine
+	config       *configs.Config
+	outboxWorker *services.OutboxWorker
 )
 
 func TestMain(m *testing.M) {
@@ -28,9 +37,24 @@ func TestMain(m *testing.M) {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	// Override config with test values
+	// Override config with test values. The suite runs against an in-memory
+	// SQLite database unless TEST_DB_DRIVER names a database server, where
+	// zione_test_db is used.
 	config.App.Env = "testing"
+	config.Database.Driver = os.Getenv("TEST_DB_DRIVER")
 	config.Database.Name = "zione_test_db"
+	if config.Database.Driver == "" || strings.EqualFold(config.Database.Driver, "sqlite") {
+		config.Database.Driver = "sqlite"
+		config.Database.Name = ":memory:"
+	}
+	config.Cache.ListTTL = 0 // tests seed the database directly, bypassing cache invalidation
+	config.Webhook.RetryBackoff = 10 * time.Millisecond
+
+	// Keep uploaded files out of the working tree
+	config.Storage.LocalPath, err = os.MkdirTemp("", "zione-test-uploads")
+	if err != nil {
+		log.Fatalf("Failed to create upload directory: %v", err)
+	}
 
 	// Setup database connection
 	_, err = database.Connect(config)
@@ -43,34 +67,73 @@ func TestMain(m *testing.M) {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	// Create the roles, the account the tests log in with and the content they assume
+	if err := seedFixtures(); err != nil {
+		log.Fatalf("Failed to seed database: %v", err)
+	}
+
 	// Initialize router and routes
 	router = gin.Default()
+	router.RedirectTrailingSlash = true
+	router.HandleMethodNotAllowed = true
+	router.NoMethod(middleware.MethodNotAllowed(router))
+	if err := router.SetTrustedProxies(config.App.TrustedProxies); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
 	setupRoutes(router)
 
+	// Deliver webhook events promptly
+	outboxWorker = services.NewOutboxWorker(services.NewWebhookDispatcher(config.Webhook), config.Webhook.OutboxBatchSize).
+		Start(20 * time.Millisecond)
+
 	// Run tests
 	code := m.Run()
 
 	// Clean up
+	outboxWorker.Stop()
+	if err := cleanupFixtures(); err != nil {
+		log.Fatalf("Failed to clean up database: %v", err)
+	}
 	if err := database.Close(); err != nil {
 		log.Fatalf("Failed to close database connection: %v", err)
 	}
+	os.RemoveAll(config.Storage.LocalPath)
 
 	os.Exit(code)
 }
 
 func setupRoutes(router *gin.Engine) {
-	// API base group
+	// Sitemap lives outside the API group
+	controllers.NewSitemapController(config).Routes(router)
+
+	// API base group, where request bodies must be JSON
 	api := router.Group("/api")
+	api.Use(middleware.RequireJSON())
 
 	// Initialize controllers
 	authController := controllers.NewAuthController(config)
+	versionController := controllers.NewVersionController(config)
 	projectController := controllers.NewProjectController(config)
 	blogController := controllers.NewBlogController(config)
 	categoryController := controllers.NewCategoryController(config)
 	tagController := controllers.NewTagController(config)
+	technologyController := controllers.NewTechnologyController(config)
+	auditController := controllers.NewAuditController(config)
+	statsController := controllers.NewStatsController(config)
+	activityController := controllers.NewActivityController(config)
+	draftController := controllers.NewDraftController(config)
+	tokenController := controllers.NewTokenController(config)
+	webhookController := controllers.NewWebhookController(config)
+	commentController := controllers.NewCommentController(config)
+	contactController := controllers.NewContactController(config)
+	backupController := controllers.NewBackupController(config)
+	uploadController := controllers.NewUploadController(config)
+	userController := controllers.NewUserController(config)
+	resumeController := controllers.NewResumeController(database.DB, config)
 
 	// Register routes
 	authController.Routes(api)
+	versionController.Routes(api)
 	
 	// Create auth middleware for protected routes
 	authMiddleware := middleware.Auth(config)
@@ -80,4 +143,45 @@ func setupRoutes(router *gin.Engine) {
 	blogController.Routes(api, authMiddleware)
 	categoryController.Routes(api, authMiddleware)
 	tagController.Routes(api, authMiddleware)
-}
\ No newline at end of file
+	technologyController.Routes(api, authMiddleware)
+	auditController.Routes(api, authMiddleware)
+	statsController.Routes(api, authMiddleware)
+	activityController.Routes(api, authMiddleware)
+	draftController.Routes(api, authMiddleware)
+	tokenController.Routes(api, authMiddleware)
+	webhookController.Routes(api, authMiddleware)
+	commentController.Routes(api, authMiddleware)
+	contactController.Routes(api, authMiddleware)
+	backupController.Routes(api, authMiddleware)
+	uploadController.Routes(router.Group("/api"), authMiddleware)
+	userController.Routes(api, middleware.OptionalAuth(config))
+	resumeController.Routes(api, authMiddleware)
+	resumeController.UploadRoutes(router.Group("/api"), authMiddleware)
+}
+
+// doJSON sends a request through the test router. A []byte body is sent as
+// is and any other non-nil body is encoded as JSON. The request is authorized
+// with token unless it is empty.
+func doJSON(t *testing.T, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	var payload []byte
+	switch body := body.(type) {
+	case nil:
+	case []byte:
+		payload = body
+	default:
+		var err error
+		payload, err = json.Marshal(body)
+		assert.NoError(t, err)
+	}
+
+	req, err := http.NewRequest(method, path, bytes.NewReader(payload))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func TestSitemapListsPublishedContent(t *testing.T) {
+	projectCategory := models.ProjectCategory{Name: "Sitemap Projects", Slug: "sitemap-projects"}
+	assert.NoError(t, database.DB.Create(&projectCategory).Error)
+	draftCategory := models.BlogCategory{Name: "Sitemap Drafts", Slug: "sitemap-drafts"}
+	assert.NoError(t, database.DB.Create(&draftCategory).Error)
+	blogCategory := models.BlogCategory{Name: "Sitemap Posts", Slug: "sitemap-posts"}
+	assert.NoError(t, database.DB.Create(&blogCategory).Error)
+
+	updatedAt := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	published := models.Project{Title: "Sitemap Project", Slug: "sitemap-project", CategoryID: projectCategory.ID, Published: true}
+	assert.NoError(t, database.DB.Create(&published).Error)
+	assert.NoError(t, database.DB.Model(&published).UpdateColumn("updated_at", updatedAt).Error)
+
+	post := models.BlogPost{Title: "Sitemap Post", Slug: "sitemap-post", CategoryID: blogCategory.ID, Published: true}
+	assert.NoError(t, database.DB.Create(&post).Error)
+
+	// Drafts and categories with only drafts stay out of the sitemap
+	draftProject := models.Project{Title: "Sitemap Draft", Slug: "sitemap-draft-project", CategoryID: projectCategory.ID}
+	assert.NoError(t, database.DB.Create(&draftProject).Error)
+	assert.NoError(t, database.DB.Model(&draftProject).Update("published", false).Error)
+	draftPost := models.BlogPost{Title: "Sitemap Draft", Slug: "sitemap-draft-post", CategoryID: draftCategory.ID}
+	assert.NoError(t, database.DB.Create(&draftPost).Error)
+	assert.NoError(t, database.DB.Model(&draftPost).Update("published", false).Error)
+
+	req, err := http.NewRequest("GET", "/sitemap.xml", nil)
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), xml.Header))
+
+	var urlSet services.SitemapURLSet
+	assert.NoError(t, xml.Unmarshal(w.Body.Bytes(), &urlSet))
+	assert.Equal(t, "urlset", urlSet.XMLName.Local)
+	assert.Equal(t, services.SitemapNamespace, urlSet.XMLName.Space)
+
+	lastMods := make(map[string]string)
+	for _, u := range urlSet.URLs {
+		_, err := time.Parse(time.RFC3339, u.LastMod)
+		assert.NoError(t, err, u.Loc)
+		lastMods[u.Loc] = u.LastMod
+	}
+
+	baseURL := strings.TrimRight(config.App.URL, "/")
+	assert.Equal(t, "2024-03-01T12:00:00Z", lastMods[baseURL+"/projects/sitemap-project"])
+	assert.Contains(t, lastMods, baseURL+"/blog/sitemap-post")
+	assert.Contains(t, lastMods, baseURL+"/projects/category/sitemap-projects")
+	assert.Contains(t, lastMods, baseURL+"/blog/category/sitemap-posts")
+
+	assert.NotContains(t, lastMods, baseURL+"/projects/sitemap-draft-project")
+	assert.NotContains(t, lastMods, baseURL+"/blog/sitemap-draft-post")
+	assert.NotContains(t, lastMods, baseURL+"/blog/category/sitemap-drafts")
+}
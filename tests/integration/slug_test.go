@@ -0,0 +1,277 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+// checkSlug calls a slug availability endpoint and returns the response data
+func checkSlug(t *testing.T, path, title string) map[string]interface{} {
+	req, err := http.NewRequest("GET", path+"?title="+url.QueryEscape(title), nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	return response["data"].(map[string]interface{})
+}
+
+func TestProjectSlugAvailable(t *testing.T) {
+	category := models.ProjectCategory{Name: "Slug Projects", Slug: "slug-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	project := models.Project{Title: "Taken Project", Slug: "taken-project", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	data := checkSlug(t, "/api/projects/slug-available", "Taken Project")
+	assert.Equal(t, "taken-project", data["slug"])
+	assert.Equal(t, false, data["available"])
+
+	data = checkSlug(t, "/api/projects/slug-available", "Brand New Project!")
+	assert.Equal(t, "brand-new-project", data["slug"])
+	assert.Equal(t, true, data["available"])
+}
+
+func TestBlogSlugAvailable(t *testing.T) {
+	category := models.BlogCategory{Name: "Slug Posts", Slug: "slug-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	post := models.BlogPost{Title: "Taken Post", Slug: "taken-post", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&post).Error)
+
+	data := checkSlug(t, "/api/blog/slug-available", "Taken Post")
+	assert.Equal(t, "taken-post", data["slug"])
+	assert.Equal(t, false, data["available"])
+
+	data = checkSlug(t, "/api/blog/slug-available", "Café Post")
+	assert.Equal(t, "cafe-post", data["slug"])
+	assert.Equal(t, true, data["available"])
+}
+
+func TestSlugAvailableRequiresTitle(t *testing.T) {
+	for _, path := range []string{"/api/projects/slug-available", "/api/blog/slug-available?title=%21%21"} {
+		req, err := http.NewRequest("GET", path, nil)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
+
+func TestCreateBlogWithPersianTitles(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := models.BlogCategory{Name: "Persian Posts", Slug: "persian-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	first := createBlog(t, services.CreateBlogRequest{
+		Title:      "سلام دنیا",
+		Content:    "<p>محتوا</p>",
+		CategoryID: category.ID,
+	})
+	second := createBlog(t, services.CreateBlogRequest{
+		Title:      "خداحافظ دنیا",
+		Content:    "<p>محتوا</p>",
+		CategoryID: category.ID,
+	})
+
+	// Persian titles are transliterated into distinct, usable slugs
+	assert.Equal(t, "slam-dnya", first["slug"])
+	assert.Equal(t, "khdahafz-dnya", second["slug"])
+
+	// Repeating a Persian title still yields a unique slug
+	repeated := createBlog(t, services.CreateBlogRequest{
+		Title:      "سلام دنیا",
+		Content:    "<p>محتوا</p>",
+		CategoryID: category.ID,
+	})
+	assert.NotEqual(t, first["slug"], repeated["slug"])
+	assert.NotEmpty(t, repeated["slug"])
+}
+
+// regenerateSlug calls a slug regeneration endpoint as the admin and returns the response data
+func regenerateSlug(t *testing.T, path string) map[string]interface{} {
+	loginAndGetToken(t)
+
+	req, err := http.NewRequest("POST", path, nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	return response["data"].(map[string]interface{})
+}
+
+func TestRegenerateProjectSlug(t *testing.T) {
+	category := models.ProjectCategory{Name: "Regenerated Projects", Slug: "regenerated-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	project := models.Project{Title: "Old Project Title", Slug: "old-project-title", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	// An unchanged title keeps its slug
+	path := fmt.Sprintf("/api/projects/%d/regenerate-slug", project.ID)
+	data := regenerateSlug(t, path)
+	assert.Equal(t, "old-project-title", data["slug"])
+	assert.Equal(t, false, data["changed"])
+
+	// The slug follows a renamed title
+	assert.NoError(t, database.DB.Model(&project).Update("title", "New Project Title").Error)
+	data = regenerateSlug(t, path)
+	assert.Equal(t, "new-project-title", data["slug"])
+	assert.Equal(t, "old-project-title", data["previous_slug"])
+	assert.Equal(t, true, data["changed"])
+
+	var stored models.Project
+	assert.NoError(t, database.DB.First(&stored, project.ID).Error)
+	assert.Equal(t, "new-project-title", stored.Slug)
+
+	// The new URL is announced and audited
+	outboxEvent := outboxEventFor(t, services.WebhookEventProjectUpdated, project.ID)
+	if assert.NotNil(t, outboxEvent) {
+		assert.Contains(t, outboxEvent.Payload, `"slug":"new-project-title"`)
+	}
+	assert.Equal(t, int64(1), countRows(t, &models.AuditLog{}, "resource_type = ? AND resource_id = ? AND action = ?",
+		services.AuditResourceProject, project.ID, models.AuditActionUpdate))
+}
+
+func TestRegenerateBlogSlugIsUnique(t *testing.T) {
+	category := models.BlogCategory{Name: "Regenerated Posts", Slug: "regenerated-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	taken := models.BlogPost{Title: "Popular Post", Slug: "popular-post", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&taken).Error)
+	post := models.BlogPost{Title: "Popular Post", Slug: "draft-post", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&post).Error)
+
+	// The slug of the title is taken, so the new slug is suffixed
+	data := regenerateSlug(t, fmt.Sprintf("/api/blog/%d/regenerate-slug", post.ID))
+	slug := data["slug"].(string)
+	assert.Regexp(t, `^popular-post-[0-9a-f]+$`, slug)
+	assert.NotEqual(t, "draft-post", slug)
+	assert.Equal(t, "draft-post", data["previous_slug"])
+
+	var count int64
+	assert.NoError(t, database.DB.Model(&models.BlogPost{}).Where("slug = ?", slug).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	outboxEvent := outboxEventFor(t, services.WebhookEventBlogUpdated, post.ID)
+	if assert.NotNil(t, outboxEvent) {
+		assert.Contains(t, outboxEvent.Payload, `"slug":"`+slug+`"`)
+	}
+	assert.Equal(t, int64(1), countRows(t, &models.AuditLog{}, "resource_type = ? AND resource_id = ? AND action = ?",
+		services.AuditResourceBlogPost, post.ID, models.AuditActionUpdate))
+
+	// Regenerating again keeps the suffixed slug rather than churning it
+	data = regenerateSlug(t, fmt.Sprintf("/api/blog/%d/regenerate-slug", post.ID))
+	assert.Equal(t, slug, data["slug"])
+	assert.Equal(t, false, data["changed"])
+}
+
+func TestRegenerateSlugErrors(t *testing.T) {
+	loginAndGetToken(t)
+
+	tests := []struct {
+		method string
+		path   string
+		token  string
+		status int
+	}{
+		{"POST", "/api/projects/999999/regenerate-slug", accessToken, http.StatusNotFound},
+		{"POST", "/api/blog/abc/regenerate-slug", accessToken, http.StatusBadRequest},
+		{"POST", "/api/projects/1/regenerate-slug", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, tt.path, nil)
+		assert.NoError(t, err)
+		if tt.token != "" {
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tt.token))
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.status, w.Code, tt.path)
+	}
+}
+
+// getSlug requests a slug route without following redirects
+func getSlug(t *testing.T, path string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest("GET", path, nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOldProjectSlugRedirectsToCurrent(t *testing.T) {
+	category := models.ProjectCategory{Name: "Renamed Projects", Slug: "renamed-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	project := models.Project{Title: "First Name Project", Slug: "first-name-project", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	// Rename twice, so the first slug is two changes behind
+	projectService := services.NewProjectService()
+	for _, title := range []string{"Second Name Project", "Third Name Project"} {
+		_, err := projectService.UpdateProject(project.ID, services.UpdateProjectRequest{Title: stringPtr(title)}, 1)
+		assert.NoError(t, err)
+	}
+
+	for _, old := range []string{"first-name-project", "second-name-project"} {
+		w := getSlug(t, "/api/projects/slug/"+old+"?locale=en")
+		assert.Equal(t, http.StatusMovedPermanently, w.Code, old)
+		assert.Equal(t, "/api/projects/slug/third-name-project?locale=en", w.Header().Get("Location"))
+		assert.Contains(t, w.Body.String(), `"slug":"third-name-project"`)
+	}
+
+	// Following the redirect resolves to the renamed project
+	w := getSlug(t, "/api/projects/slug/third-name-project")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(project.ID), response["data"].(map[string]interface{})["id"])
+}
+
+func TestOldBlogSlugRedirectsToCurrent(t *testing.T) {
+	category := models.BlogCategory{Name: "Renamed Posts", Slug: "renamed-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	post := models.BlogPost{Title: "Renamed Post", Slug: "stale-post", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&post).Error)
+
+	// Regenerating the slug records the old one too
+	regenerateSlug(t, fmt.Sprintf("/api/blog/%d/regenerate-slug", post.ID))
+
+	w := getSlug(t, "/api/blog/slug/stale-post")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/api/blog/slug/renamed-post", w.Header().Get("Location"))
+
+	w = getSlug(t, "/api/blog/slug/stale-post/meta")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/api/blog/slug/renamed-post/meta", w.Header().Get("Location"))
+
+	w = getSlug(t, "/api/blog/slug/renamed-post")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), fmt.Sprintf(`"id":%d`, post.ID))
+
+	// Slugs no record ever had are still not found
+	w = getSlug(t, "/api/blog/slug/never-a-post")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
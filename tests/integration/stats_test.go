@@ -0,0 +1,88 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+// getStats fetches the portfolio statistics as the logged in test user
+func getStats(t *testing.T) services.StatsResponse {
+	req, err := http.NewRequest("GET", "/api/stats", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data services.StatsResponse `json:"data"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	return response.Data
+}
+
+// countRows counts the rows of a model matching an optional condition
+func countRows(t *testing.T, model interface{}, query ...interface{}) int64 {
+	var count int64
+	db := database.DB.Model(model)
+	if len(query) > 0 {
+		db = db.Where(query[0], query[1:]...)
+	}
+	assert.NoError(t, db.Count(&count).Error)
+	return count
+}
+
+func TestStats(t *testing.T) {
+	loginAndGetToken(t)
+
+	stats := getStats(t)
+
+	// The counts match the seeded data
+	assert.Equal(t, countRows(t, &models.Project{}, "published = ?", true), stats.Projects.Published)
+	assert.Equal(t, countRows(t, &models.Project{}, "published = ?", false), stats.Projects.Draft)
+	assert.Equal(t, countRows(t, &models.BlogPost{}, "published = ?", true), stats.BlogPosts.Published)
+	assert.Equal(t, countRows(t, &models.BlogPost{}, "published = ?", false), stats.BlogPosts.Draft)
+	assert.Equal(t, countRows(t, &models.ProjectCategory{})+countRows(t, &models.BlogCategory{}), stats.Categories)
+	assert.Equal(t, countRows(t, &models.Tag{}), stats.Tags)
+	assert.Equal(t, countRows(t, &models.ProjectMedia{})+countRows(t, &models.BlogMedia{}), stats.Media)
+
+	// Creating a category, a tag and a project is reflected in the next call
+	category := createProjectCategory(t, "Stats Projects")
+	tag := models.Tag{Name: "Stats Tag", Slug: "stats-tag"}
+	assert.NoError(t, database.DB.Create(&tag).Error)
+
+	w := doJSON(t, "POST", "/api/projects", accessToken, services.CreateProjectRequest{
+		Title:       "Stats Project",
+		Description: "Description",
+		Content:     "Content",
+		CategoryID:  uint(category["id"].(float64)),
+		Published:   true,
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	updated := getStats(t)
+	assert.Equal(t, stats.Projects.Published+1, updated.Projects.Published)
+	assert.Equal(t, stats.Projects.Draft, updated.Projects.Draft)
+	assert.Equal(t, stats.Categories+1, updated.Categories)
+	assert.Equal(t, stats.Tags+1, updated.Tags)
+}
+
+func TestStatsRequiresAdmin(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/stats", nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,241 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func TestTagGetOrCreate(t *testing.T) {
+	existing := models.Tag{Name: "Golang", Slug: "golang"}
+	assert.NoError(t, database.DB.Create(&existing).Error)
+
+	tagService := services.NewTagService()
+	tags, err := tagService.GetOrCreate([]string{"Golang", "Fresh Tag", " fresh tag ", ""})
+	assert.NoError(t, err)
+
+	// The existing tag is reused and the repeated new name creates one tag
+	assert.Len(t, tags, 2)
+	assert.Equal(t, existing.ID, tags[0].ID)
+	assert.Equal(t, "fresh-tag", tags[1].Slug)
+
+	var count int64
+	assert.NoError(t, database.DB.Model(&models.Tag{}).Where("slug IN ?", []string{"golang", "fresh-tag"}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	// Resolving the same names again creates nothing new
+	again, err := tagService.GetOrCreate([]string{"fresh tag", "golang"})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{tags[1].ID, existing.ID}, []uint{again[0].ID, again[1].ID})
+}
+
+func TestCreateProjectWithTagNames(t *testing.T) {
+	loginAndGetToken(t)
+
+	existing := models.Tag{Name: "Existing Name Tag", Slug: "existing-name-tag"}
+	assert.NoError(t, database.DB.Create(&existing).Error)
+	category := createProjectCategory(t, "Tag Name Projects")
+
+	createRequest := services.CreateProjectRequest{
+		Title:       "Tag Name Project",
+		Description: "Project tagged by name",
+		Content:     "Content",
+		CategoryID:  uint(category["id"].(float64)),
+		TagIDs:      []uint{existing.ID},
+		Tags:        []string{"Existing Name Tag", "Brand New Name Tag"},
+	}
+
+	jsonData, err := json.Marshal(createRequest)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest("POST", "/api/projects", bytes.NewBuffer(jsonData))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	// The tag given by both id and name is attached once
+	tags := response["data"].(map[string]interface{})["tags"].([]interface{})
+	slugs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		slugs = append(slugs, tag.(map[string]interface{})["slug"].(string))
+	}
+	assert.ElementsMatch(t, []string{"existing-name-tag", "brand-new-name-tag"}, slugs)
+}
+
+func TestMergeTag(t *testing.T) {
+	loginAndGetToken(t)
+
+	source := models.Tag{Name: "Merge Source", Slug: "merge-source"}
+	target := models.Tag{Name: "Merge Target", Slug: "merge-target"}
+	assert.NoError(t, database.DB.Create(&source).Error)
+	assert.NoError(t, database.DB.Create(&target).Error)
+
+	category := models.ProjectCategory{Name: "Merge Projects", Slug: "merge-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	// One project carries only the source tag, the other carries both
+	sourceOnly := models.Project{Title: "Source Only", Slug: "merge-source-only", CategoryID: category.ID, Tags: []models.Tag{source}}
+	both := models.Project{Title: "Both Tags", Slug: "merge-both-tags", CategoryID: category.ID, Tags: []models.Tag{source, target}}
+	assert.NoError(t, database.DB.Create(&sourceOnly).Error)
+	assert.NoError(t, database.DB.Create(&both).Error)
+
+	w := doJSON(t, "POST", fmt.Sprintf("/api/tags/%d/merge", source.ID), accessToken, services.MergeTagRequest{TargetID: target.ID})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Both projects carry the target tag exactly once
+	for _, projectID := range []uint{sourceOnly.ID, both.ID} {
+		var tagIDs []uint
+		assert.NoError(t, database.DB.Table("project_tags").Where("project_id = ?", projectID).Pluck("tag_id", &tagIDs).Error)
+		assert.Equal(t, []uint{target.ID}, tagIDs)
+	}
+
+	// The source tag is gone
+	var count int64
+	assert.NoError(t, database.DB.Model(&models.Tag{}).Where("id = ?", source.ID).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+
+	// Merging a tag into itself is rejected
+	w = doJSON(t, "POST", fmt.Sprintf("/api/tags/%d/merge", target.ID), accessToken, services.MergeTagRequest{TargetID: target.ID})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAssignTag(t *testing.T) {
+	loginAndGetToken(t)
+
+	tag := models.Tag{Name: "Assigned Tag", Slug: "assigned-tag"}
+	assert.NoError(t, database.DB.Create(&tag).Error)
+
+	projectCategory := models.ProjectCategory{Name: "Assign Projects", Slug: "assign-projects"}
+	assert.NoError(t, database.DB.Create(&projectCategory).Error)
+	blogCategory := models.BlogCategory{Name: "Assign Posts", Slug: "assign-posts"}
+	assert.NoError(t, database.DB.Create(&blogCategory).Error)
+
+	// One project already carries the tag
+	tagged := models.Project{Title: "Already Tagged", Slug: "assign-already-tagged", CategoryID: projectCategory.ID, Tags: []models.Tag{tag}}
+	untagged := models.Project{Title: "Untagged", Slug: "assign-untagged", CategoryID: projectCategory.ID}
+	assert.NoError(t, database.DB.Create(&tagged).Error)
+	assert.NoError(t, database.DB.Create(&untagged).Error)
+	blog := models.BlogPost{Title: "Assign Post", Slug: "assign-post", Content: "Content", CategoryID: blogCategory.ID}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	req := services.AssignTagRequest{
+		ProjectIDs: []uint{tagged.ID, untagged.ID, untagged.ID, 999999},
+		BlogIDs:    []uint{blog.ID},
+	}
+
+	assign := func() services.AssignTagResponse {
+		w := doJSON(t, "POST", fmt.Sprintf("/api/tags/%d/assign", tag.ID), accessToken, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data services.AssignTagResponse `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Data
+	}
+
+	result := assign()
+	assert.Equal(t, int64(1), result.Projects)
+	assert.Equal(t, int64(1), result.BlogPosts)
+	assert.Equal(t, []uint{999999}, result.InvalidProjectIDs)
+	assert.Empty(t, result.InvalidBlogIDs)
+
+	// Running the assignment again changes nothing
+	result = assign()
+	assert.Equal(t, int64(0), result.Projects)
+	assert.Equal(t, int64(0), result.BlogPosts)
+	assert.Equal(t, []uint{999999}, result.InvalidProjectIDs)
+
+	// Each item carries the tag exactly once
+	for _, projectID := range []uint{tagged.ID, untagged.ID} {
+		var tagIDs []uint
+		assert.NoError(t, database.DB.Table("project_tags").Where("project_id = ?", projectID).Pluck("tag_id", &tagIDs).Error)
+		assert.Equal(t, []uint{tag.ID}, tagIDs)
+	}
+	var blogTagIDs []uint
+	assert.NoError(t, database.DB.Table("blog_tags").Where("blog_post_id = ?", blog.ID).Pluck("tag_id", &blogTagIDs).Error)
+	assert.Equal(t, []uint{tag.ID}, blogTagIDs)
+
+	// An empty request is rejected
+	w := doJSON(t, "POST", fmt.Sprintf("/api/tags/%d/assign", tag.ID), accessToken, services.AssignTagRequest{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUnassignTag(t *testing.T) {
+	loginAndGetToken(t)
+
+	tag := models.Tag{Name: "Unassigned Tag", Slug: "unassigned-tag"}
+	otherTag := models.Tag{Name: "Kept Tag", Slug: "kept-tag"}
+	assert.NoError(t, database.DB.Create(&tag).Error)
+	assert.NoError(t, database.DB.Create(&otherTag).Error)
+
+	projectCategory := models.ProjectCategory{Name: "Unassign Projects", Slug: "unassign-projects"}
+	assert.NoError(t, database.DB.Create(&projectCategory).Error)
+	blogCategory := models.BlogCategory{Name: "Unassign Posts", Slug: "unassign-posts"}
+	assert.NoError(t, database.DB.Create(&blogCategory).Error)
+
+	// One project carries the tag, the other only a different tag
+	tagged := models.Project{Title: "Tagged", Slug: "unassign-tagged", CategoryID: projectCategory.ID, Tags: []models.Tag{tag, otherTag}}
+	untagged := models.Project{Title: "Not Tagged", Slug: "unassign-not-tagged", CategoryID: projectCategory.ID, Tags: []models.Tag{otherTag}}
+	assert.NoError(t, database.DB.Create(&tagged).Error)
+	assert.NoError(t, database.DB.Create(&untagged).Error)
+	blog := models.BlogPost{Title: "Unassign Post", Slug: "unassign-post", Content: "Content", CategoryID: blogCategory.ID, Tags: []models.Tag{tag}}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	req := services.UnassignTagRequest{
+		ProjectIDs: []uint{tagged.ID, untagged.ID, 999999},
+		BlogIDs:    []uint{blog.ID},
+	}
+
+	unassign := func() services.UnassignTagResponse {
+		w := doJSON(t, "POST", fmt.Sprintf("/api/tags/%d/unassign", tag.ID), accessToken, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data services.UnassignTagResponse `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Data
+	}
+
+	// Only the items that carried the tag are counted
+	result := unassign()
+	assert.Equal(t, tag.ID, result.TagID)
+	assert.Equal(t, int64(1), result.Projects)
+	assert.Equal(t, int64(1), result.BlogPosts)
+
+	// Removing a tag the items no longer have is a no-op
+	result = unassign()
+	assert.Equal(t, int64(0), result.Projects)
+	assert.Equal(t, int64(0), result.BlogPosts)
+
+	// Other tags of the items are kept
+	for _, projectID := range []uint{tagged.ID, untagged.ID} {
+		var tagIDs []uint
+		assert.NoError(t, database.DB.Table("project_tags").Where("project_id = ?", projectID).Pluck("tag_id", &tagIDs).Error)
+		assert.Equal(t, []uint{otherTag.ID}, tagIDs)
+	}
+	var blogTagIDs []uint
+	assert.NoError(t, database.DB.Table("blog_tags").Where("blog_post_id = ?", blog.ID).Pluck("tag_id", &blogTagIDs).Error)
+	assert.Empty(t, blogTagIDs)
+
+	// An empty request is rejected
+	w := doJSON(t, "POST", fmt.Sprintf("/api/tags/%d/unassign", tag.ID), accessToken, services.UnassignTagRequest{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
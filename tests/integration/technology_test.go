@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+// createTechnology creates a technology through the API
+func createTechnology(t *testing.T, req services.TechnologyRequest) services.TechnologyResponse {
+	w := doJSON(t, "POST", "/api/technologies", accessToken, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response struct {
+		Data services.TechnologyResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	return response.Data
+}
+
+func TestFilterProjectsByTechnology(t *testing.T) {
+	loginAndGetToken(t)
+
+	golang := createTechnology(t, services.TechnologyRequest{Name: "Go", Category: "Language"})
+	assert.Equal(t, "go", golang.Slug)
+	assert.Equal(t, "language", golang.Category)
+	cpp := createTechnology(t, services.TechnologyRequest{Name: "C++", Category: "language"})
+	assert.Equal(t, "c-plus-plus", cpp.Slug)
+
+	// Names that share a slug are rejected
+	assert.Equal(t, http.StatusConflict, doJSON(t, "POST", "/api/technologies", accessToken, services.TechnologyRequest{Name: "GO"}).Code)
+
+	category := models.ProjectCategory{Name: "Technology Projects", Slug: "technology-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	// One project per technology, plus one using both
+	for _, req := range []services.CreateProjectRequest{
+		{Title: "Go Service", TechnologyIDs: []uint{golang.ID}},
+		{Title: "C++ Engine", TechnologyIDs: []uint{cpp.ID}},
+		{Title: "Go Bindings", TechnologyIDs: []uint{golang.ID, cpp.ID}},
+	} {
+		req.Description = "Built with technologies"
+		req.Content = "Content"
+		req.CategoryID = category.ID
+		req.Published = true
+
+		w := doJSON(t, "POST", "/api/projects", accessToken, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var created struct {
+			Data services.ProjectResponse `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		assert.Len(t, created.Data.Technologies, len(req.TechnologyIDs))
+	}
+
+	assert.Equal(t, float64(2), listTotal(t, "/api/projects?technology=go"))
+	assert.Equal(t, float64(2), listTotal(t, "/api/projects?technology=c-plus-plus"))
+	assert.Equal(t, float64(0), listTotal(t, "/api/projects?technology=missing-technology"))
+
+	// The catalog can be narrowed to a category
+	req, err := http.NewRequest("GET", "/api/technologies?category=language", nil)
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var languages struct {
+		Data []services.TechnologyResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &languages))
+	assert.Len(t, languages.Data, 2)
+}
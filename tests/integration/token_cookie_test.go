@@ -0,0 +1,152 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+)
+
+// responseCookie returns the cookie of the given name set by a response
+func responseCookie(w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+// useTokenDelivery configures token delivery for the duration of a test
+func useTokenDelivery(t *testing.T, delivery string) {
+	previous := config.Auth
+	config.Auth.TokenDelivery = delivery
+	config.Auth.CookieSecure = true
+	config.Auth.CookieSameSite = "lax"
+	t.Cleanup(func() { config.Auth = previous })
+}
+
+func TestLoginDeliversTokensAsCookies(t *testing.T) {
+	useTokenDelivery(t, "body")
+
+	w := doJSON(t, "POST", "/api/auth/login?token_delivery=cookie", "", services.LoginRequest{
+		Phone:    fixtureAdminPhone,
+		Password: fixtureAdminPassword,
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// The tokens are only in the cookies
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotContains(t, response.Data, "access_token")
+	assert.NotContains(t, response.Data, "refresh_token")
+	assert.NotNil(t, response.Data["user"])
+
+	access := responseCookie(w, middleware.AccessTokenCookie)
+	refresh := responseCookie(w, middleware.RefreshTokenCookie)
+	if !assert.NotNil(t, access) || !assert.NotNil(t, refresh) {
+		return
+	}
+	assert.True(t, access.HttpOnly)
+	assert.True(t, access.Secure)
+	assert.Equal(t, http.SameSiteLaxMode, access.SameSite)
+	assert.True(t, refresh.HttpOnly)
+	assert.Equal(t, "/api/auth", refresh.Path)
+
+	// A protected route accepts the access token cookie in place of the Authorization header
+	req, err := http.NewRequest("GET", "/api/drafts", nil)
+	assert.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: middleware.AccessTokenCookie, Value: access.Value})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// And refresh reads the refresh token cookie
+	req, err = http.NewRequest("POST", "/api/auth/refresh?token_delivery=cookie", nil)
+	assert.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: middleware.RefreshTokenCookie, Value: refresh.Value})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotNil(t, responseCookie(w, middleware.AccessTokenCookie))
+}
+
+func TestLogoutExpiresTokenCookies(t *testing.T) {
+	useTokenDelivery(t, "cookie")
+	config.Auth.CookieDomain = "example.com"
+
+	w := doJSON(t, "POST", "/api/auth/login", "", services.LoginRequest{
+		Phone:    fixtureAdminPhone,
+		Password: fixtureAdminPassword,
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	loginCookies := map[string]*http.Cookie{
+		middleware.AccessTokenCookie:  responseCookie(w, middleware.AccessTokenCookie),
+		middleware.RefreshTokenCookie: responseCookie(w, middleware.RefreshTokenCookie),
+	}
+
+	w = doJSON(t, "POST", "/api/auth/logout", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Both cookies are overwritten where they were set, emptied and expired
+	for name, set := range loginCookies {
+		if !assert.NotNil(t, set, name) {
+			continue
+		}
+		cleared := responseCookie(w, name)
+		if !assert.NotNil(t, cleared, name) {
+			continue
+		}
+		assert.Empty(t, cleared.Value, name)
+		assert.Less(t, cleared.MaxAge, 0, name)
+		assert.Equal(t, set.Path, cleared.Path, name)
+		assert.Equal(t, set.Domain, cleared.Domain, name)
+		assert.True(t, cleared.HttpOnly, name)
+	}
+}
+
+func TestLoginDeliversTokensInBodyByDefault(t *testing.T) {
+	useTokenDelivery(t, "body")
+
+	w := doJSON(t, "POST", "/api/auth/login", "", services.LoginRequest{Phone: fixtureAdminPhone, Password: fixtureAdminPassword})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, responseCookie(w, middleware.AccessTokenCookie))
+
+	w = doJSON(t, "POST", "/api/auth/login?token_delivery=both", "", services.LoginRequest{Phone: fixtureAdminPhone, Password: fixtureAdminPassword})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotNil(t, responseCookie(w, middleware.AccessTokenCookie))
+	assert.Contains(t, w.Body.String(), `"access_token"`)
+
+	w = doJSON(t, "POST", "/api/auth/login?token_delivery=carrier-pigeon", "", services.LoginRequest{Phone: fixtureAdminPhone, Password: fixtureAdminPassword})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestConfiguredCookieDelivery(t *testing.T) {
+	useTokenDelivery(t, "cookie")
+
+	w := doJSON(t, "POST", "/api/auth/login", "", services.LoginRequest{Phone: fixtureAdminPhone, Password: fixtureAdminPassword})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotNil(t, responseCookie(w, middleware.AccessTokenCookie))
+	assert.NotContains(t, w.Body.String(), `"access_token"`)
+
+	// A request can still ask for the tokens in the body
+	w = doJSON(t, "POST", "/api/auth/login?token_delivery=body", "", services.LoginRequest{Phone: fixtureAdminPhone, Password: fixtureAdminPassword})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, responseCookie(w, middleware.AccessTokenCookie))
+	assert.Contains(t, w.Body.String(), `"access_token"`)
+}
+
+func TestProtectedRouteWithoutTokenOrCookie(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/drafts", bytes.NewReader(nil))
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
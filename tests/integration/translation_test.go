@@ -0,0 +1,128 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func getResponseData(t *testing.T, path string) map[string]interface{} {
+	req, err := http.NewRequest("GET", path, nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	return response["data"].(map[string]interface{})
+}
+
+func TestBlogTranslationFallback(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := models.BlogCategory{Name: "Translated Posts", Slug: "translated-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	blog := models.BlogPost{
+		Title:      "Hello World",
+		Slug:       "translated-hello-world",
+		Excerpt:    "An English excerpt",
+		Content:    "<p>English content</p>",
+		CategoryID: category.ID,
+		Published:  true,
+	}
+	assert.NoError(t, database.DB.Create(&blog).Error)
+
+	// Translate the title and content, leaving the excerpt to fall back
+	translationPath := fmt.Sprintf("/api/blog/%d/translations/fa", blog.ID)
+	w := doJSON(t, "PUT", translationPath, accessToken, services.TranslationRequest{
+		Title:   "سلام دنیا",
+		Content: "<p>محتوای فارسی</p>",
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	detailPath := fmt.Sprintf("/api/blog/%d", blog.ID)
+	localized := getResponseData(t, detailPath+"?locale=fa")
+	assert.Equal(t, "سلام دنیا", localized["title"])
+	assert.Equal(t, "<p>محتوای فارسی</p>", localized["content"])
+	assert.Equal(t, "An English excerpt", localized["excerpt"])
+	assert.Equal(t, "translated-hello-world", localized["slug"])
+
+	// The slug endpoint and listings overlay the same translation
+	bySlug := getResponseData(t, "/api/blog/slug/translated-hello-world?locale=FA")
+	assert.Equal(t, "سلام دنیا", bySlug["title"])
+
+	list := getResponseData(t, fmt.Sprintf("/api/blog?category_id=%d&locale=fa", category.ID))
+	blogs := list["blogs"].([]interface{})
+	if assert.Len(t, blogs, 1) {
+		assert.Equal(t, "سلام دنیا", blogs[0].(map[string]interface{})["title"])
+	}
+
+	// Locales without a translation and the default locale serve the base content
+	for _, locale := range []string{"de", "en"} {
+		base := getResponseData(t, detailPath+"?locale="+locale)
+		assert.Equal(t, "Hello World", base["title"], locale)
+		assert.Equal(t, "<p>English content</p>", base["content"], locale)
+	}
+
+	// Malformed locales are rejected
+	req, err := http.NewRequest("GET", detailPath+"?locale=not+a+locale", nil)
+	assert.NoError(t, err)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Deleting the translation restores the fallback
+	req, err = http.NewRequest("DELETE", translationPath, nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	fallback := getResponseData(t, detailPath+"?locale=fa")
+	assert.Equal(t, "Hello World", fallback["title"])
+}
+
+func TestSaveTranslationUpdatesExistingLocale(t *testing.T) {
+	loginAndGetToken(t)
+
+	category := createProjectCategory(t, "Translated Projects")
+	project := models.Project{
+		Title:       "Base Project",
+		Slug:        "translated-base-project",
+		Description: "Base description",
+		CategoryID:  uint(category["id"].(float64)),
+		Published:   true,
+	}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	path := fmt.Sprintf("/api/projects/%d/translations/fa", project.ID)
+	w := doJSON(t, "PUT", path, accessToken, services.TranslationRequest{Title: "پروژه"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doJSON(t, "PUT", path, accessToken, services.TranslationRequest{Description: "توضیحات"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Saving again replaces the translation instead of adding a second one
+	var translations []models.Translation
+	assert.NoError(t, database.DB.Where("resource_type = ? AND resource_id = ?", models.TranslationResourceProject, project.ID).
+		Find(&translations).Error)
+	if assert.Len(t, translations, 1) {
+		assert.Equal(t, "", translations[0].Title)
+		assert.Equal(t, "توضیحات", translations[0].Description)
+	}
+
+	// Translations of missing projects are rejected
+	w = doJSON(t, "PUT", "/api/projects/999999/translations/fa", accessToken, services.TranslationRequest{Title: "پروژه"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,147 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+// pngHeader is enough of a PNG file for its content type to be detected, but not to be decoded
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// pngFile returns a small valid PNG image
+func pngFile(t *testing.T) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 2, 2))))
+	return buf.Bytes()
+}
+
+// uploadFile uploads content as a multipart file with the given name
+func uploadFile(t *testing.T, filename string, content []byte) *httptest.ResponseRecorder {
+	return uploadFileTo(t, "/api/uploads", filename, content)
+}
+
+// uploadFileTo posts content as the multipart file field of path
+func uploadFileTo(t *testing.T, path, filename string, content []byte) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	assert.NoError(t, err)
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	req, err := http.NewRequest("POST", path, &body)
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestDeletingMediaDeletesUploadedFile(t *testing.T) {
+	if config.Storage.Driver != "local" {
+		t.Skip("requires the local storage driver")
+	}
+	loginAndGetToken(t)
+
+	w := uploadFile(t, "cover.png", pngFile(t))
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var uploaded struct {
+		Data services.UploadResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &uploaded))
+	assert.Equal(t, "image/png", uploaded.Data.ContentType)
+
+	path := filepath.Join(config.Storage.LocalPath, filepath.FromSlash(uploaded.Data.Key))
+	_, err := os.Stat(path)
+	assert.NoError(t, err)
+
+	category := models.ProjectCategory{Name: "Upload Projects", Slug: "upload-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	project := models.Project{Title: "Upload Project", Slug: "upload-project", Content: "Content", CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	w = doJSON(t, "POST", fmt.Sprintf("/api/projects/%d/media", project.ID), accessToken, services.ProjectMediaRequest{URL: uploaded.Data.URL})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var media struct {
+		Data services.ProjectMediaResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &media))
+
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("/api/projects/media/%d", media.Data.ID), nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDeletingMediaKeepsFileStillReferenced(t *testing.T) {
+	if config.Storage.Driver != "local" {
+		t.Skip("requires the local storage driver")
+	}
+	loginAndGetToken(t)
+
+	w := uploadFile(t, "shared.png", pngFile(t))
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var uploaded struct {
+		Data services.UploadResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &uploaded))
+	path := filepath.Join(config.Storage.LocalPath, filepath.FromSlash(uploaded.Data.Key))
+
+	// The upload is both a media item and the SEO image of the project
+	category := models.ProjectCategory{Name: "Shared Upload Projects", Slug: "shared-upload-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	project := models.Project{Title: "Shared Upload Project", Slug: "shared-upload-project", Content: "Content", OGImage: uploaded.Data.URL, CategoryID: category.ID}
+	assert.NoError(t, database.DB.Create(&project).Error)
+	media := models.ProjectMedia{ProjectID: project.ID, URL: uploaded.Data.URL}
+	assert.NoError(t, database.DB.Create(&media).Error)
+
+	w = doJSON(t, "DELETE", fmt.Sprintf("/api/projects/media/%d", media.ID), accessToken, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	_, err := os.Stat(path)
+	assert.NoError(t, err)
+}
+
+func TestUploadRejectsDisallowedFiles(t *testing.T) {
+	loginAndGetToken(t)
+
+	// The extension must be allowed
+	assert.Equal(t, http.StatusUnprocessableEntity, uploadFile(t, "script.sh", []byte("#!/bin/sh")).Code)
+
+	// So must the detected content type, whatever the extension claims
+	assert.Equal(t, http.StatusUnprocessableEntity, uploadFile(t, "page.png", []byte("<html><script>alert(1)</script></html>")).Code)
+
+	// Images must decode, not just start with the right signature
+	w := uploadFile(t, "broken.png", pngHeader)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "not a valid image")
+
+	// Files over the size limit are refused
+	assert.Equal(t, http.StatusRequestEntityTooLarge, uploadFile(t, "large.png", append(pngHeader, make([]byte, config.Storage.MaxSize)...)).Code)
+}
@@ -0,0 +1,68 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func TestListUserContentHidesDrafts(t *testing.T) {
+	// Register a dedicated author
+	w := doJSON(t, "POST", "/api/auth/register", "", services.RegisterRequest{
+		Name:     "Author User",
+		Email:    "author@example.com",
+		Phone:    "+1234567892",
+		Password: "password123",
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	data := response["data"].(map[string]interface{})
+	authorToken := data["access_token"].(string)
+	authorID := uint(data["user"].(map[string]interface{})["id"].(float64))
+
+	// Create a published item and a draft of each kind for the author
+	projectCategory := models.ProjectCategory{Name: "Author Projects", Slug: "author-projects"}
+	assert.NoError(t, database.DB.Create(&projectCategory).Error)
+	blogCategory := models.BlogCategory{Name: "Author Posts", Slug: "author-posts"}
+	assert.NoError(t, database.DB.Create(&blogCategory).Error)
+
+	for _, slug := range []string{"author-published", "author-draft"} {
+		project := models.Project{Title: slug, Slug: slug, CategoryID: projectCategory.ID, CreatedBy: authorID}
+		assert.NoError(t, database.DB.Create(&project).Error)
+		blog := models.BlogPost{Title: slug, Slug: slug, CategoryID: blogCategory.ID, CreatedBy: authorID}
+		assert.NoError(t, database.DB.Create(&blog).Error)
+	}
+	assert.NoError(t, database.DB.Model(&models.Project{}).Where("slug = ?", "author-draft").Update("published", false).Error)
+	assert.NoError(t, database.DB.Model(&models.BlogPost{}).Where("slug = ?", "author-draft").Update("published", false).Error)
+
+	for _, resource := range []string{"projects", "blog"} {
+		path := fmt.Sprintf("/api/users/%d/%s", authorID, resource)
+
+		// Anonymous viewers only see published content
+		assert.Equal(t, float64(1), authorizedListTotal(t, path, ""), path)
+
+		// The owner also sees drafts
+		assert.Equal(t, float64(2), authorizedListTotal(t, path, authorToken), path)
+	}
+}
+
+func TestListUserContentNotFound(t *testing.T) {
+	for _, resource := range []string{"projects", "blog"} {
+		req, err := http.NewRequest("GET", fmt.Sprintf("/api/users/999999/%s", resource), nil)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	}
+}
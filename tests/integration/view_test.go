@@ -0,0 +1,75 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+func TestViewCounterFlushesConcurrentViews(t *testing.T) {
+	category := models.ProjectCategory{Name: "Viewed Projects", Slug: "viewed-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	projects := []models.Project{
+		{Title: "Viewed One", Slug: "viewed-one", CategoryID: category.ID, Published: true},
+		{Title: "Viewed Two", Slug: "viewed-two", CategoryID: category.ID, Published: true},
+	}
+	for i := range projects {
+		assert.NoError(t, database.DB.Create(&projects[i]).Error)
+	}
+
+	counter := services.NewViewCounter("projects")
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				counter.Record(projects[i%2].ID)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.NoError(t, counter.Flush())
+
+	// Views recorded during a flush are kept for the next one
+	counter.Record(projects[0].ID)
+	assert.Equal(t, uint(1), counter.Pending(projects[0].ID))
+	assert.NoError(t, counter.Stop())
+
+	var stored []models.Project
+	assert.NoError(t, database.DB.Order("id ASC").Find(&stored, []uint{projects[0].ID, projects[1].ID}).Error)
+	if assert.Len(t, stored, 2) {
+		assert.Equal(t, uint(501), stored[0].Views)
+		assert.Equal(t, uint(500), stored[1].Views)
+	}
+	assert.Equal(t, uint(0), counter.Pending(projects[0].ID))
+}
+
+func TestGetProjectCountsViews(t *testing.T) {
+	category := models.ProjectCategory{Name: "Counted Projects", Slug: "counted-projects"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+	project := models.Project{Title: "Counted Project", Slug: "counted-project", CategoryID: category.ID, Published: true}
+	assert.NoError(t, database.DB.Create(&project).Error)
+
+	// Views not yet written to the database are included in the count
+	for _, expected := range []float64{1, 2} {
+		req, err := http.NewRequest("GET", fmt.Sprintf("/api/projects/%d", project.ID), nil)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, expected, response["data"].(map[string]interface{})["views"])
+	}
+}
@@ -0,0 +1,155 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/database"
+	"zionechainapi/internal/models"
+	"zionechainapi/internal/services"
+)
+
+// receivedWebhook is a delivery captured by a test webhook server
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookReceiver starts a test server that records deliveries, answering the
+// first failures requests with a server error
+func webhookReceiver(t *testing.T, failures int) (*httptest.Server, <-chan receivedWebhook) {
+	deliveries := make(chan receivedWebhook, 10)
+	var mu sync.Mutex
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		mu.Lock()
+		attempts++
+		failed := attempts <= failures
+		mu.Unlock()
+
+		deliveries <- receivedWebhook{header: r.Header.Clone(), body: body}
+		if failed {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, deliveries
+}
+
+// subscribeWebhook creates a webhook subscription through the API and removes it when the test ends
+func subscribeWebhook(t *testing.T, url, secret string, events ...string) {
+	w := doJSON(t, "POST", "/api/webhooks", accessToken, services.CreateWebhookRequest{URL: url, Secret: secret, Events: events})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response struct {
+		Data services.WebhookResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	t.Cleanup(func() {
+		database.DB.Delete(&models.Webhook{}, response.Data.ID)
+	})
+}
+
+// nextDelivery waits for the next delivery of a test webhook server
+func nextDelivery(t *testing.T, deliveries <-chan receivedWebhook) receivedWebhook {
+	select {
+	case delivery := <-deliveries:
+		return delivery
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for webhook delivery")
+		return receivedWebhook{}
+	}
+}
+
+func TestWebhookBlogPublishedSignedPayload(t *testing.T) {
+	loginAndGetToken(t)
+
+	server, deliveries := webhookReceiver(t, 0)
+	secret := "blog-published-secret"
+	subscribeWebhook(t, server.URL, secret, services.WebhookEventBlogPublished)
+
+	category := models.BlogCategory{Name: "Webhook Posts", Slug: "webhook-posts"}
+	assert.NoError(t, database.DB.Create(&category).Error)
+
+	blog := createBlog(t, services.CreateBlogRequest{
+		Title:      "Webhook Post",
+		Content:    "<p>Published content</p>",
+		CategoryID: category.ID,
+		Published:  true,
+	})
+
+	delivery := nextDelivery(t, deliveries)
+
+	// The body is signed with the subscription secret
+	assert.Equal(t, services.SignWebhookPayload(secret, delivery.body), delivery.header.Get(services.WebhookSignatureHeader))
+	assert.Equal(t, services.WebhookEventBlogPublished, delivery.header.Get(services.WebhookEventHeader))
+	assert.Equal(t, "application/json", delivery.header.Get("Content-Type"))
+
+	var payload struct {
+		ID    string                 `json:"id"`
+		Event string                 `json:"event"`
+		Data  map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(delivery.body, &payload))
+	assert.Equal(t, services.WebhookEventBlogPublished, payload.Event)
+	assert.Equal(t, delivery.header.Get(services.WebhookDeliveryHeader), payload.ID)
+	assert.Equal(t, blog["id"], payload.Data["id"])
+	assert.Equal(t, "Webhook Post", payload.Data["title"])
+
+	// Events the subscription does not listen to are not delivered
+	select {
+	case unexpected := <-deliveries:
+		t.Fatalf("unexpected webhook delivery: %s", unexpected.header.Get(services.WebhookEventHeader))
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWebhookDeliveryRetries(t *testing.T) {
+	loginAndGetToken(t)
+
+	server, deliveries := webhookReceiver(t, 2)
+	subscribeWebhook(t, server.URL, "project-created-secret", services.WebhookEventProjectCreated)
+
+	category := createProjectCategory(t, "Webhook Projects")
+	w := doJSON(t, "POST", "/api/projects", accessToken, services.CreateProjectRequest{
+		Title:       "Webhook Project",
+		Description: "Description",
+		Content:     "Content",
+		CategoryID:  uint(category["id"].(float64)),
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Two failed attempts are retried with the same delivery id until the third succeeds
+	first := nextDelivery(t, deliveries)
+	second := nextDelivery(t, deliveries)
+	third := nextDelivery(t, deliveries)
+
+	deliveryID := first.header.Get(services.WebhookDeliveryHeader)
+	assert.NotEmpty(t, deliveryID)
+	assert.Equal(t, deliveryID, second.header.Get(services.WebhookDeliveryHeader))
+	assert.Equal(t, deliveryID, third.header.Get(services.WebhookDeliveryHeader))
+}
+
+func TestCreateWebhookRejectsUnknownEvent(t *testing.T) {
+	loginAndGetToken(t)
+
+	w := doJSON(t, "POST", "/api/webhooks", accessToken, services.CreateWebhookRequest{
+		URL:    "https://example.com/hook",
+		Secret: "a-long-enough-secret",
+		Events: []string{"blog.exploded"},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
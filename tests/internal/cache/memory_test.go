@@ -0,0 +1,72 @@
+package cache_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/configs"
+	"zionechainapi/internal/cache"
+	"zionechainapi/internal/clock"
+)
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC))
+	store := cache.NewMemoryCache().WithClock(fake)
+
+	store.Set("/api/projects", []byte("a"), time.Minute)
+
+	fake.Advance(59 * time.Second)
+	value, ok := store.Get("/api/projects")
+	assert.True(t, ok)
+	assert.Equal(t, "a", string(value))
+
+	fake.Advance(2 * time.Second)
+	_, ok = store.Get("/api/projects")
+	assert.False(t, ok)
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	store := cache.NewMemoryCache()
+
+	// Fill the cache with distinct query strings, as a crawler would
+	for i := 0; i < 1024; i++ {
+		store.Set(fmt.Sprintf("/api/projects?page=%d", i), []byte("page"), time.Hour)
+	}
+
+	// Reading the oldest entry makes it the most recently used
+	_, ok := store.Get("/api/projects?page=0")
+	assert.True(t, ok)
+
+	// The next entries push out the least recently used ones
+	store.Set("/api/projects?page=1024", []byte("page"), time.Hour)
+	store.Set("/api/projects?page=1025", []byte("page"), time.Hour)
+
+	_, ok = store.Get("/api/projects?page=0")
+	assert.True(t, ok)
+	_, ok = store.Get("/api/projects?page=1")
+	assert.False(t, ok)
+	_, ok = store.Get("/api/projects?page=2")
+	assert.False(t, ok)
+	_, ok = store.Get("/api/projects?page=3")
+	assert.True(t, ok)
+	_, ok = store.Get("/api/projects?page=1025")
+	assert.True(t, ok)
+}
+
+func TestNewSharesMemoryStoresByPrefix(t *testing.T) {
+	config := &configs.Config{Cache: configs.CacheConfig{Driver: "memory"}}
+
+	cache.New(config, "shared-projects").Set("/api/projects", []byte("a"), time.Minute)
+
+	// A store created elsewhere with the same prefix sees and clears the entry
+	_, ok := cache.New(config, "shared-projects").Get("/api/projects")
+	assert.True(t, ok)
+	_, ok = cache.New(config, "shared-blog").Get("/api/projects")
+	assert.False(t, ok)
+
+	cache.New(config, "shared-projects").Clear()
+	_, ok = cache.New(config, "shared-projects").Get("/api/projects")
+	assert.False(t, ok)
+}
@@ -0,0 +1,99 @@
+package cache_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/configs"
+	"zionechainapi/internal/cache"
+)
+
+// newRedisCache returns a cache backed by an in-process Redis server
+func newRedisCache(t *testing.T, prefix string) (*cache.RedisCache, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return cache.NewRedisCache(client, prefix), server
+}
+
+func TestRedisCacheGetSet(t *testing.T) {
+	store, server := newRedisCache(t, "projects")
+
+	_, ok := store.Get("/api/projects")
+	assert.False(t, ok)
+
+	store.Set("/api/projects", []byte(`{"data":[]}`), time.Minute)
+
+	value, ok := store.Get("/api/projects")
+	assert.True(t, ok)
+	assert.Equal(t, `{"data":[]}`, string(value))
+	assert.True(t, server.Exists("projects:/api/projects"))
+
+	// Entries expire with their time to live
+	server.FastForward(2 * time.Minute)
+	_, ok = store.Get("/api/projects")
+	assert.False(t, ok)
+}
+
+func TestRedisCacheClearOnlyRemovesOwnPrefix(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	projects := cache.NewRedisCache(client, "projects")
+	blog := cache.NewRedisCache(client, "blog")
+
+	projects.Set("/api/projects?page=1", []byte("a"), time.Minute)
+	projects.Set("/api/projects?page=2", []byte("b"), time.Minute)
+	blog.Set("/api/blog", []byte("c"), time.Minute)
+
+	projects.Clear()
+
+	_, ok := projects.Get("/api/projects?page=1")
+	assert.False(t, ok)
+	_, ok = projects.Get("/api/projects?page=2")
+	assert.False(t, ok)
+	_, ok = blog.Get("/api/blog")
+	assert.True(t, ok)
+}
+
+func TestRedisCacheUnavailable(t *testing.T) {
+	store, server := newRedisCache(t, "projects")
+	store.Set("/api/projects", []byte("a"), time.Minute)
+
+	server.Close()
+
+	// An unreachable server behaves as an empty cache instead of failing
+	assert.NotPanics(t, func() {
+		store.Set("/api/projects", []byte("b"), time.Minute)
+		store.Clear()
+	})
+	_, ok := store.Get("/api/projects")
+	assert.False(t, ok)
+}
+
+func TestNewSharesOneRedisClient(t *testing.T) {
+	server := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(server.Addr())
+	assert.NoError(t, err)
+	config := &configs.Config{
+		Cache: configs.CacheConfig{Driver: "redis"},
+		Redis: configs.RedisConfig{Host: host, Port: port},
+	}
+
+	// Stores created at different call sites use the same connection
+	cache.New(config, "projects").Set("/api/projects", []byte("a"), time.Minute)
+	cache.New(config, "blog").Set("/api/blog", []byte("b"), time.Minute)
+	_, ok := cache.New(config, "projects").Get("/api/projects")
+	assert.True(t, ok)
+	assert.Equal(t, 1, server.TotalConnectionCount())
+
+	// Closing releases it
+	assert.NoError(t, cache.Close())
+	assert.Eventually(t, func() bool { return server.CurrentConnectionCount() == 0 }, time.Second, 10*time.Millisecond)
+}
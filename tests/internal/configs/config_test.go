@@ -0,0 +1,107 @@
+package configs_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/configs"
+)
+
+func TestLoadConfigRefusesDefaultJWTSecretInProduction(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("DB_PASSWORD", "db-password")
+
+	config, err := configs.LoadConfig()
+	assert.ErrorIs(t, err, configs.ErrDefaultJWTSecret)
+	assert.Nil(t, config)
+}
+
+func TestValidate(t *testing.T) {
+	config := &configs.Config{
+		App:      configs.AppConfig{Env: "development"},
+		JWT:      configs.JWTConfig{Secret: configs.DefaultJWTSecret},
+		Database: configs.DatabaseConfig{},
+	}
+
+	// Defaults are fine outside production
+	assert.NoError(t, config.Validate())
+
+	config.App.Env = "Production"
+	assert.ErrorIs(t, config.Validate(), configs.ErrDefaultJWTSecret)
+
+	config.JWT.Secret = "a-real-secret"
+	assert.ErrorIs(t, config.Validate(), configs.ErrEmptyDatabasePassword)
+
+	config.Database.Password = "db-password"
+	assert.NoError(t, config.Validate())
+
+	// SQLite needs no password
+	config.Database = configs.DatabaseConfig{Driver: "sqlite"}
+	assert.NoError(t, config.Validate())
+}
+
+func TestLoadConfigCORSDefaults(t *testing.T) {
+	t.Setenv("APP_ENV", "development")
+	t.Setenv("CORS_ALLOWED_METHODS", "")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "")
+
+	config, err := configs.LoadConfig()
+	assert.NoError(t, err)
+
+	// Browsers preflight PATCH, so it must be allowed along with HEAD
+	assert.Contains(t, config.CORS.AllowedMethods, "PATCH")
+	assert.Contains(t, config.CORS.AllowedMethods, "HEAD")
+	assert.False(t, config.CORS.AllowCredentials)
+}
+
+func TestLoadConfigJWTClaimDefaults(t *testing.T) {
+	t.Setenv("APP_ENV", "development")
+	t.Setenv("JWT_ISSUER", "")
+	t.Setenv("JWT_AUDIENCE", "")
+	t.Setenv("JWT_ACCEPT_LEGACY_UNTIL", "")
+
+	config, err := configs.LoadConfig()
+	assert.NoError(t, err)
+
+	// The claims are checked out of the box, and legacy tokens only on request
+	assert.Equal(t, "zionechainapi", config.JWT.Issuer)
+	assert.Equal(t, "zionechainapi", config.JWT.Audience)
+	assert.True(t, config.JWT.AcceptLegacyUntil.IsZero())
+
+	t.Setenv("JWT_ACCEPT_LEGACY_UNTIL", "2026-11-01T00:00:00Z")
+	config, err = configs.LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC), config.JWT.AcceptLegacyUntil.UTC())
+}
+
+func TestLoadConfigTrustsNoProxiesByDefault(t *testing.T) {
+	t.Setenv("APP_ENV", "development")
+	t.Setenv("TRUSTED_PROXIES", "")
+
+	config, err := configs.LoadConfig()
+	assert.NoError(t, err)
+	assert.Nil(t, config.App.TrustedProxies)
+
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.1,172.16.0.0/12")
+	config, err = configs.LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "172.16.0.0/12"}, config.App.TrustedProxies)
+}
+
+func TestLoadConfigPaginationOverrides(t *testing.T) {
+	t.Setenv("APP_ENV", "development")
+	t.Setenv("PAGINATION_DEFAULT_LIMIT", "10")
+	t.Setenv("PAGINATION_MAX_LIMIT", "100")
+
+	// Every paginated list can be sized on its own
+	for _, resource := range []string{"projects", "blog", "audit", "comments", "media", "publications", "messages", "drafts", "activity"} {
+		t.Setenv("PAGINATION_"+strings.ToUpper(resource)+"_MAX_LIMIT", "25")
+
+		config, err := configs.LoadConfig()
+		assert.NoError(t, err)
+		assert.Equal(t, configs.PageLimits{DefaultLimit: 10, MaxLimit: 25}, config.Pagination.Limits(resource), resource)
+	}
+}
@@ -0,0 +1,136 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/configs"
+	"zionechainapi/internal/controllers"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+var authTestConfig = &configs.Config{
+	JWT: configs.JWTConfig{
+		Secret:             "test-secret",
+		AccessTokenExpiry:  time.Minute * 15,
+		RefreshTokenExpiry: time.Hour * 24 * 7,
+	},
+}
+
+func newAuthRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	controllers.NewAuthController(authTestConfig).Routes(router.Group("/api"))
+	return router
+}
+
+func signAccessToken(t *testing.T, expiresAt time.Time) string {
+	claims := &services.Claims{
+		UserID: 7,
+		Role:   "editor",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			Subject:   "7",
+		},
+	}
+
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(authTestConfig.JWT.Secret))
+	assert.NoError(t, err)
+	return tokenString
+}
+
+func verify(t *testing.T, router *gin.Engine, body interface{}, header string) (int, map[string]interface{}) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		assert.NoError(t, err)
+	}
+
+	req, err := http.NewRequest("POST", "/api/auth/verify", bytes.NewBuffer(payload))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if header != "" {
+		req.Header.Set("Authorization", header)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	data, _ := response["data"].(map[string]interface{})
+	return w.Code, data
+}
+
+func TestVerifyValidToken(t *testing.T) {
+	router := newAuthRouter()
+	token := signAccessToken(t, time.Now().Add(time.Minute*15))
+
+	// From the body
+	code, data := verify(t, router, services.VerifyTokenRequest{Token: token}, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, data["valid"])
+	assert.Equal(t, float64(7), data["user_id"])
+	assert.Equal(t, "editor", data["role"])
+	assert.NotEmpty(t, data["expires_at"])
+
+	// From the Authorization header
+	code, data = verify(t, router, nil, "Bearer "+token)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, data["valid"])
+}
+
+func TestVerifyExpiredToken(t *testing.T) {
+	router := newAuthRouter()
+	token := signAccessToken(t, time.Now().Add(-time.Hour))
+
+	code, data := verify(t, router, services.VerifyTokenRequest{Token: token}, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, false, data["valid"])
+	assert.Equal(t, utils.CodeAuthTokenExpired, data["reason"])
+	assert.Nil(t, data["user_id"])
+}
+
+func TestVerifyMalformedToken(t *testing.T) {
+	router := newAuthRouter()
+
+	code, data := verify(t, router, services.VerifyTokenRequest{Token: "not-a-jwt"}, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, false, data["valid"])
+	assert.Equal(t, utils.CodeAuthTokenInvalid, data["reason"])
+
+	// A missing token is a bad request
+	code, _ = verify(t, router, nil, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestRegisterReportsFieldErrors(t *testing.T) {
+	router := newAuthRouter()
+
+	payload, err := json.Marshal(services.RegisterRequest{Name: "Signup", Email: "signup", Phone: "+1555", Password: "abc"})
+	assert.NoError(t, err)
+	req, err := http.NewRequest("POST", "/api/auth/register", bytes.NewBuffer(payload))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]interface{}{
+		"email":    "must be a valid email address",
+		"password": "is too short, must be at least 6 characters",
+	}, response["error"])
+}
@@ -0,0 +1,114 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/controllers"
+)
+
+func newHealthRouter(ping func() error) *gin.Engine {
+	return newMigrationHealthRouter(ping, func() ([]string, error) { return nil, nil })
+}
+
+func newMigrationHealthRouter(ping func() error, pendingMigrations func() ([]string, error)) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	controllers.NewHealthController(ping, pendingMigrations).Routes(router)
+	return router
+}
+
+func TestLivez(t *testing.T) {
+	// Liveness does not depend on the database
+	router := newHealthRouter(func() error { return errors.New("connection refused") })
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/livez", nil)
+	assert.NoError(t, err)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestReadyzDatabaseDown(t *testing.T) {
+	router := newHealthRouter(func() error { return errors.New("dial tcp db.internal:3306: connection refused") })
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/readyz", nil)
+	assert.NoError(t, err)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	// The driver error is logged, not shown to the public
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "database unavailable", body["database"])
+	assert.NotContains(t, w.Body.String(), "db.internal")
+}
+
+func TestReadyzMigrationCheckFails(t *testing.T) {
+	router := newMigrationHealthRouter(
+		func() error { return nil },
+		func() ([]string, error) { return nil, errors.New("Error 1045: Access denied for user 'api'") },
+	)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/readyz", nil)
+	assert.NoError(t, err)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.NotContains(t, w.Body.String(), "Access denied")
+	assert.Contains(t, w.Body.String(), "database unavailable")
+}
+
+func TestReadyzDatabaseHealthy(t *testing.T) {
+	router := newHealthRouter(func() error { return nil })
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/readyz", nil)
+	assert.NoError(t, err)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestReadyzReportsMigrationStatus(t *testing.T) {
+	router := newHealthRouter(func() error { return nil })
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/readyz", nil)
+	assert.NoError(t, err)
+	router.ServeHTTP(w, req)
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "ready", body["status"])
+	assert.Equal(t, "up-to-date", body["migrations"])
+}
+
+func TestReadyzMigrationsPending(t *testing.T) {
+	router := newMigrationHealthRouter(
+		func() error { return nil },
+		func() ([]string, error) { return []string{"outbox_events"}, nil },
+	)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/readyz", nil)
+	assert.NoError(t, err)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "not ready", body["status"])
+	assert.Equal(t, "pending", body["migrations"])
+	assert.Equal(t, []interface{}{"outbox_events"}, body["pending_tables"])
+}
@@ -0,0 +1,113 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/configs"
+	"zionechainapi/internal/controllers"
+	"zionechainapi/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestExperienceResponseOngoingDuration(t *testing.T) {
+	// A stale end date on a current job is ignored
+	staleEnd := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	experience := models.Experience{
+		JobTitle:   "Engineer",
+		StartDate:  time.Date(2021, time.March, 15, 0, 0, 0, 0, time.UTC),
+		EndDate:    &staleEnd,
+		CurrentJob: true,
+	}
+
+	now := time.Date(2023, time.June, 20, 0, 0, 0, 0, time.UTC)
+	response := controllers.NewExperienceResponse(experience, now)
+	assert.Nil(t, response.EndDate)
+	assert.Equal(t, "2 yrs 3 mos", response.Duration)
+
+	// The duration keeps growing as time passes
+	later := controllers.NewExperienceResponse(experience, now.AddDate(0, 9, 0))
+	assert.Equal(t, "3 yrs", later.Duration)
+
+	body, err := json.Marshal(response)
+	assert.NoError(t, err)
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Contains(t, decoded, "end_date")
+	assert.Nil(t, decoded["end_date"])
+	assert.Equal(t, "2 yrs 3 mos", decoded["duration"])
+	assert.Equal(t, "Engineer", decoded["job_title"])
+}
+
+func TestExperienceResponseFinishedDuration(t *testing.T) {
+	end := time.Date(2022, time.February, 10, 0, 0, 0, 0, time.UTC)
+	experience := models.Experience{
+		StartDate: time.Date(2021, time.March, 15, 0, 0, 0, 0, time.UTC),
+		EndDate:   &end,
+	}
+
+	// A finished entry does not depend on now
+	response := controllers.NewExperienceResponse(experience, time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, &end, response.EndDate)
+	assert.Equal(t, "10 mos", response.Duration)
+}
+
+func TestEducationResponseOngoingDuration(t *testing.T) {
+	education := models.Education{
+		Degree:    "MSc",
+		StartDate: time.Date(2023, time.September, 1, 0, 0, 0, 0, time.UTC),
+		Current:   true,
+	}
+
+	response := controllers.NewEducationResponse(education, time.Date(2023, time.September, 20, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, response.EndDate)
+	assert.Equal(t, "less than a month", response.Duration)
+
+	response = controllers.NewEducationResponse(education, time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "1 yr 1 mo", response.Duration)
+}
+
+func TestResumeListingsQuoteReservedColumnsForPostgres(t *testing.T) {
+	// Build the queries against the Postgres dialect without a server
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=zionechainapi"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	assert.NoError(t, err)
+
+	var statements []string
+	err = db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	})
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	controller := controllers.NewResumeController(db, &configs.Config{})
+	router.GET("/resume", controller.GetCompleteResume)
+	router.GET("/resume/education", controller.GetEducations)
+
+	for _, path := range []string{"/resume", "/resume/education"} {
+		statements = nil
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+
+		var education string
+		for _, statement := range statements {
+			if strings.Contains(statement, `FROM "educations"`) {
+				education = statement
+			}
+		}
+		assert.Contains(t, education, `ORDER BY "current" DESC,"start_date" DESC,"id" DESC`, path)
+		assert.NotContains(t, education, "`", path)
+	}
+}
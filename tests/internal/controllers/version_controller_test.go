@@ -0,0 +1,46 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/configs"
+	"zionechainapi/internal/controllers"
+)
+
+func TestVersionReportsBuildInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	config := &configs.Config{
+		App:      configs.AppConfig{Name: "Zione API", Env: "staging"},
+		JWT:      configs.JWTConfig{Secret: "do-not-leak"},
+		Database: configs.DatabaseConfig{Password: "do-not-leak"},
+	}
+	controllers.NewVersionController(config).Routes(router)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/version", nil)
+	assert.NoError(t, err)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data map[string]string `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]string{
+		"name":        "Zione API",
+		"version":     "dev",
+		"commit":      "unknown",
+		"build_time":  "unknown",
+		"environment": "staging",
+	}, response.Data)
+
+	// Nothing but the build details is reported
+	assert.NotContains(t, w.Body.String(), "do-not-leak")
+}
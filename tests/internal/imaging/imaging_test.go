@@ -0,0 +1,169 @@
+package imaging_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/webp"
+	"zionechainapi/internal/imaging"
+)
+
+// roundTrip encodes img as WebP and decodes it again
+func roundTrip(t *testing.T, img image.Image) image.Image {
+	var buf bytes.Buffer
+	assert.NoError(t, imaging.EncodeWebP(&buf, img))
+
+	decoded, err := webp.Decode(&buf)
+	assert.NoError(t, err)
+	return decoded
+}
+
+// assertSamePixels checks that two images have the same non-premultiplied colors
+func assertSamePixels(t *testing.T, want, got image.Image) {
+	assert.Equal(t, want.Bounds().Size(), got.Bounds().Size())
+	for y := 0; y < want.Bounds().Dy(); y++ {
+		for x := 0; x < want.Bounds().Dx(); x++ {
+			w := color.NRGBAModel.Convert(want.At(want.Bounds().Min.X+x, want.Bounds().Min.Y+y))
+			g := color.NRGBAModel.Convert(got.At(got.Bounds().Min.X+x, got.Bounds().Min.Y+y))
+			if !assert.Equal(t, w, g, "pixel %d,%d", x, y) {
+				return
+			}
+		}
+	}
+}
+
+func TestEncodeWebPIsLossless(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	img := image.NewNRGBA(image.Rect(0, 0, 37, 23))
+	for i := range img.Pix {
+		img.Pix[i] = byte(random.Intn(256))
+	}
+
+	assertSamePixels(t, img, roundTrip(t, img))
+}
+
+func TestEncodeWebPSkewedColors(t *testing.T) {
+	// A few colors with very uneven counts exercise long prefix codes
+	img := image.NewNRGBA(image.Rect(0, 0, 300, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 300; x++ {
+			c := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+			if x%(y+1) == 0 {
+				c = color.NRGBA{R: byte(x), G: byte(y), B: byte(x * y), A: 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	assertSamePixels(t, img, roundTrip(t, img))
+}
+
+func TestEncodeWebPSingleColor(t *testing.T) {
+	img := image.NewUniform(color.NRGBA{R: 10, G: 200, B: 30, A: 128})
+	bounded := image.NewNRGBA(image.Rect(5, 5, 9, 8))
+	for y := 5; y < 8; y++ {
+		for x := 5; x < 9; x++ {
+			bounded.Set(x, y, img.C)
+		}
+	}
+
+	assertSamePixels(t, bounded, roundTrip(t, bounded))
+}
+
+func TestEncodeWebPImageTypes(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+
+	gray := image.NewGray(image.Rect(0, 0, 17, 9))
+	random.Read(gray.Pix)
+
+	// Premultiplied colors with partial alpha are stored unpremultiplied
+	rgba := image.NewRGBA(image.Rect(0, 0, 13, 11))
+	for i := 0; i < len(rgba.Pix); i += 4 {
+		a := byte(random.Intn(256))
+		rgba.Pix[i+3] = a
+		for c := 0; c < 3; c++ {
+			rgba.Pix[i+c] = byte(random.Intn(int(a) + 1))
+		}
+	}
+
+	paletted := image.NewPaletted(image.Rect(0, 0, 8, 8), color.Palette{color.Black, color.White, color.Transparent})
+	for i := range paletted.Pix {
+		paletted.Pix[i] = byte(i % 3)
+	}
+
+	for name, img := range map[string]image.Image{
+		"gray":     gray,
+		"rgba":     rgba,
+		"paletted": paletted,
+		"pixel":    image.NewNRGBA(image.Rect(0, 0, 1, 1)),
+		"row":      image.NewNRGBA(image.Rect(0, 0, 1000, 1)),
+		"column":   image.NewNRGBA(image.Rect(0, 0, 1, 1000)),
+		"offset":   image.NewGray(image.Rect(-3, 4, 2, 9)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			assertSamePixels(t, img, roundTrip(t, img))
+		})
+	}
+}
+
+func FuzzEncodeWebP(f *testing.F) {
+	f.Add(uint8(1), uint8(1), []byte{0, 0, 0, 0})
+	f.Add(uint8(3), uint8(2), []byte{255, 0, 0, 255, 0, 255, 0, 128, 0, 0, 255, 0})
+	f.Add(uint8(64), uint8(64), []byte{1, 2, 3, 4, 5, 6, 7})
+
+	f.Fuzz(func(t *testing.T, width, height uint8, data []byte) {
+		if width == 0 || height == 0 || len(data) == 0 {
+			t.Skip()
+		}
+
+		// Any non-premultiplied image must decode to exactly the same colors
+		img := image.NewNRGBA(image.Rect(0, 0, int(width), int(height)))
+		for i := range img.Pix {
+			img.Pix[i] = data[i%len(data)]
+		}
+
+		assertSamePixels(t, img, roundTrip(t, img))
+	})
+}
+
+func TestEncodeWebPRejectsOversizedImages(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, imaging.MaxWebPDimension+1, 1))
+	assert.ErrorIs(t, imaging.EncodeWebP(&bytes.Buffer{}, img), imaging.ErrWebPTooLarge)
+}
+
+func TestValidateDecodesImages(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 3))))
+
+	img, err := imaging.Validate(buf.Bytes(), "image/png")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, img.Bounds().Dx())
+
+	// The content must match the claimed type
+	_, err = imaging.Validate(buf.Bytes(), "image/jpeg")
+	assert.ErrorIs(t, err, imaging.ErrInvalidImage)
+}
+
+func TestValidateRejectsDisguisedFiles(t *testing.T) {
+	_, err := imaging.Validate([]byte("just some text, not a picture"), "image/png")
+	assert.ErrorIs(t, err, imaging.ErrInvalidImage)
+
+	// A PNG signature followed by garbage is sniffed as PNG but cannot be decoded
+	_, err = imaging.Validate([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png")
+	assert.ErrorIs(t, err, imaging.ErrInvalidImage)
+}
+
+func TestValidateChecksAVIFStructure(t *testing.T) {
+	avif := []byte("\x00\x00\x00\x1cftypavif\x00\x00\x00\x00avifmif1miaf")
+	img, err := imaging.Validate(avif, "image/avif")
+	assert.NoError(t, err)
+	assert.Nil(t, img)
+
+	_, err = imaging.Validate([]byte("\x00\x00\x00\x18ftypisom\x00\x00\x00\x00isommp41"), "image/avif")
+	assert.ErrorIs(t, err, imaging.ErrInvalidImage)
+}
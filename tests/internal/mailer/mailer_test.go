@@ -0,0 +1,42 @@
+package mailer_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/configs"
+	"zionechainapi/internal/mailer"
+)
+
+func TestRecordingCapturesMessages(t *testing.T) {
+	recording := mailer.NewRecording()
+
+	assert.NoError(t, recording.Send("ada@example.com", "Welcome", "Hello Ada"))
+	assert.NoError(t, recording.Send("alan@example.com", "Verify your email address", "Open the link"))
+
+	assert.Equal(t, []mailer.Message{
+		{To: "ada@example.com", Subject: "Welcome", Body: "Hello Ada"},
+		{To: "alan@example.com", Subject: "Verify your email address", Body: "Open the link"},
+	}, recording.Messages())
+}
+
+func TestNewSelectsDriver(t *testing.T) {
+	config := &configs.Config{}
+	assert.IsType(t, mailer.Log{}, mailer.New(config))
+
+	config.Mail.Driver = "SMTP"
+	assert.IsType(t, &mailer.SMTPMailer{}, mailer.New(config))
+
+	config.Mail.Driver = "carrier-pigeon"
+	assert.IsType(t, mailer.Log{}, mailer.New(config))
+}
+
+func TestSMTPMailerRejectsHeaderInjection(t *testing.T) {
+	smtpMailer := mailer.NewSMTPMailer(configs.MailConfig{Host: "localhost", Port: 25, From: "no-reply@localhost"})
+
+	err := smtpMailer.Send("ada@example.com\r\nBcc: eve@example.com", "Welcome", "Hello")
+	assert.ErrorIs(t, err, mailer.ErrInvalidHeader)
+
+	err = smtpMailer.Send("ada@example.com", "Welcome\nBcc: eve@example.com", "Hello")
+	assert.ErrorIs(t, err, mailer.ErrInvalidHeader)
+}
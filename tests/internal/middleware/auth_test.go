@@ -0,0 +1,172 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/configs"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/utils"
+)
+
+func TestAuthDistinguishesExpiredAndInvalidTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := &configs.Config{
+		JWT: configs.JWTConfig{
+			Secret:             "test-secret",
+			AccessTokenExpiry:  time.Minute * 15,
+			RefreshTokenExpiry: time.Hour * 24 * 7,
+		},
+	}
+
+	// Create a router with a protected route
+	router := gin.New()
+	router.GET("/protected", middleware.Auth(config), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// Create an expired token
+	claims := &services.Claims{
+		UserID: 1,
+		Role:   "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+			Subject:   "1",
+		},
+	}
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.JWT.Secret))
+	assert.NoError(t, err)
+
+	for token, code := range map[string]string{
+		expired:       utils.CodeAuthTokenExpired,
+		expired + "x": utils.CodeAuthTokenInvalid,
+		"not-a-jwt":   utils.CodeAuthTokenInvalid,
+	} {
+		req, err := http.NewRequest("GET", "/protected", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err = json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, false, response["success"], token)
+		assert.Equal(t, code, response["code"], token)
+	}
+}
+
+func TestScopedTokenOnlyPassesRoutesAcceptingItsScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := &configs.Config{
+		JWT: configs.JWTConfig{
+			Secret:             "test-secret",
+			AccessTokenExpiry:  time.Minute * 15,
+			RefreshTokenExpiry: time.Hour * 24 * 7,
+		},
+	}
+
+	preview, err := services.NewAuthService(config).IssueScopedToken(1, services.ScopedTokenRequest{
+		Scope:     services.ScopePreviewRead,
+		ExpiresIn: 3600,
+	})
+	assert.NoError(t, err)
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router := gin.New()
+	router.GET("/draft", middleware.Auth(config), middleware.RequireRoleOrScope(services.ScopePreviewRead, "admin", "editor"), ok)
+	router.POST("/create", middleware.Auth(config), middleware.RequireRole("admin", "editor"), ok)
+
+	for _, tc := range []struct {
+		method string
+		path   string
+		code   int
+	}{
+		{"GET", "/draft", http.StatusOK},
+		{"POST", "/create", http.StatusForbidden},
+	} {
+		req, err := http.NewRequest(tc.method, tc.path, nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+preview.Token)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tc.code, w.Code, tc.path)
+		if tc.code == http.StatusForbidden {
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, utils.CodeAuthInsufficientRole, response["code"])
+		}
+	}
+}
+
+func TestAuthReadsAccessTokenCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := &configs.Config{
+		JWT: configs.JWTConfig{
+			Secret:             "test-secret",
+			AccessTokenExpiry:  time.Minute * 15,
+			RefreshTokenExpiry: time.Hour * 24 * 7,
+		},
+	}
+
+	router := gin.New()
+	router.GET("/protected", middleware.Auth(config), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": middleware.GetUserID(c)})
+	})
+	router.GET("/optional", middleware.OptionalAuth(config), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": middleware.GetUserID(c)})
+	})
+
+	claims := &services.Claims{
+		UserID: 7,
+		Role:   "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			Subject:   "7",
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.JWT.Secret))
+	assert.NoError(t, err)
+
+	request := func(path, header string, cookie bool) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", path, nil)
+		assert.NoError(t, err)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		if cookie {
+			req.AddCookie(&http.Cookie{Name: middleware.AccessTokenCookie, Value: token})
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// The cookie authenticates when no Authorization header is sent
+	w := request("/protected", "", true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"user_id":7}`, w.Body.String())
+
+	w = request("/optional", "", true)
+	assert.JSONEq(t, `{"user_id":7}`, w.Body.String())
+
+	// Without either the route stays protected
+	assert.Equal(t, http.StatusUnauthorized, request("/protected", "", false).Code)
+
+	// A header takes precedence over the cookie
+	assert.Equal(t, http.StatusUnauthorized, request("/protected", "Bearer not-a-jwt", true).Code)
+	assert.Equal(t, http.StatusUnauthorized, request("/protected", "Token "+token, true).Code)
+}
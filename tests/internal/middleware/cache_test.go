@@ -0,0 +1,134 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/cache"
+	"zionechainapi/internal/middleware"
+)
+
+// newCachedRouter returns a router with a cached list route and a create route,
+// and a pointer to the number of times the list handler ran
+func newCachedRouter(ttl time.Duration) (*gin.Engine, *int) {
+	gin.SetMode(gin.TestMode)
+
+	store := cache.NewMemoryCache()
+	calls := 0
+
+	router := gin.New()
+	router.GET("/items", middleware.CacheResponse(store, ttl), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"calls": calls})
+	})
+	router.POST("/items", middleware.InvalidateCache(store), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	return router, &calls
+}
+
+func serve(t *testing.T, router *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, path, nil)
+	assert.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCacheResponseHit(t *testing.T) {
+	router, calls := newCachedRouter(time.Minute)
+
+	first := serve(t, router, "GET", "/items?page=1", "")
+	assert.Equal(t, "MISS", first.Header().Get("X-Cache"))
+
+	second := serve(t, router, "GET", "/items?page=1", "")
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, "HIT", second.Header().Get("X-Cache"))
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, 1, *calls)
+
+	// A different query string is a different key
+	serve(t, router, "GET", "/items?page=2", "")
+	assert.Equal(t, 2, *calls)
+
+	// Authenticated requests are never cached
+	w := serve(t, router, "GET", "/items?page=1", "token")
+	assert.Empty(t, w.Header().Get("X-Cache"))
+	assert.Equal(t, 3, *calls)
+
+	// Neither are requests signed in with the access token cookie
+	req, err := http.NewRequest("GET", "/items?page=1", nil)
+	assert.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: middleware.AccessTokenCookie, Value: "token"})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Empty(t, w.Header().Get("X-Cache"))
+	assert.Equal(t, 4, *calls)
+}
+
+func TestCacheResponseExpiry(t *testing.T) {
+	router, calls := newCachedRouter(50 * time.Millisecond)
+
+	serve(t, router, "GET", "/items", "")
+	serve(t, router, "GET", "/items", "")
+	assert.Equal(t, 1, *calls)
+
+	// Once the TTL passes the handler runs again
+	time.Sleep(100 * time.Millisecond)
+	w := serve(t, router, "GET", "/items", "")
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Equal(t, 2, *calls)
+}
+
+func TestCacheInvalidatedAfterCreate(t *testing.T) {
+	router, calls := newCachedRouter(time.Minute)
+
+	serve(t, router, "GET", "/items", "")
+	serve(t, router, "GET", "/items", "")
+	assert.Equal(t, 1, *calls)
+
+	w := serve(t, router, "POST", "/items", "")
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// The next list request is served fresh
+	w = serve(t, router, "GET", "/items", "")
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Equal(t, 2, *calls)
+}
+
+func TestCacheInvalidationClearsEveryStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	projects := cache.NewMemoryCache()
+	blog := cache.NewMemoryCache()
+	projects.Set("/api/projects", []byte("a"), time.Minute)
+	blog.Set("/api/blog", []byte("b"), time.Minute)
+
+	// A write elsewhere, such as renaming a tag, clears both lists
+	router := gin.New()
+	router.PUT("/tags/:id", middleware.InvalidateCache(projects, blog), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.DELETE("/tags/:id", middleware.InvalidateCache(projects, blog), func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+
+	// A failed write leaves them alone
+	serve(t, router, "DELETE", "/tags/1", "")
+	_, ok := projects.Get("/api/projects")
+	assert.True(t, ok)
+
+	serve(t, router, "PUT", "/tags/1", "")
+	_, ok = projects.Get("/api/projects")
+	assert.False(t, ok)
+	_, ok = blog.Get("/api/blog")
+	assert.False(t, ok)
+}
@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/middleware"
+)
+
+func TestRequireJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.RequireJSON())
+	router.GET("/items", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.POST("/items", func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	send := func(method, body, contentType string) int {
+		req := httptest.NewRequest(method, "/items", strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, send("POST", `{"name":"Go"}`, "application/json"))
+	assert.Equal(t, http.StatusCreated, send("POST", `{"name":"Go"}`, "application/json; charset=utf-8"))
+
+	// Form-encoded and untyped bodies are refused
+	assert.Equal(t, http.StatusUnsupportedMediaType, send("POST", "name=Go", "application/x-www-form-urlencoded"))
+	assert.Equal(t, http.StatusUnsupportedMediaType, send("POST", `{"name":"Go"}`, ""))
+
+	// Requests without a body and reads are not checked
+	assert.Equal(t, http.StatusCreated, send("POST", "", ""))
+	assert.Equal(t, http.StatusOK, send("GET", "", ""))
+}
+
+func TestRequireContentTypeAllowsMultipart(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/uploads", middleware.RequireContentType("multipart/form-data"), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest("POST", "/uploads", strings.NewReader("--boundary--"))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=boundary")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	req = httptest.NewRequest("POST", "/uploads", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
@@ -0,0 +1,163 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/middleware"
+)
+
+// corsRouter returns a router with a public list route and admin write routes,
+// where any origin may read and only the admin panel may write
+func corsRouter() *gin.Engine {
+	public := middleware.CORSPolicy{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+	}
+	admin := public
+	admin.AllowedOrigins = []string{"https://admin.example.com"}
+	admin.Routes = []middleware.CORSRoute{
+		{Method: "POST", Path: "/api/projects"},
+		{Method: "PUT", Path: "/api/projects/:id"},
+	}
+
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+	router.NoMethod(middleware.MethodNotAllowed(router))
+	router.Use(middleware.CORS(public, admin))
+	handler := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/projects", handler)
+	router.POST("/api/projects", handler)
+	router.GET("/api/projects/:id", handler)
+	router.PUT("/api/projects/:id", handler)
+	return router
+}
+
+// corsRequest sends a request from origin, as a preflight for method when preflight is set
+func corsRequest(router *gin.Engine, method, path, origin string, preflight bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if preflight {
+		req = httptest.NewRequest("OPTIONS", path, nil)
+		req.Header.Set("Access-Control-Request-Method", method)
+	}
+	req.Header.Set("Origin", origin)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSPublicRoutesAllowAnyOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := corsRouter()
+
+	w := corsRequest(router, "GET", "/api/projects/42", "https://blog.example.org", false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+
+	w = corsRequest(router, "GET", "/api/projects", "https://blog.example.org", true)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST, PUT, DELETE, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type, Authorization", w.Header().Get("Access-Control-Allow-Headers"))
+}
+
+func TestCORSAdminRoutesOnlyAllowAdminOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := corsRouter()
+
+	// Other origins are refused at preflight and get no CORS headers otherwise
+	w := corsRequest(router, "POST", "/api/projects", "https://blog.example.org", true)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	w = corsRequest(router, "PUT", "/api/projects/42", "https://blog.example.org", false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	// The admin panel is allowed, by name rather than with a wildcard
+	w = corsRequest(router, "POST", "/api/projects", "https://admin.example.com", true)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://admin.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "Origin", w.Header().Get("Vary"))
+
+	w = corsRequest(router, "PUT", "/api/projects/42", "https://admin.example.com", false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://admin.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSIgnoresSameOriginRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := corsRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/projects", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Vary"))
+}
+
+func TestCORSFirstCoveringOverrideApplies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	public := middleware.CORSPolicy{AllowedOrigins: []string{"https://blog.example.org"}}
+	publicWrites := public
+	publicWrites.Routes = []middleware.CORSRoute{{Method: "POST", Path: "/api/auth/login"}}
+	admin := middleware.CORSPolicy{
+		AllowedOrigins: []string{"https://admin.example.com"},
+		Routes:         []middleware.CORSRoute{{Method: "POST", Path: "/*path"}},
+	}
+
+	router := gin.New()
+	router.Use(middleware.CORS(public, publicWrites, admin))
+	handler := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.POST("/api/auth/login", handler)
+	router.POST("/api/categories/projects", handler)
+
+	// The public write is listed before the catch-all admin writes
+	w := corsRequest(router, "POST", "/api/auth/login", "https://blog.example.org", true)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	// Any other write falls to the admin policy
+	w = corsRequest(router, "POST", "/api/categories/projects", "https://blog.example.org", true)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = corsRequest(router, "POST", "/api/categories/projects", "https://admin.example.com", true)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestCORSAllowCredentialsEchoesOrigin(t *testing.T) {
+	policy := middleware.CORSPolicy{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{"GET", "POST", "PATCH"},
+		AllowedHeaders:   []string{"Content-Type"},
+		AllowCredentials: true,
+	}
+	router := gin.New()
+	router.Use(middleware.CORS(policy))
+	router.POST("/api/auth/refresh", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Browsers reject credentials with a wildcard origin, so the origin is echoed
+	for _, preflight := range []bool{true, false} {
+		w := corsRequest(router, "POST", "/api/auth/refresh", "https://app.example.com", preflight)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	}
+
+	// A policy allowing any origin never allows credentials, so that no other
+	// site can read responses meant for the signed-in user
+	policy.AllowedOrigins = []string{"https://app.example.com", "*"}
+	router = gin.New()
+	router.Use(middleware.CORS(policy))
+	router.GET("/api/projects", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, preflight := range []bool{true, false} {
+		w := corsRequest(router, "GET", "/api/projects", "https://evil.example.net", preflight)
+		assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	}
+}
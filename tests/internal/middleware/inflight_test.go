@@ -0,0 +1,54 @@
+package middleware_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/middleware"
+)
+
+func TestShutdownDrainsInFlightRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Create a router with a slow handler
+	inFlight := middleware.NewInFlightCounter()
+	router := gin.New()
+	router.Use(inFlight.Middleware())
+	router.GET("/slow", func(c *gin.Context) {
+		time.Sleep(200 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	srv := &http.Server{Handler: router}
+	go srv.Serve(listener)
+
+	// Start a request and wait until it is being handled
+	result := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/slow")
+		if err != nil {
+			result <- 0
+			return
+		}
+		defer resp.Body.Close()
+		result <- resp.StatusCode
+	}()
+
+	assert.Eventually(t, func() bool { return inFlight.Count() == 1 }, time.Second, 5*time.Millisecond)
+
+	// Shutdown waits for the in-flight request to complete
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	assert.NoError(t, srv.Shutdown(ctx))
+
+	assert.Equal(t, http.StatusOK, <-result)
+	assert.Equal(t, int64(0), inFlight.Count())
+}
@@ -0,0 +1,45 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/utils"
+)
+
+func TestMethodNotAllowedListsAllowedMethods(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+	router.NoMethod(middleware.MethodNotAllowed(router))
+	handler := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/projects", handler)
+	router.POST("/projects", handler)
+	router.GET("/projects/:id", handler)
+	router.PUT("/projects/:id", handler)
+	router.DELETE("/projects/:id", handler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/projects", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, POST", w.Header().Get("Allow"))
+
+	var response utils.Response
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Success)
+	assert.Equal(t, "Method not allowed", response.Message)
+
+	// Path parameters are matched too
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/projects/42", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "DELETE, GET, PUT", w.Header().Get("Allow"))
+}
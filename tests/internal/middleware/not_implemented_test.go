@@ -0,0 +1,59 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/utils"
+)
+
+func TestNotImplementedAnswersDeclaredButUnregisteredRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	declared := []middleware.DeclaredRoute{
+		{Method: "GET", Path: "/projects"},
+		{Method: "GET", Path: "/projects/:id/media"},
+		{Method: "DELETE", Path: "/projects"},
+	}
+
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+	router.GET("/projects", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.NoRoute(middleware.NotImplemented(router, declared, nil))
+	router.NoMethod(middleware.NotImplemented(router, declared, middleware.MethodNotAllowed(router)))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/projects/42/media", nil))
+
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+
+	var response utils.Response
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Success)
+	assert.Equal(t, "Not implemented", response.Message)
+	assert.Equal(t, utils.CodeNotImplemented, response.Code)
+
+	// A declared method missing on a registered path is not implemented either
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/projects", nil))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+
+	// Registered routes are served, and undeclared ones keep their 404 and 405
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/projects", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/projects", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET", w.Header().Get("Allow"))
+}
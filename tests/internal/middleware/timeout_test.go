@@ -0,0 +1,123 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/middleware"
+	"zionechainapi/internal/utils"
+)
+
+func newTimeoutRouter(d time.Duration, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Timeout(d))
+	router.GET("/", handler)
+	return router
+}
+
+func TestTimeoutCancelsSlowHandler(t *testing.T) {
+	cancelled := make(chan error, 1)
+	router := newTimeoutRouter(50*time.Millisecond, func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			cancelled <- c.Request.Context().Err()
+		case <-time.After(time.Second):
+			cancelled <- nil
+		}
+		// Written after the deadline, so it never reaches the client
+		c.JSON(http.StatusOK, gin.H{"late": true})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, context.DeadlineExceeded, <-cancelled)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, false, response["success"])
+	assert.Equal(t, utils.CodeRequestTimeout, response["code"])
+	assert.NotContains(t, response, "late")
+}
+
+func TestTimeoutRespondsBeforeHandlerReturns(t *testing.T) {
+	release := make(chan struct{})
+	router := newTimeoutRouter(50*time.Millisecond, func(c *gin.Context) {
+		// Ignores the context, like a call that cannot be cancelled
+		<-release
+		c.String(http.StatusOK, "done")
+	})
+
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+	// Closing the server waits for the handler, so release it first
+	defer close(release)
+
+	client := &http.Client{Timeout: time.Second}
+	resp, err := client.Get(srv.URL)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		resp.Body.Close()
+	}
+}
+
+func TestTimeoutPassesFastResponsesThrough(t *testing.T) {
+	router := newTimeoutRouter(time.Second, func(c *gin.Context) {
+		c.Header("X-Handled", "yes")
+		c.JSON(http.StatusCreated, gin.H{"ok": true})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "yes", w.Header().Get("X-Handled"))
+	assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+}
+
+func TestTimeoutZeroDisablesLimit(t *testing.T) {
+	router := newTimeoutRouter(0, func(c *gin.Context) {
+		_, hasDeadline := c.Request.Context().Deadline()
+		assert.False(t, hasDeadline)
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestTimeoutExemptRoutesStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Timeout(20*time.Millisecond, "/export/:id"))
+	router.GET("/export/:id", func(c *gin.Context) {
+		_, hasDeadline := c.Request.Context().Deadline()
+		assert.False(t, hasDeadline)
+
+		// Flushes reach the client while the handler is still running
+		c.String(http.StatusOK, "first ")
+		c.Writer.Flush()
+		time.Sleep(50 * time.Millisecond)
+		c.String(http.StatusOK, "second")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/export/1", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "first second", w.Body.String())
+}
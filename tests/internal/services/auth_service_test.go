@@ -1,13 +1,14 @@
 package services_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"zionechainapi/configs"
-	"zionechainapi/internal/models"
+	"zionechainapi/internal/clock"
 	"zionechainapi/internal/services"
 )
 
@@ -65,4 +66,254 @@ func TestValidateToken(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, uint(1), validatedClaims.UserID)
 	assert.Equal(t, "admin", validatedClaims.Role)
-}
\ No newline at end of file
+}
+
+func TestValidateTokenSecondaryKey(t *testing.T) {
+	// The primary key has been rotated, but the old key is still accepted
+	config := &configs.Config{
+		JWT: configs.JWTConfig{
+			Secret:             "new-secret",
+			KeyID:              "2024-02",
+			SecondaryKeys:      map[string]string{"2024-01": "old-secret"},
+			AccessTokenExpiry:  time.Minute * 15,
+			RefreshTokenExpiry: time.Hour * 24 * 7,
+		},
+	}
+	authService := services.NewAuthService(config)
+
+	// Sign a token with the old key
+	tokenString := signTestToken(t, "2024-01", "old-secret")
+
+	// Validate the token
+	validatedClaims, err := authService.ValidateToken(tokenString)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), validatedClaims.UserID)
+}
+
+func TestValidateTokenRemovedKey(t *testing.T) {
+	// The old key is no longer configured
+	config := &configs.Config{
+		JWT: configs.JWTConfig{
+			Secret:             "new-secret",
+			KeyID:              "2024-02",
+			AccessTokenExpiry:  time.Minute * 15,
+			RefreshTokenExpiry: time.Hour * 24 * 7,
+		},
+	}
+	authService := services.NewAuthService(config)
+
+	// Sign a token with the removed key
+	tokenString := signTestToken(t, "2024-01", "old-secret")
+
+	// Validation fails
+	_, err := authService.ValidateToken(tokenString)
+	assert.ErrorIs(t, err, services.ErrUnknownSigningKey)
+
+	// A token claiming the current kid but signed with the old secret fails as well
+	tokenString = signTestToken(t, "2024-02", "old-secret")
+	_, err = authService.ValidateToken(tokenString)
+	assert.Error(t, err)
+}
+
+func signTestToken(t *testing.T, kid, secret string) string {
+	claims := &services.Claims{
+		UserID: 1,
+		Role:   "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 15)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Subject:   "1",
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = kid
+	tokenString, err := token.SignedString([]byte(secret))
+	assert.NoError(t, err)
+
+	return tokenString
+}
+
+func TestValidateTokenAudience(t *testing.T) {
+	config := &configs.Config{
+		JWT: configs.JWTConfig{
+			Secret:             "test-secret",
+			Issuer:             "zionechainapi",
+			Audience:           "zionechainapi",
+			AccessTokenExpiry:  time.Minute * 15,
+			RefreshTokenExpiry: time.Hour * 24 * 7,
+		},
+	}
+	authService := services.NewAuthService(config)
+
+	newToken := func(issuer, audience string) string {
+		claims := &services.Claims{
+			UserID: 1,
+			Role:   "admin",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 15)),
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+				Subject:   "1",
+				Issuer:    issuer,
+				Audience:  jwt.ClaimStrings{audience},
+			},
+		}
+
+		tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.JWT.Secret))
+		assert.NoError(t, err)
+		return tokenString
+	}
+
+	// A token for this service validates
+	_, err := authService.ValidateToken(newToken("zionechainapi", "zionechainapi"))
+	assert.NoError(t, err)
+
+	// A token minted for another service sharing the secret is rejected
+	_, err = authService.ValidateToken(newToken("zionechainapi", "other-service"))
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+
+	_, err = authService.RefreshToken(newToken("zionechainapi", "other-service"))
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+
+	// A token from another issuer is rejected
+	_, err = authService.ValidateToken(newToken("other-issuer", "zionechainapi"))
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+
+	// Tokens without the claims are rejected, unless legacy tokens are still accepted
+	legacy := func() string {
+		claims := &services.Claims{
+			UserID: 1,
+			Role:   "admin",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 15)),
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+				Subject:   "1",
+			},
+		}
+
+		tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.JWT.Secret))
+		assert.NoError(t, err)
+		return tokenString
+	}
+	_, err = authService.ValidateToken(legacy())
+	assert.ErrorIs(t, err, services.ErrTokenInvalid)
+
+	config.JWT.AcceptLegacyUntil = time.Now().Add(time.Hour)
+	_, err = authService.ValidateToken(legacy())
+	assert.NoError(t, err)
+	_, err = authService.ValidateToken(newToken("zionechainapi", "zionechainapi"))
+	assert.NoError(t, err)
+
+	// Tokens naming another issuer or audience are still rejected meanwhile
+	_, err = authService.ValidateToken(newToken("other-issuer", "zionechainapi"))
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+	_, err = authService.ValidateToken(newToken("zionechainapi", "other-service"))
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+
+	// Once the window has passed they are rejected again
+	config.JWT.AcceptLegacyUntil = time.Now().Add(-time.Minute)
+	_, err = authService.ValidateToken(legacy())
+	assert.ErrorIs(t, err, services.ErrTokenInvalid)
+}
+
+func TestValidateTokenExpiry(t *testing.T) {
+	config := &configs.Config{
+		JWT: configs.JWTConfig{
+			Secret:             "test-secret",
+			Leeway:             time.Minute,
+			AccessTokenExpiry:  time.Minute * 15,
+			RefreshTokenExpiry: time.Hour * 24 * 7,
+		},
+	}
+	authService := services.NewAuthService(config)
+
+	newToken := func(expiresAt time.Time) string {
+		claims := &services.Claims{
+			UserID: 1,
+			Role:   "admin",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+				IssuedAt:  jwt.NewNumericDate(expiresAt.Add(-time.Minute * 15)),
+				Subject:   "1",
+			},
+		}
+
+		tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.JWT.Secret))
+		assert.NoError(t, err)
+		return tokenString
+	}
+
+	// A token that expired within the leeway is still accepted
+	_, err := authService.ValidateToken(newToken(time.Now().Add(-time.Second * 30)))
+	assert.NoError(t, err)
+
+	// A token that expired beyond the leeway produces the expiry error
+	_, err = authService.ValidateToken(newToken(time.Now().Add(-time.Hour)))
+	assert.ErrorIs(t, err, services.ErrTokenExpired)
+	assert.NotErrorIs(t, err, services.ErrTokenInvalid)
+}
+
+func TestValidateTokenTampered(t *testing.T) {
+	config := &configs.Config{
+		JWT: configs.JWTConfig{
+			Secret:             "test-secret",
+			AccessTokenExpiry:  time.Minute * 15,
+			RefreshTokenExpiry: time.Hour * 24 * 7,
+		},
+	}
+	authService := services.NewAuthService(config)
+
+	// Sign a valid token, then swap in a forged payload
+	tokenString := signTestToken(t, "", config.JWT.Secret)
+	forged := signTestToken(t, "", "another-secret")
+	parts := strings.Split(tokenString, ".")
+	parts[1] = strings.Split(forged, ".")[1] + "x"
+	tampered := strings.Join(parts, ".")
+
+	_, err := authService.ValidateToken(tampered)
+	assert.ErrorIs(t, err, services.ErrTokenInvalid)
+	assert.NotErrorIs(t, err, services.ErrTokenExpired)
+
+	// A token signed with a different secret is invalid as well
+	_, err = authService.ValidateToken(forged)
+	assert.ErrorIs(t, err, services.ErrTokenInvalid)
+}
+
+func TestValidateTokenUsesClock(t *testing.T) {
+	config := &configs.Config{
+		JWT: configs.JWTConfig{
+			Secret:             "test-secret",
+			Leeway:             time.Minute,
+			AccessTokenExpiry:  time.Minute * 15,
+			RefreshTokenExpiry: time.Hour * 24 * 7,
+		},
+	}
+	fake := clock.NewFake(time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC))
+	authService := services.NewAuthService(config).WithClock(fake)
+
+	// A token issued long ago is valid while the clock is frozen at its issue time
+	claims := &services.Claims{
+		UserID: 1,
+		Role:   "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(fake.Now().Add(config.JWT.AccessTokenExpiry)),
+			IssuedAt:  jwt.NewNumericDate(fake.Now()),
+			Subject:   "1",
+		},
+	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.JWT.Secret))
+	assert.NoError(t, err)
+
+	_, err = authService.ValidateToken(tokenString)
+	assert.NoError(t, err)
+
+	// Still accepted within the leeway after expiry
+	fake.Advance(config.JWT.AccessTokenExpiry + time.Second*30)
+	_, err = authService.ValidateToken(tokenString)
+	assert.NoError(t, err)
+
+	// Rejected once the leeway has passed
+	fake.Advance(time.Minute)
+	_, err = authService.ValidateToken(tokenString)
+	assert.ErrorIs(t, err, services.ErrTokenExpired)
+}
@@ -0,0 +1,114 @@
+package services_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/webp"
+	"zionechainapi/configs"
+	"zionechainapi/internal/imaging"
+	"zionechainapi/internal/services"
+	"zionechainapi/internal/storage"
+)
+
+// fileHeader returns the header of a multipart file upload with the given name and content
+func fileHeader(t *testing.T, filename string, content []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	assert.NoError(t, err)
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return form.File["file"][0]
+}
+
+// newUploadService creates an upload service storing files in a temporary directory
+func newUploadService(t *testing.T, webpDerivatives bool) (*services.UploadService, string) {
+	root := t.TempDir()
+	service := services.NewUploadService(storage.NewLocalStorage(root, "http://files.test"), configs.StorageConfig{
+		MaxSize:             1 << 20,
+		AllowedExtensions:   []string{".png", ".txt"},
+		AllowedContentTypes: []string{"image/png", "text/plain"},
+		WebPDerivatives:     webpDerivatives,
+	})
+	return service, root
+}
+
+func testPNG(t *testing.T) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, 6, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 6; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: byte(40 * x), G: byte(60 * y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestUploadPNGSavesWebPDerivative(t *testing.T) {
+	service, root := newUploadService(t, true)
+
+	upload, err := service.Upload(fileHeader(t, "cover.png", testPNG(t)))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "image/png", upload.ContentType)
+	assert.Equal(t, "http://files.test/"+upload.Key, upload.URL)
+	assert.Equal(t, upload.Key[:len(upload.Key)-len(".png")]+".webp", upload.WebPKey)
+	assert.Equal(t, "http://files.test/"+upload.WebPKey, upload.WebPURL)
+
+	file, err := os.Open(filepath.Join(root, filepath.FromSlash(upload.WebPKey)))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer file.Close()
+	derivative, err := webp.Decode(file)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Pt(6, 4), derivative.Bounds().Size())
+	assert.Equal(t, color.NRGBA{R: 200, G: 180, B: 200, A: 255}, color.NRGBAModel.Convert(derivative.At(5, 3)))
+}
+
+func TestUploadWithoutWebPDerivatives(t *testing.T) {
+	service, _ := newUploadService(t, false)
+
+	upload, err := service.Upload(fileHeader(t, "cover.png", testPNG(t)))
+	assert.NoError(t, err)
+	assert.Empty(t, upload.WebPKey)
+	assert.Empty(t, upload.WebPURL)
+}
+
+func TestUploadRejectsImagesThatDoNotDecode(t *testing.T) {
+	service, _ := newUploadService(t, true)
+
+	// A text file renamed .png is refused
+	_, err := service.Upload(fileHeader(t, "notes.png", []byte("these are not the pixels you are looking for")))
+	assert.True(t, services.IsUploadValidationError(err))
+
+	// So is a file that is sniffed as PNG but is not a whole image
+	_, err = service.Upload(fileHeader(t, "broken.png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")))
+	assert.ErrorIs(t, err, imaging.ErrInvalidImage)
+	assert.True(t, services.IsUploadValidationError(err))
+}
+
+func TestUploadDoesNotDecodeOtherFiles(t *testing.T) {
+	service, _ := newUploadService(t, true)
+
+	upload, err := service.Upload(fileHeader(t, "notes.txt", []byte("plain text")))
+	assert.NoError(t, err)
+	assert.Equal(t, "text/plain", upload.ContentType)
+	assert.Empty(t, upload.WebPKey)
+}
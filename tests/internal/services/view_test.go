@@ -0,0 +1,44 @@
+package services_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/services"
+)
+
+func TestViewCounterAccumulatesConcurrentViews(t *testing.T) {
+	counter := services.NewViewCounter("projects")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				counter.Record(uint(1 + i%2))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, uint(500), counter.Pending(1))
+	assert.Equal(t, uint(500), counter.Pending(2))
+	assert.Equal(t, uint(0), counter.Pending(3))
+}
+
+func TestNilViewCounterCountsNothing(t *testing.T) {
+	var counter *services.ViewCounter
+
+	counter.Record(1)
+	assert.Equal(t, uint(0), counter.Pending(1))
+	assert.NoError(t, counter.Flush())
+	assert.NoError(t, counter.Stop())
+}
+
+func TestViewCounterWithoutViewsFlushesNothing(t *testing.T) {
+	// Nothing is written, so no database is needed
+	counter := services.NewViewCounter("projects").Start(0)
+	assert.NoError(t, counter.Stop())
+}
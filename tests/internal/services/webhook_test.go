@@ -0,0 +1,22 @@
+package services_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/services"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	body := []byte(`{"event":"blog.published"}`)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	assert.Equal(t, expected, services.SignWebhookPayload("secret", body))
+	assert.NotEqual(t, expected, services.SignWebhookPayload("other-secret", body))
+}
@@ -0,0 +1,79 @@
+package storage_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/storage"
+)
+
+func TestLocalStorageSaveAndDelete(t *testing.T) {
+	root := t.TempDir()
+	store := storage.NewLocalStorage(root, "http://localhost:8080/uploads/")
+
+	err := store.Save("2024/05/cover.png", strings.NewReader("image bytes"), "image/png")
+	assert.NoError(t, err)
+
+	path := filepath.Join(root, "2024", "05", "cover.png")
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "image bytes", string(content))
+
+	url := store.URL("2024/05/cover.png")
+	assert.Equal(t, "http://localhost:8080/uploads/2024/05/cover.png", url)
+
+	key, ok := storage.KeyFromURL(store, url)
+	assert.True(t, ok)
+	assert.Equal(t, "2024/05/cover.png", key)
+
+	assert.NoError(t, store.Delete(key))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	// Deleting again is not an error
+	assert.NoError(t, store.Delete(key))
+}
+
+func TestLocalStorageRejectsKeysOutsideRoot(t *testing.T) {
+	store := storage.NewLocalStorage(t.TempDir(), "http://localhost:8080/uploads")
+
+	for _, key := range []string{"", "../secret.png", "2024/../../secret.png"} {
+		assert.ErrorIs(t, store.Save(key, strings.NewReader("x"), "image/png"), storage.ErrInvalidKey, key)
+		assert.ErrorIs(t, store.Delete(key), storage.ErrInvalidKey, key)
+	}
+}
+
+func TestKeyFromURLIgnoresExternalURLs(t *testing.T) {
+	store := storage.NewLocalStorage(t.TempDir(), "http://localhost:8080/uploads")
+
+	_, ok := storage.KeyFromURL(store, "https://cdn.example.com/cover.png")
+	assert.False(t, ok)
+
+	_, ok = storage.KeyFromURL(store, "http://localhost:8080/uploads/")
+	assert.False(t, ok)
+}
+
+func TestLocalStorageList(t *testing.T) {
+	root := t.TempDir()
+	store := storage.NewLocalStorage(root, "http://localhost:8080/uploads")
+
+	assert.NoError(t, store.Save("2024/05/cover.png", strings.NewReader("image bytes"), "image/png"))
+	assert.NoError(t, store.Save("2024/06/notes.txt", strings.NewReader("notes"), "text/plain"))
+
+	files, err := store.List()
+	assert.NoError(t, err)
+	if assert.Len(t, files, 2) {
+		assert.Equal(t, "2024/05/cover.png", files[0].Key)
+		assert.Equal(t, int64(len("image bytes")), files[0].Size)
+		assert.False(t, files[0].ModTime.IsZero())
+		assert.Equal(t, "2024/06/notes.txt", files[1].Key)
+	}
+
+	// Nothing is stored before the root exists
+	files, err = storage.NewLocalStorage(filepath.Join(root, "missing"), "http://localhost:8080/uploads").List()
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+}
@@ -0,0 +1,143 @@
+package storage_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/configs"
+	"zionechainapi/internal/clock"
+	"zionechainapi/internal/storage"
+)
+
+// s3Request is a request received by the mocked object store
+type s3Request struct {
+	method        string
+	path          string
+	body          string
+	contentType   string
+	authorization string
+	amzDate       string
+	payloadHash   string
+}
+
+// newMockS3 starts a fake object store that records requests and answers with status
+func newMockS3(t *testing.T, status int) (*httptest.Server, *[]s3Request) {
+	var requests []s3Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requests = append(requests, s3Request{
+			method:        r.Method,
+			path:          r.URL.Path,
+			body:          string(body),
+			contentType:   r.Header.Get("Content-Type"),
+			authorization: r.Header.Get("Authorization"),
+			amzDate:       r.Header.Get("X-Amz-Date"),
+			payloadHash:   r.Header.Get("X-Amz-Content-Sha256"),
+		})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func newTestS3Storage(endpoint, baseURL string) *storage.S3Storage {
+	config := configs.S3Config{
+		Endpoint:  endpoint,
+		Region:    "eu-west-1",
+		Bucket:    "portfolio",
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "secret",
+	}
+	fake := clock.NewFake(time.Date(2024, time.May, 1, 12, 30, 0, 0, time.UTC))
+	return storage.NewS3Storage(config, baseURL).WithClock(fake)
+}
+
+func TestS3StorageSaveAndDelete(t *testing.T) {
+	server, requests := newMockS3(t, http.StatusOK)
+	store := newTestS3Storage(server.URL, "")
+
+	assert.NoError(t, store.Save("2024/05/cover.png", strings.NewReader("image bytes"), "image/png"))
+	assert.NoError(t, store.Delete("2024/05/cover.png"))
+
+	if assert.Len(t, *requests, 2) {
+		put := (*requests)[0]
+		assert.Equal(t, http.MethodPut, put.method)
+		assert.Equal(t, "/portfolio/2024/05/cover.png", put.path)
+		assert.Equal(t, "image bytes", put.body)
+		assert.Equal(t, "image/png", put.contentType)
+		assert.Equal(t, "20240501T123000Z", put.amzDate)
+		// SHA-256 of the body
+		assert.Equal(t, "de7030234493a8bea844dbe1d8676e68a2c1a4b014c721f0425a22b6df66faec", put.payloadHash)
+		assert.True(t, strings.HasPrefix(put.authorization,
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240501/eu-west-1/s3/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature="),
+			put.authorization)
+
+		del := (*requests)[1]
+		assert.Equal(t, http.MethodDelete, del.method)
+		assert.Equal(t, "/portfolio/2024/05/cover.png", del.path)
+		assert.Contains(t, del.authorization, "SignedHeaders=host;x-amz-content-sha256;x-amz-date,")
+	}
+}
+
+func TestS3StorageURL(t *testing.T) {
+	store := newTestS3Storage("https://s3.eu-west-1.amazonaws.com/", "")
+	assert.Equal(t, "https://s3.eu-west-1.amazonaws.com/portfolio/2024/05/cover.png", store.URL("2024/05/cover.png"))
+
+	// A CDN in front of the bucket
+	store = newTestS3Storage("https://s3.eu-west-1.amazonaws.com", "https://cdn.example.com/")
+	url := store.URL("2024/05/cover.png")
+	assert.Equal(t, "https://cdn.example.com/2024/05/cover.png", url)
+
+	key, ok := storage.KeyFromURL(store, url)
+	assert.True(t, ok)
+	assert.Equal(t, "2024/05/cover.png", key)
+}
+
+func TestS3StorageReportsErrors(t *testing.T) {
+	server, _ := newMockS3(t, http.StatusForbidden)
+	store := newTestS3Storage(server.URL, "")
+
+	assert.Error(t, store.Save("2024/05/cover.png", strings.NewReader("image bytes"), "image/png"))
+	assert.Error(t, store.Delete("2024/05/cover.png"))
+	_, err := store.List()
+	assert.Error(t, err)
+}
+
+func TestS3StorageListFollowsContinuationTokens(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/portfolio", r.URL.Path)
+		assert.Contains(t, r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/")
+		queries = append(queries, r.URL.RawQuery)
+
+		if r.URL.Query().Get("continuation-token") == "" {
+			io.WriteString(w, `<ListBucketResult>
+	<Contents><Key>2024/05/cover.png</Key><Size>11</Size><LastModified>2024-05-01T12:00:00.000Z</LastModified></Contents>
+	<IsTruncated>true</IsTruncated>
+	<NextContinuationToken>page/2+</NextContinuationToken>
+</ListBucketResult>`)
+			return
+		}
+		io.WriteString(w, `<ListBucketResult>
+	<Contents><Key>2024/06/notes.txt</Key><Size>5</Size><LastModified>2024-06-01T08:00:00.000Z</LastModified></Contents>
+	<IsTruncated>false</IsTruncated>
+</ListBucketResult>`)
+	}))
+	t.Cleanup(server.Close)
+
+	files, err := newTestS3Storage(server.URL, "").List()
+	assert.NoError(t, err)
+	assert.Equal(t, []storage.File{
+		{Key: "2024/05/cover.png", Size: 11, ModTime: time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)},
+		{Key: "2024/06/notes.txt", Size: 5, ModTime: time.Date(2024, time.June, 1, 8, 0, 0, 0, time.UTC)},
+	}, files)
+	assert.Equal(t, []string{"list-type=2", "continuation-token=page%2F2%2B&list-type=2"}, queries)
+}
@@ -0,0 +1,33 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/utils"
+)
+
+func TestGenerateExcerptShortContent(t *testing.T) {
+	excerpt := utils.GenerateExcerpt("<p>Hello <strong>world</strong> &amp; friends</p>", 160)
+	assert.Equal(t, "Hello world & friends", excerpt)
+}
+
+func TestGenerateExcerptTruncatesAtWordBoundary(t *testing.T) {
+	content := "<p>" + strings.Repeat("lorem ipsum ", 30) + "</p>"
+
+	excerpt := utils.GenerateExcerpt(content, 160)
+	assert.LessOrEqual(t, len([]rune(excerpt)), 160)
+	assert.True(t, strings.HasSuffix(excerpt, "..."))
+	assert.NotContains(t, excerpt, "<p>")
+
+	// No word is cut in half
+	for _, word := range strings.Fields(strings.TrimSuffix(excerpt, "...")) {
+		assert.Contains(t, []string{"lorem", "ipsum"}, word)
+	}
+}
+
+func TestGenerateExcerptKeepsAdjacentElementsApart(t *testing.T) {
+	excerpt := utils.GenerateExcerpt("<h1>Title</h1><p>First paragraph</p>", 160)
+	assert.Equal(t, "Title First paragraph", excerpt)
+}
@@ -0,0 +1,54 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/utils"
+)
+
+func respond(handler func(c *gin.Context)) map[string]interface{} {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	handler(c)
+
+	var response map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return response
+}
+
+func TestErrorResponsesCarryStatusCode(t *testing.T) {
+	response := respond(func(c *gin.Context) { utils.NotFoundResponse(c, "Project not found") })
+	assert.Equal(t, false, response["success"])
+	assert.Equal(t, utils.CodeResourceNotFound, response["code"])
+	assert.Equal(t, "Project not found", response["message"])
+
+	response = respond(func(c *gin.Context) { utils.ValidationErrorResponse(c, "title is required") })
+	assert.Equal(t, utils.CodeValidationFailed, response["code"])
+
+	response = respond(func(c *gin.Context) { utils.InternalServerErrorResponse(c, nil) })
+	assert.Equal(t, utils.CodeInternalError, response["code"])
+}
+
+func TestCodedErrorResponseOverridesStatusCode(t *testing.T) {
+	response := respond(func(c *gin.Context) {
+		utils.CodedErrorResponse(c, http.StatusUnauthorized, utils.CodeAuthInvalidCredentials, "invalid phone or password", nil)
+	})
+	assert.Equal(t, utils.CodeAuthInvalidCredentials, response["code"])
+}
+
+func TestSuccessResponseHasNoCode(t *testing.T) {
+	response := respond(func(c *gin.Context) { utils.OKResponse(c, "ok", nil) })
+	_, ok := response["code"]
+	assert.False(t, ok)
+}
+
+func TestCodeForStatusFallsBackByClass(t *testing.T) {
+	assert.Equal(t, utils.CodeBadRequest, utils.CodeForStatus(http.StatusTeapot))
+	assert.Equal(t, utils.CodeInternalError, utils.CodeForStatus(http.StatusBadGateway))
+}
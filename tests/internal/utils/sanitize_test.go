@@ -0,0 +1,35 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/utils"
+)
+
+func TestSanitizeHTMLStripsScripts(t *testing.T) {
+	sanitized := utils.SanitizeHTML(`<p>Hello</p><script>alert("xss")</script><style>p{}</style>`)
+	assert.Equal(t, "<p>Hello</p>", sanitized)
+}
+
+func TestSanitizeHTMLKeepsSafeMarkup(t *testing.T) {
+	content := `<p>Read <a href="https://example.com/post" title="Post">this</a></p><img src="/uploads/a.png" alt="A"/>`
+	assert.Equal(t, content, utils.SanitizeHTML(content))
+}
+
+func TestSanitizeHTMLStripsUnsafeAttributes(t *testing.T) {
+	for content, expected := range map[string]string{
+		`<a href="javascript:alert(1)" onclick="steal()">link</a>`: `<a>link</a>`,
+		`<a href=" JavaScript:alert(1)">link</a>`:                 `<a>link</a>`,
+		`<img src="x" onerror="alert(1)">`:                         `<img src="x">`,
+		`<img src="data:text/html;base64,PHNjcmlwdD4=">`:           `<img>`,
+		`<p style="color:red" class="lead">text</p>`:               `<p>text</p>`,
+	} {
+		assert.Equal(t, expected, utils.SanitizeHTML(content), content)
+	}
+}
+
+func TestSanitizeHTMLKeepsTextOfDisallowedTags(t *testing.T) {
+	sanitized := utils.SanitizeHTML(`<form><button>Click</button> &lt;b&gt;</form><iframe src="https://evil.example">frame</iframe>`)
+	assert.Equal(t, "Click &lt;b&gt;", sanitized)
+}
@@ -0,0 +1,40 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zionechainapi/internal/utils"
+)
+
+func TestSanitizeSlugLatin(t *testing.T) {
+	assert.Equal(t, "cafe-au-lait", utils.SanitizeSlug("Café au Lait!"))
+}
+
+func TestSanitizeSlugTransliteratesPersian(t *testing.T) {
+	assert.Equal(t, "slam-dnya", utils.SanitizeSlug("سلام دنیا"))
+	assert.Equal(t, "mqalh-2", utils.SanitizeSlug("مقاله ۲"))
+	assert.Equal(t, "mydanym", utils.SanitizeSlug("می‌دانیم"))
+}
+
+func TestSanitizeSlugPersianTitlesDoNotCollide(t *testing.T) {
+	first := utils.SanitizeSlug("برنامه نویسی با گو")
+	second := utils.SanitizeSlug("طراحی پایگاه داده")
+
+	assert.NotEmpty(t, first)
+	assert.NotEmpty(t, second)
+	assert.NotEqual(t, first, second)
+}
+
+func TestSanitizeSlugFallsBackToHash(t *testing.T) {
+	slug := utils.SanitizeSlug("你好世界")
+
+	assert.Regexp(t, `^[0-9a-f]{12}$`, slug)
+	assert.Equal(t, slug, utils.SanitizeSlug("你好世界"))
+	assert.NotEqual(t, slug, utils.SanitizeSlug("再见世界"))
+}
+
+func TestSanitizeSlugWithoutLettersIsEmpty(t *testing.T) {
+	assert.Equal(t, "", utils.SanitizeSlug("!!"))
+	assert.Equal(t, "", utils.SanitizeSlug("   "))
+}